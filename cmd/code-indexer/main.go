@@ -2,11 +2,18 @@ package main
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"os/signal"
 	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
 	"syscall"
+	"time"
 
 	"github.com/aaamil13/CodeIndexerMCP/internal/core"
 	"github.com/aaamil13/CodeIndexerMCP/internal/mcp"
@@ -31,7 +38,7 @@ func run() error {
 
 	// Get project path (current directory by default)
 	projectPath := "."
-	if len(os.Args) > 2 {
+	if len(os.Args) > 2 && !strings.HasPrefix(os.Args[2], "--") {
 		projectPath = os.Args[2]
 	}
 
@@ -43,19 +50,30 @@ func run() error {
 
 	switch command {
 	case "index":
-		return runIndex(absPath)
+		return runIndex(absPath, os.Args[2:])
 	case "watch":
-		return runWatch(absPath)
+		return runWatch(absPath, os.Args[2:])
 	case "mcp":
-		return runMCP(absPath)
+		return runMCP(absPath, os.Args[2:])
 	case "search":
 		if len(os.Args) < 3 {
 			return fmt.Errorf("search requires a query argument")
 		}
 		query := os.Args[2]
-		return runSearch(absPath, query)
+		return runSearch(absPath, query, os.Args[3:])
 	case "overview":
-		return runOverview(absPath)
+		return runOverview(absPath, os.Args[2:])
+	case "languages":
+		return runLanguages()
+	case "snapshot":
+		return runSnapshot(os.Args[2:])
+	case "vacuum":
+		return runVacuum(absPath, os.Args[2:])
+	case "purge":
+		if len(os.Args) < 3 || strings.HasPrefix(os.Args[2], "--") {
+			return fmt.Errorf("purge requires a project path")
+		}
+		return runPurge(absPath, os.Args[3:])
 	case "help", "--help", "-h":
 		printUsage()
 		return nil
@@ -64,33 +82,265 @@ func run() error {
 	}
 }
 
-func runIndex(projectPath string) error {
+func runIndex(projectPath string, args []string) error {
+	since := ""
+	profile := false
+	dryRun := false
+	for _, arg := range args {
+		if strings.HasPrefix(arg, "--since=") {
+			since = strings.TrimPrefix(arg, "--since=")
+		}
+		if arg == "--profile" {
+			profile = true
+		}
+		if arg == "--dry-run" {
+			dryRun = true
+		}
+	}
+
 	fmt.Println("🚀 Code Indexer - Indexing project...")
 	fmt.Println("Project:", projectPath)
 
-	indexer, err := core.NewIndexer(projectPath, nil)
+	cfg, err := loadConfig(projectPath, args, "--since", "--profile", "--dry-run")
+	if err != nil {
+		return err
+	}
+
+	indexer, err := core.NewIndexer(projectPath, cfg)
 	if err != nil {
 		return err
 	}
 	defer indexer.Close()
 
+	if dryRun {
+		if err := indexer.InitializeReadOnly(); err != nil {
+			return err
+		}
+		report, err := indexer.DryRun()
+		if err != nil {
+			return err
+		}
+		writeDryRunReport(os.Stdout, report)
+		return nil
+	}
+
 	if err := indexer.Initialize(); err != nil {
 		return err
 	}
 
-	if err := indexer.IndexAll(); err != nil {
+	if since != "" {
+		sinceTime, err := parseSince(since, indexer.LastIndexed())
+		if err != nil {
+			return err
+		}
+
+		count, err := indexer.IndexChangedSince(sinceTime)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("✅ Indexed %d files changed since %s\n", count, sinceTime.Format("2006-01-02 15:04:05"))
+		if profile {
+			fmt.Println()
+			writeIndexStats(os.Stdout, indexer.LastIndexStats())
+		}
+		return nil
+	}
+
+	// Ctrl+C stops between files, keeping what was indexed so far
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	if err := indexer.IndexAllContext(ctx); err != nil {
+		if errors.Is(err, context.Canceled) {
+			return fmt.Errorf("indexing cancelled; run index again to finish")
+		}
 		return err
 	}
 
 	fmt.Println("✅ Indexing completed successfully!")
+	if profile {
+		fmt.Println()
+		writeIndexStats(os.Stdout, indexer.LastIndexStats())
+	}
 	return nil
 }
 
-func runWatch(projectPath string) error {
+// writeIndexStats prints how long each indexing phase took
+func writeIndexStats(w io.Writer, stats *types.IndexStats) {
+	phase := func(indent, name string, d time.Duration) {
+		share := 0.0
+		if stats.Total > 0 {
+			share = 100 * float64(d) / float64(stats.Total)
+		}
+		fmt.Fprintf(w, "%s%-*s %12v %6.1f%%\n", indent, 12-len(indent), name+":", d.Round(time.Microsecond), share)
+	}
+
+	fmt.Fprintln(w, "⏱️  Index Profile")
+	fmt.Fprintln(w, "================")
+	fmt.Fprintf(w, "Files: %d\n", stats.Files)
+	phase("", "Total", stats.Total)
+	phase("  ", "Scan", stats.Scan)
+	phase("  ", "Hash", stats.Hash)
+	phase("  ", "Parse", stats.Parse)
+
+	languages := make([]string, 0, len(stats.ParseByLanguage))
+	for lang := range stats.ParseByLanguage {
+		languages = append(languages, lang)
+	}
+	sort.Strings(languages)
+	for _, lang := range languages {
+		phase("    ", lang, stats.ParseByLanguage[lang])
+	}
+
+	phase("  ", "Write", stats.Write)
+	phase("  ", "Link", stats.Link)
+	fmt.Fprintln(w, "\nHash, parse and write add up the time of every worker.")
+}
+
+// writeDryRunReport prints what index would do, listing the files it would
+// index or drop
+func writeDryRunReport(w io.Writer, report *types.DryRunReport) {
+	fmt.Fprintln(w, "🔍 Dry Run (the index was not changed)")
+	fmt.Fprintln(w, "=======================================")
+	fmt.Fprintf(w, "Files: %d\n", report.Files)
+	fmt.Fprintf(w, "  New:       %d\n", len(report.New))
+	fmt.Fprintf(w, "  Changed:   %d\n", len(report.Changed))
+	fmt.Fprintf(w, "  Unchanged: %d\n", report.Unchanged)
+	fmt.Fprintf(w, "Missing:   %d\n", len(report.Missing))
+	fmt.Fprintf(w, "Symbols in new and changed files: %d\n", report.Symbols)
+
+	languages := make([]string, 0, len(report.ByLanguage))
+	for lang := range report.ByLanguage {
+		languages = append(languages, lang)
+	}
+	sort.Strings(languages)
+	fmt.Fprintln(w, "\nBy language:")
+	for _, lang := range languages {
+		fmt.Fprintf(w, "  %-12s %d\n", lang+":", report.ByLanguage[lang])
+	}
+
+	list := func(title string, paths []string) {
+		if len(paths) == 0 {
+			return
+		}
+		fmt.Fprintf(w, "\n%s:\n", title)
+		for _, path := range paths {
+			fmt.Fprintf(w, "  %s\n", path)
+		}
+	}
+	list("New", report.New)
+	list("Changed", report.Changed)
+	list("Missing", report.Missing)
+	list("Failed to parse", report.Failed)
+}
+
+// loadConfig reads the project's configuration file, then applies the
+// --project-name, --workers, --exclude, --languages, --max-file-size,
+// --debounce, --tool-timeout and --extract-method-lines flags over it. Any
+// other flag is rejected unless it is one of the command's own flags.
+func loadConfig(projectPath string, args []string, commandFlags ...string) (*core.Config, error) {
+	cfg, err := core.LoadConfig(projectPath)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, arg := range args {
+		if !strings.HasPrefix(arg, "--") {
+			continue // The project path or another positional argument
+		}
+		name, value, _ := strings.Cut(arg, "=")
+		switch name {
+		case "--project-name":
+			if value == "" {
+				return nil, fmt.Errorf("invalid --project-name value: use a non-empty name")
+			}
+			cfg.ProjectName = value
+		case "--workers":
+			workers, err := strconv.Atoi(value)
+			if err != nil || workers < 1 {
+				return nil, fmt.Errorf("invalid --workers value %q: use a positive number", value)
+			}
+			cfg.WorkerCount = workers
+		case "--exclude":
+			cfg.Exclude = append(cfg.Exclude, strings.Split(value, ",")...)
+		case "--languages":
+			cfg.Languages = strings.Split(value, ",")
+		case "--max-file-size":
+			size, err := strconv.ParseInt(value, 10, 64)
+			if err != nil || size < 0 {
+				return nil, fmt.Errorf("invalid --max-file-size value %q: use a number of bytes", value)
+			}
+			cfg.MaxFileSize = size
+		case "--debounce":
+			debounce, err := time.ParseDuration(value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid --debounce value %q: use a duration such as 500ms", value)
+			}
+			cfg.WatchDebounce = debounce
+		case "--tool-timeout":
+			timeout, err := time.ParseDuration(value)
+			if err != nil || timeout < 0 {
+				return nil, fmt.Errorf("invalid --tool-timeout value %q: use a duration such as 2m, or 0 for no limit", value)
+			}
+			cfg.ToolTimeout = timeout
+		case "--extract-method-lines":
+			lines, err := strconv.Atoi(value)
+			if err != nil || lines < 1 {
+				return nil, fmt.Errorf("invalid --extract-method-lines value %q: use a positive number", value)
+			}
+			cfg.ExtractMethodLines = lines
+		default:
+			known := false
+			for _, flag := range commandFlags {
+				known = known || flag == name
+			}
+			if !known {
+				return nil, fmt.Errorf("unknown flag %s", name)
+			}
+		}
+	}
+
+	return cfg, nil
+}
+
+// parseSince parses a --since value: "last", an RFC 3339 timestamp or a date
+func parseSince(value string, lastIndexed time.Time) (time.Time, error) {
+	if value == "last" {
+		return lastIndexed, nil
+	}
+
+	for _, layout := range []string{time.RFC3339, "2006-01-02 15:04:05", "2006-01-02"} {
+		if t, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+			return t, nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("invalid --since value %q: use \"last\", RFC 3339 or YYYY-MM-DD", value)
+}
+
+func runWatch(projectPath string, args []string) error {
+	indexOnStart := true
+	for _, arg := range args {
+		if strings.HasPrefix(arg, "--index-on-start=") {
+			value := strings.TrimPrefix(arg, "--index-on-start=")
+			enabled, err := strconv.ParseBool(value)
+			if err != nil {
+				return fmt.Errorf("invalid --index-on-start value %q: use true or false", value)
+			}
+			indexOnStart = enabled
+		}
+	}
+
 	fmt.Println("🔍 Code Indexer - Watch Mode")
 	fmt.Println("Project:", projectPath)
 
-	indexer, err := core.NewIndexer(projectPath, nil)
+	cfg, err := loadConfig(projectPath, args, "--index-on-start")
+	if err != nil {
+		return err
+	}
+
+	indexer, err := core.NewIndexer(projectPath, cfg)
 	if err != nil {
 		return err
 	}
@@ -100,12 +350,30 @@ func runWatch(projectPath string) error {
 		return err
 	}
 
-	// Initial index
-	fmt.Println("Performing initial index...")
-	if err := indexer.IndexAll(); err != nil {
-		return err
+	// Ctrl+C cancels the initial index as well as stopping the watcher
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	// Initial index, skipped when the stored index is already fresh
+	needed := false
+	if indexOnStart {
+		if needed, err = indexer.NeedsReindex(); err != nil {
+			return err
+		}
+	}
+	if needed {
+		fmt.Println("Performing initial index...")
+		if err := indexer.IndexAllContext(ctx); err != nil {
+			if errors.Is(err, context.Canceled) {
+				fmt.Println("\n🛑 Initial index cancelled")
+				return nil
+			}
+			return err
+		}
+		fmt.Println("✅ Initial indexing complete")
+	} else {
+		fmt.Println("Skipping initial index")
 	}
-	fmt.Println("✅ Initial indexing complete")
 
 	// Start watching
 	fmt.Println("👀 Watching for file changes... (Press Ctrl+C to stop)")
@@ -113,11 +381,7 @@ func runWatch(projectPath string) error {
 		return err
 	}
 
-	// Handle signals
-	sigChan := make(chan os.Signal, 1)
-	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
-
-	<-sigChan
+	<-ctx.Done()
 	fmt.Println("\n🛑 Stopping watcher...")
 
 	if err := indexer.StopWatch(); err != nil {
@@ -128,33 +392,29 @@ func runWatch(projectPath string) error {
 	return nil
 }
 
-func runMCP(projectPath string) error {
+func runMCP(projectPath string, args []string) error {
 	fmt.Println("🚀 Code Indexer MCP Server")
 	fmt.Println("Project:", projectPath)
 
-	indexer, err := core.NewIndexer(projectPath, nil)
+	cfg, err := loadConfig(projectPath, args)
 	if err != nil {
 		return err
 	}
-	defer indexer.Close()
 
-	if err := indexer.Initialize(); err != nil {
+	indexer, err := core.NewIndexer(projectPath, cfg)
+	if err != nil {
 		return err
 	}
+	defer indexer.Close()
 
-	// Index project on startup
-	fmt.Println("Indexing project...")
-	if err := indexer.IndexAll(); err != nil {
+	if err := indexer.Initialize(); err != nil {
 		return err
 	}
 
-	// Start MCP server
-	server := mcp.NewServer(indexer)
-
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
-	// Handle signals
+	// Handle signals, which also cancel the startup index
 	sigChan := make(chan os.Signal, 1)
 	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
 
@@ -164,6 +424,19 @@ func runMCP(projectPath string) error {
 		cancel()
 	}()
 
+	// Index project on startup
+	fmt.Println("Indexing project...")
+	if err := indexer.IndexAllContext(ctx); err != nil {
+		if errors.Is(err, context.Canceled) {
+			return nil
+		}
+		return err
+	}
+
+	// Start MCP server
+	server := mcp.NewServer(indexer)
+	server.SetToolTimeout(cfg.ToolTimeout)
+
 	fmt.Fprintln(os.Stderr, "MCP Server started. Ready to receive requests.")
 
 	if err := server.Start(ctx); err != nil && err != context.Canceled {
@@ -173,8 +446,23 @@ func runMCP(projectPath string) error {
 	return nil
 }
 
-func runSearch(projectPath string, query string) error {
-	indexer, err := core.NewIndexer(projectPath, nil)
+func runSearch(projectPath string, query string, args []string) error {
+	format := "text"
+	for _, arg := range args {
+		if strings.HasPrefix(arg, "--format=") {
+			format = strings.TrimPrefix(arg, "--format=")
+		}
+	}
+	if format != "text" && format != "tree" {
+		return fmt.Errorf("invalid --format value %q: use text or tree", format)
+	}
+
+	cfg, err := loadConfig(projectPath, args, "--format")
+	if err != nil {
+		return err
+	}
+
+	indexer, err := core.NewIndexer(projectPath, cfg)
 	if err != nil {
 		return err
 	}
@@ -189,19 +477,43 @@ func runSearch(projectPath string, query string) error {
 		Limit: 20,
 	}
 
-	symbols, err := indexer.SearchSymbols(opts)
-	if err != nil {
-		return err
-	}
+	if format == "tree" {
+		var results []*types.Symbol
+		if err := indexer.SearchSymbolsStream(opts, func(symbol *types.Symbol) error {
+			results = append(results, symbol)
+			return nil
+		}); err != nil {
+			return err
+		}
+		if len(results) == 0 {
+			fmt.Println("No symbols found matching:", query)
+			return nil
+		}
 
-	if len(symbols) == 0 {
-		fmt.Println("No symbols found matching:", query)
+		files, err := indexer.GetAllFiles()
+		if err != nil {
+			return err
+		}
+		matched := make(map[int64]bool)
+		for _, symbol := range results {
+			matched[symbol.FileID] = true
+		}
+		var matchedFiles []*types.File
+		for _, file := range files {
+			if matched[file.ID] {
+				matchedFiles = append(matchedFiles, file)
+			}
+		}
+
+		utils.WriteTree(os.Stdout, symbolTree(matchedFiles, results))
+		fmt.Printf("\nFound %d symbols\n", len(results))
 		return nil
 	}
 
-	fmt.Printf("Found %d symbols:\n\n", len(symbols))
-
-	for _, symbol := range symbols {
+	// Print results as they are read rather than after the whole search
+	found := 0
+	err = indexer.SearchSymbolsStream(opts, func(symbol *types.Symbol) error {
+		found++
 		fmt.Printf("📍 %s (%s)\n", symbol.Name, symbol.Type)
 		if symbol.Signature != "" {
 			fmt.Printf("   Signature: %s\n", symbol.Signature)
@@ -211,13 +523,39 @@ func runSearch(projectPath string, query string) error {
 			fmt.Printf("   Docs: %s\n", truncate(symbol.Documentation, 80))
 		}
 		fmt.Println()
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if found == 0 {
+		fmt.Println("No symbols found matching:", query)
+		return nil
 	}
 
+	fmt.Printf("Found %d symbols\n", found)
+
 	return nil
 }
 
-func runOverview(projectPath string) error {
-	indexer, err := core.NewIndexer(projectPath, nil)
+func runOverview(projectPath string, args []string) error {
+	format := "text"
+	for _, arg := range args {
+		if strings.HasPrefix(arg, "--format=") {
+			format = strings.TrimPrefix(arg, "--format=")
+		}
+	}
+	if format != "text" && format != "json" && format != "tree" {
+		return fmt.Errorf("invalid --format value %q: use text, json or tree", format)
+	}
+
+	cfg, err := loadConfig(projectPath, args, "--format")
+	if err != nil {
+		return err
+	}
+
+	indexer, err := core.NewIndexer(projectPath, cfg)
 	if err != nil {
 		return err
 	}
@@ -232,21 +570,243 @@ func runOverview(projectPath string) error {
 		return err
 	}
 
-	fmt.Println("📊 Project Overview")
-	fmt.Println("==================")
-	fmt.Printf("Name: %s\n", overview.Project.Name)
-	fmt.Printf("Path: %s\n", overview.Project.Path)
-	fmt.Printf("Total Files: %d\n", overview.TotalFiles)
-	fmt.Printf("Total Symbols: %d\n", overview.TotalSymbols)
+	if format == "tree" {
+		files, err := indexer.GetAllFiles()
+		if err != nil {
+			return err
+		}
+		var symbols []*types.Symbol
+		for _, file := range files {
+			structure, err := indexer.GetFileStructure(file.RelativePath)
+			if err != nil {
+				return err
+			}
+			symbols = append(symbols, structure.Symbols...)
+		}
+
+		fmt.Printf("📊 %s (%d files, %d symbols)\n\n", overview.Project.Name, overview.TotalFiles, overview.TotalSymbols)
+		utils.WriteTree(os.Stdout, symbolTree(files, symbols))
+		return nil
+	}
+
+	return writeOverview(os.Stdout, overview, format)
+}
+
+// symbolTree arranges symbols under the files they're in, ordered by path,
+// with methods and fields nested under their class or type
+func symbolTree(files []*types.File, symbols []*types.Symbol) []*utils.TreeNode {
+	byFile := make(map[int64][]*types.Symbol)
+	for _, symbol := range symbols {
+		byFile[symbol.FileID] = append(byFile[symbol.FileID], symbol)
+	}
+
+	sorted := append([]*types.File(nil), files...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].RelativePath < sorted[j].RelativePath })
+
+	var convert func(nodes []*types.SymbolNode) []*utils.TreeNode
+	convert = func(nodes []*types.SymbolNode) []*utils.TreeNode {
+		tree := make([]*utils.TreeNode, len(nodes))
+		for i, node := range nodes {
+			tree[i] = &utils.TreeNode{
+				Label:    fmt.Sprintf("%s (%s) :%d", node.Symbol.Name, node.Symbol.Type, node.Symbol.StartLine),
+				Children: convert(node.Children),
+			}
+		}
+		return tree
+	}
+
+	roots := make([]*utils.TreeNode, 0, len(sorted))
+	for _, file := range sorted {
+		fileSymbols := byFile[file.ID]
+		sort.SliceStable(fileSymbols, func(i, j int) bool { return fileSymbols[i].StartLine < fileSymbols[j].StartLine })
+		roots = append(roots, &utils.TreeNode{
+			Label:    file.RelativePath,
+			Children: convert(core.NestSymbols(fileSymbols)),
+		})
+	}
+	return roots
+}
+
+// writeOverview prints a project overview as text or JSON
+func writeOverview(w io.Writer, overview *types.ProjectOverview, format string) error {
+	if format == "json" {
+		// Map keys are sorted by encoding/json, so LanguageStats is stable
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(overview)
+	}
+
+	fmt.Fprintln(w, "📊 Project Overview")
+	fmt.Fprintln(w, "==================")
+	fmt.Fprintf(w, "Name: %s\n", overview.Project.Name)
+	fmt.Fprintf(w, "Path: %s\n", overview.Project.Path)
+	fmt.Fprintf(w, "Total Files: %d\n", overview.TotalFiles)
+	fmt.Fprintf(w, "Total Symbols: %d\n", overview.TotalSymbols)
 
 	if len(overview.LanguageStats) > 0 {
-		fmt.Println("\nLanguages:")
-		for lang, count := range overview.LanguageStats {
-			fmt.Printf("  - %s: %d files\n", lang, count)
+		languages := make([]string, 0, len(overview.LanguageStats))
+		for lang := range overview.LanguageStats {
+			languages = append(languages, lang)
+		}
+		sort.Strings(languages)
+
+		fmt.Fprintln(w, "\nLanguages:")
+		for _, lang := range languages {
+			fmt.Fprintf(w, "  - %s: %d files\n", lang, overview.LanguageStats[lang])
+		}
+	}
+
+	fmt.Fprintf(w, "\nLast Indexed: %s\n", overview.Project.LastIndexed.Format("2006-01-02 15:04:05"))
+
+	return nil
+}
+
+func runLanguages() error {
+	registry, err := core.NewParserRegistry(nil)
+	if err != nil {
+		return err
+	}
+
+	parsers := registry.ListParsers()
+
+	fmt.Printf("🧩 Supported Languages (%d)\n", len(parsers))
+	fmt.Println("======================")
+	for _, p := range parsers {
+		fmt.Printf("  - %-12s %s (priority %d)\n", p.Language, strings.Join(p.Extensions, ", "), p.Priority)
+		if len(p.Frameworks) > 0 {
+			fmt.Printf("    Frameworks: %s\n", strings.Join(p.Frameworks, ", "))
+		}
+	}
+
+	return nil
+}
+
+func runPurge(projectPath string, args []string) error {
+	dbPath := filepath.Join(core.DefaultConfig().IndexDir, core.DatabaseFile)
+	for _, arg := range args {
+		if strings.HasPrefix(arg, "--db=") {
+			dbPath = strings.TrimPrefix(arg, "--db=")
+		}
+	}
+
+	project, err := core.PurgeProject(dbPath, projectPath)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("🗑️  Purged project %s (%s) from %s\n", project.Name, project.Path, dbPath)
+	return nil
+}
+
+func runVacuum(projectPath string, args []string) error {
+	cfg, err := loadConfig(projectPath, args)
+	if err != nil {
+		return err
+	}
+
+	indexer, err := core.NewIndexer(projectPath, cfg)
+	if err != nil {
+		return err
+	}
+	defer indexer.Close()
+
+	if err := indexer.Initialize(); err != nil {
+		return err
+	}
+
+	removed, err := indexer.Vacuum()
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("🧹 Removed %d orphaned references\n", removed)
+	fmt.Println("✅ Database vacuumed")
+	return nil
+}
+
+func runSnapshot(args []string) error {
+	var positional []string
+	for _, arg := range args {
+		if !strings.HasPrefix(arg, "--") {
+			positional = append(positional, arg)
 		}
 	}
+	if len(positional) < 1 || (positional[0] != "save" && positional[0] != "diff") {
+		return fmt.Errorf("snapshot requires a subcommand: save or diff")
+	}
+	action := positional[0]
 
-	fmt.Printf("\nLast Indexed: %s\n", overview.Project.LastIndexed.Format("2006-01-02 15:04:05"))
+	projectPath := "."
+	if len(positional) > 1 {
+		projectPath = positional[1]
+	}
+	absPath, err := filepath.Abs(projectPath)
+	if err != nil {
+		return fmt.Errorf("invalid project path: %w", err)
+	}
+
+	cfg, err := loadConfig(absPath, args)
+	if err != nil {
+		return err
+	}
+
+	indexer, err := core.NewIndexer(absPath, cfg)
+	if err != nil {
+		return err
+	}
+	defer indexer.Close()
+
+	if err := indexer.Initialize(); err != nil {
+		return err
+	}
+
+	snapshotPath := indexer.SnapshotPath()
+	if len(positional) > 2 {
+		snapshotPath = positional[2]
+	}
+
+	// Bring the index up to date before recording or comparing
+	if err := indexer.IndexAll(); err != nil {
+		return err
+	}
+
+	if action == "save" {
+		snap, err := indexer.SnapshotSymbols()
+		if err != nil {
+			return err
+		}
+		if err := core.SaveSnapshot(snapshotPath, snap); err != nil {
+			return fmt.Errorf("failed to save snapshot: %w", err)
+		}
+		fmt.Printf("📸 Saved snapshot of %d symbols to %s\n", len(snap.Symbols), snapshotPath)
+		return nil
+	}
+
+	snap, err := core.LoadSnapshot(snapshotPath)
+	if err != nil {
+		return fmt.Errorf("failed to load snapshot: %w", err)
+	}
+
+	diff, err := indexer.DiffAgainstSnapshot(snap)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("🔀 Changes since %s\n", snap.CreatedAt.Format("2006-01-02 15:04:05"))
+	fmt.Println("==================")
+	for _, group := range []struct {
+		marker  string
+		symbols []*types.SymbolSnapshot
+	}{
+		{"+", diff.Added},
+		{"-", diff.Removed},
+		{"~", diff.Modified},
+	} {
+		for _, sym := range group.symbols {
+			fmt.Printf("  %s %s %s (%s:%d)\n", group.marker, sym.Type, sym.Name, sym.FilePath, sym.StartLine)
+		}
+	}
+	fmt.Printf("\n%d added, %d removed, %d modified\n", len(diff.Added), len(diff.Removed), len(diff.Modified))
 
 	return nil
 }
@@ -258,19 +818,65 @@ Usage:
   code-indexer <command> [arguments]
 
 Commands:
-  index [path]      Index the project at the given path (default: current directory)
-  watch [path]      Watch for file changes and auto-index (default: current directory)
-  mcp [path]        Start MCP server for the project
-  search <query>    Search for symbols in the project
-  overview [path]   Show project overview and statistics
+  index [path] [--since=<time>] [--profile] [--dry-run] [config flags]
+                    Index the project at the given path (default: current directory);
+                    --since only indexes files modified after the time ("last", RFC 3339 or YYYY-MM-DD);
+                    --profile prints the time spent scanning, hashing, parsing and writing;
+                    --dry-run reports new, changed and unchanged files without writing the index
+  watch [path] [--index-on-start=false] [config flags]
+                    Watch for file changes and auto-index (default: current directory);
+                    the initial index is skipped if nothing changed since the last one,
+                    or always with --index-on-start=false
+  mcp [path] [config flags]
+                    Start MCP server for the project
+  search <query> [--format=text|tree] [config flags]
+                    Search for symbols in the project; tree groups results by file
+  overview [path] [--format=text|json|tree] [config flags]
+                    Show project overview and statistics, or every file's symbols as a tree
+  languages         List supported languages and file extensions
+  snapshot save [path] [file] [config flags]
+                    Save a snapshot of all indexed symbols
+  snapshot diff [path] [file] [config flags]
+                    Show symbols added, removed or modified since the snapshot
+  vacuum [path] [config flags]
+                    Remove orphaned references and compact the index database
+  purge <path> [--db=<file>]
+                    Delete the project indexed from path, e.g. a repository's old location,
+                    from the index database (default: .projectIndex/index.db here)
   help              Show this help message
 
+Config flags override the project's .codeindexer.yaml or .codeindexer.json:
+  --project-name=<name>   Name recorded for the project (default: its directory's)
+  --workers=<n>           Number of parallel indexing workers
+  --exclude=<patterns>    Comma-separated patterns to skip, added to the file's
+  --languages=<list>      Comma-separated languages to index (default: all)
+  --max-file-size=<bytes> Skip larger files
+  --debounce=<duration>   Quiet period before watch mode indexes changes (e.g. 500ms)
+  --tool-timeout=<duration>
+                          Time limit for one MCP tool call (default: 30s, 0: no limit)
+  --extract-method-lines=<n>
+                          Function length above which extract-method is suggested
+
 Examples:
   code-indexer index .
+  code-indexer index . --since=last
+  code-indexer index . --profile
+  code-indexer index . --dry-run
   code-indexer watch /path/to/project
+  code-indexer watch . --index-on-start=false
+  code-indexer index . --languages=go,python --workers=4
   code-indexer mcp /path/to/project
+  code-indexer mcp . --tool-timeout=2m
   code-indexer search "MyFunction"
   code-indexer overview
+  code-indexer search "Server" --format=tree
+  code-indexer overview . --format=json
+  code-indexer overview . --format=tree
+  code-indexer languages
+  code-indexer snapshot save .
+  code-indexer snapshot diff .
+  code-indexer vacuum .
+  code-indexer purge /old/path/to/project
 
 For more information, visit: https://github.com/aaamil13/CodeIndexerMCP
 `)
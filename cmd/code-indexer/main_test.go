@@ -0,0 +1,150 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aaamil13/CodeIndexerMCP/internal/utils"
+	"github.com/aaamil13/CodeIndexerMCP/pkg/types"
+)
+
+func TestWriteOverview_JSON(t *testing.T) {
+	overview := &types.ProjectOverview{
+		Project: &types.Project{
+			Name:        "demo",
+			Path:        "/tmp/demo",
+			LastIndexed: time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC),
+		},
+		TotalFiles:    3,
+		TotalSymbols:  12,
+		LanguageStats: map[string]int{"python": 1, "go": 2},
+	}
+
+	var out bytes.Buffer
+	if err := writeOverview(&out, overview, "json"); err != nil {
+		t.Fatalf("writeOverview failed: %v", err)
+	}
+
+	var decoded types.ProjectOverview
+	if err := json.Unmarshal(out.Bytes(), &decoded); err != nil {
+		t.Fatalf("Expected valid JSON, got %q: %v", out.String(), err)
+	}
+
+	if decoded.Project.Name != "demo" || decoded.TotalFiles != 3 || decoded.TotalSymbols != 12 {
+		t.Errorf("Unexpected round-trip result: %+v", decoded)
+	}
+	if decoded.LanguageStats["go"] != 2 || decoded.LanguageStats["python"] != 1 {
+		t.Errorf("Unexpected language stats: %v", decoded.LanguageStats)
+	}
+
+	// Language keys are emitted in sorted order
+	if strings.Index(out.String(), `"go"`) > strings.Index(out.String(), `"python"`) {
+		t.Errorf("Expected sorted language keys, got %s", out.String())
+	}
+}
+
+func TestWriteOverview_Text(t *testing.T) {
+	overview := &types.ProjectOverview{
+		Project:       &types.Project{Name: "demo", Path: "/tmp/demo"},
+		LanguageStats: map[string]int{"python": 1, "go": 2},
+	}
+
+	var out bytes.Buffer
+	if err := writeOverview(&out, overview, "text"); err != nil {
+		t.Fatalf("writeOverview failed: %v", err)
+	}
+
+	text := out.String()
+	if !strings.Contains(text, "Name: demo") {
+		t.Errorf("Expected project name in text output, got %q", text)
+	}
+	if strings.Index(text, "- go:") > strings.Index(text, "- python:") {
+		t.Errorf("Expected languages in sorted order, got %q", text)
+	}
+}
+
+func TestWriteIndexStats(t *testing.T) {
+	stats := &types.IndexStats{
+		Files:           4,
+		Total:           100 * time.Millisecond,
+		Scan:            10 * time.Millisecond,
+		Hash:            20 * time.Millisecond,
+		Parse:           40 * time.Millisecond,
+		ParseByLanguage: map[string]time.Duration{"python": 15 * time.Millisecond, "go": 25 * time.Millisecond},
+		Write:           20 * time.Millisecond,
+		Link:            5 * time.Millisecond,
+	}
+
+	var out bytes.Buffer
+	writeIndexStats(&out, stats)
+	text := out.String()
+
+	for _, want := range []string{"Files: 4", "Parse:", "40ms", "40.0%", "go:", "25.0%", "Write:", "Link:", "5.0%"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("Expected %q in output:\n%s", want, text)
+		}
+	}
+	if strings.Index(text, "go:") > strings.Index(text, "python:") {
+		t.Errorf("Expected sorted languages, got:\n%s", text)
+	}
+}
+
+func TestSymbolTree(t *testing.T) {
+	files := []*types.File{
+		{ID: 2, RelativePath: "util.go"},
+		{ID: 1, RelativePath: "server.go"},
+	}
+	serverID := int64(10)
+	symbols := []*types.Symbol{
+		{ID: 13, FileID: 1, Name: "main", Type: types.SymbolTypeFunction, StartLine: 20, EndLine: 22},
+		{ID: 11, FileID: 1, Name: "Start", Type: types.SymbolTypeMethod, StartLine: 5, EndLine: 8, ParentID: &serverID},
+		{ID: serverID, FileID: 1, Name: "Server", Type: types.SymbolTypeClass, StartLine: 3, EndLine: 15},
+		{ID: 12, FileID: 1, Name: "Stop", Type: types.SymbolTypeMethod, StartLine: 10, EndLine: 14},
+		{ID: 20, FileID: 2, Name: "helper", Type: types.SymbolTypeFunction, StartLine: 3, EndLine: 5},
+	}
+
+	var out bytes.Buffer
+	utils.WriteTree(&out, symbolTree(files, symbols))
+
+	want := strings.Join([]string{
+		"server.go",
+		"├── Server (class) :3",
+		"│   ├── Start (method) :5",
+		"│   └── Stop (method) :10",
+		"└── main (function) :20",
+		"util.go",
+		"└── helper (function) :3",
+		"",
+	}, "\n")
+	if out.String() != want {
+		t.Errorf("Unexpected tree:\n%s\nwant:\n%s", out.String(), want)
+	}
+}
+
+func TestLoadConfig_Flags(t *testing.T) {
+	projectPath := t.TempDir()
+
+	cfg, err := loadConfig(projectPath, []string{projectPath, "--workers=3", "--extract-method-lines=60", "--tool-timeout=0", "--project-name=app-fork", "--profile"}, "--profile")
+	if err != nil {
+		t.Fatalf("loadConfig failed: %v", err)
+	}
+	if cfg.WorkerCount != 3 || cfg.ExtractMethodLines != 60 {
+		t.Errorf("Expected 3 workers and extract-method at 60 lines, got %d and %d", cfg.WorkerCount, cfg.ExtractMethodLines)
+	}
+	if cfg.ProjectName != "app-fork" {
+		t.Errorf("Expected --project-name to name the project, got %q", cfg.ProjectName)
+	}
+	if cfg.ToolTimeout != 0 {
+		t.Errorf("Expected --tool-timeout=0 to lift the tool time limit, got %v", cfg.ToolTimeout)
+	}
+
+	// A misspelled flag is an error rather than silently ignored
+	for _, args := range [][]string{{"--worker=4"}, {"--profile"}, {"--extract-method-lines=0"}, {"--tool-timeout=-1s"}, {"--project-name="}} {
+		if _, err := loadConfig(projectPath, args); err == nil {
+			t.Errorf("Expected an error for %v", args)
+		}
+	}
+}
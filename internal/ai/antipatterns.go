@@ -0,0 +1,350 @@
+package ai
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/aaamil13/CodeIndexerMCP/internal/database"
+	"github.com/aaamil13/CodeIndexerMCP/internal/utils"
+	"github.com/aaamil13/CodeIndexerMCP/pkg/types"
+)
+
+// Thresholds of the built-in anti-pattern rules
+const (
+	maxFunctionParameters = 5 // More parameters than this is flagged
+	maxNestingDepth       = 4 // Control flow nested deeper than this is flagged
+)
+
+// Names of the built-in anti-pattern rules
+const (
+	RuleTooManyParameters = "too_many_parameters"
+	RuleDeepNesting       = "deep_nesting"
+	RuleEmptyCatch        = "empty_catch"
+)
+
+// FunctionSource is a function as seen by anti-pattern rules
+type FunctionSource struct {
+	Symbol     *types.Symbol
+	File       *types.File
+	Parameters []*types.Parameter // From the parser if recorded, else split from the signature
+	Source     string             // The function's lines, comments and strings blanked out
+}
+
+// AntiPatternRule checks functions for one anti-pattern. Check returns the
+// problems found; the detector fills in the rule name, file and symbol of
+// each finding, and Line defaults to the function's first line.
+type AntiPatternRule struct {
+	Name  string
+	Check func(fn *FunctionSource) []*types.AntiPatternFinding
+}
+
+var (
+	// emptyCatchPattern matches catch blocks with nothing in them (Java, C#,
+	// JavaScript, Kotlin, PHP, C++, ...) and Go error checks that ignore the error
+	emptyCatchPattern = regexp.MustCompile(`\bcatch\b\s*(?:\([^)]*\))?\s*\{\s*\}|\bif\s+err\s*!=\s*nil\s*\{\s*\}`)
+
+	// emptyExceptPattern matches Python except blocks holding only pass
+	emptyExceptPattern = regexp.MustCompile(`(?m)^[ \t]*except\b[^:\n]*:\s*pass\b`)
+)
+
+// AntiPatternDetector runs anti-pattern rules over the functions of indexed files
+type AntiPatternDetector struct {
+	db    *database.DB
+	rules []AntiPatternRule
+}
+
+// NewAntiPatternDetector creates a detector with the built-in rules
+func NewAntiPatternDetector(db *database.DB) *AntiPatternDetector {
+	return &AntiPatternDetector{
+		db: db,
+		rules: []AntiPatternRule{
+			{Name: RuleTooManyParameters, Check: checkTooManyParameters},
+			{Name: RuleDeepNesting, Check: checkDeepNesting},
+			{Name: RuleEmptyCatch, Check: checkEmptyCatch},
+		},
+	}
+}
+
+// AddRule adds a rule run by DetectProject
+func (apd *AntiPatternDetector) AddRule(rule AntiPatternRule) {
+	apd.rules = append(apd.rules, rule)
+}
+
+// DetectProject runs every rule over the functions of a project, returning
+// findings ordered by file and line
+func (apd *AntiPatternDetector) DetectProject(projectID int64) ([]*types.AntiPatternFinding, error) {
+	files, err := apd.db.GetAllFilesForProject(projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	findings := []*types.AntiPatternFinding{}
+	seen := make(map[string]int) // rule, file and line -> index in findings
+	for _, file := range files {
+		symbols, err := apd.db.GetSymbolsByFile(file.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		content, err := utils.ReadFileUTF8(file.Path, file.Encoding)
+		if err != nil {
+			continue // File may have been removed since indexing
+		}
+		lines := strings.Split(maskStringsAndComments(string(content), file.Language), "\n")
+
+		for _, symbol := range symbols {
+			end := functionEnd(symbol, lines, file.Language)
+			if !isFunction(symbol) || symbol.StartLine <= 0 || end > len(lines) || symbol.StartLine > end {
+				continue
+			}
+
+			fn := &FunctionSource{
+				Symbol: symbol,
+				File:   file,
+				Source: strings.Join(lines[symbol.StartLine-1:end], "\n"),
+			}
+			if fn.Parameters, err = apd.parameters(symbol); err != nil {
+				return nil, err
+			}
+
+			for _, rule := range apd.rules {
+				for _, finding := range rule.Check(fn) {
+					finding.Rule = rule.Name
+					finding.FilePath = file.RelativePath
+					finding.Symbol = symbol.Name
+					if finding.Line == 0 {
+						finding.Line = symbol.StartLine
+					}
+
+					// A nested function's problems are also inside its parent;
+					// report them against the innermost function
+					key := fmt.Sprintf("%s:%s:%d", finding.Rule, finding.FilePath, finding.Line)
+					if i, ok := seen[key]; ok {
+						findings[i] = finding
+						continue
+					}
+					seen[key] = len(findings)
+					findings = append(findings, finding)
+				}
+			}
+		}
+	}
+
+	sort.SliceStable(findings, func(i, j int) bool {
+		if findings[i].FilePath != findings[j].FilePath {
+			return findings[i].FilePath < findings[j].FilePath
+		}
+		return findings[i].Line < findings[j].Line
+	})
+
+	return findings, nil
+}
+
+// parameters returns a function's parameters as parsed at indexing time, or
+// failing that as split from its signature
+func (apd *AntiPatternDetector) parameters(symbol *types.Symbol) ([]*types.Parameter, error) {
+	details, err := apd.db.GetFunctionDetails(symbol.ID)
+	if err != nil {
+		return nil, err
+	}
+	if details != nil {
+		return details.Parameters, nil
+	}
+
+	signature := symbol.Signature
+	if i := strings.Index(signature, symbol.Name); i >= 0 {
+		signature = signature[i+len(symbol.Name):]
+	}
+	open := strings.Index(signature, "(")
+	if open < 0 {
+		return nil, nil
+	}
+
+	var params []*types.Parameter
+	depth, start := 0, open+1
+	for i := open; i < len(signature); i++ {
+		switch signature[i] {
+		case '(', '[', '{', '<':
+			depth++
+		case ')', ']', '}', '>':
+			depth--
+		case ',':
+			if depth == 1 {
+				params = append(params, &types.Parameter{Name: strings.TrimSpace(signature[start:i])})
+				start = i + 1
+			}
+		}
+		if depth == 0 {
+			if last := strings.TrimSpace(signature[start:i]); last != "" {
+				params = append(params, &types.Parameter{Name: last})
+			}
+			break
+		}
+	}
+	return params, nil
+}
+
+// functionEnd returns a function's last line. The Python parser records
+// only where functions start, so their end is found by indentation.
+func functionEnd(symbol *types.Symbol, lines []string, language string) int {
+	if symbol.EndLine >= symbol.StartLine || language != "python" || symbol.StartLine <= 0 || symbol.StartLine > len(lines) {
+		return symbol.EndLine
+	}
+
+	indentOf := func(text string) int { return len(text) - len(strings.TrimLeft(text, " \t")) }
+	defIndent := indentOf(lines[symbol.StartLine-1])
+	end := symbol.StartLine
+	for i := symbol.StartLine; i < len(lines); i++ {
+		if strings.TrimSpace(lines[i]) == "" {
+			continue
+		}
+		if indentOf(lines[i]) <= defIndent {
+			break
+		}
+		end = i + 1
+	}
+	return end
+}
+
+// isFunction reports whether a symbol is a named function or method
+func isFunction(symbol *types.Symbol) bool {
+	switch symbol.Type {
+	case types.SymbolTypeFunction, types.SymbolTypeMethod, types.SymbolTypeConstructor:
+		// The Python parser records decorators as "@name" functions
+		return symbol.Name != "" && !strings.HasPrefix(symbol.Name, "@")
+	}
+	return false
+}
+
+// checkTooManyParameters flags functions taking more than
+// maxFunctionParameters parameters, not counting Python's self and cls
+func checkTooManyParameters(fn *FunctionSource) []*types.AntiPatternFinding {
+	count := 0
+	for _, param := range fn.Parameters {
+		if fn.File.Language == "python" && (param.Name == "self" || param.Name == "cls") {
+			continue
+		}
+		count++
+	}
+	if count <= maxFunctionParameters {
+		return nil
+	}
+
+	return []*types.AntiPatternFinding{{
+		Message:    fmt.Sprintf("%s takes %d parameters (more than %d)", fn.Symbol.Name, count, maxFunctionParameters),
+		Suggestion: "Group related parameters into a struct or options object",
+	}}
+}
+
+// checkDeepNesting flags functions whose blocks nest more than
+// maxNestingDepth levels inside the body, by indentation in Python and by
+// block braces elsewhere, leaving out the braces of composite literals
+func checkDeepNesting(fn *FunctionSource) []*types.AntiPatternFinding {
+	lines := strings.Split(fn.Source, "\n")
+	depth, line := 0, 0
+
+	if fn.File.Language == "python" {
+		var indents []int // Enclosing indentation levels within the body
+		for i, text := range lines[1:] {
+			if strings.TrimSpace(text) == "" {
+				continue
+			}
+			indent := len(text) - len(strings.TrimLeft(text, " \t"))
+			for len(indents) > 0 && indents[len(indents)-1] >= indent {
+				indents = indents[:len(indents)-1]
+			}
+			indents = append(indents, indent)
+			if len(indents)-1 > depth {
+				depth, line = len(indents)-1, i+1
+			}
+		}
+	} else {
+		var open []bool // Whether each enclosing brace opens a block
+		blocks := 0
+		since, i := 0, 0 // Offset after the last brace, line number
+		for offset, c := range fn.Source {
+			switch c {
+			case '{':
+				// The first brace opens the function body
+				block := len(open) == 0 || opensBlock(fn.Source[since:offset])
+				open = append(open, block)
+				if block {
+					blocks++
+					if blocks-1 > depth {
+						depth, line = blocks-1, i
+					}
+				}
+				since = offset + 1
+			case '}':
+				if n := len(open); n > 0 {
+					if open[n-1] {
+						blocks--
+					}
+					open = open[:n-1]
+				}
+				since = offset + 1
+			case '\n':
+				i++
+			}
+		}
+	}
+
+	if depth <= maxNestingDepth {
+		return nil
+	}
+
+	return []*types.AntiPatternFinding{{
+		Line:       fn.Symbol.StartLine + line,
+		Message:    fmt.Sprintf("%s nests blocks %d levels deep (more than %d)", fn.Symbol.Name, depth, maxNestingDepth),
+		Suggestion: "Return early or extract the inner blocks into functions",
+	}}
+}
+
+// blockKeywords start statements whose braces open a block
+var blockKeywords = map[string]bool{
+	"if": true, "else": true, "for": true, "foreach": true, "while": true, "do": true,
+	"switch": true, "select": true, "match": true, "when": true, "loop": true,
+	"try": true, "catch": true, "finally": true, "with": true, "using": true,
+	"lock": true, "synchronized": true, "unsafe": true, "go": true, "defer": true,
+}
+
+// functionKeyword matches the start of a function literal
+var functionKeyword = regexp.MustCompile(`\b(?:func|function)\b`)
+
+// opensBlock reports whether a brace opens a block, such as an if body or a
+// function literal, rather than a composite or object literal such as
+// Point{1, 2}, []int{1} or {a: 1}. before is the code since the previous
+// brace; its last non-blank line holds the statement the brace ends.
+func opensBlock(before string) bool {
+	lines := strings.Split(strings.TrimSpace(before), "\n")
+	statement := strings.TrimSpace(lines[len(lines)-1])
+	if strings.HasSuffix(statement, ")") || strings.HasSuffix(statement, "=>") || functionKeyword.MatchString(statement) {
+		return true
+	}
+	if i := strings.IndexFunc(statement, func(r rune) bool { return !unicode.IsLetter(r) }); i >= 0 {
+		statement = statement[:i]
+	}
+	return blockKeywords[statement]
+}
+
+// checkEmptyCatch flags catch and except blocks that silently drop errors
+func checkEmptyCatch(fn *FunctionSource) []*types.AntiPatternFinding {
+	pattern := emptyCatchPattern
+	if fn.File.Language == "python" {
+		pattern = emptyExceptPattern
+	}
+
+	var findings []*types.AntiPatternFinding
+	for _, loc := range pattern.FindAllStringIndex(fn.Source, -1) {
+		offset := loc[0] + len(fn.Source[loc[0]:loc[1]]) - len(strings.TrimLeft(fn.Source[loc[0]:loc[1]], " \t"))
+		findings = append(findings, &types.AntiPatternFinding{
+			Line:       fn.Symbol.StartLine + strings.Count(fn.Source[:offset], "\n"),
+			Message:    "Empty error handler in " + fn.Symbol.Name + " silently ignores the error",
+			Suggestion: "Handle, log or return the error",
+		})
+	}
+	return findings
+}
@@ -0,0 +1,60 @@
+package ai
+
+import (
+	"testing"
+
+	"github.com/aaamil13/CodeIndexerMCP/pkg/types"
+)
+
+func TestCheckDeepNesting(t *testing.T) {
+	tests := []struct {
+		name     string
+		language string
+		source   string
+		want     int // Expected findings
+	}{
+		{
+			name:     "nested ifs",
+			language: "go",
+			source:   "func F(x int) {\n\tif x > 0 {\n\t\tfor {\n\t\t\tif x > 1 {\n\t\t\t\tswitch {\n\t\t\t\tdefault:\n\t\t\t\t\tif x > 2 {\n\t\t\t\t\t}\n\t\t\t\t}\n\t\t\t}\n\t\t}\n\t}\n}",
+			want:     1,
+		},
+		{
+			name:     "composite literals don't nest",
+			language: "go",
+			source:   "func F() []Point {\n\tif true {\n\t\treturn []Point{\n\t\t\t{X: 1, Y: Pair{A: Inner{B: []int{1}}}},\n\t\t}\n\t}\n\treturn nil\n}",
+			want:     0,
+		},
+		{
+			name:     "go init statement",
+			language: "go",
+			source:   "func F() {\n\tif err := a(); err != nil {\n\t\tif err := b(); err != nil {\n\t\t\tif err := c(); err != nil {\n\t\t\t\tif err := d(); err != nil {\n\t\t\t\t\tif err := e(); err != nil {\n\t\t\t\t\t}\n\t\t\t\t}\n\t\t\t}\n\t\t}\n\t}\n}",
+			want:     1,
+		},
+		{
+			name:     "javascript object literals don't nest",
+			language: "javascript",
+			source:   "function f(x) {\n  if (x) {\n    return { a: { b: { c: { d: { e: 1 } } } } };\n  }\n}",
+			want:     0,
+		},
+		{
+			name:     "javascript callbacks nest",
+			language: "javascript",
+			source:   "function f() {\n  a(() => {\n    b(function () {\n      if (x) {\n        while (y) {\n          try {\n          } catch (e) {}\n        }\n      }\n    });\n  });\n}",
+			want:     1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fn := &FunctionSource{
+				Symbol: &types.Symbol{Name: "F", StartLine: 1},
+				File:   &types.File{Language: tt.language},
+				Source: tt.source,
+			}
+			if got := checkDeepNesting(fn); len(got) != tt.want {
+				t.Errorf("checkDeepNesting() = %d findings, want %d", len(got), tt.want)
+			}
+		})
+	}
+}
@@ -2,7 +2,6 @@ package ai
 
 import (
 	"fmt"
-	"strings"
 	"time"
 
 	"github.com/aaamil13/CodeIndexerMCP/internal/database"
@@ -40,7 +39,11 @@ func (ct *ChangeTracker) AnalyzeSymbolChange(change *types.Change) (*types.Chang
 	}
 
 	// Get impact analysis
-	impact, err := ct.impactAnalyzer.AnalyzeChangeImpact(change.Symbol.Name)
+	symbol, err := ct.changedSymbol(change)
+	if err != nil {
+		return nil, err
+	}
+	impact, err := ct.impactAnalyzer.AnalyzeSymbolImpact(symbol)
 	if err != nil {
 		return nil, err
 	}
@@ -65,6 +68,39 @@ func (ct *ChangeTracker) AnalyzeSymbolChange(change *types.Change) (*types.Chang
 	return result, nil
 }
 
+// changedSymbol looks up the indexed symbol a change applies to. A rename's
+// Symbol carries the new name, so it is found by its OldSymbol; every change
+// is looked up by ID first and only falls back to the name when that name is
+// the one in the index.
+func (ct *ChangeTracker) changedSymbol(change *types.Change) (*types.Symbol, error) {
+	target := change.Symbol
+	if change.Type == types.ChangeTypeRename && change.OldSymbol != nil {
+		target = change.OldSymbol
+	}
+
+	if target.ID != 0 {
+		symbol, err := ct.db.GetSymbolByID(target.ID)
+		if err != nil {
+			return nil, err
+		}
+		if symbol != nil {
+			return symbol, nil
+		}
+	}
+
+	if change.Type == types.ChangeTypeRename && change.OldSymbol == nil {
+		return nil, fmt.Errorf("rename of %s needs the old symbol or its ID", change.Symbol.Name)
+	}
+	symbol, err := ct.db.GetSymbolByName(target.Name)
+	if err != nil {
+		return nil, err
+	}
+	if symbol == nil {
+		return nil, fmt.Errorf("symbol not found: %s", target.Name)
+	}
+	return symbol, nil
+}
+
 // analyzeDelete analyzes symbol deletion impact
 func (ct *ChangeTracker) analyzeDelete(change *types.Change, impact *types.ChangeImpact, result *types.ChangeImpactResult) {
 	// Get all references
@@ -110,15 +146,13 @@ func (ct *ChangeTracker) analyzeDelete(change *types.Change, impact *types.Chang
 
 // analyzeRename analyzes symbol rename impact
 func (ct *ChangeTracker) analyzeRename(change *types.Change, impact *types.ChangeImpact, result *types.ChangeImpactResult) {
-	if change.OldSymbol == nil {
-		return
-	}
-
-	oldName := change.OldSymbol.Name
+	// The symbol as indexed, before the rename
+	oldSymbol := impact.Symbol
+	oldName := oldSymbol.Name
 	newName := change.Symbol.Name
 
 	// Get all references
-	references, err := ct.db.GetReferencesBySymbol(change.OldSymbol.ID)
+	references, err := ct.db.GetReferencesBySymbol(oldSymbol.ID)
 	if err != nil {
 		return
 	}
@@ -154,16 +188,62 @@ func (ct *ChangeTracker) analyzeRename(change *types.Change, impact *types.Chang
 	}
 
 	// Check if rename might cause conflicts
-	if existingSymbol, _ := ct.db.GetSymbolByName(newName); existingSymbol != nil {
-		valError := &types.ValidationError{
-			Type:     "semantic",
-			File:     change.File,
-			Line:     change.LineStart,
-			Message:  fmt.Sprintf("Symbol '%s' already exists - rename would cause conflict", newName),
-			Severity: "error",
+	result.ValidationErrors = append(result.ValidationErrors, ct.renameCollisions(change, oldSymbol, references)...)
+}
+
+// renameCollisions reports each affected file (the definition's and every
+// referencing one) that already declares the new name, since renaming there
+// would shadow or clash with the existing symbol
+func (ct *ChangeTracker) renameCollisions(change *types.Change, oldSymbol *types.Symbol, references []*types.Reference) []*types.ValidationError {
+	newName := change.Symbol.Name
+
+	scopes := []int64{}
+	seen := make(map[int64]bool)
+	addScope := func(fileID int64) {
+		if fileID != 0 && !seen[fileID] {
+			seen[fileID] = true
+			scopes = append(scopes, fileID)
+		}
+	}
+	addScope(oldSymbol.FileID)
+	if change.File != nil {
+		addScope(change.File.ID)
+	}
+	for _, ref := range references {
+		addScope(ref.FileID)
+	}
+
+	collisions := []*types.ValidationError{}
+	for _, fileID := range scopes {
+		symbols, err := ct.db.GetSymbolsByFile(fileID)
+		if err != nil {
+			continue
+		}
+
+		for _, sym := range symbols {
+			if sym.Name != newName || sym.ID == oldSymbol.ID {
+				continue
+			}
+
+			file, _ := ct.db.GetFile(fileID)
+			scope := fmt.Sprintf("file %d", fileID)
+			if file != nil {
+				scope = file.RelativePath
+			}
+
+			collisions = append(collisions, &types.ValidationError{
+				Type:     "semantic",
+				File:     file,
+				Line:     sym.StartLine,
+				Message:  fmt.Sprintf("Symbol '%s' already exists in %s - rename would cause conflict", newName, scope),
+				Severity: "error",
+				Code:     "rename_collision",
+			})
+			break // One collision per scope is enough
 		}
-		result.ValidationErrors = append(result.ValidationErrors, valError)
 	}
+
+	return collisions
 }
 
 // analyzeModify analyzes symbol modification impact
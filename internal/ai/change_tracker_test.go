@@ -1,6 +1,7 @@
 package ai
 
 import (
+	"fmt"
 	"path/filepath"
 	"testing"
 
@@ -8,25 +9,55 @@ import (
 	"github.com/aaamil13/CodeIndexerMCP/pkg/types"
 )
 
-func setupTestDBForAI(t *testing.T) *database.Database {
+func setupTestDBForAI(t *testing.T) *database.DB {
 	tmpDir := t.TempDir()
 	dbPath := filepath.Join(tmpDir, "test.db")
 
-	db, err := database.New(dbPath)
+	db, err := database.Open(dbPath)
 	if err != nil {
 		t.Fatalf("Failed to create test database: %v", err)
 	}
 
 	// Create test data
 	project := &types.Project{Name: "test", Path: "/test"}
-	db.CreateProject(project)
+	if err := db.CreateProject(project); err != nil {
+		t.Fatalf("Failed to create project: %v", err)
+	}
 
-	file := &types.File{ProjectID: project.ID, Path: "/test/file.go", Language: "go"}
-	db.CreateFile(file)
+	file := &types.File{ProjectID: project.ID, Path: "/test/file.go", RelativePath: "file.go", Language: "go"}
+	if err := db.SaveFile(file); err != nil {
+		t.Fatalf("Failed to save file: %v", err)
+	}
 
 	return db
 }
 
+// saveTestSymbol saves a symbol, failing the test on error
+func saveTestSymbol(t *testing.T, db *database.DB, symbol *types.Symbol) {
+	t.Helper()
+	if err := db.SaveSymbol(symbol); err != nil {
+		t.Fatalf("Failed to save symbol %s: %v", symbol.Name, err)
+	}
+}
+
+// saveTestReference saves a reference, failing the test on error
+func saveTestReference(t *testing.T, db *database.DB, ref *types.Reference) {
+	t.Helper()
+	if err := db.SaveReference(ref); err != nil {
+		t.Fatalf("Failed to save reference: %v", err)
+	}
+}
+
+// saveTestFile saves another file in the test project
+func saveTestFile(t *testing.T, db *database.DB, relPath string) *types.File {
+	t.Helper()
+	file := &types.File{ProjectID: 1, Path: "/test/" + relPath, RelativePath: relPath, Language: "go"}
+	if err := db.SaveFile(file); err != nil {
+		t.Fatalf("Failed to save file %s: %v", relPath, err)
+	}
+	return file
+}
+
 func TestAnalyzeSymbolChange_Rename(t *testing.T) {
 	db := setupTestDBForAI(t)
 	defer db.Close()
@@ -37,26 +68,30 @@ func TestAnalyzeSymbolChange_Rename(t *testing.T) {
 		Name:       "OldFunc",
 		Type:       types.SymbolTypeFunction,
 		Visibility: types.VisibilityPublic,
+		StartLine:  1,
+		EndLine:    3,
 	}
-	db.CreateSymbol(symbol)
+	saveTestSymbol(t, db, symbol)
+	saveTestSymbol(t, db, &types.Symbol{FileID: 1, Name: "Caller", Type: types.SymbolTypeFunction, StartLine: 9, EndLine: 13})
 
 	// Create some references
 	for i := 0; i < 3; i++ {
-		ref := &types.Reference{
-			SymbolID: symbol.ID,
-			FileID:   1,
-			Line:     10 + i,
-			Column:   5,
-			Context:  "OldFunc()",
-		}
-		db.CreateReference(ref)
+		saveTestReference(t, db, &types.Reference{
+			SymbolID:      symbol.ID,
+			FileID:        1,
+			LineNumber:    10 + i,
+			ColumnNumber:  5,
+			ReferenceType: "call",
+		})
 	}
 
 	// Analyze rename change
 	tracker := NewChangeTracker(db)
+	renamed := *symbol
+	renamed.Name = "NewFunc"
 	change := &types.Change{
 		Type:   types.ChangeTypeRename,
-		Symbol: symbol,
+		Symbol: &renamed,
 		OldSymbol: &types.Symbol{
 			Name: "OldFunc",
 		},
@@ -99,17 +134,16 @@ func TestAnalyzeSymbolChange_Delete(t *testing.T) {
 		Type:       types.SymbolTypeFunction,
 		Visibility: types.VisibilityPublic,
 	}
-	db.CreateSymbol(symbol)
+	saveTestSymbol(t, db, symbol)
 
 	// Create references
-	ref := &types.Reference{
-		SymbolID: symbol.ID,
-		FileID:   1,
-		Line:     20,
-		Column:   5,
-		Context:  "DeprecatedFunc()",
-	}
-	db.CreateReference(ref)
+	saveTestReference(t, db, &types.Reference{
+		SymbolID:      symbol.ID,
+		FileID:        1,
+		LineNumber:    20,
+		ColumnNumber:  5,
+		ReferenceType: "call",
+	})
 
 	// Analyze delete change
 	tracker := NewChangeTracker(db)
@@ -151,15 +185,15 @@ func TestAnalyzeSymbolChange_Modify(t *testing.T) {
 		Signature:  "func Calculate(x int) int",
 		Visibility: types.VisibilityPublic,
 	}
-	db.CreateSymbol(symbol)
+	saveTestSymbol(t, db, symbol)
 
 	// Create references
-	ref := &types.Reference{
-		SymbolID: symbol.ID,
-		FileID:   1,
-		Line:     30,
-	}
-	db.CreateReference(ref)
+	saveTestReference(t, db, &types.Reference{
+		SymbolID:      symbol.ID,
+		FileID:        1,
+		LineNumber:    30,
+		ReferenceType: "call",
+	})
 
 	// Analyze modify change (signature change)
 	tracker := NewChangeTracker(db)
@@ -168,6 +202,7 @@ func TestAnalyzeSymbolChange_Modify(t *testing.T) {
 		Signature: "func Calculate(x int) int",
 	}
 	newSymbol := &types.Symbol{
+		ID:        symbol.ID,
 		Name:      "Calculate",
 		Signature: "func Calculate(x, y int) int",
 	}
@@ -200,16 +235,11 @@ func TestSimulateChange(t *testing.T) {
 		Type:       types.SymbolTypeFunction,
 		Visibility: types.VisibilityPublic,
 	}
-	db.CreateSymbol(symbol)
+	saveTestSymbol(t, db, symbol)
 
 	// Simulate change
 	tracker := NewChangeTracker(db)
-	change := &types.Change{
-		Type:   types.ChangeTypeRename,
-		Symbol: symbol,
-	}
-
-	result, err := tracker.SimulateChange(change)
+	result, err := tracker.SimulateChange("TestFunc", types.ChangeTypeRename, "RenamedFunc")
 	if err != nil {
 		t.Fatalf("SimulateChange failed: %v", err)
 	}
@@ -234,14 +264,14 @@ func TestValidateChanges(t *testing.T) {
 		Name:   "Func1",
 		Type:   types.SymbolTypeFunction,
 	}
-	db.CreateSymbol(symbol1)
+	saveTestSymbol(t, db, symbol1)
 
 	symbol2 := &types.Symbol{
 		FileID: 1,
 		Name:   "Func2",
 		Type:   types.SymbolTypeFunction,
 	}
-	db.CreateSymbol(symbol2)
+	saveTestSymbol(t, db, symbol2)
 
 	// Create changes
 	changes := []*types.Change{
@@ -267,8 +297,8 @@ func TestValidateChanges(t *testing.T) {
 	}
 
 	// Should have analyzed all changes
-	if len(result.Changes) != len(changes) {
-		t.Errorf("Expected %d changes, got %d", len(changes), len(result.Changes))
+	if len(result.ChangeSet.Changes) != len(changes) {
+		t.Errorf("Expected %d changes, got %d", len(changes), len(result.ChangeSet.Changes))
 	}
 }
 
@@ -283,7 +313,11 @@ func TestChangeTracker_VisibilityChange(t *testing.T) {
 		Type:       types.SymbolTypeFunction,
 		Visibility: types.VisibilityPublic,
 	}
-	db.CreateSymbol(symbol)
+	saveTestSymbol(t, db, symbol)
+
+	// Used from another file
+	other := saveTestFile(t, db, "other.go")
+	saveTestReference(t, db, &types.Reference{SymbolID: symbol.ID, FileID: other.ID, LineNumber: 4, ReferenceType: "call"})
 
 	// Analyze changing to private
 	tracker := NewChangeTracker(db)
@@ -291,10 +325,15 @@ func TestChangeTracker_VisibilityChange(t *testing.T) {
 	newSymbol := *symbol
 	newSymbol.Visibility = types.VisibilityPrivate
 
+	file, err := db.GetFile(1)
+	if err != nil {
+		t.Fatalf("GetFile failed: %v", err)
+	}
 	change := &types.Change{
 		Type:      types.ChangeTypeModify,
 		Symbol:    &newSymbol,
 		OldSymbol: &oldSymbol,
+		File:      file,
 	}
 
 	impact, err := tracker.AnalyzeSymbolChange(change)
@@ -318,7 +357,7 @@ func TestChangeTracker_ConflictDetection(t *testing.T) {
 		Name:   "ExistingFunc",
 		Type:   types.SymbolTypeFunction,
 	}
-	db.CreateSymbol(existing)
+	saveTestSymbol(t, db, existing)
 
 	// Create symbol to rename
 	toRename := &types.Symbol{
@@ -326,7 +365,7 @@ func TestChangeTracker_ConflictDetection(t *testing.T) {
 		Name:   "OldFunc",
 		Type:   types.SymbolTypeFunction,
 	}
-	db.CreateSymbol(toRename)
+	saveTestSymbol(t, db, toRename)
 
 	// Try to rename to existing name
 	tracker := NewChangeTracker(db)
@@ -349,3 +388,49 @@ func TestChangeTracker_ConflictDetection(t *testing.T) {
 		t.Error("Expected validation errors for naming conflict")
 	}
 }
+
+func TestChangeTracker_RenameCollisionScoped(t *testing.T) {
+	db := setupTestDBForAI(t)
+	defer db.Close()
+
+	// Foo is defined in file 1 and used from files 1, 2 and 3; only file 2
+	// already declares Bar
+	for i := 2; i <= 3; i++ {
+		saveTestFile(t, db, fmt.Sprintf("file%d.go", i))
+	}
+
+	foo := &types.Symbol{FileID: 1, Name: "Foo", Type: types.SymbolTypeFunction}
+	saveTestSymbol(t, db, foo)
+	saveTestSymbol(t, db, &types.Symbol{FileID: 2, Name: "Bar", Type: types.SymbolTypeFunction, StartLine: 7})
+
+	for fileID := int64(1); fileID <= 3; fileID++ {
+		saveTestReference(t, db, &types.Reference{SymbolID: foo.ID, FileID: fileID, LineNumber: 20, ReferenceType: "call"})
+	}
+
+	tracker := NewChangeTracker(db)
+	impact, err := tracker.SimulateChange("Foo", types.ChangeTypeRename, "Bar")
+	if err != nil {
+		t.Fatalf("SimulateChange failed: %v", err)
+	}
+
+	collisions := []*types.ValidationError{}
+	for _, valError := range impact.ValidationErrors {
+		if valError.Code == "rename_collision" {
+			collisions = append(collisions, valError)
+		}
+	}
+
+	if len(collisions) != 1 {
+		t.Fatalf("Expected 1 collision, got %d: %+v", len(collisions), collisions)
+	}
+	if collisions[0].File == nil || collisions[0].File.ID != 2 {
+		t.Errorf("Expected collision in file 2, got %+v", collisions[0].File)
+	}
+	if collisions[0].Line != 7 {
+		t.Errorf("Expected collision at the existing Bar (line 7), got %d", collisions[0].Line)
+	}
+
+	if len(impact.RequiredUpdates) != 3 {
+		t.Errorf("Expected 3 reference edits, got %d", len(impact.RequiredUpdates))
+	}
+}
@@ -0,0 +1,171 @@
+package ai
+
+import "strings"
+
+// delimiterSyntax describes how a language spells comments and string literals
+type delimiterSyntax struct {
+	lineComment  string
+	blockStart   string
+	blockEnd     string
+	quotes       string // Characters that open a string literal
+	rawQuote     byte   // Quote whose strings ignore backslash escapes, e.g. Go's backtick
+	tripleQuotes bool   // Python-style """ and ''' strings
+}
+
+// delimiterSyntaxFor returns the comment and string syntax for a language
+func delimiterSyntaxFor(language string) delimiterSyntax {
+	switch language {
+	case "python":
+		return delimiterSyntax{lineComment: "#", quotes: `"'`, tripleQuotes: true}
+	case "ruby", "bash", "shell":
+		return delimiterSyntax{lineComment: "#", quotes: `"'`}
+	case "go":
+		return delimiterSyntax{lineComment: "//", blockStart: "/*", blockEnd: "*/", quotes: "\"'`", rawQuote: '`'}
+	case "javascript", "typescript":
+		return delimiterSyntax{lineComment: "//", blockStart: "/*", blockEnd: "*/", quotes: "\"'`"}
+	case "rust":
+		// ' also introduces lifetimes, so only double quotes are treated as strings
+		return delimiterSyntax{lineComment: "//", blockStart: "/*", blockEnd: "*/", quotes: `"`}
+	default:
+		return delimiterSyntax{lineComment: "//", blockStart: "/*", blockEnd: "*/", quotes: `"'`}
+	}
+}
+
+// balancedDelimiters reports whether every (, [ and { in code is closed in
+// order, ignoring those inside strings and comments. Unterminated strings and
+// block comments count as unbalanced.
+func balancedDelimiters(code, language string) bool {
+	syntax := delimiterSyntaxFor(language)
+	openers := map[byte]byte{')': '(', ']': '[', '}': '{'}
+	var stack []byte
+
+	for i := 0; i < len(code); {
+		rest := code[i:]
+		switch {
+		case syntax.lineComment != "" && strings.HasPrefix(rest, syntax.lineComment):
+			end := strings.IndexByte(rest, '\n')
+			if end < 0 {
+				return len(stack) == 0
+			}
+			i += end
+			continue
+
+		case syntax.blockStart != "" && strings.HasPrefix(rest, syntax.blockStart):
+			end := strings.Index(rest[len(syntax.blockStart):], syntax.blockEnd)
+			if end < 0 {
+				return false
+			}
+			i += len(syntax.blockStart) + end + len(syntax.blockEnd)
+			continue
+
+		case syntax.tripleQuotes && (strings.HasPrefix(rest, `"""`) || strings.HasPrefix(rest, `'''`)):
+			end := strings.Index(rest[3:], rest[:3])
+			if end < 0 {
+				return false
+			}
+			i += 3 + end + 3
+			continue
+
+		case strings.IndexByte(syntax.quotes, code[i]) >= 0:
+			end := stringEnd(code, i, syntax.rawQuote)
+			if end < 0 {
+				return false
+			}
+			i = end
+			continue
+		}
+
+		switch c := code[i]; c {
+		case '(', '[', '{':
+			stack = append(stack, c)
+		case ')', ']', '}':
+			if len(stack) == 0 || stack[len(stack)-1] != openers[c] {
+				return false
+			}
+			stack = stack[:len(stack)-1]
+		}
+		i++
+	}
+
+	return len(stack) == 0
+}
+
+// stringEnd returns the index just past the string literal opened at
+// code[start], or -1 if it's unterminated
+func stringEnd(code string, start int, rawQuote byte) int {
+	quote := code[start]
+	// Backtick strings (Go raw strings, JS template literals) may span lines
+	multiline := quote == '`'
+
+	for i := start + 1; i < len(code); i++ {
+		switch code[i] {
+		case '\\':
+			if quote != rawQuote {
+				i++
+			}
+		case quote:
+			return i + 1
+		case '\n':
+			if !multiline {
+				return -1
+			}
+		}
+	}
+	return -1
+}
+
+// maskStringsAndComments blanks out the contents of comments and string
+// literals, keeping newlines so line numbers still match. Unterminated
+// block comments and multi-line strings are masked to the end of the code.
+func maskStringsAndComments(code, language string) string {
+	syntax := delimiterSyntaxFor(language)
+	masked := []byte(code)
+	blank := func(from, to int) {
+		for i := from; i < to && i < len(masked); i++ {
+			if masked[i] != '\n' {
+				masked[i] = ' '
+			}
+		}
+	}
+
+	for i := 0; i < len(code); {
+		rest := code[i:]
+		end := -1
+		switch {
+		case syntax.lineComment != "" && strings.HasPrefix(rest, syntax.lineComment):
+			if end = strings.IndexByte(rest, '\n'); end >= 0 {
+				end += i
+			}
+
+		case syntax.blockStart != "" && strings.HasPrefix(rest, syntax.blockStart):
+			if end = strings.Index(rest[len(syntax.blockStart):], syntax.blockEnd); end >= 0 {
+				end += i + len(syntax.blockStart) + len(syntax.blockEnd)
+			}
+
+		case syntax.tripleQuotes && (strings.HasPrefix(rest, `"""`) || strings.HasPrefix(rest, `'''`)):
+			if end = strings.Index(rest[3:], rest[:3]); end >= 0 {
+				end += i + 6
+			}
+
+		case strings.IndexByte(syntax.quotes, code[i]) >= 0:
+			// An unterminated single-line string only runs to the end of its line
+			if end = stringEnd(code, i, syntax.rawQuote); end < 0 {
+				if end = strings.IndexByte(rest, '\n'); end >= 0 {
+					end += i
+				}
+			}
+
+		default:
+			i++
+			continue
+		}
+
+		if end < 0 {
+			end = len(code)
+		}
+		blank(i, end)
+		i = end
+	}
+
+	return string(masked)
+}
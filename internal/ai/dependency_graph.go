@@ -1,7 +1,9 @@
 package ai
 
 import (
+	"context"
 	"fmt"
+	"sort"
 
 	"github.com/aaamil13/CodeIndexerMCP/internal/database"
 	"github.com/aaamil13/CodeIndexerMCP/pkg/types"
@@ -19,6 +21,11 @@ func NewDependencyGraphBuilder(db *database.DB) *DependencyGraphBuilder {
 
 // BuildSymbolDependencyGraph builds a dependency graph for a symbol
 func (dgb *DependencyGraphBuilder) BuildSymbolDependencyGraph(symbolName string, maxDepth int) (*types.DependencyGraph, error) {
+	return dgb.BuildSymbolDependencyGraphContext(context.Background(), symbolName, maxDepth)
+}
+
+// BuildSymbolDependencyGraphContext builds a dependency graph for a symbol, stopping when ctx is done
+func (dgb *DependencyGraphBuilder) BuildSymbolDependencyGraphContext(ctx context.Context, symbolName string, maxDepth int) (*types.DependencyGraph, error) {
 	symbol, err := dgb.db.GetSymbolByName(symbolName)
 	if err != nil {
 		return nil, err
@@ -50,24 +57,33 @@ func (dgb *DependencyGraphBuilder) BuildSymbolDependencyGraph(symbolName string,
 	visited[symbol.ID] = true
 
 	// Build graph recursively
-	dgb.buildGraphRecursive(symbol, graph, visited, 0, maxDepth)
+	if err := dgb.buildGraphRecursive(ctx, symbol, graph, visited, 0, maxDepth); err != nil {
+		return nil, err
+	}
 
 	return graph, nil
 }
 
 // buildGraphRecursive builds graph recursively
-func (dgb *DependencyGraphBuilder) buildGraphRecursive(symbol *types.Symbol, graph *types.DependencyGraph, visited map[int64]bool, currentDepth, maxDepth int) {
+func (dgb *DependencyGraphBuilder) buildGraphRecursive(ctx context.Context, symbol *types.Symbol, graph *types.DependencyGraph, visited map[int64]bool, currentDepth, maxDepth int) error {
 	if currentDepth >= maxDepth {
-		return
+		return nil
+	}
+	if err := ctx.Err(); err != nil {
+		return err
 	}
 
 	// Get relationships
 	relationships, err := dgb.db.GetRelationshipsForSymbol(symbol.ID)
 	if err != nil {
-		return
+		return nil
 	}
 
 	for _, rel := range relationships {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
 		var targetSymbolID int64
 		var edgeFrom, edgeTo string
 
@@ -101,6 +117,8 @@ func (dgb *DependencyGraphBuilder) buildGraphRecursive(symbol *types.Symbol, gra
 		}
 		graph.Edges = append(graph.Edges, edge)
 	}
+
+	return nil
 }
 
 // BuildFileDependencyGraph builds a dependency graph for a file
@@ -173,24 +191,27 @@ func (dgb *DependencyGraphBuilder) GetDependentsFor(symbolName string) ([]*types
 	}
 
 	dependents := []*types.Symbol{}
-	seenFiles := make(map[int64]bool)
+	fileSymbols := make(map[int64][]*types.Symbol)
+	seen := make(map[int64]bool)
 
 	for _, ref := range references {
-		if seenFiles[ref.FileID] {
-			continue
-		}
-		seenFiles[ref.FileID] = true
-
 		// Get all symbols in this file that might depend on our symbol
-		symbols, err := dgb.db.GetSymbolsByFile(ref.FileID)
-		if err != nil {
-			continue
+		symbols, ok := fileSymbols[ref.FileID]
+		if !ok {
+			symbols, err = dgb.db.GetSymbolsByFile(ref.FileID)
+			if err != nil {
+				continue
+			}
+			fileSymbols[ref.FileID] = symbols
 		}
 
 		for _, sym := range symbols {
 			// Check if this symbol contains the reference
 			if sym.StartLine <= ref.LineNumber && sym.EndLine >= ref.LineNumber {
-				dependents = append(dependents, sym)
+				if !seen[sym.ID] {
+					seen[sym.ID] = true
+					dependents = append(dependents, sym)
+				}
 				break
 			}
 		}
@@ -199,6 +220,81 @@ func (dgb *DependencyGraphBuilder) GetDependentsFor(symbolName string) ([]*types
 	return dependents, nil
 }
 
+// FindCouplingHotspots computes afferent and efferent coupling for every
+// symbol in the project and returns the most coupled ones
+func (dgb *DependencyGraphBuilder) FindCouplingHotspots(projectID int64, limit int) ([]*types.CouplingHotspot, error) {
+	relationships, err := dgb.db.GetRelationshipsByProject(projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	dependents := make(map[int64]map[int64]bool)   // Ca edges per symbol
+	dependencies := make(map[int64]map[int64]bool) // Ce edges per symbol
+	addEdge := func(edges map[int64]map[int64]bool, from, to int64) {
+		if edges[from] == nil {
+			edges[from] = make(map[int64]bool)
+		}
+		edges[from][to] = true
+	}
+
+	for _, rel := range relationships {
+		// Structural and test links aren't coupling
+		if rel.Type == types.RelationshipContains || rel.Type == types.RelationshipTests {
+			continue
+		}
+		if rel.FromSymbolID == rel.ToSymbolID {
+			continue
+		}
+		addEdge(dependencies, rel.FromSymbolID, rel.ToSymbolID)
+		addEdge(dependents, rel.ToSymbolID, rel.FromSymbolID)
+	}
+
+	ids := make(map[int64]bool)
+	for id := range dependents {
+		ids[id] = true
+	}
+	for id := range dependencies {
+		ids[id] = true
+	}
+
+	hotspots := []*types.CouplingHotspot{}
+	for id := range ids {
+		symbol, file, err := dgb.db.GetSymbolWithFile(id)
+		if err != nil {
+			return nil, err
+		}
+		if symbol == nil {
+			continue // Relationship to a symbol that no longer exists
+		}
+
+		ca, ce := len(dependents[id]), len(dependencies[id])
+		hotspots = append(hotspots, &types.CouplingHotspot{
+			Symbol:      symbol,
+			FilePath:    file.RelativePath,
+			Afferent:    ca,
+			Efferent:    ce,
+			Instability: float64(ce) / float64(ca+ce),
+		})
+	}
+
+	sort.Slice(hotspots, func(i, j int) bool {
+		a, b := hotspots[i], hotspots[j]
+		if a.Afferent+a.Efferent != b.Afferent+b.Efferent {
+			return a.Afferent+a.Efferent > b.Afferent+b.Efferent
+		}
+		if a.Afferent != b.Afferent {
+			return a.Afferent > b.Afferent
+		}
+		return a.Symbol.Name < b.Symbol.Name
+	})
+
+	if limit > 0 && len(hotspots) > limit {
+		hotspots = hotspots[:limit]
+	}
+
+	return hotspots, nil
+}
+
 // AnalyzeDependencyChain analyzes the full dependency chain
 func (dgb *DependencyGraphBuilder) AnalyzeDependencyChain(symbolName string) (map[string]interface{}, error) {
 	dependencies, err := dgb.GetDependenciesFor(symbolName)
@@ -0,0 +1,308 @@
+package ai
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/aaamil13/CodeIndexerMCP/internal/utils"
+	"github.com/aaamil13/CodeIndexerMCP/pkg/types"
+)
+
+// DefaultExtractMethodLines is the function length above which extract-method
+// opportunities are suggested
+const DefaultExtractMethodLines = 40
+
+const (
+	extractMinBlockLines   = 5 // Smaller blocks aren't worth a function of their own
+	extractMaxSuggestions  = 3
+	extractInnerBlockRatio = 0.8 // A block covering this much of its range is searched inside instead
+)
+
+// codeBlock is a candidate extraction range, as 0-based indices into a body
+type codeBlock struct {
+	start, end int
+}
+
+// blockLine describes one line of a function body for block detection
+type blockLine struct {
+	depth int  // Nesting depth at the start of the line
+	blank bool // Empty or whitespace only
+	end   int  // For a line opening a nested block, the index of the block's last line; otherwise -1
+}
+
+// SetExtractMethodThreshold sets the function length above which
+// SuggestRefactorings looks for extract-method opportunities
+func (ia *ImpactAnalyzer) SetExtractMethodThreshold(lines int) {
+	ia.extractMethodLines = lines
+}
+
+// suggestExtractMethod proposes contiguous blocks of a long function that
+// could be pulled out into their own function. content is the whole file.
+func (ia *ImpactAnalyzer) suggestExtractMethod(symbol *types.Symbol, file *types.File, content []byte) []*types.RefactoringOpportunity {
+	threshold := ia.extractMethodLines
+	if threshold <= 0 {
+		threshold = DefaultExtractMethodLines
+	}
+
+	length := symbol.EndLine - symbol.StartLine + 1
+	if length <= threshold {
+		return nil
+	}
+
+	lines := strings.Split(string(content), "\n")
+	if symbol.StartLine < 1 || symbol.EndLine > len(lines) {
+		return nil
+	}
+
+	// The body is everything after the signature line; brace languages also
+	// drop the closing brace
+	indentBased := file.Language == "python"
+	bodyStart, bodyEnd := symbol.StartLine, symbol.EndLine // 0-based, exclusive end
+	if !indentBased {
+		bodyEnd--
+	}
+	if bodyEnd-bodyStart < extractMinBlockLines {
+		return nil
+	}
+	body := lines[bodyStart:bodyEnd]
+
+	var info []blockLine
+	if indentBased {
+		info = indentBlockLines(body)
+	} else {
+		info = braceBlockLines(body, file.Language)
+	}
+
+	blocks := extractableBlocks(info)
+
+	opportunities := make([]*types.RefactoringOpportunity, 0, len(blocks))
+	for _, block := range blocks {
+		start, end := bodyStart+block.start+1, bodyStart+block.end+1 // 1-based file lines
+		opportunities = append(opportunities, &types.RefactoringOpportunity{
+			Type:        "extract_method",
+			Symbol:      symbol,
+			File:        file,
+			Description: fmt.Sprintf("Extract lines %d-%d of %s into a separate function", start, end, symbol.Name),
+			Reason:      fmt.Sprintf("%s is %d lines long (threshold %d); lines %d-%d form a self-contained block", symbol.Name, length, threshold, start, end),
+			Impact:      "low",
+			Effort:      "low",
+			Benefits:    []string{"Shorter, more readable function", "Extracted block can be named, reused and tested"},
+			Risks:       []string{"The block may need many parameters if it shares a lot of local state"},
+			StartLine:   start,
+			EndLine:     end,
+		})
+	}
+
+	return opportunities
+}
+
+// extractableBlocks picks the largest non-overlapping candidate blocks of a
+// body: nested constructs (loops, conditionals) at the top level of the body,
+// and runs of straight-line code separated by blank lines
+func extractableBlocks(info []blockLine) []codeBlock {
+	if len(info) == 0 {
+		return nil
+	}
+
+	base := -1
+	for _, l := range info {
+		if !l.blank && (base < 0 || l.depth < base) {
+			base = l.depth
+		}
+	}
+	if base < 0 {
+		return nil
+	}
+
+	blocks := nestedBlocks(info, 0, len(info)-1, base)
+	nested := len(blocks)
+	for _, p := range paragraphBlocks(info, base) {
+		overlaps := false
+		for _, b := range blocks[:nested] {
+			if p.start <= b.end && b.start <= p.end {
+				overlaps = true
+				break
+			}
+		}
+		if !overlaps {
+			blocks = append(blocks, p)
+		}
+	}
+
+	sort.SliceStable(blocks, func(i, j int) bool {
+		return blocks[i].end-blocks[i].start > blocks[j].end-blocks[j].start
+	})
+	if len(blocks) > extractMaxSuggestions {
+		blocks = blocks[:extractMaxSuggestions]
+	}
+	sort.Slice(blocks, func(i, j int) bool {
+		return blocks[i].start < blocks[j].start
+	})
+
+	return blocks
+}
+
+// nestedBlocks finds blocks opened at depth base within info[from:to+1].
+// A block covering most of the range (e.g. a loop wrapping the whole body)
+// is searched inside for smaller blocks instead.
+func nestedBlocks(info []blockLine, from, to, base int) []codeBlock {
+	var blocks []codeBlock
+	span := to - from + 1
+
+	for i := from; i <= to; i++ {
+		l := info[i]
+		if l.blank || l.depth != base || l.end < 0 {
+			continue
+		}
+
+		end := l.end
+		if end > to {
+			end = to
+		}
+		size := end - i + 1
+
+		if float64(size) >= extractInnerBlockRatio*float64(span) {
+			if inner := innerBlocks(info, i+1, end); len(inner) > 0 {
+				blocks = append(blocks, inner...)
+				i = end
+				continue
+			}
+		}
+
+		if size >= extractMinBlockLines && size < len(info) {
+			blocks = append(blocks, codeBlock{start: i, end: end})
+		}
+		i = end
+	}
+
+	return blocks
+}
+
+// innerBlocks searches the interior of a block, whose base depth is that of
+// its first non-blank line
+func innerBlocks(info []blockLine, from, to int) []codeBlock {
+	for i := from; i <= to; i++ {
+		if !info[i].blank {
+			return nestedBlocks(info, i, to, info[i].depth)
+		}
+	}
+	return nil
+}
+
+// paragraphBlocks splits a body at blank lines at the body's base depth
+func paragraphBlocks(info []blockLine, base int) []codeBlock {
+	var blocks []codeBlock
+	start := -1
+
+	flush := func(end int) {
+		for end >= start && start >= 0 && info[end].blank {
+			end--
+		}
+		if start >= 0 && end-start+1 >= extractMinBlockLines && end-start+1 < len(info) {
+			blocks = append(blocks, codeBlock{start: start, end: end})
+		}
+		start = -1
+	}
+
+	for i, l := range info {
+		if l.blank && l.depth <= base {
+			flush(i - 1)
+			continue
+		}
+		if start < 0 && !l.blank {
+			start = i
+		}
+	}
+	flush(len(info) - 1)
+
+	return blocks
+}
+
+// braceBlockLines computes nesting from braces, ignoring braces in comments
+// and string literals
+func braceBlockLines(body []string, language string) []blockLine {
+	info := make([]blockLine, len(body))
+	after := make([]int, len(body))
+	code := strings.Split(utils.StripCommentsAndStrings(strings.Join(body, "\n"), language), "\n")
+
+	depth := 0
+	for i, line := range body {
+		info[i] = blockLine{depth: depth, blank: strings.TrimSpace(line) == "", end: -1}
+		depth += strings.Count(code[i], "{") - strings.Count(code[i], "}")
+		after[i] = depth
+	}
+
+	for i := range body {
+		if after[i] <= info[i].depth {
+			continue
+		}
+		for j := i + 1; j < len(body); j++ {
+			if after[j] <= info[i].depth {
+				info[i].end = j
+				break
+			}
+		}
+	}
+
+	return info
+}
+
+// indentBlockLines computes nesting from indentation, for Python
+func indentBlockLines(body []string) []blockLine {
+	info := make([]blockLine, len(body))
+	for i, line := range body {
+		info[i] = blockLine{depth: indentWidth(line), blank: strings.TrimSpace(line) == "", end: -1}
+	}
+
+	// Blank lines belong to the depth of the code that follows them
+	next := 0
+	for i := len(body) - 1; i >= 0; i-- {
+		if info[i].blank {
+			info[i].depth = next
+		} else {
+			next = info[i].depth
+		}
+	}
+
+	for i, line := range body {
+		code := strings.TrimSpace(line)
+		if hash := strings.Index(code, "#"); hash >= 0 {
+			code = strings.TrimSpace(code[:hash])
+		}
+		if info[i].blank || !strings.HasSuffix(code, ":") {
+			continue
+		}
+
+		end := -1
+		for j := i + 1; j < len(body); j++ {
+			if info[j].blank {
+				continue
+			}
+			if info[j].depth <= info[i].depth {
+				break
+			}
+			end = j
+		}
+		info[i].end = end
+	}
+
+	return info
+}
+
+// indentWidth returns the width of a line's leading whitespace, counting a
+// tab as four spaces
+func indentWidth(line string) int {
+	width := 0
+	for _, r := range line {
+		switch r {
+		case ' ':
+			width++
+		case '\t':
+			width += 4
+		default:
+			return width
+		}
+	}
+	return width
+}
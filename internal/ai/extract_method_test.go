@@ -0,0 +1,135 @@
+package ai
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/aaamil13/CodeIndexerMCP/pkg/types"
+)
+
+// longGoFunction builds a 60-line Go function: setup, a 20-line loop and a
+// run of straight-line reporting code
+func longGoFunction() string {
+	var b strings.Builder
+	b.WriteString("package report\n\n")
+	b.WriteString("func Process(items []int) int {\n") // line 3
+	for i := 0; i < 8; i++ {
+		fmt.Fprintf(&b, "\tv%d := %d\n", i, i)
+	}
+	b.WriteString("\n")
+	b.WriteString("\ttotal := 0\n")
+	b.WriteString("\tfor _, item := range items {\n") // line 14
+	for i := 0; i < 6; i++ {
+		fmt.Fprintf(&b, "\t\tif item > %d {\n\t\t\ttotal += item\n\t\t}\n", i)
+	}
+	b.WriteString("\t}\n")
+	b.WriteString("\n")
+	for i := 0; i < 26; i++ {
+		fmt.Fprintf(&b, "\tprintln(\"line %d\", v%d)\n", i, i%8)
+	}
+	b.WriteString("\treturn total\n")
+	b.WriteString("}\n")
+	return b.String()
+}
+
+func TestSuggestExtractMethod_LongGoFunction(t *testing.T) {
+	code := longGoFunction()
+	lines := strings.Split(code, "\n")
+
+	symbol := &types.Symbol{Name: "Process", Type: types.SymbolTypeFunction, StartLine: 3, EndLine: len(lines) - 1}
+	if length := symbol.EndLine - symbol.StartLine + 1; length < 60 {
+		t.Fatalf("Fixture should be at least 60 lines, got %d", length)
+	}
+	file := &types.File{Path: "/test/report.go", RelativePath: "report.go", Language: "go"}
+
+	analyzer := &ImpactAnalyzer{extractMethodLines: DefaultExtractMethodLines}
+	opportunities := analyzer.suggestExtractMethod(symbol, file, []byte(code))
+	if len(opportunities) == 0 {
+		t.Fatal("Expected at least one extract-method suggestion")
+	}
+
+	for _, opp := range opportunities {
+		if opp.Type != "extract_method" {
+			t.Errorf("Expected extract_method, got %s", opp.Type)
+		}
+		if opp.StartLine <= symbol.StartLine || opp.EndLine >= symbol.EndLine || opp.EndLine-opp.StartLine+1 < extractMinBlockLines {
+			t.Errorf("Implausible range %d-%d for function at %d-%d", opp.StartLine, opp.EndLine, symbol.StartLine, symbol.EndLine)
+		}
+	}
+
+	// The loop is the obvious self-contained block
+	loopStart := 14
+	found := false
+	for _, opp := range opportunities {
+		if opp.StartLine == loopStart && strings.TrimSpace(lines[opp.EndLine-1]) == "}" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("Expected the loop starting at line %d to be suggested, got %+v", loopStart, opportunities)
+	}
+}
+
+func TestSuggestExtractMethod_ShortFunction(t *testing.T) {
+	code := "package report\n\nfunc Short() int {\n\treturn 1\n}\n"
+	symbol := &types.Symbol{Name: "Short", Type: types.SymbolTypeFunction, StartLine: 3, EndLine: 5}
+	file := &types.File{Path: "/test/report.go", Language: "go"}
+
+	analyzer := &ImpactAnalyzer{extractMethodLines: DefaultExtractMethodLines}
+	if opportunities := analyzer.suggestExtractMethod(symbol, file, []byte(code)); len(opportunities) != 0 {
+		t.Errorf("Expected no suggestions for a short function, got %d", len(opportunities))
+	}
+}
+
+func TestSuggestExtractMethod_Python(t *testing.T) {
+	var b strings.Builder
+	b.WriteString("def process(items):\n") // line 1
+	b.WriteString("    total = 0\n")
+	b.WriteString("    for item in items:\n") // line 3
+	for i := 0; i < 12; i++ {
+		fmt.Fprintf(&b, "        total += item * %d\n", i)
+	}
+	b.WriteString("\n")
+	for i := 0; i < 30; i++ {
+		fmt.Fprintf(&b, "    print(%d)\n", i)
+	}
+	b.WriteString("    return total\n")
+	code := b.String()
+
+	symbol := &types.Symbol{Name: "process", Type: types.SymbolTypeFunction, StartLine: 1, EndLine: strings.Count(code, "\n")}
+	file := &types.File{Path: "/test/report.py", Language: "python"}
+
+	analyzer := &ImpactAnalyzer{extractMethodLines: DefaultExtractMethodLines}
+	opportunities := analyzer.suggestExtractMethod(symbol, file, []byte(code))
+
+	found := false
+	for _, opp := range opportunities {
+		if opp.StartLine == 3 && opp.EndLine == 15 {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("Expected the for loop at lines 3-15 to be suggested, got %+v", opportunities)
+	}
+}
+
+func TestBraceBlockLines_IgnoresCommentsAndStrings(t *testing.T) {
+	body := []string{
+		"\tif ok {",
+		"\t\t/* a { in a comment",
+		"\t\t   spanning lines } */",
+		"\t\ts := `raw {",
+		"\t\t{ string`",
+		"\t}",
+		"\tdone()",
+	}
+
+	info := braceBlockLines(body, "go")
+	if info[0].end != 5 {
+		t.Errorf("Expected the if block to end at line 5, got %d", info[0].end)
+	}
+	if info[6].depth != 0 {
+		t.Errorf("Expected depth 0 after the block, got %d", info[6].depth)
+	}
+}
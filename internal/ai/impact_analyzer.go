@@ -2,19 +2,22 @@ package ai
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/aaamil13/CodeIndexerMCP/internal/database"
+	"github.com/aaamil13/CodeIndexerMCP/internal/utils"
 	"github.com/aaamil13/CodeIndexerMCP/pkg/types"
 )
 
 // ImpactAnalyzer analyzes the impact of code changes
 type ImpactAnalyzer struct {
-	db *database.DB
+	db                 *database.DB
+	extractMethodLines int // Function length above which extract-method is suggested
 }
 
 // NewImpactAnalyzer creates a new impact analyzer
 func NewImpactAnalyzer(db *database.DB) *ImpactAnalyzer {
-	return &ImpactAnalyzer{db: db}
+	return &ImpactAnalyzer{db: db, extractMethodLines: DefaultExtractMethodLines}
 }
 
 // AnalyzeChangeImpact analyzes the impact of changing a symbol
@@ -28,6 +31,11 @@ func (ia *ImpactAnalyzer) AnalyzeChangeImpact(symbolName string) (*types.ChangeI
 		return nil, fmt.Errorf("symbol not found: %s", symbolName)
 	}
 
+	return ia.AnalyzeSymbolImpact(symbol)
+}
+
+// AnalyzeSymbolImpact analyzes the impact of changing an indexed symbol
+func (ia *ImpactAnalyzer) AnalyzeSymbolImpact(symbol *types.Symbol) (*types.ChangeImpact, error) {
 	// Get direct references
 	references, err := ia.db.GetReferencesBySymbol(symbol.ID)
 	if err != nil {
@@ -79,7 +87,7 @@ func (ia *ImpactAnalyzer) AnalyzeChangeImpact(symbolName string) (*types.ChangeI
 	// Calculate indirect references (transitive)
 	indirectReferences := ia.calculateIndirectReferences(affectedSymbols)
 
-	return &types.ChangeImpact{
+	impact := &types.ChangeImpact{
 		Symbol:             symbol,
 		DirectReferences:   directReferences,
 		IndirectReferences: indirectReferences,
@@ -88,7 +96,58 @@ func (ia *ImpactAnalyzer) AnalyzeChangeImpact(symbolName string) (*types.ChangeI
 		RiskLevel:          riskLevel,
 		Suggestions:        suggestions,
 		BreakingChanges:    breakingChanges,
-	}, nil
+	}
+	impact.Summary, impact.RiskFactors = explainImpact(impact)
+
+	return impact, nil
+}
+
+// explainImpact summarizes why a change has the risk level it does, listing
+// the contributing factors most significant first
+func explainImpact(impact *types.ChangeImpact) (string, []string) {
+	factors := []string{}
+
+	if impact.DirectReferences == 0 {
+		factors = append(factors, "no references found")
+	} else {
+		factors = append(factors, fmt.Sprintf("used %d times in %d files", impact.DirectReferences, len(impact.AffectedFiles)))
+	}
+
+	// Exported callers pass the change on to their own consumers
+	publicConsumers := 0
+	seen := make(map[int64]bool)
+	for _, consumer := range impact.AffectedSymbols {
+		if seen[consumer.ID] {
+			continue
+		}
+		seen[consumer.ID] = true
+		if consumer.IsExported || consumer.Visibility == types.VisibilityPublic {
+			publicConsumers++
+		}
+	}
+	if publicConsumers > 0 {
+		factors = append(factors, fmt.Sprintf("%d public API consumers", publicConsumers))
+	}
+
+	if impact.IndirectReferences > 0 {
+		factors = append(factors, fmt.Sprintf("%d indirect references through its callers", impact.IndirectReferences))
+	}
+	if impact.Symbol.IsExported {
+		factors = append(factors, "exported from its package")
+	}
+	if impact.BreakingChanges {
+		factors = append(factors, "changing its signature breaks existing callers")
+	}
+	if impact.Symbol.Type == types.SymbolTypeInterface || impact.Symbol.Type == types.SymbolTypeClass {
+		factors = append(factors, fmt.Sprintf("implementations and subclasses of this %s may depend on it", impact.Symbol.Type))
+	}
+
+	level := impact.RiskLevel
+	if level != "" {
+		level = strings.ToUpper(level[:1]) + level[1:]
+	}
+	summary := fmt.Sprintf("%s risk to change %s: %s", level, impact.Symbol.Name, strings.Join(factors, ", "))
+	return summary, factors
 }
 
 // calculateRiskLevel determines the risk level of a change
@@ -250,5 +309,14 @@ func (ia *ImpactAnalyzer) SuggestRefactorings(symbolName string) ([]*types.Refac
 		})
 	}
 
+	// Long functions - look for blocks that could be extracted
+	if impact.Symbol.Type == types.SymbolTypeFunction || impact.Symbol.Type == types.SymbolTypeMethod {
+		if file, err := ia.db.GetFile(impact.Symbol.FileID); err == nil {
+			if content, err := utils.ReadFileUTF8(file.Path, file.Encoding); err == nil {
+				opportunities = append(opportunities, ia.suggestExtractMethod(impact.Symbol, file, content)...)
+			}
+		}
+	}
+
 	return opportunities, nil
 }
@@ -0,0 +1,58 @@
+package ai
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/aaamil13/CodeIndexerMCP/pkg/types"
+)
+
+func TestExplainImpact_WidelyUsed(t *testing.T) {
+	symbol := &types.Symbol{ID: 1, Name: "ParseConfig", Type: types.SymbolTypeFunction, IsExported: true}
+
+	var files []*types.File
+	var consumers []*types.Symbol
+	for i := 0; i < 12; i++ {
+		files = append(files, &types.File{ID: int64(i + 1), RelativePath: fmt.Sprintf("pkg%d/use.go", i)})
+		// Three of the callers are themselves public API
+		consumers = append(consumers, &types.Symbol{ID: int64(100 + i), Name: fmt.Sprintf("Caller%d", i), IsExported: i < 3})
+	}
+
+	impact := &types.ChangeImpact{
+		Symbol:           symbol,
+		DirectReferences: 25,
+		AffectedFiles:    files,
+		AffectedSymbols:  consumers,
+		RiskLevel:        "high",
+		BreakingChanges:  true,
+	}
+
+	summary, factors := explainImpact(impact)
+
+	joined := strings.Join(factors, "; ")
+	if !strings.Contains(joined, "used 25 times in 12 files") {
+		t.Errorf("Expected the reference count in risk factors, got %q", factors)
+	}
+	if !strings.Contains(joined, "3 public API consumers") {
+		t.Errorf("Expected the public consumer count in risk factors, got %q", factors)
+	}
+	if !strings.HasPrefix(summary, "High risk to change ParseConfig") {
+		t.Errorf("Unexpected summary: %s", summary)
+	}
+}
+
+func TestExplainImpact_Unused(t *testing.T) {
+	impact := &types.ChangeImpact{
+		Symbol:    &types.Symbol{Name: "helper", Type: types.SymbolTypeFunction},
+		RiskLevel: "low",
+	}
+
+	summary, factors := explainImpact(impact)
+	if len(factors) != 1 || factors[0] != "no references found" {
+		t.Errorf("Expected only 'no references found', got %q", factors)
+	}
+	if summary != "Low risk to change helper: no references found" {
+		t.Errorf("Unexpected summary: %s", summary)
+	}
+}
@@ -5,7 +5,9 @@ import (
 	"fmt"
 	"os"
 	"regexp"
+	"sort"
 	"strings"
+	"sync"
 
 	"github.com/aaamil13/CodeIndexerMCP/internal/database"
 	"github.com/aaamil13/CodeIndexerMCP/pkg/types"
@@ -14,11 +16,23 @@ import (
 // MetricsCalculator calculates code quality metrics
 type MetricsCalculator struct {
 	db *database.DB
+
+	mu    sync.Mutex
+	cache map[int64]*cachedMetrics // symbol ID -> metrics
+}
+
+// cachedMetrics holds metrics computed for a symbol at a given file hash
+type cachedMetrics struct {
+	fileHash string
+	metrics  *types.CodeMetrics
 }
 
 // NewMetricsCalculator creates a new metrics calculator
 func NewMetricsCalculator(db *database.DB) *MetricsCalculator {
-	return &MetricsCalculator{db: db}
+	return &MetricsCalculator{
+		db:    db,
+		cache: make(map[int64]*cachedMetrics),
+	}
 }
 
 // CalculateMetrics calculates metrics for a symbol
@@ -37,6 +51,92 @@ func (mc *MetricsCalculator) CalculateMetrics(symbolName string) (*types.CodeMet
 	if err != nil {
 		return nil, err
 	}
+	if file == nil {
+		return nil, fmt.Errorf("file not found for symbol: %s", symbolName)
+	}
+
+	return mc.calculateSymbolMetrics(symbol, file)
+}
+
+// RankFunctions returns metrics for the most complex functions and methods in
+// a project, ordered by descending cyclomatic complexity
+func (mc *MetricsCalculator) RankFunctions(projectID int64, limit, minComplexity int) ([]*types.CodeMetrics, error) {
+	files, err := mc.db.GetAllFilesForProject(projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	ranked := []*types.CodeMetrics{}
+	for _, file := range files {
+		symbols, err := mc.db.GetSymbolsByFile(file.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, symbol := range symbols {
+			if symbol.Type != types.SymbolTypeFunction && symbol.Type != types.SymbolTypeMethod {
+				continue
+			}
+
+			metric, err := mc.calculateSymbolMetrics(symbol, file)
+			if err != nil {
+				continue // Skip symbols whose source can't be read
+			}
+			if metric.CyclomaticComplexity < minComplexity {
+				continue
+			}
+			ranked = append(ranked, metric)
+		}
+	}
+
+	sort.SliceStable(ranked, func(i, j int) bool {
+		if ranked[i].CyclomaticComplexity != ranked[j].CyclomaticComplexity {
+			return ranked[i].CyclomaticComplexity > ranked[j].CyclomaticComplexity
+		}
+		if ranked[i].CognitiveComplexity != ranked[j].CognitiveComplexity {
+			return ranked[i].CognitiveComplexity > ranked[j].CognitiveComplexity
+		}
+		return ranked[i].FunctionName < ranked[j].FunctionName
+	})
+
+	if limit > 0 && len(ranked) > limit {
+		ranked = ranked[:limit]
+	}
+
+	return ranked, nil
+}
+
+// FileComplexity returns the total and maximum cyclomatic complexity of the
+// functions and methods among a file's symbols
+func (mc *MetricsCalculator) FileComplexity(file *types.File, symbols []*types.Symbol) (total, max int) {
+	for _, symbol := range symbols {
+		if symbol.Type != types.SymbolTypeFunction && symbol.Type != types.SymbolTypeMethod {
+			continue
+		}
+
+		metric, err := mc.calculateSymbolMetrics(symbol, file)
+		if err != nil {
+			continue // Skip symbols whose source can't be read
+		}
+
+		total += metric.CyclomaticComplexity
+		if metric.CyclomaticComplexity > max {
+			max = metric.CyclomaticComplexity
+		}
+	}
+
+	return total, max
+}
+
+// calculateSymbolMetrics calculates metrics for a symbol, reusing cached
+// results while the containing file is unchanged
+func (mc *MetricsCalculator) calculateSymbolMetrics(symbol *types.Symbol, file *types.File) (*types.CodeMetrics, error) {
+	mc.mu.Lock()
+	cached, ok := mc.cache[symbol.ID]
+	mc.mu.Unlock()
+	if ok && cached.fileHash == file.Hash {
+		return cached.metrics, nil
+	}
 
 	// Extract code
 	code, err := mc.extractCode(file.Path, symbol.StartLine, symbol.EndLine)
@@ -71,7 +171,7 @@ func (mc *MetricsCalculator) CalculateMetrics(symbolName string) (*types.CodeMet
 	// Determine quality
 	quality := mc.determineQuality(cyclomaticComplexity, cognitiveComplexity, maintainability, hasDocumentation)
 
-	return &types.CodeMetrics{
+	metrics := &types.CodeMetrics{
 		FilePath:             file.RelativePath,
 		FunctionName:         symbol.Name,
 		LinesOfCode:          loc,
@@ -84,11 +184,23 @@ func (mc *MetricsCalculator) CalculateMetrics(symbolName string) (*types.CodeMet
 		CommentDensity:       commentDensity,
 		HasDocumentation:     hasDocumentation,
 		Quality:              quality,
-	}, nil
+	}
+
+	mc.mu.Lock()
+	mc.cache[symbol.ID] = &cachedMetrics{fileHash: file.Hash, metrics: metrics}
+	mc.mu.Unlock()
+
+	return metrics, nil
 }
 
 // calculateCyclomaticComplexity calculates cyclomatic complexity
 func (mc *MetricsCalculator) calculateCyclomaticComplexity(code, language string) int {
+	return CyclomaticComplexity(code, language)
+}
+
+// CyclomaticComplexity estimates the cyclomatic complexity of a code
+// fragment by counting its decision points
+func CyclomaticComplexity(code, language string) int {
 	complexity := 1 // Base complexity
 
 	// Language-specific patterns
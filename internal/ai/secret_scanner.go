@@ -0,0 +1,167 @@
+package ai
+
+import (
+	"bufio"
+	"math"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/aaamil13/CodeIndexerMCP/internal/database"
+	"github.com/aaamil13/CodeIndexerMCP/pkg/types"
+)
+
+// Generic tokens must be at least this long and random-looking to be reported
+const (
+	minSecretTokenLength = 24
+	minSecretEntropy     = 4.0
+)
+
+var (
+	// stringLiteralPattern matches double-quoted, single-quoted and backtick string literals
+	stringLiteralPattern = regexp.MustCompile("\"(?:[^\"\\\\]|\\\\.)*\"|'(?:[^'\\\\]|\\\\.)*'|`[^`]*`")
+
+	secretPatterns = []struct {
+		kind    types.SecretKind
+		pattern *regexp.Regexp
+	}{
+		{types.SecretKindAWSAccessKey, regexp.MustCompile(`\b(?:AKIA|ASIA)[0-9A-Z]{16}\b`)},
+		{types.SecretKindPrivateKey, regexp.MustCompile(`-----BEGIN (?:RSA |EC |DSA |OPENSSH |PGP )?PRIVATE KEY(?: BLOCK)?-----`)},
+		{types.SecretKindGitHubToken, regexp.MustCompile(`\bgh[pousr]_[A-Za-z0-9]{36}\b`)},
+	}
+
+	genericTokenPattern = regexp.MustCompile(`^[A-Za-z0-9+/=_\-]+$`)
+)
+
+// SecretScanner detects hardcoded credentials in indexed source files
+type SecretScanner struct {
+	db *database.DB
+}
+
+// NewSecretScanner creates a new secret scanner
+func NewSecretScanner(db *database.DB) *SecretScanner {
+	return &SecretScanner{db: db}
+}
+
+// ScanProject scans every indexed file in a project
+func (ss *SecretScanner) ScanProject(projectID int64) ([]*types.SecretFinding, error) {
+	files, err := ss.db.GetAllFilesForProject(projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	findings := []*types.SecretFinding{}
+	for _, file := range files {
+		fileFindings, err := ss.ScanFile(file.Path, file.RelativePath)
+		if err != nil {
+			continue // File may have been removed since indexing
+		}
+		findings = append(findings, fileFindings...)
+	}
+
+	return findings, nil
+}
+
+// ScanFile scans the string literals of a single file
+func (ss *SecretScanner) ScanFile(filePath, relativePath string) ([]*types.SecretFinding, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var findings []*types.SecretFinding
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	lineNum := 0
+
+	for scanner.Scan() {
+		lineNum++
+		for _, literal := range stringLiteralPattern.FindAllString(scanner.Text(), -1) {
+			for _, finding := range scanLiteral(literal[1 : len(literal)-1]) {
+				finding.FilePath = relativePath
+				finding.Line = lineNum
+				findings = append(findings, finding)
+			}
+		}
+	}
+
+	return findings, scanner.Err()
+}
+
+// scanLiteral runs the detectors over the contents of one string literal.
+// Known credential formats take precedence over the generic entropy check.
+func scanLiteral(value string) []*types.SecretFinding {
+	var findings []*types.SecretFinding
+
+	for _, sp := range secretPatterns {
+		for _, match := range sp.pattern.FindAllString(value, -1) {
+			findings = append(findings, &types.SecretFinding{
+				Kind:  sp.kind,
+				Match: redactSecret(match),
+			})
+		}
+	}
+	if len(findings) > 0 {
+		return findings
+	}
+
+	for _, token := range strings.Fields(value) {
+		if !looksLikeSecretToken(token) {
+			continue
+		}
+		findings = append(findings, &types.SecretFinding{
+			Kind:    types.SecretKindHighEntropy,
+			Match:   redactSecret(token),
+			Entropy: math.Round(shannonEntropy(token)*100) / 100,
+		})
+	}
+
+	return findings
+}
+
+// looksLikeSecretToken reports whether a token is long, mixes letters and
+// digits, and is random enough to be a key rather than an identifier or word
+func looksLikeSecretToken(token string) bool {
+	if len(token) < minSecretTokenLength || !genericTokenPattern.MatchString(token) {
+		return false
+	}
+	if !strings.ContainsAny(token, "0123456789") || strings.IndexFunc(token, isASCIILetter) < 0 {
+		return false
+	}
+	return shannonEntropy(token) >= minSecretEntropy
+}
+
+// isASCIILetter reports whether r is an ASCII letter
+func isASCIILetter(r rune) bool {
+	return (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z')
+}
+
+// shannonEntropy calculates the Shannon entropy of s in bits per character
+func shannonEntropy(s string) float64 {
+	if s == "" {
+		return 0
+	}
+
+	counts := make(map[rune]int)
+	for _, r := range s {
+		counts[r]++
+	}
+
+	entropy := 0.0
+	length := float64(len([]rune(s)))
+	for _, count := range counts {
+		p := float64(count) / length
+		entropy -= p * math.Log2(p)
+	}
+
+	return entropy
+}
+
+// redactSecret keeps only the first four characters of a secret
+func redactSecret(secret string) string {
+	if len(secret) <= 4 {
+		return strings.Repeat("*", len(secret))
+	}
+	return secret[:4] + strings.Repeat("*", len(secret)-4)
+}
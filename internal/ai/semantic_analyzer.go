@@ -2,7 +2,9 @@ package ai
 
 import (
 	"fmt"
+	"runtime"
 	"strings"
+	"sync"
 
 	"github.com/aaamil13/CodeIndexerMCP/internal/database"
 	"github.com/aaamil13/CodeIndexerMCP/pkg/types"
@@ -10,18 +12,28 @@ import (
 
 // SemanticAnalyzer performs semantic analysis across files
 type SemanticAnalyzer struct {
-	db            *database.Database
+	db            *database.DB
 	typeValidator *TypeValidator
+	workers       int // Files validated in parallel by AnalyzeProject
 }
 
 // NewSemanticAnalyzer creates a new semantic analyzer
-func NewSemanticAnalyzer(db *database.Database) *SemanticAnalyzer {
+func NewSemanticAnalyzer(db *database.DB) *SemanticAnalyzer {
 	return &SemanticAnalyzer{
 		db:            db,
 		typeValidator: NewTypeValidator(db),
+		workers:       runtime.NumCPU(),
 	}
 }
 
+// SetWorkerCount sets how many files AnalyzeProject validates in parallel
+func (sa *SemanticAnalyzer) SetWorkerCount(n int) {
+	if n < 1 {
+		n = 1
+	}
+	sa.workers = n
+}
+
 // AnalyzeProject performs full semantic analysis on a project
 func (sa *SemanticAnalyzer) AnalyzeProject(projectID int64) (*types.SemanticAnalysisResult, error) {
 	result := &types.SemanticAnalysisResult{
@@ -40,14 +52,12 @@ func (sa *SemanticAnalyzer) AnalyzeProject(projectID int64) (*types.SemanticAnal
 		return nil, fmt.Errorf("failed to get files: %w", err)
 	}
 
-	// Analyze each file
-	for _, file := range files {
-		// Type validation
-		validation, err := sa.typeValidator.ValidateFile(file.ID)
-		if err != nil {
+	// Type validation is per file, so spread it across workers; results are
+	// merged in file order so the output doesn't depend on scheduling
+	for _, validation := range sa.validateFiles(files) {
+		if validation == nil {
 			continue
 		}
-
 		result.TypeErrors = append(result.TypeErrors, validation.TypeMismatches...)
 		result.UndefinedReferences = append(result.UndefinedReferences, validation.UndefinedSymbols...)
 	}
@@ -78,12 +88,53 @@ func (sa *SemanticAnalyzer) AnalyzeProject(projectID int64) (*types.SemanticAnal
 	return result, nil
 }
 
+// validateFiles validates files with a bounded worker pool. The result at
+// index i belongs to files[i] and is nil if validation failed.
+func (sa *SemanticAnalyzer) validateFiles(files []*types.File) []*types.TypeValidation {
+	validations := make([]*types.TypeValidation, len(files))
+
+	workers := sa.workers
+	if workers < 1 {
+		workers = 1
+	}
+	if workers > len(files) {
+		workers = len(files)
+	}
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				validation, err := sa.typeValidator.ValidateFile(files[i].ID)
+				if err != nil {
+					continue
+				}
+				validations[i] = validation
+			}
+		}()
+	}
+
+	for i := range files {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	return validations
+}
+
 // InferType infers the type of a symbol
 func (sa *SemanticAnalyzer) InferType(symbolID int64) (*types.TypeInference, error) {
-	symbol, err := sa.db.GetSymbol(symbolID)
+	symbol, err := sa.db.GetSymbolByID(symbolID)
 	if err != nil {
 		return nil, err
 	}
+	if symbol == nil {
+		return nil, fmt.Errorf("symbol not found: %d", symbolID)
+	}
 
 	inference := &types.TypeInference{
 		SymbolName: symbol.Name,
@@ -93,6 +144,17 @@ func (sa *SemanticAnalyzer) InferType(symbolID int64) (*types.TypeInference, err
 	// Type inference based on symbol type
 	switch symbol.Type {
 	case types.SymbolTypeFunction, types.SymbolTypeMethod:
+		details, err := sa.db.GetFunctionDetails(symbol.ID)
+		if err != nil {
+			return nil, err
+		}
+		if details != nil && details.ReturnType != "" {
+			inference.InferredType = details.ReturnType
+			inference.Confidence = 0.95
+			inference.Reasoning = "Declared return type"
+			break
+		}
+
 		inference.InferredType = sa.inferFunctionType(symbol)
 		inference.Confidence = 0.8
 		inference.Reasoning = "Inferred from function signature"
@@ -262,8 +324,7 @@ func (sa *SemanticAnalyzer) findUnusedSymbols(projectID int64) ([]*types.Symbol,
 		}
 
 		for _, symbol := range symbols {
-			// Skip exported symbols (might be used externally)
-			if symbol.IsExported {
+			if !isUnusedCandidate(symbol) {
 				continue
 			}
 
@@ -282,6 +343,18 @@ func (sa *SemanticAnalyzer) findUnusedSymbols(projectID int64) ([]*types.Symbol,
 	return unused, nil
 }
 
+// isUnusedCandidate reports whether a symbol could be flagged as unused.
+// Exported symbols might be used externally, and anonymous symbols (empty
+// names, e.g. config keys or function literals) and the blank identifier
+// can't be referenced by name at all.
+func isUnusedCandidate(symbol *types.Symbol) bool {
+	if symbol.IsExported {
+		return false
+	}
+	name := strings.TrimSpace(symbol.Name)
+	return name != "" && name != "_"
+}
+
 func (sa *SemanticAnalyzer) detectCircularDependencies(projectID int64) []*types.CircularDependency {
 	circular := make([]*types.CircularDependency, 0)
 
@@ -438,17 +511,25 @@ func (sa *SemanticAnalyzer) AnalyzeCallGraph(projectID int64) (*types.CallGraph,
 			}
 
 			for _, rel := range relationships {
-				if rel.Type == types.RelationshipTypeCalls {
-					edge := &types.CallGraphEdge{
-						FromSymbolID: symbol.ID,
-						ToSymbolID:   rel.ToSymbolID,
-						CallSites:    1, // Simplified
-					}
-					callGraph.Edges = append(callGraph.Edges, edge)
+				// The symbol's relationships include calls to it; those edges
+				// are added when visiting the caller
+				if rel.Type != types.RelationshipCalls || rel.FromSymbolID != symbol.ID {
+					continue
+				}
+
+				edge := &types.CallGraphEdge{
+					FromSymbolID: rel.FromSymbolID,
+					ToSymbolID:   rel.ToSymbolID,
+					CallSites:    1, // Simplified
+					IsRecursive:  rel.FromSymbolID == rel.ToSymbolID,
+				}
+				callGraph.Edges = append(callGraph.Edges, edge)
 
-					// Update call count
-					if node, ok := symbolToNode[rel.ToSymbolID]; ok {
-						node.CallCount++
+				// Update call count
+				if node, ok := symbolToNode[rel.ToSymbolID]; ok {
+					node.CallCount++
+					if edge.IsRecursive {
+						node.IsRecursive = true
 					}
 				}
 			}
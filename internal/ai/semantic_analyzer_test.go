@@ -0,0 +1,183 @@
+package ai
+
+import (
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/aaamil13/CodeIndexerMCP/internal/database"
+	"github.com/aaamil13/CodeIndexerMCP/pkg/types"
+)
+
+// openAnalyzerTestDB opens a database in a temp directory, closed when tb ends
+func openAnalyzerTestDB(tb testing.TB) *database.DB {
+	tb.Helper()
+
+	db, err := database.Open(filepath.Join(tb.TempDir(), "test.db"))
+	if err != nil {
+		tb.Fatalf("Failed to open database: %v", err)
+	}
+	tb.Cleanup(func() { db.Close() })
+	return db
+}
+
+// setupMultiFileProject creates a project with several files, each holding a
+// few symbols, one of which is referenced from the next file
+func setupMultiFileProject(tb testing.TB, db *database.DB, fileCount int) int64 {
+	tb.Helper()
+
+	project := &types.Project{Name: "multi", Path: "/multi"}
+	if err := db.CreateProject(project); err != nil {
+		tb.Fatalf("CreateProject failed: %v", err)
+	}
+
+	var previous *types.Symbol
+	for i := 0; i < fileCount; i++ {
+		file := &types.File{
+			ProjectID:    project.ID,
+			Path:         fmt.Sprintf("/multi/file%02d.go", i),
+			RelativePath: fmt.Sprintf("file%02d.go", i),
+			Language:     "go",
+		}
+		if err := db.SaveFile(file); err != nil {
+			tb.Fatalf("SaveFile failed: %v", err)
+		}
+
+		var first *types.Symbol
+		for j := 0; j < 3; j++ {
+			symbol := &types.Symbol{
+				FileID: file.ID,
+				Name:   fmt.Sprintf("func%02d_%d", i, j),
+				Type:   types.SymbolTypeFunction,
+			}
+			if err := db.SaveSymbol(symbol); err != nil {
+				tb.Fatalf("SaveSymbol failed: %v", err)
+			}
+			if first == nil {
+				first = symbol
+			}
+		}
+
+		if previous != nil {
+			ref := &types.Reference{
+				SymbolID:      previous.ID,
+				FileID:        file.ID,
+				LineNumber:    10 + i,
+				ReferenceType: previous.Name + "()",
+			}
+			if err := db.SaveReference(ref); err != nil {
+				tb.Fatalf("SaveReference failed: %v", err)
+			}
+		}
+		previous = first
+	}
+
+	return project.ID
+}
+
+func TestAnalyzeProject_WorkerCountIndependent(t *testing.T) {
+	db := openAnalyzerTestDB(t)
+
+	projectID := setupMultiFileProject(t, db, 12)
+
+	analyzer := NewSemanticAnalyzer(db)
+	analyzer.SetWorkerCount(1)
+	want, err := analyzer.AnalyzeProject(projectID)
+	if err != nil {
+		t.Fatalf("AnalyzeProject failed: %v", err)
+	}
+	// Only the first symbol of each file but the last is referenced
+	if len(want.UnusedSymbols) != 25 {
+		t.Errorf("Expected 25 unused symbols, got %d", len(want.UnusedSymbols))
+	}
+
+	for _, workers := range []int{2, 4, 16} {
+		analyzer.SetWorkerCount(workers)
+		got, err := analyzer.AnalyzeProject(projectID)
+		if err != nil {
+			t.Fatalf("AnalyzeProject with %d workers failed: %v", workers, err)
+		}
+
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("Expected identical results with %d workers, got %+v, want %+v", workers, got, want)
+		}
+	}
+}
+
+func BenchmarkAnalyzeProject(b *testing.B) {
+	for _, workers := range []int{1, 4} {
+		b.Run(fmt.Sprintf("workers=%d", workers), func(b *testing.B) {
+			db := openAnalyzerTestDB(b)
+			projectID := setupMultiFileProject(b, db, 50)
+
+			analyzer := NewSemanticAnalyzer(db)
+			analyzer.SetWorkerCount(workers)
+
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if _, err := analyzer.AnalyzeProject(projectID); err != nil {
+					b.Fatalf("AnalyzeProject failed: %v", err)
+				}
+			}
+		})
+	}
+}
+
+func TestFindUnusedSymbols_OddNames(t *testing.T) {
+	db := openAnalyzerTestDB(t)
+
+	project := &types.Project{Name: "odd", Path: "/odd"}
+	if err := db.CreateProject(project); err != nil {
+		t.Fatalf("CreateProject failed: %v", err)
+	}
+	file := &types.File{ProjectID: project.ID, Path: "/odd/odd.go", RelativePath: "odd.go", Language: "go"}
+	if err := db.SaveFile(file); err != nil {
+		t.Fatalf("SaveFile failed: %v", err)
+	}
+	for _, name := range []string{"", "_", "_helper", "Exported"} {
+		symbol := &types.Symbol{
+			FileID:     file.ID,
+			Name:       name,
+			Type:       types.SymbolTypeFunction,
+			IsExported: name == "Exported",
+		}
+		if err := db.SaveSymbol(symbol); err != nil {
+			t.Fatalf("SaveSymbol failed: %v", err)
+		}
+	}
+
+	analyzer := NewSemanticAnalyzer(db)
+	unused, err := analyzer.findUnusedSymbols(project.ID)
+	if err != nil {
+		t.Fatalf("findUnusedSymbols failed: %v", err)
+	}
+
+	var names []string
+	for _, symbol := range unused {
+		names = append(names, symbol.Name)
+	}
+	if !reflect.DeepEqual(names, []string{"_helper"}) {
+		t.Errorf("Expected only _helper to be unused, got %q", names)
+	}
+}
+
+func TestIsUnusedCandidate(t *testing.T) {
+	tests := []struct {
+		symbol *types.Symbol
+		want   bool
+	}{
+		{&types.Symbol{Name: ""}, false},
+		{&types.Symbol{Name: "  "}, false},
+		{&types.Symbol{Name: "_"}, false},
+		{&types.Symbol{Name: "_helper"}, true},
+		{&types.Symbol{Name: "helper"}, true},
+		{&types.Symbol{Name: "Helper", IsExported: true}, false},
+	}
+
+	for _, tt := range tests {
+		if got := isUnusedCandidate(tt.symbol); got != tt.want {
+			t.Errorf("isUnusedCandidate(%q) = %v, want %v", tt.symbol.Name, got, tt.want)
+		}
+	}
+}
@@ -4,9 +4,12 @@ import (
 	"bufio"
 	"fmt"
 	"os"
+	"regexp"
+	"sort"
 	"strings"
 
 	"github.com/aaamil13/CodeIndexerMCP/internal/database"
+	"github.com/aaamil13/CodeIndexerMCP/internal/utils"
 	"github.com/aaamil13/CodeIndexerMCP/pkg/types"
 )
 
@@ -37,11 +40,13 @@ func (se *SnippetExtractor) ExtractSmartSnippet(symbolName string, includeTests
 		return nil, err
 	}
 
-	// Extract main code
-	code, err := se.extractCode(file.Path, symbol.StartLine, symbol.EndLine)
+	// Read the file once; the snippet and its helpers are all cut from it
+	content, err := utils.ReadFileUTF8(file.Path, file.Encoding)
 	if err != nil {
 		return nil, err
 	}
+	lines := strings.Split(string(content), "\n")
+	code := sliceLines(lines, symbol.StartLine, symbol.EndLine)
 
 	// Get dependencies (imports)
 	imports, err := se.db.GetImportsByFile(file.ID)
@@ -54,17 +59,23 @@ func (se *SnippetExtractor) ExtractSmartSnippet(symbolName string, includeTests
 		dependencies = append(dependencies, imp.Source)
 	}
 
-	// Get related code (helper functions, types used by this symbol)
-	relatedCode, err := se.extractRelatedCode(symbol, file)
-	if err != nil {
-		relatedCode = []string{} // Non-fatal
+	// Get related code (helper functions, types used by this symbol), found
+	// by the names used outside comments and strings
+	stripped := strings.Split(utils.StripCommentsAndStrings(string(content), file.Language), "\n")
+	related := []*types.Symbol{}
+	if symbols, err := se.db.GetSymbolsByFile(file.ID); err == nil { // Non-fatal
+		related = relatedDefinitions(symbol, symbols, stripped)
+	}
+	relatedCode := make([]string, len(related))
+	for i, sym := range related {
+		relatedCode[i] = sliceLines(lines, sym.StartLine, sym.EndLine)
 	}
 
 	// Generate usage hints
 	usageHints := se.generateUsageHints(symbol, file.Language)
 
-	// Check if complete (has all dependencies resolved)
-	complete := se.isComplete(symbol, dependencies)
+	// Check if complete (code and helpers are syntactically self-contained)
+	complete := se.isComplete(append([]*types.Symbol{symbol}, related...), lines, stripped, file.Language)
 
 	return &types.SmartSnippet{
 		Symbol:        symbol,
@@ -102,42 +113,101 @@ func (se *SnippetExtractor) extractCode(filePath string, startLine, endLine int)
 	return strings.Join(lines, "\n"), scanner.Err()
 }
 
-// extractRelatedCode extracts related code (helper functions, types)
-func (se *SnippetExtractor) extractRelatedCode(symbol *types.Symbol, file *types.File) ([]string, error) {
-	relatedCode := []string{}
-
-	// Get all symbols in the file
-	symbols, err := se.db.GetSymbolsByFile(file.ID)
-	if err != nil {
-		return relatedCode, err
+// sliceLines joins lines startLine..endLine (1-based, inclusive)
+func sliceLines(lines []string, startLine, endLine int) string {
+	if startLine < 1 {
+		startLine = 1
 	}
+	if endLine < startLine {
+		endLine = startLine
+	}
+	if endLine > len(lines) {
+		endLine = len(lines)
+	}
+	if startLine > endLine {
+		return ""
+	}
+	return strings.Join(lines[startLine-1:endLine], "\n")
+}
 
-	// Find symbols that this symbol might depend on
-	// (This is simplified - in production we'd parse the code to find actual dependencies)
+// relatedDefinitions returns the file-local helpers and types the symbol
+// refers to, directly or through other helpers, in file order. code is the
+// file's lines with comments and strings blanked, so names mentioned there
+// don't count.
+func relatedDefinitions(symbol *types.Symbol, symbols []*types.Symbol, code []string) []*types.Symbol {
+	candidates := []*types.Symbol{}
 	for _, sym := range symbols {
-		// Skip the symbol itself
-		if sym.ID == symbol.ID {
+		if sym.ID == symbol.ID || !isDefinition(sym) || sym.Name == symbol.Name {
 			continue
 		}
-
-		// Include private helpers that might be used
-		if sym.Visibility == types.VisibilityPrivate && sym.Type == types.SymbolTypeFunction {
-			code, err := se.extractCode(file.Path, sym.StartLine, sym.EndLine)
-			if err == nil {
-				relatedCode = append(relatedCode, code)
+		// Members of the symbol itself or of another definition come with their container
+		nested := false
+		for _, other := range symbols {
+			if other != sym && isDefinition(other) && encloses(other, sym) {
+				nested = true
+				break
 			}
 		}
+		if !nested {
+			candidates = append(candidates, sym)
+		}
+	}
+
+	included := make(map[*types.Symbol]bool)
+	pending := []string{sliceLines(code, symbol.StartLine, symbol.EndLine)}
+	for len(pending) > 0 {
+		names := identifiers(pending[0])
+		pending = pending[1:]
 
-		// Include types/interfaces this might use
-		if sym.Type == types.SymbolTypeType || sym.Type == types.SymbolTypeInterface || sym.Type == types.SymbolTypeStruct {
-			code, err := se.extractCode(file.Path, sym.StartLine, sym.EndLine)
-			if err == nil {
-				relatedCode = append(relatedCode, code)
+		for _, sym := range candidates {
+			if included[sym] || !names[sym.Name] {
+				continue
 			}
+			included[sym] = true
+			pending = append(pending, sliceLines(code, sym.StartLine, sym.EndLine))
+		}
+	}
+
+	selected := []*types.Symbol{}
+	for _, sym := range candidates {
+		if included[sym] {
+			selected = append(selected, sym)
 		}
 	}
+	sort.Slice(selected, func(i, j int) bool { return selected[i].StartLine < selected[j].StartLine })
 
-	return relatedCode, nil
+	return selected
+}
+
+// isDefinition reports whether a symbol is a standalone definition a snippet can pull in
+func isDefinition(sym *types.Symbol) bool {
+	switch sym.Type {
+	case types.SymbolTypeFunction, types.SymbolTypeMethod, types.SymbolTypeClass,
+		types.SymbolTypeStruct, types.SymbolTypeInterface, types.SymbolTypeType,
+		types.SymbolTypeEnum, types.SymbolTypeConstant, types.SymbolTypeVariable:
+		return true
+	}
+	return false
+}
+
+// encloses reports whether inner's line range lies strictly within outer's
+func encloses(outer, inner *types.Symbol) bool {
+	if inner.StartLine < outer.StartLine || inner.EndLine > outer.EndLine {
+		return false
+	}
+	return inner.StartLine != outer.StartLine || inner.EndLine != outer.EndLine
+}
+
+// identifierPattern matches identifier-like words
+var identifierPattern = regexp.MustCompile(`[A-Za-z_][A-Za-z0-9_]*`)
+
+// identifiers returns the set of identifier-like words in code
+func identifiers(code string) map[string]bool {
+	names := make(map[string]bool)
+	for _, name := range identifierPattern.FindAllString(code, -1) {
+		names[name] = true
+	}
+	return names
 }
 
 // generateUsageHints generates hints on how to use the symbol
@@ -176,7 +246,7 @@ func (se *SnippetExtractor) generateUsageHints(symbol *types.Symbol, language st
 			hints = append(hints, "Import from package to use")
 		}
 	case "python":
-		if symbol.Name == "__init__":
+		if symbol.Name == "__init__" {
 			hints = append(hints, "This is a constructor - called automatically when creating instance")
 		}
 	case "typescript":
@@ -186,19 +256,49 @@ func (se *SnippetExtractor) generateUsageHints(symbol *types.Symbol, language st
 	return hints
 }
 
-// isComplete checks if snippet is complete and runnable
-func (se *SnippetExtractor) isComplete(symbol *types.Symbol, dependencies []string) bool {
-	// Simple heuristic - if it has few dependencies and is self-contained
-	if len(dependencies) > 10 {
+// isComplete checks if the snippet and its related code are each a whole
+// definition, i.e. would parse on their own ignoring imports. code is the
+// file's lines with comments and strings blanked.
+func (se *SnippetExtractor) isComplete(symbols []*types.Symbol, lines, code []string, language string) bool {
+	for _, sym := range symbols {
+		if !symbolComplete(sym, lines, code, language) {
+			return false
+		}
+	}
+	return true
+}
+
+// symbolComplete reports whether a symbol's recorded range holds its whole
+// definition. Balanced delimiters alone aren't enough: a range cut short
+// after the declaration line can balance too, so the line after the range
+// must not carry on the definition, either as a block opened on the next
+// line or, in Python, as a deeper-indented body.
+func symbolComplete(sym *types.Symbol, lines, code []string, language string) bool {
+	if sym.StartLine < 1 || sym.EndLine < sym.StartLine || sym.EndLine > len(lines) || sym.EndLine > len(code) {
+		return false
+	}
+	snippet := sliceLines(lines, sym.StartLine, sym.EndLine)
+	if strings.TrimSpace(snippet) == "" || !balancedDelimiters(snippet, language) {
 		return false
 	}
 
-	// Functions without external deps are usually complete
-	if symbol.Type == types.SymbolTypeFunction && len(dependencies) < 5 {
-		return true
+	next := ""
+	for _, line := range code[sym.EndLine:] {
+		if strings.TrimSpace(line) != "" {
+			next = line
+			break
+		}
 	}
 
-	return false
+	if language == "python" {
+		indent := func(line string) int { return len(line) - len(strings.TrimLeft(line, " \t")) }
+		declaration := code[sym.StartLine-1]
+		if sym.EndLine == sym.StartLine && strings.HasSuffix(strings.TrimSpace(declaration), ":") {
+			return false // A block header without its body
+		}
+		return next == "" || indent(next) <= indent(declaration)
+	}
+	return !strings.HasPrefix(strings.TrimSpace(next), "{")
 }
 
 // ExtractMinimalSnippet extracts the absolute minimum code needed
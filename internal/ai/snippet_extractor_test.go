@@ -0,0 +1,40 @@
+package ai
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/aaamil13/CodeIndexerMCP/internal/utils"
+	"github.com/aaamil13/CodeIndexerMCP/pkg/types"
+)
+
+func TestSymbolComplete(t *testing.T) {
+	tests := []struct {
+		name       string
+		language   string
+		code       string
+		start, end int
+		want       bool
+	}{
+		{"whole go function", "go", "package p\n\nfunc F() int {\n\treturn 1\n}\n", 3, 5, true},
+		{"go function cut to its signature", "go", "package p\n\nfunc F() int {\n\treturn 1\n}\n", 3, 3, false},
+		{"brace on the next line", "csharp", "class C\n{\n    void Run()\n    {\n        Go();\n    }\n}\n", 3, 3, false},
+		{"whole brace-on-next-line method", "csharp", "class C\n{\n    void Run()\n    {\n        Go();\n    }\n}\n", 3, 6, true},
+		{"python header without its body", "python", "def f(x):\n    return x\n", 1, 1, false},
+		{"python body cut short", "python", "def f(x):\n    y = x\n    return y\n", 1, 2, false},
+		{"whole python function", "python", "def f(x):\n    y = x\n    return y\n\ndef g():\n    pass\n", 1, 3, true},
+		{"python range not recorded", "python", "def f(x):\n    return x\n", 1, 0, false},
+		{"braces in a string don't balance", "go", "package p\n\nvar s = \"{\"\n", 3, 3, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lines := strings.Split(tt.code, "\n")
+			code := strings.Split(utils.StripCommentsAndStrings(tt.code, tt.language), "\n")
+			sym := &types.Symbol{Name: "f", StartLine: tt.start, EndLine: tt.end}
+			if got := symbolComplete(sym, lines, code, tt.language); got != tt.want {
+				t.Errorf("symbolComplete(lines %d-%d) = %v, want %v", tt.start, tt.end, got, tt.want)
+			}
+		})
+	}
+}
@@ -2,19 +2,30 @@ package ai
 
 import (
 	"fmt"
+	"go/ast"
+	"go/importer"
+	goparser "go/parser"
+	"go/token"
+	gotypes "go/types"
+	"path"
+	"path/filepath"
+	"slices"
 	"strings"
+	"unicode"
 
 	"github.com/aaamil13/CodeIndexerMCP/internal/database"
+	"github.com/aaamil13/CodeIndexerMCP/internal/parser"
+	"github.com/aaamil13/CodeIndexerMCP/internal/utils"
 	"github.com/aaamil13/CodeIndexerMCP/pkg/types"
 )
 
 // TypeValidator validates types and finds undefined usages
 type TypeValidator struct {
-	db *database.Database
+	db *database.DB
 }
 
 // NewTypeValidator creates a new type validator
-func NewTypeValidator(db *database.Database) *TypeValidator {
+func NewTypeValidator(db *database.DB) *TypeValidator {
 	return &TypeValidator{
 		db: db,
 	}
@@ -59,8 +70,8 @@ func (tv *TypeValidator) ValidateFile(fileID int64) (*types.TypeValidation, erro
 	// Check each reference
 	for _, ref := range references {
 		// Get the referenced symbol
-		refSymbol, err := tv.db.GetSymbol(ref.SymbolID)
-		if err != nil {
+		refSymbol, err := tv.db.GetSymbolByID(ref.SymbolID)
+		if err != nil || refSymbol == nil {
 			// Symbol not found - undefined usage
 			context := ref.ReferenceType
 
@@ -99,9 +110,11 @@ func (tv *TypeValidator) ValidateFile(fileID int64) (*types.TypeValidation, erro
 
 	// Check for unused imports
 	imports, err := tv.db.GetImportsByFile(fileID)
-	if err == nil {
+	content, readErr := utils.ReadFileUTF8(file.Path, file.Encoding)
+	if err == nil && readErr == nil {
+		code := parser.CodeOutsideImports(string(content), imports)
 		for _, imp := range imports {
-			if !tv.isImportUsed(imp, references) {
+			if !tv.isImportUsed(imp, file.Language, code) {
 				validation.UnusedImports = append(validation.UnusedImports, imp)
 				validation.Suggestions = append(validation.Suggestions,
 					fmt.Sprintf("Import '%s' is unused and can be removed", imp.Source))
@@ -137,8 +150,11 @@ func (tv *TypeValidator) FindUndefinedUsages(fileID int64) ([]*types.UndefinedUs
 // CheckMethodExists checks if a method exists on a type
 func (tv *TypeValidator) CheckMethodExists(typeName, methodName string, projectID int64) (*types.MissingMethod, error) {
 	// Find the type symbol
-	typeSymbol, err := tv.db.GetSymbolByName(typeName, projectID)
+	typeSymbol, err := tv.findConcreteType(typeName, projectID)
 	if err != nil {
+		return nil, err
+	}
+	if typeSymbol == nil {
 		return &types.MissingMethod{
 			TypeName:   typeName,
 			MethodName: methodName,
@@ -180,12 +196,439 @@ func (tv *TypeValidator) CheckMethodExists(typeName, methodName string, projectI
 	return missing, nil
 }
 
+// CheckConformance checks which methods of an interface a concrete type implements
+func (tv *TypeValidator) CheckConformance(typeName, interfaceName string, projectID int64) (*types.ConformanceReport, error) {
+	typeSymbol, err := tv.findConcreteType(typeName, projectID)
+	if err != nil {
+		return nil, err
+	}
+	if typeSymbol == nil {
+		return nil, fmt.Errorf("type not found: %s", typeName)
+	}
+
+	ifaceCandidates, err := tv.db.GetSymbolsByName(projectID, interfaceName)
+	if err != nil {
+		return nil, err
+	}
+	if len(ifaceCandidates) == 0 {
+		return nil, fmt.Errorf("interface not found: %s", interfaceName)
+	}
+	var ifaceSymbol *types.Symbol
+	for _, candidate := range ifaceCandidates {
+		if candidate.Type == types.SymbolTypeInterface {
+			ifaceSymbol = candidate
+			break
+		}
+	}
+	if ifaceSymbol == nil {
+		return nil, fmt.Errorf("%s is not an interface", interfaceName)
+	}
+
+	ifaceMethods, err := tv.interfaceMethods(ifaceSymbol)
+	if err != nil {
+		return nil, err
+	}
+	if len(ifaceMethods) == 0 {
+		return nil, fmt.Errorf("interface %s has no methods", interfaceName)
+	}
+
+	report := &types.ConformanceReport{
+		TypeName:          typeName,
+		InterfaceName:     interfaceName,
+		PresentMethods:    make([]string, 0),
+		MissingMethods:    make([]string, 0),
+		MismatchedMethods: make([]*types.MethodMismatch, 0),
+	}
+
+	for _, required := range ifaceMethods {
+		method, err := tv.findMethod(typeSymbol, required.Name, projectID)
+		if err != nil {
+			return nil, err
+		}
+		if method == nil {
+			report.MissingMethods = append(report.MissingMethods, required.Name)
+			continue
+		}
+
+		if !tv.signaturesMatch(required, method) {
+			report.MismatchedMethods = append(report.MismatchedMethods, &types.MethodMismatch{
+				MethodName:        required.Name,
+				ExpectedSignature: required.Signature,
+				ActualSignature:   method.Signature,
+			})
+			continue
+		}
+
+		report.PresentMethods = append(report.PresentMethods, required.Name)
+	}
+
+	report.Coverage = float64(len(report.PresentMethods)) / float64(len(ifaceMethods))
+	report.Conforms = len(report.MissingMethods) == 0 && len(report.MismatchedMethods) == 0
+
+	return report, nil
+}
+
+// findConcreteType returns the project's struct, class or named type with the
+// given name, or nil if there is none
+func (tv *TypeValidator) findConcreteType(name string, projectID int64) (*types.Symbol, error) {
+	candidates, err := tv.db.GetSymbolsByName(projectID, name)
+	if err != nil {
+		return nil, err
+	}
+	for _, candidate := range candidates {
+		switch candidate.Type {
+		case types.SymbolTypeStruct, types.SymbolTypeClass, types.SymbolTypeType:
+			return candidate, nil
+		}
+	}
+	return nil, nil
+}
+
+// interfaceMethods returns the methods an interface requires. Go parsers don't
+// emit interface members, so for Go they are read from the declaration itself.
+func (tv *TypeValidator) interfaceMethods(iface *types.Symbol) ([]*types.Symbol, error) {
+	return tv.interfaceMethodSet(iface, make(map[int64]bool))
+}
+
+// interfaceMethodSet is interfaceMethods with the interfaces already being
+// expanded, so that a malformed embedding cycle terminates
+func (tv *TypeValidator) interfaceMethodSet(iface *types.Symbol, expanding map[int64]bool) ([]*types.Symbol, error) {
+	if expanding[iface.ID] {
+		return nil, fmt.Errorf("interface %s embeds itself", iface.Name)
+	}
+	expanding[iface.ID] = true
+	defer delete(expanding, iface.ID)
+
+	methods, err := tv.db.GetMethodsForType(iface.ID)
+	if err != nil {
+		return nil, err
+	}
+	if len(methods) > 0 {
+		return methods, nil
+	}
+
+	file, err := tv.db.GetFile(iface.FileID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get file: %w", err)
+	}
+	if file.Language != "go" {
+		return nil, nil
+	}
+	return tv.goInterfaceMethods(file, iface.Name, expanding)
+}
+
+// goInterfaceMethods parses a Go file and lists the methods of the named
+// interface, with signatures like "func Read(p []byte) (int, error)".
+// Embedded interfaces are expanded, whether declared in the same package,
+// predeclared (error) or imported.
+func (tv *TypeValidator) goInterfaceMethods(file *types.File, name string, expanding map[int64]bool) ([]*types.Symbol, error) {
+	parsed, err := goparser.ParseFile(token.NewFileSet(), file.Path, nil, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", file.Path, err)
+	}
+
+	var iface *ast.InterfaceType
+	ast.Inspect(parsed, func(n ast.Node) bool {
+		if spec, ok := n.(*ast.TypeSpec); ok && spec.Name.Name == name {
+			iface, _ = spec.Type.(*ast.InterfaceType)
+			return false
+		}
+		return iface == nil
+	})
+	if iface == nil {
+		return nil, nil
+	}
+
+	var methods []*types.Symbol
+	for _, field := range iface.Methods.List {
+		if fn, ok := field.Type.(*ast.FuncType); ok {
+			for _, method := range field.Names {
+				methods = append(methods, &types.Symbol{
+					Name:      method.Name,
+					Type:      types.SymbolTypeMethod,
+					Signature: "func " + method.Name + strings.TrimPrefix(gotypes.ExprString(fn), "func"),
+				})
+			}
+			continue
+		}
+
+		embedded, err := tv.embeddedMethods(file, parsed, field.Type, expanding)
+		if err != nil {
+			return nil, err
+		}
+		methods = append(methods, embedded...)
+	}
+	return methods, nil
+}
+
+// embeddedMethods returns the methods of an interface embedded in another.
+// Type constraint elements such as ~int or comparable contribute none.
+func (tv *TypeValidator) embeddedMethods(file *types.File, parsed *ast.File, expr ast.Expr, expanding map[int64]bool) ([]*types.Symbol, error) {
+	switch embedded := expr.(type) {
+	case *ast.Ident:
+		symbol, err := tv.findGoInterface(file.ProjectID, embedded.Name, func(candidate *types.File) bool {
+			return filepath.Dir(candidate.Path) == filepath.Dir(file.Path)
+		})
+		if err != nil {
+			return nil, err
+		}
+		if symbol != nil {
+			return tv.interfaceMethodSet(symbol, expanding)
+		}
+		if obj := gotypes.Universe.Lookup(embedded.Name); obj != nil {
+			return typeMethods(obj.Type()), nil
+		}
+		return nil, fmt.Errorf("cannot resolve embedded interface %s", embedded.Name)
+
+	case *ast.SelectorExpr:
+		pkgName, ok := embedded.X.(*ast.Ident)
+		if !ok {
+			return nil, nil
+		}
+		importPath := importPathFor(parsed, pkgName.Name)
+		if importPath == "" {
+			return nil, fmt.Errorf("cannot resolve embedded interface %s: %s is not imported", gotypes.ExprString(embedded), pkgName.Name)
+		}
+
+		// Project packages are resolved from the index, anything else
+		// (the standard library, modules) from its compiled export data
+		symbol, err := tv.findGoInterface(file.ProjectID, embedded.Sel.Name, func(candidate *types.File) bool {
+			dir := filepath.ToSlash(filepath.Dir(candidate.RelativePath))
+			return dir != "." && (importPath == dir || strings.HasSuffix(importPath, "/"+dir))
+		})
+		if err != nil {
+			return nil, err
+		}
+		if symbol != nil {
+			return tv.interfaceMethodSet(symbol, expanding)
+		}
+		pkg, err := importer.Default().Import(importPath)
+		if err != nil {
+			return nil, fmt.Errorf("cannot resolve embedded interface %s: %w", gotypes.ExprString(embedded), err)
+		}
+		obj := pkg.Scope().Lookup(embedded.Sel.Name)
+		if obj == nil {
+			return nil, fmt.Errorf("cannot resolve embedded interface %s", gotypes.ExprString(embedded))
+		}
+		return typeMethods(obj.Type()), nil
+	}
+	return nil, nil
+}
+
+// findGoInterface returns the project's Go interface with the given name
+// declared in a file accepted by inPackage, or nil if there is none
+func (tv *TypeValidator) findGoInterface(projectID int64, name string, inPackage func(file *types.File) bool) (*types.Symbol, error) {
+	candidates, err := tv.db.GetSymbolsByName(projectID, name)
+	if err != nil {
+		return nil, err
+	}
+	for _, candidate := range candidates {
+		if candidate.Type != types.SymbolTypeInterface {
+			continue
+		}
+		file, err := tv.db.GetFile(candidate.FileID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get file: %w", err)
+		}
+		if file.Language == "go" && inPackage(file) {
+			return candidate, nil
+		}
+	}
+	return nil, nil
+}
+
+// importPathFor returns the path a Go file imports under the given package
+// name, either as an explicit alias or as the path's last element
+func importPathFor(file *ast.File, name string) string {
+	for _, spec := range file.Imports {
+		importPath := strings.Trim(spec.Path.Value, "`\"")
+		if spec.Name != nil {
+			if spec.Name.Name == name {
+				return importPath
+			}
+			continue
+		}
+		if path.Base(importPath) == name {
+			return importPath
+		}
+	}
+	return ""
+}
+
+// typeMethods lists the full method set of a type-checked interface
+func typeMethods(typ gotypes.Type) []*types.Symbol {
+	iface, ok := typ.Underlying().(*gotypes.Interface)
+	if !ok {
+		return nil
+	}
+
+	qualifier := func(pkg *gotypes.Package) string { return pkg.Name() }
+	methods := make([]*types.Symbol, 0, iface.NumMethods())
+	for i := 0; i < iface.NumMethods(); i++ {
+		fn := iface.Method(i)
+		methods = append(methods, &types.Symbol{
+			Name:      fn.Name(),
+			Type:      types.SymbolTypeMethod,
+			Signature: "func " + fn.Name() + strings.TrimPrefix(gotypes.TypeString(fn.Type(), qualifier), "func"),
+		})
+	}
+	return methods
+}
+
+// findMethod finds the method with the given name declared on a type, either
+// as its child or, for Go, by a receiver of that type in the same package
+func (tv *TypeValidator) findMethod(typeSymbol *types.Symbol, name string, projectID int64) (*types.Symbol, error) {
+	candidates, err := tv.db.GetSymbolsByName(projectID, name)
+	if err != nil {
+		return nil, err
+	}
+
+	var typeFile *types.File
+	for _, candidate := range candidates {
+		if candidate.Type != types.SymbolTypeMethod {
+			continue
+		}
+		if candidate.ParentID != nil && *candidate.ParentID == typeSymbol.ID {
+			return candidate, nil
+		}
+		if receiverType(candidate.Signature) != typeSymbol.Name {
+			continue
+		}
+
+		if typeFile == nil {
+			if typeFile, err = tv.db.GetFile(typeSymbol.FileID); err != nil {
+				return nil, fmt.Errorf("failed to get file: %w", err)
+			}
+		}
+		methodFile, err := tv.db.GetFile(candidate.FileID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get file: %w", err)
+		}
+		if filepath.Dir(methodFile.Path) == filepath.Dir(typeFile.Path) {
+			return candidate, nil
+		}
+	}
+	return nil, nil
+}
+
+// receiverType returns the type named by a Go method signature's receiver,
+// e.g. "func (c *Cache[K, V]) Get(key K) V" -> "Cache"
+func receiverType(signature string) string {
+	rest, ok := strings.CutPrefix(signature, "func (")
+	if !ok {
+		return ""
+	}
+	end := strings.Index(rest, ")")
+	if end < 0 {
+		return ""
+	}
+
+	fields := strings.Fields(rest[:end])
+	if len(fields) == 0 {
+		return ""
+	}
+	receiver := strings.TrimPrefix(fields[len(fields)-1], "*")
+	if i := strings.Index(receiver, "["); i >= 0 {
+		receiver = receiver[:i]
+	}
+	return receiver
+}
+
+// signaturesMatch compares the parameter and result types of two methods.
+// Parameter and result names, receivers and formatting are ignored; a missing
+// signature never matches.
+func (tv *TypeValidator) signaturesMatch(required, actual *types.Symbol) bool {
+	if required.Signature == "" || actual.Signature == "" {
+		return false
+	}
+
+	requiredTail, actualTail := signatureTail(required.Signature), signatureTail(actual.Signature)
+	requiredParams, requiredResults, requiredOK := signatureTypes(requiredTail)
+	actualParams, actualResults, actualOK := signatureTypes(actualTail)
+	if !requiredOK || !actualOK {
+		// Not Go syntax, so only an identical parameter list and result match
+		return strings.Join(strings.Fields(requiredTail), "") == strings.Join(strings.Fields(actualTail), "")
+	}
+	return slices.Equal(requiredParams, actualParams) && slices.Equal(requiredResults, actualResults)
+}
+
+// signatureTail strips the keyword, receiver, name and type parameters from a
+// signature, e.g. "func (c *Cache[K, V]) Get(key K) (V, bool)" -> "(key K) (V, bool)"
+func signatureTail(signature string) string {
+	rest := strings.TrimSpace(signature)
+	if keyword, after, ok := strings.Cut(rest, " "); ok && !strings.Contains(keyword, "(") {
+		rest = strings.TrimSpace(after)
+	}
+	if strings.HasPrefix(rest, "(") {
+		rest = strings.TrimSpace(rest[closingBracket(rest):])
+	}
+	rest = strings.TrimLeftFunc(rest, func(r rune) bool {
+		return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+	})
+	if strings.HasPrefix(rest, "[") {
+		rest = rest[closingBracket(rest):]
+	}
+	return strings.TrimSpace(rest)
+}
+
+// closingBracket returns the offset just past the bracket closing the one s
+// starts with, or len(s) if it is never closed
+func closingBracket(s string) int {
+	depth := 0
+	for i, r := range s {
+		switch r {
+		case '(', '[', '{':
+			depth++
+		case ')', ']', '}':
+			depth--
+			if depth == 0 {
+				return i + 1
+			}
+		}
+	}
+	return len(s)
+}
+
+// signatureTypes parses a Go signature tail into its canonically formatted
+// parameter and result types, one per name, e.g. "(a, b int) (n int, err error)"
+// -> ["int", "int"], ["int", "error"]. ok is false if the tail isn't Go syntax.
+func signatureTypes(tail string) (params, results []string, ok bool) {
+	expr, err := goparser.ParseExpr("func" + tail)
+	if err != nil {
+		return nil, nil, false
+	}
+	fn, isFunc := expr.(*ast.FuncType)
+	if !isFunc {
+		return nil, nil, false
+	}
+	return fieldTypes(fn.Params), fieldTypes(fn.Results), true
+}
+
+// fieldTypes lists the type of every field in a parameter or result list,
+// repeated once per name
+func fieldTypes(fields *ast.FieldList) []string {
+	if fields == nil {
+		return nil
+	}
+	var list []string
+	for _, field := range fields.List {
+		typ := gotypes.ExprString(field.Type)
+		for range max(len(field.Names), 1) {
+			list = append(list, typ)
+		}
+	}
+	return list
+}
+
 // ValidateSymbolTypes validates types for a specific symbol
 func (tv *TypeValidator) ValidateSymbolTypes(symbolID int64) (*types.TypeValidation, error) {
-	symbol, err := tv.db.GetSymbol(symbolID)
+	symbol, err := tv.db.GetSymbolByID(symbolID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get symbol: %w", err)
 	}
+	if symbol == nil {
+		return nil, fmt.Errorf("symbol not found: %d", symbolID)
+	}
 
 	file, err := tv.db.GetFile(symbol.FileID)
 	if err != nil {
@@ -210,10 +653,10 @@ func (tv *TypeValidator) ValidateSymbolTypes(symbolID int64) (*types.TypeValidat
 	}
 
 	for _, rel := range relationships {
-		if rel.Type == types.RelationshipTypeCalls {
+		if rel.Type == types.RelationshipCalls {
 			// Check if the called symbol exists
-			_, err := tv.db.GetSymbol(rel.ToSymbolID)
-			if err != nil {
+			called, err := tv.db.GetSymbolByID(rel.ToSymbolID)
+			if err != nil || called == nil {
 				// Called symbol doesn't exist
 				undefined := &types.UndefinedUsage{
 					Name:      "unknown",
@@ -449,22 +892,10 @@ func (tv *TypeValidator) validateReference(ref *types.Reference, refSymbol *type
 	}
 }
 
-func (tv *TypeValidator) isImportUsed(imp *types.Import, references []*types.Reference) bool {
-	// Check if any reference uses this import
-	importName := imp.Source
-	if strings.Contains(importName, "/") {
-		// Get last part of import path
-		parts := strings.Split(importName, "/")
-		importName = parts[len(parts)-1]
-	}
-
-	for _, ref := range references {
-		// Check if import is used in reference type
-		if strings.Contains(ref.ReferenceType, importName) {
-			return true
-		}
-	}
-
-	// If no explicit usage found, assume it's used (to avoid false positives)
-	return len(references) > 0
+// isImportUsed reports whether code uses any member of an import. Imports
+// whose usage can't be determined, such as wildcard or Go blank imports,
+// count as used.
+func (tv *TypeValidator) isImportUsed(imp *types.Import, language, code string) bool {
+	members, known := parser.ImportMembers(imp, language, code)
+	return !known || len(members) > 0
 }
@@ -0,0 +1,261 @@
+package ai
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/aaamil13/CodeIndexerMCP/internal/database"
+	"github.com/aaamil13/CodeIndexerMCP/internal/parsers/golang"
+	"github.com/aaamil13/CodeIndexerMCP/pkg/types"
+)
+
+// indexGoSource writes Go source files under a temp project, runs them through
+// the Go parser and saves the results
+func indexGoSource(t *testing.T, sources map[string]string) (*database.DB, *types.Project) {
+	t.Helper()
+
+	dir := t.TempDir()
+	db, err := database.Open(filepath.Join(dir, "test.db"))
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	project := &types.Project{Path: dir, Name: "test"}
+	if err := db.CreateProject(project); err != nil {
+		t.Fatalf("Failed to create project: %v", err)
+	}
+
+	parser := golang.NewParser()
+	for relPath, source := range sources {
+		path := filepath.Join(dir, relPath)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(source), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", relPath, err)
+		}
+
+		result, err := parser.Parse([]byte(source), path)
+		if err != nil {
+			t.Fatalf("Failed to parse %s: %v", relPath, err)
+		}
+
+		file := &types.File{ProjectID: project.ID, Path: path, RelativePath: relPath, Language: "go"}
+		if err := db.SaveFile(file); err != nil {
+			t.Fatalf("Failed to save file: %v", err)
+		}
+		for _, symbol := range result.Symbols {
+			symbol.FileID = file.ID
+			if err := db.SaveSymbol(symbol); err != nil {
+				t.Fatalf("Failed to save symbol %s: %v", symbol.Name, err)
+			}
+		}
+		for _, imp := range result.Imports {
+			imp.FileID = file.ID
+			if err := db.SaveImport(imp); err != nil {
+				t.Fatalf("Failed to save import %s: %v", imp.Source, err)
+			}
+		}
+	}
+
+	return db, project
+}
+
+func TestCheckConformance_MissingMethod(t *testing.T) {
+	db, project := indexGoSource(t, map[string]string{
+		"io/io.go": `package io
+
+type ReadCloser interface {
+	Read(p []byte) (int, error)
+	Close() error
+}
+
+type FileReader struct{}
+
+func (f *FileReader) Read(p []byte) (int, error) { return 0, nil }
+`,
+		// Same-named type in another package must not lend its methods
+		"other/other.go": `package other
+
+type FileReader struct{}
+
+func (FileReader) Close() error { return nil }
+`,
+	})
+
+	validator := NewTypeValidator(db)
+	report, err := validator.CheckConformance("FileReader", "ReadCloser", project.ID)
+	if err != nil {
+		t.Fatalf("CheckConformance failed: %v", err)
+	}
+
+	if report.Conforms {
+		t.Error("Expected FileReader not to conform to ReadCloser")
+	}
+	if len(report.MissingMethods) != 1 || report.MissingMethods[0] != "Close" {
+		t.Errorf("Expected missing method Close, got %v", report.MissingMethods)
+	}
+	if len(report.PresentMethods) != 1 || report.PresentMethods[0] != "Read" {
+		t.Errorf("Expected present method Read, got %v", report.PresentMethods)
+	}
+	if report.Coverage != 0.5 {
+		t.Errorf("Expected coverage 0.5, got %f", report.Coverage)
+	}
+}
+
+func TestCheckConformance_MismatchedSignature(t *testing.T) {
+	db, project := indexGoSource(t, map[string]string{
+		"shape.go": `package shape
+
+type Shape interface {
+	Area() float64
+}
+
+type Square struct{ side int }
+
+func (s Square) Area() int { return s.side * s.side }
+`,
+	})
+
+	report, err := NewTypeValidator(db).CheckConformance("Square", "Shape", project.ID)
+	if err != nil {
+		t.Fatalf("CheckConformance failed: %v", err)
+	}
+
+	if report.Conforms {
+		t.Error("Expected Square not to conform to Shape")
+	}
+	if len(report.MismatchedMethods) != 1 || report.MismatchedMethods[0].MethodName != "Area" {
+		t.Fatalf("Expected mismatched method Area, got %v", report.MismatchedMethods)
+	}
+	if report.MismatchedMethods[0].ActualSignature != "func (s Square) Area() int" {
+		t.Errorf("Unexpected actual signature: %s", report.MismatchedMethods[0].ActualSignature)
+	}
+}
+
+func TestCheckConformance_EmptyInterface(t *testing.T) {
+	db, project := indexGoSource(t, map[string]string{
+		"any.go": `package any
+
+type Any interface{}
+
+type Value struct{}
+`,
+	})
+
+	if _, err := NewTypeValidator(db).CheckConformance("Value", "Any", project.ID); err == nil {
+		t.Error("Expected an error for an interface with no methods")
+	}
+}
+
+func TestCheckConformance_ParameterNames(t *testing.T) {
+	db, project := indexGoSource(t, map[string]string{
+		"reader.go": `package reader
+
+type Reader interface {
+	Read(p []byte) (n int, err error)
+}
+
+type Buffer struct{}
+
+func (b *Buffer) Read(buf []byte) (int, error) { return 0, nil }
+`,
+	})
+
+	report, err := NewTypeValidator(db).CheckConformance("Buffer", "Reader", project.ID)
+	if err != nil {
+		t.Fatalf("CheckConformance failed: %v", err)
+	}
+	if !report.Conforms {
+		t.Errorf("Expected Buffer to conform to Reader despite different names, got mismatches %v", report.MismatchedMethods)
+	}
+}
+
+func TestCheckConformance_EmbeddedInterfaces(t *testing.T) {
+	db, project := indexGoSource(t, map[string]string{
+		"stream/stream.go": `package stream
+
+import "io"
+
+type Reader interface {
+	Read(p []byte) (n int, err error)
+}
+
+type ReadCloser interface {
+	Reader
+	io.Closer
+}
+
+type File struct{}
+
+func (f *File) Read(buf []byte) (int, error) { return 0, nil }
+`,
+	})
+
+	report, err := NewTypeValidator(db).CheckConformance("File", "ReadCloser", project.ID)
+	if err != nil {
+		t.Fatalf("CheckConformance failed: %v", err)
+	}
+
+	if report.Conforms {
+		t.Error("Expected File not to conform to ReadCloser")
+	}
+	if len(report.PresentMethods) != 1 || report.PresentMethods[0] != "Read" {
+		t.Errorf("Expected present method Read from the embedded Reader, got %v", report.PresentMethods)
+	}
+	if len(report.MissingMethods) != 1 || report.MissingMethods[0] != "Close" {
+		t.Errorf("Expected missing method Close from io.Closer, got %v", report.MissingMethods)
+	}
+}
+
+func TestSignaturesMatch_EmptySignature(t *testing.T) {
+	tv := &TypeValidator{}
+	required := &types.Symbol{Name: "Close", Signature: "func Close() error"}
+
+	if tv.signaturesMatch(required, &types.Symbol{Name: "Close"}) {
+		t.Error("Expected a missing signature not to match")
+	}
+	if !tv.signaturesMatch(required, &types.Symbol{Name: "Close", Signature: "func (f *File) Close() error"}) {
+		t.Error("Expected matching signatures to match")
+	}
+	if tv.signaturesMatch(required, &types.Symbol{Name: "Close", Signature: "func (f *File) Close()"}) {
+		t.Error("Expected differing result types not to match")
+	}
+}
+
+func TestValidateFile_UnusedImports(t *testing.T) {
+	db, project := indexGoSource(t, map[string]string{
+		"main.go": `package main
+
+import (
+	f "fmt"
+	_ "net/http/pprof"
+	"os"
+	str "strings"
+)
+
+func main() {
+	f.Println(os.Args)
+}
+`,
+	})
+
+	files, err := db.GetAllFilesForProject(project.ID)
+	if err != nil || len(files) != 1 {
+		t.Fatalf("Expected one file, got %v (%v)", files, err)
+	}
+	validation, err := NewTypeValidator(db).ValidateFile(files[0].ID)
+	if err != nil {
+		t.Fatalf("ValidateFile failed: %v", err)
+	}
+
+	// The blank import is for its side effects; only str goes unused
+	if len(validation.UnusedImports) != 1 || validation.UnusedImports[0].Source != "strings" {
+		t.Errorf("Expected only strings to be unused, got %v", validation.UnusedImports)
+	}
+	if validation.UnusedImports[0].Alias != "str" {
+		t.Errorf("Expected the alias str to be stored, got %q", validation.UnusedImports[0].Alias)
+	}
+}
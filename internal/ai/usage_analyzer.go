@@ -1,10 +1,12 @@
 package ai
 
 import (
+	"context"
 	"fmt"
 	"strings"
 
 	"github.com/aaamil13/CodeIndexerMCP/internal/database"
+	"github.com/aaamil13/CodeIndexerMCP/internal/parser"
 	"github.com/aaamil13/CodeIndexerMCP/pkg/types"
 )
 
@@ -135,6 +137,10 @@ func (ua *UsageAnalyzer) detectUsagePatterns(symbol *types.Symbol, references []
 
 // isDeprecated checks if a symbol is deprecated
 func (ua *UsageAnalyzer) isDeprecated(symbol *types.Symbol) bool {
+	if deprecated, _ := symbol.Metadata[parser.DeprecatedKey].(bool); deprecated {
+		return true
+	}
+
 	// Check documentation for deprecation markers
 	doc := strings.ToLower(symbol.Documentation)
 	return strings.Contains(doc, "deprecated") ||
@@ -167,15 +173,44 @@ func (ua *UsageAnalyzer) findAlternatives(symbol *types.Symbol) []string {
 
 // FindUnusedSymbols finds symbols that are never used
 func (ua *UsageAnalyzer) FindUnusedSymbols(projectID int64) ([]*types.Symbol, error) {
+	return ua.FindUnusedSymbolsExcluding(projectID, nil)
+}
+
+// FindUnusedSymbolsExcluding finds symbols that are never used outside the
+// files matched by exclude. Symbols in excluded files are not reported, and
+// references from them don't count as uses; with a test-file matcher this
+// keeps production code referenced only by tests from looking alive.
+func (ua *UsageAnalyzer) FindUnusedSymbolsExcluding(projectID int64, exclude func(*types.File) bool) ([]*types.Symbol, error) {
+	return ua.FindUnusedSymbolsContext(context.Background(), projectID, exclude)
+}
+
+// FindUnusedSymbolsContext is FindUnusedSymbolsExcluding, stopping when ctx is done
+func (ua *UsageAnalyzer) FindUnusedSymbolsContext(ctx context.Context, projectID int64, exclude func(*types.File) bool) ([]*types.Symbol, error) {
 	// Get all files for project
 	files, err := ua.db.GetAllFilesForProject(projectID)
 	if err != nil {
 		return nil, err
 	}
 
+	excluded := make(map[int64]bool)
+	if exclude != nil {
+		for _, file := range files {
+			if exclude(file) {
+				excluded[file.ID] = true
+			}
+		}
+	}
+
 	unusedSymbols := []*types.Symbol{}
 
 	for _, file := range files {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		if excluded[file.ID] {
+			continue
+		}
+
 		// Get symbols in file
 		symbols, err := ua.db.GetSymbolsByFile(file.ID)
 		if err != nil {
@@ -194,8 +229,16 @@ func (ua *UsageAnalyzer) FindUnusedSymbols(projectID int64) ([]*types.Symbol, er
 				continue
 			}
 
+			used := false
+			for _, ref := range refs {
+				if !excluded[ref.FileID] {
+					used = true
+					break
+				}
+			}
+
 			// No references = unused
-			if len(refs) == 0 {
+			if !used {
 				unusedSymbols = append(unusedSymbols, symbol)
 			}
 		}
@@ -0,0 +1,103 @@
+package core
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/aaamil13/CodeIndexerMCP/pkg/types"
+)
+
+// FindSymbolsByAnnotation finds symbols carrying the named annotation,
+// attribute or decorator. The name matches with or without its @ or [], its
+// package qualifier, its arguments and C#'s Attribute suffix, so "GetMapping",
+// "@GetMapping" and "HttpGetAttribute" all work.
+func (idx *Indexer) FindSymbolsByAnnotation(annotation string) ([]*types.AnnotatedSymbol, error) {
+	want := normalizeAnnotation(annotation)
+	if want == "" {
+		return nil, fmt.Errorf("annotation is required")
+	}
+
+	symbols, err := idx.db.GetDecoratedSymbols(idx.project.ID, want)
+	if err != nil {
+		return nil, err
+	}
+
+	paths := make(map[int64]string)
+	results := []*types.AnnotatedSymbol{}
+	for _, symbol := range symbols {
+		decorators := metadataStrings(symbol.Metadata, "decorators")
+
+		matched := false
+		for _, decorator := range decorators {
+			if strings.EqualFold(normalizeAnnotation(decorator), want) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			continue
+		}
+
+		path, ok := paths[symbol.FileID]
+		if !ok {
+			file, err := idx.db.GetFile(symbol.FileID)
+			if err != nil {
+				return nil, err
+			}
+			path = file.RelativePath
+			paths[symbol.FileID] = path
+		}
+
+		annotations := metadataStrings(symbol.Metadata, "annotations")
+		if len(annotations) == 0 {
+			annotations = decorators
+		}
+
+		results = append(results, &types.AnnotatedSymbol{
+			Symbol:      symbol,
+			FilePath:    path,
+			Annotations: annotations,
+		})
+	}
+
+	return results, nil
+}
+
+// normalizeAnnotation reduces an annotation to its bare name, e.g.
+// @org.springframework.web.bind.annotation.GetMapping("/x") -> GetMapping
+// and [HttpGetAttribute] -> HttpGet
+func normalizeAnnotation(annotation string) string {
+	name := strings.TrimSpace(annotation)
+	name = strings.TrimPrefix(name, "@")
+	name = strings.TrimPrefix(name, "[")
+	name = strings.TrimSuffix(name, "]")
+	if paren := strings.Index(name, "("); paren >= 0 {
+		name = name[:paren]
+	}
+	if dot := strings.LastIndex(name, "."); dot >= 0 {
+		name = name[dot+1:]
+	}
+	name = strings.TrimSpace(name)
+	if trimmed := strings.TrimSuffix(name, "Attribute"); trimmed != "" {
+		name = trimmed
+	}
+	return name
+}
+
+// metadataStrings reads a string list from symbol metadata, which holds
+// []string when freshly parsed and []interface{} once loaded from the database
+func metadataStrings(metadata map[string]interface{}, key string) []string {
+	switch values := metadata[key].(type) {
+	case []string:
+		return values
+	case []interface{}:
+		strs := make([]string, 0, len(values))
+		for _, value := range values {
+			if s, ok := value.(string); ok {
+				strs = append(strs, s)
+			}
+		}
+		return strs
+	}
+	return nil
+}
@@ -0,0 +1,114 @@
+package core
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/aaamil13/CodeIndexerMCP/pkg/types"
+)
+
+// isPublicSymbol reports whether a symbol belongs in the API surface
+func isPublicSymbol(symbol *types.Symbol) bool {
+	if symbol.Visibility == types.VisibilityPrivate {
+		return false
+	}
+	return symbol.IsExported || symbol.Visibility == types.VisibilityPublic
+}
+
+// GetAPISurface returns the exported symbols of the project grouped by file,
+// leaving out private symbols and test files
+func (idx *Indexer) GetAPISurface() (*types.APISurface, error) {
+	files, err := idx.db.GetAllFilesForProject(idx.project.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].RelativePath < files[j].RelativePath
+	})
+
+	surface := &types.APISurface{Files: []*types.APIFile{}}
+	for _, file := range files {
+		if isTestFile(file.RelativePath, file.Language) {
+			continue
+		}
+
+		symbols, err := idx.db.GetSymbolsByFile(file.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		byID := make(map[int64]*types.Symbol, len(symbols))
+		for _, symbol := range symbols {
+			byID[symbol.ID] = symbol
+		}
+
+		apiFile := &types.APIFile{
+			FilePath: file.RelativePath,
+			Language: file.Language,
+			Symbols:  []*types.Symbol{},
+		}
+
+		for _, symbol := range symbols {
+			if symbol.Type == types.SymbolTypePackage || symbol.Type == types.SymbolTypeModule {
+				apiFile.Package = symbol.Name
+				continue
+			}
+			if !isPublicSymbol(symbol) {
+				continue
+			}
+			// Members of a private type are not reachable from outside
+			if symbol.ParentID != nil {
+				if parent, ok := byID[*symbol.ParentID]; ok && !isPublicSymbol(parent) {
+					continue
+				}
+			}
+			apiFile.Symbols = append(apiFile.Symbols, symbol)
+		}
+
+		if len(apiFile.Symbols) == 0 {
+			continue
+		}
+
+		sort.SliceStable(apiFile.Symbols, func(i, j int) bool {
+			return apiFile.Symbols[i].StartLine < apiFile.Symbols[j].StartLine
+		})
+
+		surface.Files = append(surface.Files, apiFile)
+		surface.SymbolCount += len(apiFile.Symbols)
+	}
+
+	return surface, nil
+}
+
+// RenderAPISurfaceMarkdown formats an API surface as a reference document
+func RenderAPISurfaceMarkdown(surface *types.APISurface) string {
+	var b strings.Builder
+
+	b.WriteString("# API Reference\n")
+
+	for _, file := range surface.Files {
+		b.WriteString("\n## ")
+		b.WriteString(file.FilePath)
+		b.WriteString("\n")
+		if file.Package != "" {
+			fmt.Fprintf(&b, "\nPackage `%s`\n", file.Package)
+		}
+
+		for _, symbol := range file.Symbols {
+			fmt.Fprintf(&b, "\n### %s `%s`\n", symbol.Type, symbol.Name)
+
+			if symbol.Signature != "" {
+				fmt.Fprintf(&b, "\n```%s\n%s\n```\n", file.Language, symbol.Signature)
+			}
+			if symbol.Documentation != "" {
+				b.WriteString("\n")
+				b.WriteString(symbol.Documentation)
+				b.WriteString("\n")
+			}
+		}
+	}
+
+	return b.String()
+}
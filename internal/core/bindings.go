@@ -0,0 +1,337 @@
+package core
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/aaamil13/CodeIndexerMCP/internal/utils"
+	"github.com/aaamil13/CodeIndexerMCP/pkg/types"
+)
+
+// Foreign function interfaces recorded in a binding's "binding" metadata
+const (
+	BindingCgo    = "cgo"
+	BindingCtypes = "ctypes"
+	BindingCffi   = "cffi"
+)
+
+// languageBoundaryKey is the relationship metadata flag marking a call that
+// crosses from one language into another
+const languageBoundaryKey = "language_boundary"
+
+var (
+	// cgoImport matches a Go file's import of the cgo pseudo-package
+	cgoImport = regexp.MustCompile(`(?m)^\s*import\s+"C"|^\s*"C"\s*$`)
+
+	// cgoCall matches a call from Go into C, capturing the C function
+	cgoCall = regexp.MustCompile(`\bC\.([A-Za-z_]\w*)\s*\(`)
+
+	// cgoExport matches a directive exporting a Go function to C
+	cgoExport = regexp.MustCompile(`(?m)^//export\s+([A-Za-z_]\w*)`)
+
+	// ctypesLibrary matches a ctypes shared library handle being loaded,
+	// capturing the handle
+	ctypesLibrary = regexp.MustCompile(`([A-Za-z_][\w.]*)\s*=\s*(?:ctypes\.)?(?:(?:CDLL|PyDLL|WinDLL|OleDLL)\(|(?:cdll|pydll|windll|oledll)\.)`)
+
+	// cffiLibrary matches a cffi library handle being opened or imported from
+	// a compiled extension, capturing the handle
+	cffiLibrary = regexp.MustCompile(`([A-Za-z_][\w.]*)\s*=\s*\w+\.(?:dlopen|verify)\(|(?m)^\s*from\s+\S+\s+import\s+(?:ffi\s*,\s*)?(lib)\b`)
+)
+
+// libraryHandle is a shared library handle in a Python file, with the
+// pattern matching calls through it
+type libraryHandle struct {
+	binding string
+	call    *regexp.Regexp
+}
+
+// nativeLanguages are the languages foreign function interfaces call into
+var nativeLanguages = map[string]bool{"c": true, "cpp": true}
+
+// linkBindings records "calls" relationships across language boundaries,
+// matched by name: Go functions calling C through cgo, C functions calling
+// Go functions exported with //export, and Python functions calling shared
+// libraries through ctypes or cffi. Each carries "language_boundary" and
+// "binding" metadata.
+func (idx *Indexer) linkBindings() error {
+	files, err := idx.db.GetAllFilesForProject(idx.project.ID)
+	if err != nil {
+		return err
+	}
+
+	exported := make(map[string]*types.Symbol) // Go functions exported to C
+	var native []*types.File
+	for _, file := range files {
+		switch {
+		case nativeLanguages[file.Language]:
+			native = append(native, file)
+			continue
+		case file.Language != "go" && file.Language != "python":
+			continue
+		}
+
+		content, err := utils.ReadFileUTF8(file.Path, file.Encoding)
+		if err != nil {
+			continue // File may have been removed since indexing
+		}
+		// The cgo import and //export directives are a string and a comment,
+		// so they are matched in the raw source and calls in the stripped one
+		raw := string(content)
+		code := utils.StripCommentsAndStrings(raw, file.Language)
+
+		// Patterns matching calls through each library handle, and the
+		// interface each uses
+		handles := make(map[string]*libraryHandle)
+		addHandle := func(name, binding string) {
+			if _, ok := handles[name]; !ok {
+				pattern := regexp.MustCompile(`(?:^|[^\w.])` + regexp.QuoteMeta(name) + `\.([A-Za-z_]\w*)`)
+				handles[name] = &libraryHandle{binding: binding, call: pattern}
+			}
+		}
+		switch file.Language {
+		case "go":
+			if !cgoImport.MatchString(raw) {
+				continue
+			}
+		case "python":
+			for _, m := range ctypesLibrary.FindAllStringSubmatch(code, -1) {
+				addHandle(m[1], BindingCtypes)
+			}
+			for _, m := range cffiLibrary.FindAllStringSubmatch(code, -1) {
+				addHandle(m[1]+m[2], BindingCffi)
+			}
+			if len(handles) == 0 {
+				continue
+			}
+		}
+
+		symbols, err := idx.db.GetSymbolsByFile(file.ID)
+		if err != nil {
+			return err
+		}
+		lines := strings.Split(code, "\n")
+
+		var exports []string
+		for _, m := range cgoExport.FindAllStringSubmatch(raw, -1) {
+			exports = append(exports, m[1])
+		}
+
+		for _, symbol := range symbols {
+			if !isCallable(symbol) {
+				continue
+			}
+			for _, name := range exports {
+				if symbol.Name == name {
+					exported[name] = symbol
+				}
+			}
+
+			body := symbolBody(symbol, lines)
+			if file.Language == "python" {
+				body = indentedBody(symbol, lines)
+			}
+			targets := make(map[string]string) // Native function -> interface
+			if file.Language == "go" {
+				for _, m := range cgoCall.FindAllStringSubmatch(body, -1) {
+					targets[m[1]] = BindingCgo
+				}
+			}
+			for _, handle := range handles {
+				for _, m := range handle.call.FindAllStringSubmatch(body, -1) {
+					targets[m[1]] = handle.binding
+				}
+			}
+
+			for name, binding := range targets {
+				if err := idx.linkBinding(symbol, file.Language, name, binding); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	// C code calling back into exported Go functions
+	if len(exported) == 0 {
+		return nil
+	}
+	names := make([]string, 0, len(exported))
+	for name := range exported {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, file := range native {
+		content, err := utils.ReadFileUTF8(file.Path, file.Encoding)
+		if err != nil {
+			continue
+		}
+		lines := strings.Split(utils.StripCommentsAndStrings(string(content), file.Language), "\n")
+
+		symbols, err := idx.db.GetSymbolsByFile(file.ID)
+		if err != nil {
+			return err
+		}
+		for _, symbol := range symbols {
+			if !isCallable(symbol) {
+				continue
+			}
+			body := symbolBody(symbol, lines)
+			for _, name := range names {
+				if !mentionPattern(name).MatchString(body) {
+					continue
+				}
+				if err := idx.saveBinding(symbol, exported[name], file.Language, "go", BindingCgo); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// indentedBody returns the lines of an indentation-delimited function after
+// its declaration. The Python parser records only where functions start, so
+// the body runs until the next line indented no deeper than the declaration.
+func indentedBody(symbol *types.Symbol, lines []string) string {
+	if symbol.EndLine > symbol.StartLine {
+		return symbolBody(symbol, lines)
+	}
+	if symbol.StartLine <= 0 || symbol.StartLine > len(lines) {
+		return ""
+	}
+
+	indentOf := func(text string) int { return len(text) - len(strings.TrimLeft(text, " \t")) }
+	defIndent := indentOf(lines[symbol.StartLine-1])
+	end := symbol.StartLine
+	for i := symbol.StartLine; i < len(lines); i++ {
+		if strings.TrimSpace(lines[i]) == "" {
+			continue
+		}
+		if indentOf(lines[i]) <= defIndent {
+			break
+		}
+		end = i + 1
+	}
+	return strings.Join(lines[symbol.StartLine:end], "\n")
+}
+
+// linkBinding records calls from a symbol to the native functions with the
+// given name
+func (idx *Indexer) linkBinding(from *types.Symbol, fromLanguage, name, binding string) error {
+	candidates, err := idx.db.GetSymbolsByName(idx.project.ID, name)
+	if err != nil {
+		return err
+	}
+
+	for _, candidate := range candidates {
+		if !isCallable(candidate) {
+			continue
+		}
+		file, err := idx.db.GetFile(candidate.FileID)
+		if err != nil {
+			return err
+		}
+		if file == nil || !nativeLanguages[file.Language] {
+			continue
+		}
+		if err := idx.saveBinding(from, candidate, fromLanguage, file.Language, binding); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// saveBinding records a cross-language call. A "calls" relationship the
+// parser already recorded between the two symbols gets the binding metadata.
+func (idx *Indexer) saveBinding(from, to *types.Symbol, fromLanguage, toLanguage, binding string) error {
+	return idx.db.UpsertRelationship(&types.Relationship{
+		FromSymbolID: from.ID,
+		ToSymbolID:   to.ID,
+		Type:         types.RelationshipCalls,
+		Metadata: map[string]interface{}{
+			languageBoundaryKey: true,
+			"binding":           binding,
+			"from_language":     fromLanguage,
+			"to_language":       toLanguage,
+		},
+	})
+}
+
+// GetCrossLanguageBindings returns the calls recorded across language
+// boundaries, ordered by the calling file and line
+func (idx *Indexer) GetCrossLanguageBindings() ([]*types.CrossLanguageBinding, error) {
+	relationships, err := idx.db.GetRelationshipsByProject(idx.project.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	bindings := []*types.CrossLanguageBinding{}
+	files := make(map[int64]*types.File)
+	fileOf := func(symbol *types.Symbol) (*types.File, error) {
+		if file, ok := files[symbol.FileID]; ok {
+			return file, nil
+		}
+		file, err := idx.db.GetFile(symbol.FileID)
+		if err != nil {
+			return nil, err
+		}
+		files[symbol.FileID] = file
+		return file, nil
+	}
+
+	for _, rel := range relationships {
+		if boundary, _ := rel.Metadata[languageBoundaryKey].(bool); !boundary {
+			continue
+		}
+
+		from, err := idx.db.GetSymbolByID(rel.FromSymbolID)
+		if err != nil {
+			return nil, err
+		}
+		to, err := idx.db.GetSymbolByID(rel.ToSymbolID)
+		if err != nil {
+			return nil, err
+		}
+		if from == nil || to == nil {
+			continue
+		}
+		fromFile, err := fileOf(from)
+		if err != nil {
+			return nil, err
+		}
+		toFile, err := fileOf(to)
+		if err != nil {
+			return nil, err
+		}
+		if fromFile == nil || toFile == nil {
+			continue
+		}
+
+		binding, _ := rel.Metadata["binding"].(string)
+		bindings = append(bindings, &types.CrossLanguageBinding{
+			Binding:      binding,
+			From:         from,
+			FromFile:     fromFile.RelativePath,
+			FromLanguage: fromFile.Language,
+			To:           to,
+			ToFile:       toFile.RelativePath,
+			ToLanguage:   toFile.Language,
+		})
+	}
+
+	sort.Slice(bindings, func(i, j int) bool {
+		a, b := bindings[i], bindings[j]
+		if a.FromFile != b.FromFile {
+			return a.FromFile < b.FromFile
+		}
+		if a.From.StartLine != b.From.StartLine {
+			return a.From.StartLine < b.From.StartLine
+		}
+		return a.To.Name < b.To.Name
+	})
+
+	return bindings, nil
+}
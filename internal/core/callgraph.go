@@ -0,0 +1,482 @@
+package core
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/aaamil13/CodeIndexerMCP/internal/parser"
+	"github.com/aaamil13/CodeIndexerMCP/internal/utils"
+	"github.com/aaamil13/CodeIndexerMCP/pkg/types"
+)
+
+// callExpression matches a name followed by an argument list, capturing the
+// name called
+var callExpression = regexp.MustCompile(`([A-Za-z_$][\w$]*)\s*\(`)
+
+// callNode is a function in the project's call graph
+type callNode struct {
+	symbol   *types.Symbol
+	file     *types.File
+	owner    string // The type a method belongs to: its Go receiver type or its parent class
+	receiver string // The name a Go method's receiver is bound to
+}
+
+// callSite is a call expression in a function body: the name called and, for
+// a selector call like c.Close(), the identifier before the dot
+type callSite struct {
+	qualifier string
+	name      string
+}
+
+// chainedQualifier stands for a qualifier that isn't a plain identifier,
+// such as c.conn or f(), whose type the call graph can't know
+const chainedQualifier = "\x00"
+
+// callGraph holds the project's functions and the calls between them, with
+// edges as indexes into nodes
+type callGraph struct {
+	nodes     []*callNode
+	calls     [][]int // The other functions each node calls, without duplicates
+	selfCalls []bool  // Whether each node calls itself
+}
+
+// buildCallGraph builds the call graph of the project's functions, also
+// returning the project's files. Calls are the "calls" relationships and
+// "call" references recorded by parsers, plus the call expressions in each
+// function's body outside comments and strings. A call expression is
+// resolved within the caller's language to the one function of that name in
+// the caller's file, failing that in its directory, failing that in the
+// project; calls that remain ambiguous are left out rather than linked to
+// every candidate. A selector call x.Name() is resolved only when x is the
+// caller's receiver (or this, self), a type, or a package the caller's file
+// imports, and then only among that type's methods or that package's
+// functions; any other qualifier is a value of unknown type and the call is
+// left out.
+func (idx *Indexer) buildCallGraph() (*callGraph, []*types.File, error) {
+	files, err := idx.db.GetAllFilesForProject(idx.project.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	graph := &callGraph{}
+	var called []map[callSite]bool               // Calls made in each node's body
+	var refs [][]*types.Reference                // "call" references in each file
+	var fileNodes [][]int                        // Nodes defined in each file
+	byName := make(map[string]map[string][]int)  // language -> name -> nodes
+	byID := make(map[int64]int)                  // symbol ID -> node
+	imports := make(map[int64]map[string]string) // file ID -> qualifier -> import source
+
+	for _, file := range files {
+		symbols, err := idx.db.GetSymbolsByFile(file.ID)
+		if err != nil {
+			return nil, nil, err
+		}
+		fileRefs, err := idx.db.GetReferencesByFile(file.ID)
+		if err != nil {
+			return nil, nil, err
+		}
+		var calls []*types.Reference
+		for _, ref := range fileRefs {
+			if ref.ReferenceType == "call" {
+				calls = append(calls, ref)
+			}
+		}
+		refs = append(refs, calls)
+
+		fileImports, err := idx.db.GetImportsByFile(file.ID)
+		if err != nil {
+			return nil, nil, err
+		}
+		imports[file.ID] = importQualifiers(fileImports, file.Language)
+
+		// Calls in comments and strings aren't calls
+		var lines []string
+		if content, err := utils.ReadFileUTF8(file.Path, file.Encoding); err == nil {
+			lines = strings.Split(utils.StripCommentsAndStrings(string(content), file.Language), "\n")
+		}
+
+		names := make(map[int64]string, len(symbols))
+		for _, symbol := range symbols {
+			names[symbol.ID] = symbol.Name
+		}
+
+		if byName[file.Language] == nil {
+			byName[file.Language] = make(map[string][]int)
+		}
+		fileNodes = append(fileNodes, nil)
+		for _, symbol := range symbols {
+			if !isCallable(symbol) {
+				continue
+			}
+
+			node := &callNode{symbol: symbol, file: file}
+			if symbol.Type == types.SymbolTypeMethod && file.Language == "go" {
+				node.receiver, node.owner = goReceiver(symbol.Signature)
+			} else if symbol.ParentID != nil {
+				node.owner = names[*symbol.ParentID]
+			}
+
+			byName[file.Language][symbol.Name] = append(byName[file.Language][symbol.Name], len(graph.nodes))
+			byID[symbol.ID] = len(graph.nodes)
+			fileNodes[len(fileNodes)-1] = append(fileNodes[len(fileNodes)-1], len(graph.nodes))
+			graph.nodes = append(graph.nodes, node)
+			called = append(called, callSites(symbolBody(symbol, lines)))
+		}
+	}
+
+	graph.calls = make([][]int, len(graph.nodes))
+	graph.selfCalls = make([]bool, len(graph.nodes))
+	seen := make([]map[int]bool, len(graph.nodes))
+	addCall := func(from, to int) {
+		if from == to {
+			graph.selfCalls[from] = true
+			return
+		}
+		if seen[from] == nil {
+			seen[from] = make(map[int]bool)
+		}
+		if !seen[from][to] {
+			seen[from][to] = true
+			graph.calls[from] = append(graph.calls[from], to)
+		}
+	}
+
+	relationships, err := idx.db.GetRelationshipsByProject(idx.project.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, rel := range relationships {
+		if rel.Type != types.RelationshipCalls {
+			continue
+		}
+		from, okFrom := byID[rel.FromSymbolID]
+		to, okTo := byID[rel.ToSymbolID]
+		if okFrom && okTo {
+			addCall(from, to)
+		}
+	}
+
+	// A call reference belongs to the innermost function around its line
+	for i := range files {
+		for _, ref := range refs[i] {
+			to, ok := byID[ref.SymbolID]
+			if !ok {
+				continue
+			}
+			from := -1
+			for _, candidate := range fileNodes[i] {
+				symbol := graph.nodes[candidate].symbol
+				if ref.LineNumber < symbol.StartLine || ref.LineNumber > symbol.EndLine {
+					continue
+				}
+				if from < 0 || symbol.StartLine > graph.nodes[from].symbol.StartLine {
+					from = candidate
+				}
+			}
+			if from >= 0 {
+				addCall(from, to)
+			}
+		}
+	}
+
+	for i, n := range graph.nodes {
+		for call := range called[i] {
+			candidates := graph.callCandidates(n, call, byName[n.file.Language][call.name], imports[n.file.ID])
+			if t, ok := graph.resolveCall(n, candidates); ok {
+				addCall(i, t)
+			}
+		}
+	}
+
+	return graph, files, nil
+}
+
+// callSites returns the calls made in a function body
+func callSites(body string) map[callSite]bool {
+	sites := make(map[callSite]bool)
+	for _, match := range callExpression.FindAllStringSubmatchIndex(body, -1) {
+		sites[callSite{qualifier: selectorQualifier(body[:match[2]]), name: body[match[2]:match[3]]}] = true
+	}
+	return sites
+}
+
+// selectorQualifier returns the qualifier of a call whose name follows
+// before: "" for a plain call, the identifier for x.Name(), and
+// chainedQualifier for anything else before the dot, e.g. a.b.Name() or
+// f().Name()
+func selectorQualifier(before string) string {
+	rest := strings.TrimRight(before, " \t")
+	if !strings.HasSuffix(rest, ".") {
+		return ""
+	}
+	rest = strings.TrimRight(strings.TrimSuffix(strings.TrimSuffix(rest, "."), "?"), " \t\n")
+
+	start := len(rest)
+	for start > 0 && isIdentifierByte(rest[start-1]) {
+		start--
+	}
+	if start == len(rest) {
+		return chainedQualifier
+	}
+	if strings.HasSuffix(strings.TrimRight(rest[:start], " \t\n"), ".") {
+		return chainedQualifier
+	}
+	return rest[start:]
+}
+
+// isIdentifierByte reports whether b can appear in an identifier
+func isIdentifierByte(b byte) bool {
+	return b == '_' || b == '$' || b >= '0' && b <= '9' || b >= 'a' && b <= 'z' || b >= 'A' && b <= 'Z' || b >= 0x80
+}
+
+// callCandidates narrows the same-named functions a call could refer to by
+// its qualifier, returning nil when the qualifier can't be resolved
+func (g *callGraph) callCandidates(caller *callNode, call callSite, candidates []int, imports map[string]string) []int {
+	filter := func(keep func(n *callNode) bool) []int {
+		var kept []int
+		for _, t := range candidates {
+			if keep(g.nodes[t]) {
+				kept = append(kept, t)
+			}
+		}
+		return kept
+	}
+
+	switch q := call.qualifier; {
+	case q == "":
+		if caller.file.Language == "go" {
+			// A bare call in Go is never a method call
+			return filter(func(n *callNode) bool { return n.owner == "" })
+		}
+		return candidates
+	case q == chainedQualifier:
+		return nil
+	case caller.owner != "" && (q == caller.receiver || q == "this" || q == "self" || q == "cls"):
+		return filter(func(n *callNode) bool { return n.owner == caller.owner })
+	}
+
+	if owned := filter(func(n *callNode) bool { return n.owner == call.qualifier }); len(owned) > 0 {
+		return owned
+	}
+	if source, ok := imports[call.qualifier]; ok {
+		return filter(func(n *callNode) bool { return n.owner == "" && importedFrom(source, n.file) })
+	}
+	return nil
+}
+
+// importQualifiers maps the names a file's imports are used through to
+// their sources: a module import's alias or last element, e.g. "fmt" or
+// "np", and each name a named import binds, e.g. "utils" for
+// from pkg import utils. Go blank and dot imports bind no name.
+func importQualifiers(imports []*types.Import, language string) map[string]string {
+	qualifiers := make(map[string]string, len(imports))
+	for _, imp := range imports {
+		source, qualifier := parser.SplitImportAlias(imp.Source)
+		if len(imp.ImportedNames) > 0 {
+			for _, name := range imp.ImportedNames {
+				member, local := parser.SplitImportAlias(name)
+				qualifiers[local] = source + "/" + member
+			}
+			continue
+		}
+		switch {
+		case language == "go":
+			qualifier = parser.ImportQualifier(imp, language)
+		case qualifier == source:
+			qualifier = lastImportElement(source)
+		}
+		if qualifier != "" {
+			qualifiers[qualifier] = source
+		}
+	}
+	return qualifiers
+}
+
+// importedFrom reports whether file can be what an import of source refers
+// to: for Go a file in the package directory the import path ends with,
+// otherwise a file or directory named like the source's last element
+func importedFrom(source string, file *types.File) bool {
+	dir := filepath.ToSlash(filepath.Dir(file.RelativePath))
+	if file.Language == "go" {
+		return dir != "." && (source == dir || strings.HasSuffix(source, "/"+dir))
+	}
+	last := lastImportElement(source)
+	return last == strings.TrimSuffix(filepath.Base(file.RelativePath), filepath.Ext(file.RelativePath)) ||
+		last == filepath.Base(dir)
+}
+
+// lastImportElement returns the last element of an import source, e.g.
+// "utils" for "./lib/utils" or "path" for "os.path"
+func lastImportElement(source string) string {
+	source = strings.TrimRight(source, "/")
+	if i := strings.LastIndexAny(source, "/.\\"); i >= 0 {
+		source = source[i+1:]
+	}
+	return source
+}
+
+// goReceiver returns the name a Go method's receiver is bound to and the
+// type it belongs to, e.g. "func (c *Cache[K, V]) Get(key K) V" -> "c",
+// "Cache"
+func goReceiver(signature string) (name, typ string) {
+	rest, ok := strings.CutPrefix(signature, "func (")
+	if !ok {
+		return "", ""
+	}
+	end := strings.Index(rest, ")")
+	if end < 0 {
+		return "", ""
+	}
+
+	receiver := rest[:end]
+	if i := strings.Index(receiver, "["); i >= 0 {
+		receiver = receiver[:i]
+	}
+	fields := strings.Fields(receiver)
+	if len(fields) == 0 {
+		return "", ""
+	}
+	typ = strings.TrimPrefix(fields[len(fields)-1], "*")
+	if len(fields) > 1 {
+		name = fields[0]
+	}
+	return name, typ
+}
+
+// resolveCall picks the function a call from caller refers to among the
+// same-named candidates: the only one in the caller's file, else the only
+// one in its directory, else the only one at all
+func (g *callGraph) resolveCall(caller *callNode, candidates []int) (int, bool) {
+	var sameFile, sameDir []int
+	for _, t := range candidates {
+		file := g.nodes[t].file
+		if file.ID == caller.file.ID {
+			sameFile = append(sameFile, t)
+		}
+		if filepath.Dir(file.RelativePath) == filepath.Dir(caller.file.RelativePath) {
+			sameDir = append(sameDir, t)
+		}
+	}
+
+	for _, scope := range [][]int{sameFile, sameDir, candidates} {
+		if len(scope) > 0 {
+			return scope[0], len(scope) == 1
+		}
+	}
+	return 0, false
+}
+
+// MaxSymbolDependencies bounds the dependency names stored on each symbol
+const MaxSymbolDependencies = 50
+
+// linkDependencies fills in the Dependencies of the functions in the given
+// files, or of every function when fileIDs is nil: the names of the
+// functions they call, plus the targets of their "calls" and "uses"
+// relationships, sorted and capped at MaxSymbolDependencies
+func (idx *Indexer) linkDependencies(fileIDs map[int64]bool) error {
+	graph, _, err := idx.buildCallGraph()
+	if err != nil {
+		return err
+	}
+
+	names := make(map[int64]map[string]bool) // symbol ID -> dependency names
+	for i, n := range graph.nodes {
+		deps := make(map[string]bool, len(graph.calls[i]))
+		for _, t := range graph.calls[i] {
+			deps[graph.nodes[t].symbol.Name] = true
+		}
+		names[n.symbol.ID] = deps
+	}
+
+	relationships, err := idx.db.GetRelationshipsByProject(idx.project.ID)
+	if err != nil {
+		return err
+	}
+	for _, rel := range relationships {
+		if rel.Type != types.RelationshipCalls && rel.Type != types.RelationshipUses {
+			continue
+		}
+		deps, ok := names[rel.FromSymbolID]
+		if !ok || rel.FromSymbolID == rel.ToSymbolID {
+			continue
+		}
+		target, err := idx.db.GetSymbolByID(rel.ToSymbolID)
+		if err != nil {
+			return err
+		}
+		if target != nil {
+			deps[target.Name] = true
+		}
+	}
+
+	changed := make(map[int64][]string)
+	for _, n := range graph.nodes {
+		if fileIDs != nil && !fileIDs[n.file.ID] {
+			continue
+		}
+
+		deps := make([]string, 0, len(names[n.symbol.ID]))
+		for name := range names[n.symbol.ID] {
+			if name != n.symbol.Name {
+				deps = append(deps, name)
+			}
+		}
+		sort.Strings(deps)
+		if len(deps) > MaxSymbolDependencies {
+			deps = deps[:MaxSymbolDependencies]
+		}
+
+		if strings.Join(deps, "\x00") == strings.Join(n.symbol.Dependencies, "\x00") {
+			continue
+		}
+		changed[n.symbol.ID] = deps
+	}
+	if len(changed) == 0 {
+		return nil
+	}
+
+	return idx.db.SetSymbolDependencies(changed)
+}
+
+// GetCallGraphAdjacency returns the project's call graph as an adjacency
+// list for rendering. Each function is keyed by "file:name", with its start
+// line appended ("file:name:line") when the file has several functions of
+// that name, and maps to the sorted keys of the functions it calls, itself
+// included when it recurses. Functions that call nothing map to an empty
+// list.
+func (idx *Indexer) GetCallGraphAdjacency() (map[string][]string, error) {
+	graph, _, err := idx.buildCallGraph()
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]string, len(graph.nodes))
+	shared := make(map[string]int)
+	for i, n := range graph.nodes {
+		keys[i] = n.file.RelativePath + ":" + n.symbol.Name
+		shared[keys[i]]++
+	}
+	for i, n := range graph.nodes {
+		if shared[keys[i]] > 1 {
+			keys[i] = fmt.Sprintf("%s:%d", keys[i], n.symbol.StartLine)
+		}
+	}
+
+	adjacency := make(map[string][]string, len(graph.nodes))
+	for i := range graph.nodes {
+		list := make([]string, 0, len(graph.calls[i])+1)
+		if graph.selfCalls[i] {
+			list = append(list, keys[i])
+		}
+		for _, t := range graph.calls[i] {
+			list = append(list, keys[t])
+		}
+		sort.Strings(list)
+		adjacency[keys[i]] = list
+	}
+
+	return adjacency, nil
+}
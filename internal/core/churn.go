@@ -0,0 +1,95 @@
+package core
+
+import (
+	"bufio"
+	"bytes"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/aaamil13/CodeIndexerMCP/pkg/types"
+)
+
+// GetFileChurn ranks indexed files by how often they change in git history,
+// weighted by their complexity. Returns an empty list when the project is
+// not a git repository or git is not installed.
+func (idx *Indexer) GetFileChurn() ([]*types.FileChurn, error) {
+	commits := gitCommitCounts(idx.projectPath)
+	if len(commits) == 0 {
+		return []*types.FileChurn{}, nil
+	}
+
+	files, err := idx.db.GetAllFilesForProject(idx.project.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	churn := []*types.FileChurn{}
+	for _, file := range files {
+		count := commits[filepath.ToSlash(file.RelativePath)]
+		if count == 0 {
+			continue
+		}
+
+		symbols, err := idx.db.GetSymbolsByFile(file.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		total, max := idx.metricsCalc.FileComplexity(file, symbols)
+
+		// Files without functions still count their churn once
+		weight := total
+		if weight < 1 {
+			weight = 1
+		}
+
+		churn = append(churn, &types.FileChurn{
+			FilePath:        file.RelativePath,
+			Commits:         count,
+			SymbolCount:     len(symbols),
+			TotalComplexity: total,
+			MaxComplexity:   max,
+			Score:           float64(count * weight),
+		})
+	}
+
+	sort.Slice(churn, func(i, j int) bool {
+		if churn[i].Score != churn[j].Score {
+			return churn[i].Score > churn[j].Score
+		}
+		if churn[i].Commits != churn[j].Commits {
+			return churn[i].Commits > churn[j].Commits
+		}
+		return churn[i].FilePath < churn[j].FilePath
+	})
+
+	return churn, nil
+}
+
+// gitCommitCounts counts the commits touching each file under dir, keyed by
+// slash-separated path relative to dir. Returns nil if git history is unavailable.
+func gitCommitCounts(dir string) map[string]int {
+	if _, err := exec.LookPath("git"); err != nil {
+		return nil
+	}
+
+	// --relative limits the log to dir and reports paths relative to it
+	cmd := exec.Command("git", "log", "--name-only", "--format=", "--relative", "--", ".")
+	cmd.Dir = dir
+	output, err := cmd.Output()
+	if err != nil {
+		return nil // Not a repository, or no commits yet
+	}
+
+	counts := make(map[string]int)
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	for scanner.Scan() {
+		if path := strings.TrimSpace(scanner.Text()); path != "" {
+			counts[path]++
+		}
+	}
+
+	return counts
+}
@@ -0,0 +1,164 @@
+package core
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/aaamil13/CodeIndexerMCP/internal/utils"
+	"github.com/aaamil13/CodeIndexerMCP/pkg/types"
+)
+
+// CompareSymbols compares two symbols, such as parallel implementations of
+// the same logic: whether their signatures differ and a line-level diff of
+// their source with a similarity score. Lines are compared ignoring
+// indentation and with each symbol's own name masked, so a rename alone is
+// not a change. Comparing a name with itself compares its first two
+// definitions.
+func (idx *Indexer) CompareSymbols(nameA, nameB string) (*types.SymbolComparison, error) {
+	symbolsA, err := idx.db.GetSymbolsByName(idx.project.ID, nameA)
+	if err != nil {
+		return nil, err
+	}
+	if len(symbolsA) == 0 {
+		return nil, fmt.Errorf("symbol not found: %s", nameA)
+	}
+	symbolsB, err := idx.db.GetSymbolsByName(idx.project.ID, nameB)
+	if err != nil {
+		return nil, err
+	}
+	if len(symbolsB) == 0 {
+		return nil, fmt.Errorf("symbol not found: %s", nameB)
+	}
+
+	a, b := symbolsA[0], symbolsB[0]
+	if a.ID == b.ID {
+		if len(symbolsB) < 2 {
+			return nil, fmt.Errorf("symbol %s has only one definition", nameA)
+		}
+		b = symbolsB[1]
+	}
+
+	fileA, linesA, err := idx.symbolSource(a)
+	if err != nil {
+		return nil, err
+	}
+	fileB, linesB, err := idx.symbolSource(b)
+	if err != nil {
+		return nil, err
+	}
+
+	comparison := &types.SymbolComparison{
+		SymbolA:          a,
+		SymbolB:          b,
+		FileA:            fileA.RelativePath,
+		FileB:            fileB.RelativePath,
+		SignatureChanged: maskName(a.Signature, a.Name) != maskName(b.Signature, b.Name),
+		Diff:             []*types.DiffLine{},
+	}
+
+	keysA := make([]string, len(linesA))
+	for i, line := range linesA {
+		keysA[i] = maskName(line, a.Name)
+	}
+	keysB := make([]string, len(linesB))
+	for i, line := range linesB {
+		keysB[i] = maskName(line, b.Name)
+	}
+
+	equal := 0
+	for _, op := range diffLines(keysA, keysB) {
+		line := &types.DiffLine{Op: op.op}
+		switch op.op {
+		case types.DiffEqual:
+			equal++
+			line.Text, line.LineA, line.LineB = linesA[op.a], a.StartLine+op.a, b.StartLine+op.b
+		case types.DiffRemoved:
+			comparison.LinesRemoved++
+			line.Text, line.LineA = linesA[op.a], a.StartLine+op.a
+		case types.DiffAdded:
+			comparison.LinesAdded++
+			line.Text, line.LineB = linesB[op.b], b.StartLine+op.b
+		}
+		comparison.Diff = append(comparison.Diff, line)
+	}
+
+	comparison.Similarity = 1
+	if total := len(linesA) + len(linesB); total > 0 {
+		comparison.Similarity = float64(2*equal) / float64(total)
+	}
+
+	return comparison, nil
+}
+
+// symbolSource returns a symbol's file and source lines, falling back to its
+// signature when the file can't be read
+func (idx *Indexer) symbolSource(symbol *types.Symbol) (*types.File, []string, error) {
+	file, err := idx.db.GetFile(symbol.FileID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if file == nil {
+		return nil, nil, fmt.Errorf("file not found for symbol: %s", symbol.Name)
+	}
+
+	var lines []string
+	if content, err := utils.ReadFileUTF8(file.Path, file.Encoding); err == nil {
+		lines = strings.Split(string(content), "\n")
+	}
+	if symbol.StartLine <= 0 || symbol.StartLine > symbol.EndLine || symbol.EndLine > len(lines) {
+		return file, []string{symbol.Signature}, nil
+	}
+	return file, lines[symbol.StartLine-1 : symbol.EndLine], nil
+}
+
+// maskName normalizes a line for comparison, replacing whole-word uses of the
+// symbol's name and collapsing whitespace
+func maskName(line, name string) string {
+	if name != "" {
+		line = mentionPattern(name).ReplaceAllLiteralString(line, "\x00")
+	}
+	return strings.Join(strings.Fields(line), " ")
+}
+
+// diffOp is one step of a diff, indexing into the compared slices
+type diffOp struct {
+	op   string
+	a, b int
+}
+
+// diffLines returns the shortest edit script turning a into b, based on
+// their longest common subsequence
+func diffLines(a, b []string) []diffOp {
+	// common[i][j] is the length of the LCS of a[i:] and b[j:]
+	common := make([][]int, len(a)+1)
+	for i := range common {
+		common[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				common[i][j] = common[i+1][j+1] + 1
+			} else {
+				common[i][j] = max(common[i+1][j], common[i][j+1])
+			}
+		}
+	}
+
+	var ops []diffOp
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			ops = append(ops, diffOp{op: types.DiffEqual, a: i, b: j})
+			i++
+			j++
+		case i < len(a) && (j == len(b) || common[i+1][j] >= common[i][j+1]):
+			ops = append(ops, diffOp{op: types.DiffRemoved, a: i, b: j})
+			i++
+		default:
+			ops = append(ops, diffOp{op: types.DiffAdded, a: i, b: j})
+			j++
+		}
+	}
+	return ops
+}
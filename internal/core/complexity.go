@@ -0,0 +1,58 @@
+package core
+
+import (
+	"fmt"
+
+	"github.com/aaamil13/CodeIndexerMCP/pkg/types"
+)
+
+// complexityBuckets are the lower bounds of GetComplexityDistribution's
+// ranges; each range ends below the next bound and the last is open-ended
+var complexityBuckets = []int{1, 6, 11, 21}
+
+// maxComplexityExamples caps the example functions listed per bucket
+const maxComplexityExamples = 5
+
+// GetComplexityDistribution buckets the project's functions and methods by
+// cyclomatic complexity: 1-5, 6-10, 11-20 and 21+. Each bucket lists its
+// most complex functions as examples.
+func (idx *Indexer) GetComplexityDistribution() (*types.ComplexityHistogram, error) {
+	ranked, err := idx.metricsCalc.RankFunctions(idx.project.ID, 0, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	histogram := &types.ComplexityHistogram{
+		Buckets:        make([]*types.ComplexityBucket, len(complexityBuckets)),
+		TotalFunctions: len(ranked),
+	}
+	for i, min := range complexityBuckets {
+		bucket := &types.ComplexityBucket{
+			Label:    fmt.Sprintf("%d+", min),
+			Min:      min,
+			Examples: []*types.CodeMetrics{},
+		}
+		if i+1 < len(complexityBuckets) {
+			bucket.Max = complexityBuckets[i+1] - 1
+			bucket.Label = fmt.Sprintf("%d-%d", min, bucket.Max)
+		}
+		histogram.Buckets[i] = bucket
+	}
+
+	// Ranked functions come most complex first, so the first ones seen in a
+	// bucket are its best examples
+	for _, metric := range ranked {
+		bucket := histogram.Buckets[0]
+		for _, b := range histogram.Buckets {
+			if metric.CyclomaticComplexity >= b.Min {
+				bucket = b
+			}
+		}
+		bucket.Count++
+		if len(bucket.Examples) < maxComplexityExamples {
+			bucket.Examples = append(bucket.Examples, metric)
+		}
+	}
+
+	return histogram, nil
+}
@@ -0,0 +1,250 @@
+package core
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aaamil13/CodeIndexerMCP/internal/parser"
+)
+
+// ConfigFileNames are the per-project configuration files LoadConfig looks
+// for at the project root, in order
+var ConfigFileNames = []string{".codeindexer.yaml", ".codeindexer.yml", ".codeindexer.json"}
+
+// DefaultToolTimeout is the default time limit for a single MCP tool call
+const DefaultToolTimeout = 30 * time.Second
+
+// DefaultConfig returns the configuration used when none is given
+func DefaultConfig() *Config {
+	return &Config{
+		IndexDir:      ".projectIndex",
+		WorkerCount:   runtime.NumCPU(),
+		BatchSize:     100,
+		BulkFTS:       true,
+		SplitNames:    true,
+		IndexTests:    true,
+		ParseCacheDir: "parse-cache",
+		ToolTimeout:   DefaultToolTimeout,
+	}
+}
+
+// fileConfig is the contents of a project configuration file. Pointers tell
+// settings left out, which keep their defaults, from zero values.
+type fileConfig struct {
+	ProjectName string   `json:"project_name"`
+	WorkerCount *int     `json:"worker_count"`
+	Exclude     []string `json:"exclude"`
+	Languages   []string `json:"languages"`
+	MaxFileSize *int64   `json:"max_file_size"` // Bytes
+	Debounce    string   `json:"debounce"`      // A duration such as "500ms"
+	ToolTimeout string   `json:"tool_timeout"`  // A duration such as "2m", or "0" for no limit
+	IndexTests  *bool    `json:"index_tests"`
+	IndexMarkup *bool    `json:"index_markup"`
+
+	ExtractMethodLines *int `json:"extract_method_lines"`
+}
+
+// LoadConfig returns the default configuration overridden by the project's
+// .codeindexer.yaml or .codeindexer.json, if it has one. The YAML file holds
+// flat "key: value" settings, lists written inline or as "- item" lines.
+func LoadConfig(projectPath string) (*Config, error) {
+	cfg := DefaultConfig()
+
+	for _, name := range ConfigFileNames {
+		path := filepath.Join(projectPath, name)
+		content, err := os.ReadFile(path)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", name, err)
+		}
+
+		if filepath.Ext(name) != ".json" {
+			settings, err := parseSimpleYAML(content)
+			if err != nil {
+				return nil, fmt.Errorf("invalid %s: %w", name, err)
+			}
+			if content, err = json.Marshal(settings); err != nil {
+				return nil, err
+			}
+		}
+
+		var file fileConfig
+		decoder := json.NewDecoder(bytes.NewReader(content))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&file); err != nil {
+			return nil, fmt.Errorf("invalid %s: %w", name, err)
+		}
+		if err := file.apply(cfg); err != nil {
+			return nil, fmt.Errorf("invalid %s: %w", name, err)
+		}
+		return cfg, nil
+	}
+
+	return cfg, nil
+}
+
+// apply copies the settings present in the file onto cfg
+func (f *fileConfig) apply(cfg *Config) error {
+	if f.ProjectName != "" {
+		cfg.ProjectName = f.ProjectName
+	}
+	if f.WorkerCount != nil {
+		if *f.WorkerCount < 1 {
+			return fmt.Errorf("worker_count must be at least 1: %d", *f.WorkerCount)
+		}
+		cfg.WorkerCount = *f.WorkerCount
+	}
+	if f.MaxFileSize != nil {
+		if *f.MaxFileSize < 0 {
+			return fmt.Errorf("max_file_size must not be negative: %d", *f.MaxFileSize)
+		}
+		cfg.MaxFileSize = *f.MaxFileSize
+	}
+	if f.ExtractMethodLines != nil {
+		if *f.ExtractMethodLines < 1 {
+			return fmt.Errorf("extract_method_lines must be at least 1: %d", *f.ExtractMethodLines)
+		}
+		cfg.ExtractMethodLines = *f.ExtractMethodLines
+	}
+	if f.Debounce != "" {
+		debounce, err := time.ParseDuration(f.Debounce)
+		if err != nil {
+			return fmt.Errorf("invalid debounce: %w", err)
+		}
+		cfg.WatchDebounce = debounce
+	}
+	if f.ToolTimeout != "" {
+		timeout, err := time.ParseDuration(f.ToolTimeout)
+		if err != nil || timeout < 0 {
+			return fmt.Errorf("invalid tool_timeout %q: use a duration such as 2m, or 0 for no limit", f.ToolTimeout)
+		}
+		cfg.ToolTimeout = timeout
+	}
+	if f.IndexTests != nil {
+		cfg.IndexTests = *f.IndexTests
+	}
+	if f.IndexMarkup != nil {
+		cfg.SkipMarkup = !*f.IndexMarkup
+	}
+	cfg.Exclude = append(cfg.Exclude, f.Exclude...)
+	if len(f.Languages) > 0 {
+		cfg.Languages = f.Languages
+	}
+	return nil
+}
+
+// parseSimpleYAML reads the flat subset of YAML used by configuration files:
+// top-level "key: value" pairs whose values are scalars or lists of scalars
+func parseSimpleYAML(content []byte) (map[string]interface{}, error) {
+	settings := make(map[string]interface{})
+	var listKey string // Key whose "- item" lines are being read
+
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for lineNum := 1; scanner.Scan(); lineNum++ {
+		line := scanner.Text()
+		if i := strings.Index(line, " #"); i >= 0 {
+			line = line[:i]
+		}
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") || trimmed == "---" {
+			continue
+		}
+
+		if item, ok := strings.CutPrefix(trimmed, "- "); ok {
+			if listKey == "" {
+				return nil, fmt.Errorf("line %d: list item outside a list", lineNum)
+			}
+			settings[listKey] = append(settings[listKey].([]interface{}), yamlScalar(item))
+			continue
+		}
+		if line != strings.TrimLeft(line, " \t") {
+			return nil, fmt.Errorf("line %d: nested settings are not supported", lineNum)
+		}
+
+		key, value, ok := strings.Cut(trimmed, ":")
+		if !ok {
+			return nil, fmt.Errorf("line %d: expected \"key: value\"", lineNum)
+		}
+		key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+
+		listKey = ""
+		switch {
+		case value == "":
+			listKey = key
+			settings[key] = []interface{}{}
+		case strings.HasPrefix(value, "[") && strings.HasSuffix(value, "]"):
+			items := []interface{}{}
+			for _, item := range strings.Split(value[1:len(value)-1], ",") {
+				if item = strings.TrimSpace(item); item != "" {
+					items = append(items, yamlScalar(item))
+				}
+			}
+			settings[key] = items
+		default:
+			settings[key] = yamlScalar(value)
+		}
+	}
+
+	return settings, scanner.Err()
+}
+
+// yamlScalar converts a YAML scalar to a bool, integer or string
+func yamlScalar(value string) interface{} {
+	if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+		return value[1 : len(value)-1]
+	}
+	switch value {
+	case "true", "yes", "on":
+		return true
+	case "false", "no", "off":
+		return false
+	}
+	if n, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return n
+	}
+	return value
+}
+
+// skipFile reports whether a file should be left out of the index: tests
+// when Config.IndexTests is off, languages missing from Config.Languages and
+// files larger than Config.MaxFileSize
+func (idx *Indexer) skipFile(path, relPath string) bool {
+	if idx.skipTestFile(path, relPath) {
+		return true
+	}
+
+	if len(idx.config.Languages) > 0 {
+		p, err := idx.parsers.GetParserForFile(path)
+		if err != nil {
+			return true
+		}
+		included := false
+		for _, language := range idx.config.Languages {
+			if parser.CanonicalLanguage(language) == p.Language() {
+				included = true
+				break
+			}
+		}
+		if !included {
+			return true
+		}
+	}
+
+	if idx.config.MaxFileSize > 0 {
+		if info, err := os.Stat(path); err == nil && info.Size() > idx.config.MaxFileSize {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,333 @@
+package core
+
+import (
+	"bufio"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/aaamil13/CodeIndexerMCP/pkg/types"
+)
+
+// goModule holds the parts of go.mod needed to classify imports
+type goModule struct {
+	path     string   // Module path; imports under it are local
+	requires []string // Required module paths
+}
+
+// readGoModule reads go.mod from the project root, if present
+func readGoModule(projectPath string) *goModule {
+	file, err := os.Open(filepath.Join(projectPath, "go.mod"))
+	if err != nil {
+		return nil
+	}
+	defer file.Close()
+
+	mod := &goModule{}
+	inRequire := false
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if i := strings.Index(line, "//"); i >= 0 {
+			line = strings.TrimSpace(line[:i])
+		}
+
+		switch {
+		case strings.HasPrefix(line, "module "):
+			mod.path = strings.Trim(strings.TrimSpace(strings.TrimPrefix(line, "module ")), `"`)
+		case line == "require (":
+			inRequire = true
+		case inRequire && line == ")":
+			inRequire = false
+		case inRequire && line != "":
+			mod.requires = append(mod.requires, strings.Fields(line)[0])
+		case strings.HasPrefix(line, "require "):
+			if fields := strings.Fields(strings.TrimPrefix(line, "require ")); len(fields) > 0 {
+				mod.requires = append(mod.requires, fields[0])
+			}
+		}
+	}
+
+	return mod
+}
+
+// classifyImport decides whether an import is stdlib, local or external,
+// refining what the parser recorded with project knowledge. It returns an
+// empty type when the language gives no basis for a decision.
+func (idx *Indexer) classifyImport(language string, imp *types.Import, mod *goModule) types.ImportType {
+	source := imp.Source
+	if strings.HasPrefix(source, ".") || strings.HasPrefix(source, "/") {
+		return types.ImportTypeLocal
+	}
+
+	switch language {
+	case "go":
+		if mod != nil && mod.path != "" && (source == mod.path || strings.HasPrefix(source, mod.path+"/")) {
+			return types.ImportTypeLocal
+		}
+	case "python":
+		// A top-level package or module inside the project is a local import
+		top := strings.SplitN(source, ".", 2)[0]
+		if info, err := os.Stat(filepath.Join(idx.projectPath, top)); err == nil && info.IsDir() {
+			return types.ImportTypeLocal
+		}
+		if _, err := os.Stat(filepath.Join(idx.projectPath, top+".py")); err == nil {
+			return types.ImportTypeLocal
+		}
+	case "javascript", "typescript":
+		if strings.HasPrefix(source, "node:") {
+			return types.ImportTypeStdlib
+		}
+		if imp.ImportType == "" {
+			return types.ImportTypeExternal
+		}
+	}
+
+	return imp.ImportType
+}
+
+// externalPackageName reduces an import path to the package that provides it
+func externalPackageName(language, source string, mod *goModule) string {
+	switch language {
+	case "go":
+		// Longest required module containing the import
+		best := ""
+		if mod != nil {
+			for _, req := range mod.requires {
+				if (source == req || strings.HasPrefix(source, req+"/")) && len(req) > len(best) {
+					best = req
+				}
+			}
+		}
+		if best != "" {
+			return best
+		}
+	case "python":
+		return strings.SplitN(source, ".", 2)[0]
+	case "javascript", "typescript":
+		parts := strings.Split(source, "/")
+		if strings.HasPrefix(source, "@") && len(parts) > 1 {
+			return parts[0] + "/" + parts[1]
+		}
+		return parts[0]
+	}
+
+	return source
+}
+
+// GetExternalDependencies lists the third-party packages imported by the
+// project, with the files that use each one
+func (idx *Indexer) GetExternalDependencies() ([]*types.ExternalDep, error) {
+	files, err := idx.db.GetAllFilesForProject(idx.project.ID)
+	if err != nil {
+		return nil, err
+	}
+	filesByID := make(map[int64]*types.File, len(files))
+	for _, file := range files {
+		filesByID[file.ID] = file
+	}
+
+	imports, err := idx.db.GetImportsByProject(idx.project.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	mod := readGoModule(idx.projectPath)
+	deps := make(map[string]*types.ExternalDep)
+	seen := make(map[string]map[string]bool)
+	for _, imp := range imports {
+		file := filesByID[imp.FileID]
+		if file == nil || idx.classifyImport(file.Language, imp, mod) != types.ImportTypeExternal {
+			continue
+		}
+
+		name := externalPackageName(file.Language, imp.Source, mod)
+		key := file.Language + ":" + name
+		dep, ok := deps[key]
+		if !ok {
+			dep = &types.ExternalDep{Name: name, Language: file.Language, Files: []string{}}
+			deps[key] = dep
+			seen[key] = make(map[string]bool)
+		}
+
+		dep.UsageCount++
+		if !seen[key][file.RelativePath] {
+			seen[key][file.RelativePath] = true
+			dep.Files = append(dep.Files, file.RelativePath)
+		}
+	}
+
+	result := make([]*types.ExternalDep, 0, len(deps))
+	for _, dep := range deps {
+		sort.Strings(dep.Files)
+		result = append(result, dep)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].UsageCount != result[j].UsageCount {
+			return result[i].UsageCount > result[j].UsageCount
+		}
+		return result[i].Name < result[j].Name
+	})
+
+	return result, nil
+}
+
+// GetFileDependencies returns the project files a file imports, the files
+// importing it, and its imports that don't resolve to a project file
+func (idx *Indexer) GetFileDependencies(filePath string) (*types.FileDependencies, error) {
+	target, err := idx.lookupFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	files, err := idx.db.GetAllFilesForProject(idx.project.ID)
+	if err != nil {
+		return nil, err
+	}
+	imports, err := idx.db.GetImportsByProject(idx.project.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	resolver := newImportResolver(files, readGoModule(idx.projectPath))
+	imported, importedBy, external := make(map[string]bool), make(map[string]bool), make(map[string]bool)
+	for _, imp := range imports {
+		from := resolver.byID[imp.FileID]
+		if from == nil {
+			continue
+		}
+		resolved := resolver.resolve(from, imp.Source)
+
+		if from.ID == target.ID {
+			if len(resolved) == 0 {
+				external[imp.Source] = true
+			}
+			for _, file := range resolved {
+				if file.ID != target.ID {
+					imported[file.RelativePath] = true
+				}
+			}
+			continue
+		}
+		for _, file := range resolved {
+			if file.ID == target.ID {
+				importedBy[from.RelativePath] = true
+			}
+		}
+	}
+
+	return &types.FileDependencies{
+		FilePath:   target.RelativePath,
+		Imports:    sortedSet(imported),
+		ImportedBy: sortedSet(importedBy),
+		External:   sortedSet(external),
+	}, nil
+}
+
+// sortedSet returns the members of a set in order
+func sortedSet(set map[string]bool) []string {
+	members := make([]string, 0, len(set))
+	for member := range set {
+		members = append(members, member)
+	}
+	sort.Strings(members)
+	return members
+}
+
+// scriptExtensions are tried, in order, for extensionless JavaScript and
+// TypeScript imports
+var scriptExtensions = []string{".ts", ".tsx", ".d.ts", ".js", ".jsx", ".mjs", ".cjs"}
+
+// importResolver maps import sources to the project files they name
+type importResolver struct {
+	byID   map[int64]*types.File
+	byPath map[string]*types.File   // Slash-separated relative path -> file
+	byDir  map[string][]*types.File // Slash-separated relative directory -> files
+	mod    *goModule
+}
+
+// newImportResolver indexes a project's files for import resolution
+func newImportResolver(files []*types.File, mod *goModule) *importResolver {
+	r := &importResolver{
+		byID:   make(map[int64]*types.File, len(files)),
+		byPath: make(map[string]*types.File, len(files)),
+		byDir:  make(map[string][]*types.File),
+		mod:    mod,
+	}
+	for _, file := range files {
+		rel := filepath.ToSlash(file.RelativePath)
+		r.byID[file.ID] = file
+		r.byPath[rel] = file
+		r.byDir[path.Dir(rel)] = append(r.byDir[path.Dir(rel)], file)
+	}
+	return r
+}
+
+// resolve returns the project files an import in from refers to: every
+// non-test file of a Go package, or the module file of other languages
+func (r *importResolver) resolve(from *types.File, source string) []*types.File {
+	dir := path.Dir(filepath.ToSlash(from.RelativePath))
+
+	switch from.Language {
+	case "go":
+		if r.mod == nil || r.mod.path == "" || (source != r.mod.path && !strings.HasPrefix(source, r.mod.path+"/")) {
+			return nil
+		}
+		pkgDir := strings.TrimPrefix(strings.TrimPrefix(source, r.mod.path), "/")
+		if pkgDir == "" {
+			pkgDir = "."
+		}
+		var pkg []*types.File
+		for _, file := range r.byDir[pkgDir] {
+			if file.Language == "go" && !strings.HasSuffix(file.RelativePath, "_test.go") {
+				pkg = append(pkg, file)
+			}
+		}
+		return pkg
+
+	case "python":
+		base := ""
+		if trimmed := strings.TrimLeft(source, "."); trimmed != source {
+			// Each dot past the first climbs one package
+			base = dir
+			for i := 1; i < len(source)-len(trimmed); i++ {
+				base = path.Dir(base)
+			}
+			source = trimmed
+		}
+		module := path.Join(base, strings.ReplaceAll(source, ".", "/"))
+		return r.first(module+".py", path.Join(module, "__init__.py"))
+
+	case "typescript", "javascript":
+		if !strings.HasPrefix(source, ".") {
+			return nil
+		}
+		module := path.Join(dir, source)
+		candidates := []string{module}
+		for _, ext := range scriptExtensions {
+			candidates = append(candidates, module+ext)
+		}
+		for _, ext := range scriptExtensions {
+			candidates = append(candidates, path.Join(module, "index"+ext))
+		}
+		return r.first(candidates...)
+	}
+
+	if strings.HasPrefix(source, ".") {
+		return r.first(path.Join(dir, source))
+	}
+	return nil
+}
+
+// first returns the first of the candidate paths that is a project file
+func (r *importResolver) first(candidates ...string) []*types.File {
+	for _, candidate := range candidates {
+		if file, ok := r.byPath[candidate]; ok {
+			return []*types.File{file}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,24 @@
+package core
+
+import (
+	"github.com/aaamil13/CodeIndexerMCP/pkg/types"
+)
+
+// FindDeprecatedSymbols returns the symbols the parsers flagged deprecated,
+// ordered by file and line
+func (idx *Indexer) FindDeprecatedSymbols() ([]*types.Symbol, error) {
+	symbols, err := idx.db.GetDeprecatedSymbols(idx.project.ID)
+	if err != nil {
+		return nil, err
+	}
+	if symbols == nil {
+		symbols = []*types.Symbol{}
+	}
+	return symbols, nil
+}
+
+// GetSymbolReferences returns the recorded references to a symbol by ID,
+// for callers that already hold the symbol and mustn't re-resolve its name
+func (idx *Indexer) GetSymbolReferences(symbolID int64) ([]*types.Reference, error) {
+	return idx.db.GetReferencesBySymbol(symbolID)
+}
@@ -0,0 +1,140 @@
+package core
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/aaamil13/CodeIndexerMCP/internal/database"
+	"github.com/aaamil13/CodeIndexerMCP/internal/utils"
+	"github.com/aaamil13/CodeIndexerMCP/pkg/types"
+)
+
+// InitializeReadOnly opens the project's index for reading without creating
+// or changing anything, for DryRun. A project that was never indexed gets an
+// empty in-memory index in its place. Only read-only methods may be used.
+func (idx *Indexer) InitializeReadOnly() error {
+	dbPath := filepath.Join(idx.projectPath, idx.config.IndexDir, DatabaseFile)
+
+	var db *database.DB
+	var err error
+	if _, statErr := os.Stat(dbPath); statErr == nil {
+		db, err = database.OpenReadOnly(dbPath)
+	} else {
+		db, err = database.OpenInMemory()
+	}
+	if err != nil {
+		return fmt.Errorf("failed to open database: %w", err)
+	}
+	idx.db = db
+
+	project, err := idx.db.GetProject(idx.projectPath)
+	if err != nil {
+		return fmt.Errorf("failed to get project: %w", err)
+	}
+	if project == nil {
+		name := idx.config.ProjectName
+		if name == "" {
+			name = filepath.Base(idx.projectPath)
+		}
+		project = &types.Project{Path: idx.projectPath, Name: name}
+	}
+	idx.project = project
+
+	return nil
+}
+
+// DryRun scans and parses the project as IndexAll would and reports which
+// files would be indexed, using GetStaleFiles to tell changed files from
+// unchanged ones, without writing anything
+func (idx *Indexer) DryRun() (*types.DryRunReport, error) {
+	paths, err := idx.scanFiles()
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan files: %w", err)
+	}
+
+	files, err := idx.db.GetAllFilesForProject(idx.project.ID)
+	if err != nil {
+		return nil, err
+	}
+	indexed := make(map[string]bool, len(files))
+	for _, file := range files {
+		indexed[file.RelativePath] = true
+	}
+	staleFiles, err := idx.GetStaleFiles()
+	if err != nil {
+		return nil, err
+	}
+	stale := make(map[string]bool, len(staleFiles))
+	for _, file := range staleFiles {
+		stale[file.RelativePath] = true
+	}
+
+	report := &types.DryRunReport{
+		New:        []string{},
+		Changed:    []string{},
+		Missing:    []string{},
+		Failed:     []string{},
+		ByLanguage: make(map[string]int),
+	}
+	found := make(map[string]bool, len(paths))
+	for _, path := range paths {
+		relPath, err := filepath.Rel(idx.projectPath, path)
+		if err != nil {
+			return nil, err
+		}
+		found[relPath] = true
+
+		parser, err := idx.parsers.GetParserForFile(path)
+		if err != nil {
+			return nil, err
+		}
+		if indexed[relPath] && !stale[relPath] {
+			report.Unchanged++
+			report.ByLanguage[parser.Language()]++
+			continue
+		}
+
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+
+		// Binary files are left out of the index
+		encoding := utils.DetectEncoding(content)
+		if encoding == utils.EncodingBinary {
+			continue
+		}
+		report.ByLanguage[parser.Language()]++
+		if indexed[relPath] {
+			report.Changed = append(report.Changed, relPath)
+		} else {
+			report.New = append(report.New, relPath)
+		}
+
+		if content, err = utils.ToUTF8(content, encoding); err != nil {
+			return nil, err
+		}
+		result, err := parser.Parse(content, path)
+		if err != nil {
+			report.Failed = append(report.Failed, relPath)
+			continue
+		}
+		report.Symbols += len(result.Symbols)
+	}
+
+	for _, file := range files {
+		if !found[file.RelativePath] {
+			report.Missing = append(report.Missing, file.RelativePath)
+		}
+	}
+
+	report.Files = len(report.New) + len(report.Changed) + report.Unchanged
+	sort.Strings(report.New)
+	sort.Strings(report.Changed)
+	sort.Strings(report.Missing)
+	sort.Strings(report.Failed)
+
+	return report, nil
+}
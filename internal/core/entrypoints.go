@@ -0,0 +1,364 @@
+package core
+
+import (
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/aaamil13/CodeIndexerMCP/internal/utils"
+	"github.com/aaamil13/CodeIndexerMCP/pkg/types"
+)
+
+// Entry point kinds reported by the built-in rules
+const (
+	EntryPointMain         = "main"
+	EntryPointHTTPHandler  = "http_handler"
+	EntryPointRouteHandler = "route_handler"
+	EntryPointCLICommand   = "cli_command"
+)
+
+// EntryPointRule detects entry points in one language, either by looking at
+// each function (Symbol) or by matching source text that names them
+// (Pattern, whose first submatch lists the names), such as the calls that
+// register route handlers. A name resolves to a function in the file that
+// names it, failing that in its directory; a qualified name such as
+// handlers.List may also resolve to a directory of that name.
+type EntryPointRule struct {
+	Kind    string // Recorded in the symbol's "entry_point" metadata
+	Symbol  func(symbol *types.Symbol) bool
+	Pattern *regexp.Regexp
+
+	// When set, the rule only applies to files whose source matches, such
+	// as an import of the framework the rule is for
+	Requires *regexp.Regexp
+}
+
+// handlerName matches a plain or qualified identifier passed as a handler
+var handlerName = regexp.MustCompile(`^[A-Za-z_$][\w$]*(\.[A-Za-z_$][\w$]*)*$`)
+
+// pythonDecorated matches a function under a decorator matching decorator,
+// possibly among others
+func pythonDecorated(decorator string) *regexp.Regexp {
+	return regexp.MustCompile(`(?m)^\s*@` + decorator + `\b[^\n]*\n(?:\s*@[^\n]*\n)*\s*(?:async\s+)?def\s+(\w+)`)
+}
+
+// goImports matches a Go import of any of the given packages or their
+// subpackages
+func goImports(paths ...string) *regexp.Regexp {
+	return regexp.MustCompile(`"(?:` + quoteAll(paths) + `)(?:/[^"]*)?"`)
+}
+
+// jsImports matches an import or require of any of the given modules
+func jsImports(modules ...string) *regexp.Regexp {
+	return regexp.MustCompile(`(?:\brequire\(\s*|\bfrom\s+|\bimport\s+)['"](?:` + quoteAll(modules) + `)(?:/[^'"]*)?['"]`)
+}
+
+// pythonImports matches an import of any of the given modules
+func pythonImports(modules ...string) *regexp.Regexp {
+	return regexp.MustCompile(`(?m)^\s*(?:from|import)\s+(?:` + quoteAll(modules) + `)\b`)
+}
+
+// quoteAll joins names into a regexp alternation
+func quoteAll(names []string) string {
+	quoted := make([]string, len(names))
+	for i, name := range names {
+		quoted[i] = regexp.QuoteMeta(name)
+	}
+	return strings.Join(quoted, "|")
+}
+
+// defaultEntryPointRules returns the built-in detectors, keyed by language
+func defaultEntryPointRules() map[string][]EntryPointRule {
+	mainFunction := EntryPointRule{
+		Kind: EntryPointMain,
+		Symbol: func(symbol *types.Symbol) bool {
+			return symbol.Name == "main" && symbol.Type == types.SymbolTypeFunction
+		},
+	}
+	staticMain := EntryPointRule{
+		Kind: EntryPointMain,
+		Symbol: func(symbol *types.Symbol) bool {
+			return strings.EqualFold(symbol.Name, "main") && strings.Contains(symbol.Signature, "static")
+		},
+	}
+	goMain := mainFunction
+	goMain.Requires = regexp.MustCompile(`(?m)^package\s+main\b`)
+	javascript := []EntryPointRule{
+		{
+			// app.get("/users", auth, listUsers), router.post(...)
+			Kind:     EntryPointRouteHandler,
+			Pattern:  regexp.MustCompile(`\b(?:app|router|server|api)\.(?:get|post|put|patch|delete|all|use)\(\s*['"` + "`" + `][^'"` + "`" + `]*['"` + "`" + `]\s*,([^)]*)\)`),
+			Requires: jsImports("express", "koa-router", "@koa/router", "fastify", "restify"),
+		},
+		{
+			// commander: program.command("build").action(runBuild)
+			Kind:     EntryPointCLICommand,
+			Pattern:  regexp.MustCompile(`\.action\(\s*([\w$.]+)\s*\)`),
+			Requires: jsImports("commander"),
+		},
+	}
+
+	return map[string][]EntryPointRule{
+		"go": {
+			goMain,
+			{
+				// Exported net/http, gin, echo and fiber handlers
+				Kind:    EntryPointHTTPHandler,
+				Pattern: regexp.MustCompile(`func\s+(?:\([^)]*\)\s*)?([A-Z]\w*)\(\s*\w+\s+(?:http\.ResponseWriter,\s*\w+\s+\*http\.Request|\*gin\.Context|echo\.Context|\*fiber\.Ctx)\s*\)`),
+			},
+			{
+				// http.HandleFunc("/users", listUsers), r.GET("/users", h.List)
+				Kind:    EntryPointRouteHandler,
+				Pattern: regexp.MustCompile(`\.(?:HandleFunc|Handle|GET|POST|PUT|PATCH|DELETE|Get|Post|Put|Patch|Delete)\(\s*"[^"]*"\s*,([^)]*)\)`),
+				Requires: goImports("net/http", "github.com/gin-gonic/gin", "github.com/labstack/echo",
+					"github.com/go-chi/chi", "github.com/gorilla/mux", "github.com/gofiber/fiber", "github.com/julienschmidt/httprouter"),
+			},
+			{
+				// cobra: &cobra.Command{Use: "serve", RunE: runServe}
+				Kind:     EntryPointCLICommand,
+				Pattern:  regexp.MustCompile(`cobra\.Command\s*\{[^}]*?\b(?:Run|RunE|PreRun|PreRunE)\s*:\s*([\w.]+)`),
+				Requires: goImports("github.com/spf13/cobra"),
+			},
+		},
+		"python": {
+			mainFunction,
+			{
+				// Flask, FastAPI: @app.route("/"), @router.get("/")
+				Kind:     EntryPointRouteHandler,
+				Pattern:  pythonDecorated(`\w+\.(?:route|get|post|put|patch|delete|websocket)`),
+				Requires: pythonImports("flask", "fastapi", "starlette", "quart", "sanic"),
+			},
+			{
+				// click, typer: @click.command(), @cli.command()
+				Kind:     EntryPointCLICommand,
+				Pattern:  pythonDecorated(`\w+\.(?:command|group)`),
+				Requires: pythonImports("click", "typer"),
+			},
+			{
+				// if __name__ == "__main__": main()
+				Kind:    EntryPointMain,
+				Pattern: regexp.MustCompile(`if __name__ == ['"]__main__['"]:\s*\n\s+([\w.]+)\(`),
+			},
+		},
+		"java": {
+			staticMain,
+			{
+				// Spring MVC
+				Kind:   EntryPointRouteHandler,
+				Symbol: decoratedWith(regexp.MustCompile(`^@(?:Get|Post|Put|Patch|Delete|Request)Mapping$`)),
+			},
+		},
+		"kotlin": {mainFunction},
+		"csharp": {
+			staticMain,
+			{
+				// ASP.NET Core
+				Kind:   EntryPointRouteHandler,
+				Symbol: decoratedWith(regexp.MustCompile(`^Http(?:Get|Post|Put|Patch|Delete)$`)),
+			},
+		},
+		"rust":       {mainFunction},
+		"c":          {mainFunction},
+		"cpp":        {mainFunction},
+		"javascript": javascript,
+		"typescript": javascript,
+	}
+}
+
+// decoratedWith returns a symbol predicate matching any decorator against pattern
+func decoratedWith(pattern *regexp.Regexp) func(symbol *types.Symbol) bool {
+	return func(symbol *types.Symbol) bool {
+		for _, decorator := range metadataStrings(symbol.Metadata, "decorators") {
+			if pattern.MatchString(decorator) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// AddEntryPointRule adds a detector used by FindEntryPoints
+func (idx *Indexer) AddEntryPointRule(language string, rule EntryPointRule) {
+	idx.entryPointRules[language] = append(idx.entryPointRules[language], rule)
+}
+
+// FindEntryPoints returns the functions execution starts from: main
+// functions, HTTP and framework route handlers, and CLI commands. Each
+// symbol's "entry_point" metadata holds the kind of entry point. Test files
+// are skipped.
+func (idx *Indexer) FindEntryPoints() ([]*types.Symbol, error) {
+	files, err := idx.db.GetAllFilesForProject(idx.project.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	// A registered handler name, resolved once every file's functions are known
+	type registration struct {
+		file *types.File
+		name string // Possibly qualified, e.g. h.List
+		kind string
+	}
+	var registrations []registration
+
+	paths := make(map[int64]string)
+	functions := make(map[string]map[string][]*types.Symbol) // language -> name -> symbols
+	kinds := make(map[*types.Symbol]string)
+
+	for _, file := range files {
+		rules := idx.entryPointRules[file.Language]
+		if len(rules) == 0 || isTestFile(file.RelativePath, file.Language) {
+			continue
+		}
+		paths[file.ID] = file.RelativePath
+
+		symbols, err := idx.db.GetSymbolsByFile(file.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		content, loaded := "", false
+		applies := func(rule EntryPointRule) bool {
+			if rule.Requires == nil {
+				return true
+			}
+			if !loaded {
+				loaded = true
+				if data, err := utils.ReadFileUTF8(file.Path, file.Encoding); err == nil {
+					content = string(data)
+				}
+			}
+			return rule.Requires.MatchString(content)
+		}
+		var active []EntryPointRule
+		for _, rule := range rules {
+			if (rule.Symbol != nil || rule.Pattern != nil) && applies(rule) {
+				active = append(active, rule)
+			}
+		}
+
+		if functions[file.Language] == nil {
+			functions[file.Language] = make(map[string][]*types.Symbol)
+		}
+		for _, symbol := range symbols {
+			if !isEntryPointCandidate(symbol) {
+				continue
+			}
+			functions[file.Language][symbol.Name] = append(functions[file.Language][symbol.Name], symbol)
+
+			for _, rule := range active {
+				if rule.Symbol != nil && rule.Symbol(symbol) {
+					kinds[symbol] = rule.Kind
+					break
+				}
+			}
+		}
+
+		for _, rule := range active {
+			if rule.Pattern == nil {
+				continue
+			}
+			if !loaded {
+				loaded = true
+				data, err := utils.ReadFileUTF8(file.Path, file.Encoding)
+				if err != nil {
+					break // Deleted since indexing
+				}
+				content = string(data)
+			}
+
+			for _, match := range rule.Pattern.FindAllStringSubmatch(content, -1) {
+				for _, name := range handlerNames(match[1]) {
+					registrations = append(registrations, registration{file: file, name: name, kind: rule.Kind})
+				}
+			}
+		}
+	}
+
+	for _, reg := range registrations {
+		qualifier, name := "", reg.name
+		if i := strings.LastIndex(name, "."); i >= 0 {
+			qualifier, name = name[:i], name[i+1:]
+		}
+		for _, symbol := range registeredHandlers(reg.file, qualifier, functions[reg.file.Language][name], paths) {
+			if _, ok := kinds[symbol]; !ok {
+				kinds[symbol] = reg.kind
+			}
+		}
+	}
+
+	entryPoints := make([]*types.Symbol, 0, len(kinds))
+	for symbol, kind := range kinds {
+		if symbol.Metadata == nil {
+			symbol.Metadata = make(map[string]interface{})
+		}
+		symbol.Metadata["entry_point"] = kind
+		entryPoints = append(entryPoints, symbol)
+	}
+
+	sort.Slice(entryPoints, func(i, j int) bool {
+		a, b := entryPoints[i], entryPoints[j]
+		if paths[a.FileID] != paths[b.FileID] {
+			return paths[a.FileID] < paths[b.FileID]
+		}
+		return a.StartLine < b.StartLine
+	})
+
+	return entryPoints, nil
+}
+
+// registeredHandlers narrows the functions a handler name registered in
+// file can refer to: a qualified name's functions in a directory named like
+// its qualifier (a package), otherwise those in the same file, failing that
+// those in the same directory
+func registeredHandlers(file *types.File, qualifier string, candidates []*types.Symbol, paths map[int64]string) []*types.Symbol {
+	dir := filepath.Dir(file.RelativePath)
+	var inPackage, sameFile, sameDir []*types.Symbol
+	for _, symbol := range candidates {
+		candidateDir := filepath.Dir(paths[symbol.FileID])
+		switch {
+		case qualifier != "" && filepath.Base(candidateDir) == qualifier:
+			inPackage = append(inPackage, symbol)
+		case symbol.FileID == file.ID:
+			sameFile = append(sameFile, symbol)
+		case candidateDir == dir:
+			sameDir = append(sameDir, symbol)
+		}
+	}
+
+	for _, scope := range [][]*types.Symbol{inPackage, sameFile, sameDir} {
+		if len(scope) > 0 {
+			return scope
+		}
+	}
+	return nil
+}
+
+// isEntryPointCandidate reports whether a symbol is a named function
+func isEntryPointCandidate(symbol *types.Symbol) bool {
+	switch symbol.Type {
+	case types.SymbolTypeFunction, types.SymbolTypeMethod:
+		// The Python parser records decorators as "@name" functions
+		return symbol.Name != "" && !strings.HasPrefix(symbol.Name, "@")
+	}
+	return false
+}
+
+// handlerNames extracts the identifiers from a pattern's submatch, e.g. a
+// registration's argument list, keeping qualified names (h.List) whole and
+// skipping literals and inline functions
+func handlerNames(args string) []string {
+	// Anything from an inline function on isn't a name
+	if i := strings.IndexAny(args, "({"); i >= 0 {
+		args = args[:i]
+	}
+
+	var names []string
+	for _, arg := range strings.Split(args, ",") {
+		arg = strings.TrimSpace(arg)
+		if handlerName.MatchString(arg) {
+			names = append(names, arg)
+		}
+	}
+	return names
+}
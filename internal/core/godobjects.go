@@ -0,0 +1,195 @@
+package core
+
+import (
+	"go/ast"
+	goparser "go/parser"
+	"go/token"
+	"path/filepath"
+	"sort"
+
+	"github.com/aaamil13/CodeIndexerMCP/pkg/types"
+)
+
+// Default thresholds for FindGodObjects
+const (
+	DefaultGodObjectMethods = 20
+	DefaultGodObjectFields  = 15
+)
+
+// FindGodObjects returns classes and structs whose method or field count
+// exceeds the given thresholds, largest first. Go methods are declared
+// outside their type, so they are counted by receiver type across the
+// package's files, and struct fields are counted from the source.
+func (idx *Indexer) FindGodObjects(methodThreshold, fieldThreshold int) ([]*types.GodObject, error) {
+	if methodThreshold <= 0 {
+		methodThreshold = DefaultGodObjectMethods
+	}
+	if fieldThreshold <= 0 {
+		fieldThreshold = DefaultGodObjectFields
+	}
+
+	files, err := idx.db.GetAllFilesForProject(idx.project.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	godObjects := []*types.GodObject{}
+	var goTypes []*types.GodObject
+	goMethods := make(map[string]int) // package directory and type name -> method count
+	for _, file := range files {
+		symbols, err := idx.db.GetSymbolsByFile(file.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		if file.Language == "go" {
+			members, err := goTypeMembers(file.Path)
+			if err != nil {
+				continue // Doesn't parse; the parser kept what it could
+			}
+			dir := filepath.Dir(file.RelativePath)
+			for name, count := range members.methods {
+				goMethods[dir+"\x00"+name] += count
+			}
+			for _, symbol := range symbols {
+				if isContainerSymbol(symbol) {
+					goTypes = append(goTypes, &types.GodObject{
+						Symbol:      symbol,
+						FilePath:    file.RelativePath,
+						MethodCount: members.interfaceMethods[symbol.Name],
+						FieldCount:  members.fields[symbol.Name],
+					})
+				}
+			}
+			continue
+		}
+
+		byID := make(map[int64]*types.Symbol, len(symbols))
+		for _, symbol := range symbols {
+			byID[symbol.ID] = symbol
+		}
+
+		counts := make(map[*types.Symbol]*types.GodObject)
+		for _, symbol := range symbols {
+			parent := findParentSymbol(symbol, symbols, byID)
+			if parent == nil || !isContainerSymbol(parent) {
+				continue
+			}
+
+			entry, ok := counts[parent]
+			if !ok {
+				entry = &types.GodObject{Symbol: parent, FilePath: file.RelativePath}
+				counts[parent] = entry
+			}
+
+			switch symbol.Type {
+			case types.SymbolTypeMethod, types.SymbolTypeFunction, types.SymbolTypeConstructor:
+				entry.MethodCount++
+			case types.SymbolTypeField, types.SymbolTypeProperty, types.SymbolTypeVariable, types.SymbolTypeConstant:
+				entry.FieldCount++
+			}
+		}
+
+		for _, entry := range counts {
+			if entry.MethodCount > methodThreshold || entry.FieldCount > fieldThreshold {
+				godObjects = append(godObjects, entry)
+			}
+		}
+	}
+
+	for _, entry := range goTypes {
+		entry.MethodCount += goMethods[filepath.Dir(entry.FilePath)+"\x00"+entry.Symbol.Name]
+		if entry.MethodCount > methodThreshold || entry.FieldCount > fieldThreshold {
+			godObjects = append(godObjects, entry)
+		}
+	}
+
+	sort.Slice(godObjects, func(i, j int) bool {
+		a, b := godObjects[i], godObjects[j]
+		if a.MethodCount+a.FieldCount != b.MethodCount+b.FieldCount {
+			return a.MethodCount+a.FieldCount > b.MethodCount+b.FieldCount
+		}
+		return a.Symbol.Name < b.Symbol.Name
+	})
+
+	return godObjects, nil
+}
+
+// goMembers counts the members of the types declared in a Go file
+type goMembers struct {
+	fields           map[string]int // Struct fields by struct name
+	interfaceMethods map[string]int // Methods listed in each interface
+	methods          map[string]int // Methods declared in the file by receiver type
+}
+
+// goTypeMembers parses a Go file and counts its struct fields, interface
+// methods and methods by receiver type. A field declaring several names
+// counts each, and an embedded type counts once.
+func goTypeMembers(path string) (*goMembers, error) {
+	fset := token.NewFileSet()
+	file, err := goparser.ParseFile(fset, path, nil, goparser.SkipObjectResolution)
+	if err != nil {
+		return nil, err
+	}
+
+	members := &goMembers{
+		fields:           make(map[string]int),
+		interfaceMethods: make(map[string]int),
+		methods:          make(map[string]int),
+	}
+	countNames := func(fields *ast.FieldList) int {
+		n := 0
+		for _, field := range fields.List {
+			n += max(len(field.Names), 1)
+		}
+		return n
+	}
+
+	for _, decl := range file.Decls {
+		switch d := decl.(type) {
+		case *ast.GenDecl:
+			for _, spec := range d.Specs {
+				ts, ok := spec.(*ast.TypeSpec)
+				if !ok {
+					continue
+				}
+				switch t := ts.Type.(type) {
+				case *ast.StructType:
+					members.fields[ts.Name.Name] = countNames(t.Fields)
+				case *ast.InterfaceType:
+					members.interfaceMethods[ts.Name.Name] = countNames(t.Methods)
+				}
+			}
+		case *ast.FuncDecl:
+			if d.Recv == nil || len(d.Recv.List) == 0 {
+				continue
+			}
+			if name := receiverTypeName(d.Recv.List[0].Type); name != "" {
+				members.methods[name]++
+			}
+		}
+	}
+
+	return members, nil
+}
+
+// receiverTypeName returns the type a method receiver belongs to, e.g.
+// Cache for *Cache[K, V]
+func receiverTypeName(expr ast.Expr) string {
+	for {
+		switch e := expr.(type) {
+		case *ast.StarExpr:
+			expr = e.X
+		case *ast.IndexExpr:
+			expr = e.X
+		case *ast.IndexListExpr:
+			expr = e.X
+		case *ast.ParenExpr:
+			expr = e.X
+		case *ast.Ident:
+			return e.Name
+		default:
+			return ""
+		}
+	}
+}
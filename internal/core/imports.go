@@ -0,0 +1,45 @@
+package core
+
+import (
+	"fmt"
+
+	"github.com/aaamil13/CodeIndexerMCP/internal/parser"
+	"github.com/aaamil13/CodeIndexerMCP/internal/utils"
+	"github.com/aaamil13/CodeIndexerMCP/pkg/types"
+)
+
+// GetImportUsage reports, for each import in a file, which of its members
+// the file uses. Named imports (from x import a, b / import { a, b }) are
+// checked name by name; module imports (Go packages, import os) by the
+// members accessed through their qualifier, e.g. fmt.Println.
+func (idx *Indexer) GetImportUsage(filePath string) ([]*types.ImportUsage, error) {
+	file, err := idx.lookupFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	imports, err := idx.db.GetImportsByFile(file.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	content, err := utils.ReadFileUTF8(file.Path, file.Encoding)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+
+	// Only code outside the import statements counts as usage
+	code := parser.CodeOutsideImports(string(content), imports)
+
+	usages := make([]*types.ImportUsage, 0, len(imports))
+	for _, imp := range imports {
+		members, known := parser.ImportMembers(imp, file.Language, code)
+		usages = append(usages, &types.ImportUsage{
+			Import:  imp,
+			Members: members,
+			Unused:  known && len(members) == 0,
+		})
+	}
+
+	return usages, nil
+}
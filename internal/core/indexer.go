@@ -1,10 +1,13 @@
 package core
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
-	"runtime"
+	"sort"
+	"strings"
 	"sync"
 	"time"
 
@@ -44,6 +47,10 @@ type Indexer struct {
 	logger           *utils.Logger
 	config           *Config
 	watcher          *Watcher
+	namingRules      map[string][]NamingRule     // Conventions checked by CheckNamingConventions
+	entryPointRules  map[string][]EntryPointRule // Detectors used by FindEntryPoints
+	parseCache       *parseCache                 // Nil when caching is disabled
+	lastStats        *types.IndexStats           // Phase timings of the last IndexAll
 	// AI helpers
 	contextExtractor *ai.ContextExtractor
 	impactAnalyzer   *ai.ImpactAnalyzer
@@ -53,29 +60,43 @@ type Indexer struct {
 	changeTracker    *ai.ChangeTracker
 	depGraphBuilder  *ai.DependencyGraphBuilder
 	typeValidator    *ai.TypeValidator
+	semanticAnalyzer *ai.SemanticAnalyzer
+	secretScanner    *ai.SecretScanner
+	antiPatterns     *ai.AntiPatternDetector
 }
 
 // Config holds indexer configuration
 type Config struct {
 	IndexDir    string   // Directory for index data (default: .projectIndex)
-	WorkerCount int      // Number of parallel workers (default: CPU count)
+	ProjectName string   // Name recorded for the project (default: base name of its path)
+	WorkerCount int      // Number of parallel indexing and analysis workers (default: CPU count)
 	BatchSize   int      // Batch size for database operations
 	Exclude     []string // Additional exclude patterns
+	Languages   []string // Only index these languages (default: all)
+	MaxFileSize int64    // Skip files larger than this many bytes (0: no limit)
+	BulkFTS     bool     // Disable FTS triggers during IndexAll and rebuild once at the end
+	SplitNames  bool     // Index camelCase/snake_case-split symbol names for search
+	IndexTests  bool     // Index test files (_test.go, test_*.py, *.test.ts, ...) (default: true)
+	SkipMarkup  bool     // Don't index HTML ids, classes and custom elements or CSS selectors
+
+	NamingRules map[string][]NamingRule // Naming conventions checked after the built-ins, keyed by language
+
+	ExtraParsers  []types.Parser // Out-of-tree parsers registered after the built-ins
+	ParseCacheDir string         // Parse result cache, relative to IndexDir (empty disables)
+	WatchDebounce time.Duration  // Quiet period before the watcher indexes changes (default: 300ms)
+	ToolTimeout   time.Duration  // Time limit for one MCP tool call (default: DefaultToolTimeout, 0: no limit)
+
+	ExtractMethodLines int // Function length above which extract-method refactorings are suggested (default: 40)
 }
 
-// NewIndexer creates a new indexer for the given project path
-func NewIndexer(projectPath string, cfg *Config) (*Indexer, error) {
+// NewParserRegistry returns a registry of the built-in parsers and the
+// configuration's extra parsers, the ones an indexer with that configuration
+// uses
+func NewParserRegistry(cfg *Config) (*parser.Registry, error) {
 	if cfg == nil {
-		cfg = &Config{
-			IndexDir:    ".projectIndex",
-			WorkerCount: runtime.NumCPU(),
-			BatchSize:   100,
-		}
+		cfg = DefaultConfig()
 	}
 
-	logger := utils.NewLogger("[Indexer]")
-
-	// Initialize parser registry
 	reg := parser.NewRegistry()
 
 	// Register all built-in parsers (23 languages)
@@ -140,12 +161,14 @@ func NewIndexer(projectPath string, cfg *Config) (*Indexer, error) {
 		return nil, fmt.Errorf("failed to register SQL parser: %w", err)
 	}
 
-	// Web markup and styling
-	if err := reg.Register(html.NewParser()); err != nil {
-		return nil, fmt.Errorf("failed to register HTML parser: %w", err)
-	}
-	if err := reg.Register(css.NewParser()); err != nil {
-		return nil, fmt.Errorf("failed to register CSS parser: %w", err)
+	// Web markup and styling, for projects that want them
+	if !cfg.SkipMarkup {
+		if err := reg.Register(html.NewParser()); err != nil {
+			return nil, fmt.Errorf("failed to register HTML parser: %w", err)
+		}
+		if err := reg.Register(css.NewParser()); err != nil {
+			return nil, fmt.Errorf("failed to register CSS parser: %w", err)
+		}
 	}
 
 	// Configuration files
@@ -170,18 +193,45 @@ func NewIndexer(projectPath string, cfg *Config) (*Indexer, error) {
 		return nil, fmt.Errorf("failed to register reStructuredText parser: %w", err)
 	}
 
+	// Consumer-supplied parsers
+	for _, p := range cfg.ExtraParsers {
+		if err := reg.Register(p); err != nil {
+			return nil, fmt.Errorf("failed to register %s parser: %w", p.Language(), err)
+		}
+	}
+
+	return reg, nil
+}
+
+// NewIndexer creates a new indexer for the given project path
+func NewIndexer(projectPath string, cfg *Config) (*Indexer, error) {
+	if cfg == nil {
+		cfg = DefaultConfig()
+	}
+
+	logger := utils.NewLogger("[Indexer]")
+
+	// Initialize parser registry
+	reg, err := NewParserRegistry(cfg)
+	if err != nil {
+		return nil, err
+	}
+
 	// Initialize ignore matcher
 	ignoreMatcher, err := utils.NewIgnoreMatcher(projectPath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create ignore matcher: %w", err)
 	}
+	ignoreMatcher.AddPatterns(cfg.Exclude...)
 
 	indexer := &Indexer{
-		projectPath:   projectPath,
-		parsers:       reg,
-		ignoreMatcher: ignoreMatcher,
-		logger:        logger,
-		config:        cfg,
+		projectPath:     projectPath,
+		parsers:         reg,
+		ignoreMatcher:   ignoreMatcher,
+		logger:          logger,
+		config:          cfg,
+		namingRules:     namingRules(cfg),
+		entryPointRules: defaultEntryPointRules(),
 	}
 
 	return indexer, nil
@@ -198,49 +248,62 @@ func (idx *Indexer) Initialize() error {
 	}
 
 	// Open database
-	dbPath := filepath.Join(indexDir, "index.db")
+	dbPath := filepath.Join(indexDir, DatabaseFile)
 	db, err := database.Open(dbPath)
 	if err != nil {
 		return fmt.Errorf("failed to open database: %w", err)
 	}
 	idx.db = db
+	idx.db.SetNameSplitting(idx.config.SplitNames)
 
-	// Get or create project
-	projectName := filepath.Base(idx.projectPath)
-	project, err := idx.db.GetProject(idx.projectPath)
-	if err != nil {
-		return fmt.Errorf("failed to get project: %w", err)
+	if idx.config.ParseCacheDir != "" {
+		cache, err := newParseCache(filepath.Join(indexDir, idx.config.ParseCacheDir))
+		if err != nil {
+			return fmt.Errorf("failed to create parse cache: %w", err)
+		}
+		idx.parseCache = cache
 	}
 
-	if project == nil {
-		// Create new project
-		project = &types.Project{
-			Path:          idx.projectPath,
-			Name:          projectName,
-			LanguageStats: make(map[string]int),
-			CreatedAt:     time.Now(),
-		}
+	// Get or create project in one statement so concurrent startups don't race
+	projectName := idx.config.ProjectName
+	if projectName == "" {
+		projectName = filepath.Base(idx.projectPath)
+	}
+	project, err := idx.db.GetOrCreateProject(idx.projectPath, projectName)
+	if err != nil {
+		return fmt.Errorf("failed to get or create project: %w", err)
+	}
 
-		if err := idx.db.CreateProject(project); err != nil {
-			return fmt.Errorf("failed to create project: %w", err)
+	// A name configured after the project was first indexed replaces the old one
+	if idx.config.ProjectName != "" && project.Name != idx.config.ProjectName {
+		project.Name = idx.config.ProjectName
+		if err := idx.db.UpdateProject(project); err != nil {
+			return fmt.Errorf("failed to rename project: %w", err)
 		}
-
-		idx.logger.Info("Created new project:", projectName)
-	} else {
-		idx.logger.Info("Loaded existing project:", projectName)
 	}
 
+	idx.logger.Info("Using project:", project.Name)
+
 	idx.project = project
 
 	// Initialize AI helpers
 	idx.contextExtractor = ai.NewContextExtractor(idx.db)
 	idx.impactAnalyzer = ai.NewImpactAnalyzer(idx.db)
+	if idx.config.ExtractMethodLines > 0 {
+		idx.impactAnalyzer.SetExtractMethodThreshold(idx.config.ExtractMethodLines)
+	}
 	idx.metricsCalc = ai.NewMetricsCalculator(idx.db)
 	idx.snippetExtractor = ai.NewSnippetExtractor(idx.db)
 	idx.usageAnalyzer = ai.NewUsageAnalyzer(idx.db)
 	idx.changeTracker = ai.NewChangeTracker(idx.db)
 	idx.depGraphBuilder = ai.NewDependencyGraphBuilder(idx.db)
 	idx.typeValidator = ai.NewTypeValidator(idx.db)
+	idx.semanticAnalyzer = ai.NewSemanticAnalyzer(idx.db)
+	if idx.config.WorkerCount > 0 {
+		idx.semanticAnalyzer.SetWorkerCount(idx.config.WorkerCount)
+	}
+	idx.secretScanner = ai.NewSecretScanner(idx.db)
+	idx.antiPatterns = ai.NewAntiPatternDetector(idx.db)
 
 	return nil
 }
@@ -255,41 +318,370 @@ func (idx *Indexer) Close() error {
 
 // IndexAll indexes all files in the project
 func (idx *Indexer) IndexAll() error {
+	return idx.IndexAllContext(context.Background())
+}
+
+// IndexAllContext indexes all files in the project, stopping between files
+// once ctx is cancelled and returning its error. Files indexed before then
+// stay committed, but the project isn't marked indexed so the next run
+// picks up where this one stopped.
+func (idx *Indexer) IndexAllContext(ctx context.Context) error {
 	idx.logger.Info("Starting full index of project")
 	startTime := time.Now()
+	timer := newIndexTimer()
 
 	// Scan for files
 	files, err := idx.scanFiles()
 	if err != nil {
 		return fmt.Errorf("failed to scan files: %w", err)
 	}
+	timer.scan(startTime)
 
 	idx.logger.Infof("Found %d files to index", len(files))
 
+	// Skip per-row FTS triggers during the bulk load
+	if idx.config.BulkFTS {
+		writeStart := time.Now()
+		if err := idx.db.BeginBulkFTS(); err != nil {
+			return err
+		}
+		timer.write(writeStart)
+	}
+
 	// Index files concurrently
-	if err := idx.indexFiles(files); err != nil {
-		return fmt.Errorf("failed to index files: %w", err)
+	indexErr := idx.indexFiles(ctx, files, timer)
+
+	// Restore triggers for incremental updates even if indexing failed
+	writeStart := time.Now()
+	if idx.config.BulkFTS {
+		if err := idx.db.EndBulkFTS(); err != nil {
+			return err
+		}
+	}
+
+	if ctx.Err() != nil {
+		idx.logger.Info("Indexing cancelled")
+		return ctx.Err()
+	}
+	if indexErr != nil {
+		return fmt.Errorf("failed to index files: %w", indexErr)
+	}
+
+	// Drop files that were deleted, or that the configuration now leaves
+	// out, before linking so nothing links to them
+	if err := idx.pruneUnscannedFiles(files); err != nil {
+		return err
+	}
+	timer.write(writeStart)
+
+	// Files are indexed concurrently, so tests, bindings, foreign keys and
+	// dependencies are linked once every target exists
+	linkStart := time.Now()
+	if err := idx.linkAcrossFiles(); err != nil {
+		return err
+	}
+	if err := idx.linkDependencies(nil); err != nil {
+		return fmt.Errorf("failed to link dependencies: %w", err)
+	}
+	timer.link(linkStart)
+	writeStart = time.Now()
+
+	// Every file has been seen, so cached results nothing refers to can go
+	if err := idx.pruneParseCache(); err != nil {
+		idx.logger.Warnf("Failed to prune parse cache: %v", err)
 	}
 
 	// Update project stats
+	if err := idx.refreshLanguageStats(); err != nil {
+		return err
+	}
 	idx.project.LastIndexed = time.Now()
 	if err := idx.db.UpdateProject(idx.project); err != nil {
 		return fmt.Errorf("failed to update project: %w", err)
 	}
+	timer.write(writeStart)
+
+	idx.lastStats = timer.finish(startTime, len(files))
+	idx.logger.Infof("Indexing completed in %v", idx.lastStats.Total)
+
+	return nil
+}
+
+// IndexChangedSince indexes only files modified after since, skipping the
+// hash and parse for older files, and returns the number of files indexed.
+// Its phase timings are kept for LastIndexStats like those of IndexAll.
+func (idx *Indexer) IndexChangedSince(since time.Time) (int, error) {
+	startTime := time.Now()
+	timer := newIndexTimer()
+
+	files, err := idx.scanFiles()
+	if err != nil {
+		return 0, fmt.Errorf("failed to scan files: %w", err)
+	}
+
+	var changed []string
+	for _, path := range files {
+		info, err := os.Stat(path)
+		if err != nil {
+			continue // Removed during the scan
+		}
+		if info.ModTime().After(since) {
+			changed = append(changed, path)
+		}
+	}
+	timer.scan(startTime)
+
+	idx.logger.Infof("Found %d of %d files modified since %s", len(changed), len(files), since.Format(time.RFC3339))
+
+	if len(changed) > 0 {
+		if err := idx.indexFiles(context.Background(), changed, timer); err != nil {
+			return 0, fmt.Errorf("failed to index files: %w", err)
+		}
+		linkStart := time.Now()
+		if err := idx.linkAcrossFiles(); err != nil {
+			return 0, err
+		}
+		if err := idx.linkDependencies(nil); err != nil {
+			return 0, fmt.Errorf("failed to link dependencies: %w", err)
+		}
+		timer.link(linkStart)
+	}
+
+	writeStart := time.Now()
+	if err := idx.refreshLanguageStats(); err != nil {
+		return 0, err
+	}
+	idx.project.LastIndexed = time.Now()
+	if err := idx.db.UpdateProject(idx.project); err != nil {
+		return 0, fmt.Errorf("failed to update project: %w", err)
+	}
+	timer.write(writeStart)
+
+	idx.lastStats = timer.finish(startTime, len(changed))
+	return len(changed), nil
+}
+
+// RemovePath removes a deleted file, or every file under a deleted
+// directory, from the index and returns the number of files removed. Their
+// symbols, imports and references are deleted with them.
+func (idx *Indexer) RemovePath(path string) (int, error) {
+	relPath, err := idx.resolveFilePath(path)
+	if err != nil {
+		return 0, err
+	}
+
+	var removed int64
+	file, err := idx.db.GetFileByPath(idx.project.ID, relPath)
+	if err != nil {
+		return 0, err
+	}
+	if file != nil {
+		if err := idx.db.DeleteFile(file.ID); err != nil {
+			return 0, err
+		}
+		removed = 1
+	} else {
+		// Not an indexed file, so possibly a directory, or a file the parser
+		// failed on that only has parse errors recorded
+		if err := idx.db.ReplaceParseErrors(idx.project.ID, relPath, nil); err != nil {
+			return 0, err
+		}
+		if removed, err = idx.db.DeleteFilesWithPrefix(idx.project.ID, relPath+string(filepath.Separator)); err != nil {
+			return 0, err
+		}
+	}
+
+	if removed == 0 {
+		return 0, nil
+	}
+
+	if err := idx.refreshLanguageStats(); err != nil {
+		return 0, err
+	}
+	if err := idx.db.UpdateProject(idx.project); err != nil {
+		return 0, fmt.Errorf("failed to update project: %w", err)
+	}
+
+	return int(removed), nil
+}
+
+// pruneUnscannedFiles removes indexed files that are missing from a full
+// scan's results, either because they were deleted or because the
+// configuration excludes them now
+func (idx *Indexer) pruneUnscannedFiles(scanned []string) error {
+	indexed, err := idx.db.GetAllFilesForProject(idx.project.ID)
+	if err != nil {
+		return err
+	}
+
+	seen := make(map[string]bool, len(scanned))
+	for _, path := range scanned {
+		seen[path] = true
+	}
+
+	for _, file := range indexed {
+		if seen[file.Path] {
+			continue
+		}
+		if err := idx.db.DeleteFile(file.ID); err != nil {
+			return fmt.Errorf("failed to remove %s: %w", file.RelativePath, err)
+		}
+		idx.logger.Debugf("Removed %s from the index", file.RelativePath)
+	}
 
-	duration := time.Since(startTime)
-	idx.logger.Infof("Indexing completed in %v", duration)
+	return nil
+}
 
+// refreshLanguageStats recounts the project's files per language
+func (idx *Indexer) refreshLanguageStats() error {
+	stats, err := idx.db.CountFilesByLanguage(idx.project.ID)
+	if err != nil {
+		return fmt.Errorf("failed to count files by language: %w", err)
+	}
+	idx.project.LanguageStats = stats
 	return nil
 }
 
+// LastIndexed returns when the project was last fully or incrementally indexed
+func (idx *Indexer) LastIndexed() time.Time {
+	return idx.project.LastIndexed
+}
+
+// NeedsReindex reports whether the index may be out of date: the project
+// has never been indexed, a file was modified after the last index, or
+// files were added or removed since. Files the last index recorded parse
+// errors for, such as skipped binaries, count as seen.
+func (idx *Indexer) NeedsReindex() (bool, error) {
+	if idx.project.LastIndexed.IsZero() {
+		return true, nil
+	}
+
+	files, err := idx.scanFiles()
+	if err != nil {
+		return false, fmt.Errorf("failed to scan files: %w", err)
+	}
+	scanned := make(map[string]bool, len(files))
+	for _, path := range files {
+		scanned[path] = true
+	}
+
+	indexed, err := idx.db.GetAllFilesForProject(idx.project.ID)
+	if err != nil {
+		return false, err
+	}
+	known := make(map[string]bool, len(indexed))
+	for _, file := range indexed {
+		if !scanned[file.Path] {
+			return true, nil // Removed, or excluded by the configuration
+		}
+		known[file.Path] = true
+	}
+
+	parseErrors, err := idx.db.GetParseErrors(idx.project.ID)
+	if err != nil {
+		return false, err
+	}
+	for _, pe := range parseErrors {
+		known[filepath.Join(idx.projectPath, pe.FilePath)] = true
+	}
+
+	for _, path := range files {
+		if !known[path] {
+			return true, nil
+		}
+		info, err := os.Stat(path)
+		if err != nil {
+			return true, nil // Removed during the scan
+		}
+		if info.ModTime().After(idx.project.LastIndexed) {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
 // IndexFile indexes a single file
 func (idx *Indexer) IndexFile(filePath string) error {
+	return idx.IndexFiles([]string{filePath})
+}
+
+// IndexFiles indexes a batch of files, then relinks the relationships across
+// files once for the whole batch. A file that fails to index doesn't stop
+// the rest; the errors of every failed file are returned joined.
+func (idx *Indexer) IndexFiles(filePaths []string) error {
+	var indexErrs []error
+	changed := make(map[int64]bool)
+	for _, filePath := range filePaths {
+		fileID, err := idx.indexChangedFile(filePath)
+		if err != nil {
+			indexErrs = append(indexErrs, fmt.Errorf("%s: %w", filePath, err))
+			continue
+		}
+		if fileID != 0 {
+			changed[fileID] = true
+		}
+	}
+
+	if len(changed) > 0 {
+		// Re-indexing replaced the files' symbols, and the cascade took the
+		// relationships other files had to them along with the old rows
+		if err := idx.linkAcrossFiles(); err != nil {
+			return err
+		}
+		if err := idx.linkDependencies(changed); err != nil {
+			return fmt.Errorf("failed to link dependencies: %w", err)
+		}
+	}
+
+	return errors.Join(indexErrs...)
+}
+
+// indexChangedFile indexes a single file and returns its ID, or 0 if the
+// file is ignored or unsupported and so isn't in the index
+func (idx *Indexer) indexChangedFile(filePath string) (int64, error) {
+	if err := idx.indexFile(filePath, idx.logger, nil); err != nil {
+		return 0, err
+	}
+
+	relPath, err := idx.resolveFilePath(filePath)
+	if err != nil {
+		return 0, err
+	}
+	file, err := idx.db.GetFileByPath(idx.project.ID, relPath)
+	if err != nil {
+		return 0, err
+	}
+	if file == nil {
+		return 0, nil
+	}
+	return file.ID, nil
+}
+
+// linkAcrossFiles records the relationships whose ends may be in different
+// files: tests, cross-language bindings and foreign keys
+func (idx *Indexer) linkAcrossFiles() error {
+	if err := idx.linkAllTests(); err != nil {
+		return fmt.Errorf("failed to link tests: %w", err)
+	}
+	if err := idx.linkBindings(); err != nil {
+		return fmt.Errorf("failed to link cross-language bindings: %w", err)
+	}
+	if err := idx.linkForeignKeys(); err != nil {
+		return fmt.Errorf("failed to link foreign keys: %w", err)
+	}
+	return nil
+}
+
+// indexFile indexes a single file without linking it to other files,
+// logging to logger and adding the time of each phase to timer
+func (idx *Indexer) indexFile(filePath string, logger *utils.Logger, timer *indexTimer) error {
 	// Make path relative to project
-	relPath, err := filepath.Rel(idx.projectPath, filePath)
+	relPath, err := idx.resolveFilePath(filePath)
 	if err != nil {
 		return err
 	}
+	filePath = filepath.Join(idx.projectPath, relPath)
 
 	// Check if should ignore
 	if idx.ignoreMatcher.ShouldIgnore(relPath) {
@@ -301,7 +693,8 @@ func (idx *Indexer) IndexFile(filePath string) error {
 		return nil // Skip unsupported files silently
 	}
 
-	idx.logger.Debugf("Indexing file: %s", relPath)
+	logger.Debugf("Indexing file: %s", relPath)
+	hashStart := time.Now()
 
 	// Get file info
 	fileInfo, err := os.Stat(filePath)
@@ -324,11 +717,35 @@ func (idx *Indexer) IndexFile(filePath string) error {
 		return err
 	}
 
+	timer.hash(hashStart)
+
 	if existingFile != nil && existingFile.Hash == hash {
 		// File hasn't changed, skip
-		idx.logger.Debugf("File unchanged, skipping: %s", relPath)
+		logger.Debugf("File unchanged, skipping: %s", relPath)
+		return nil
+	}
+
+	// Parsers expect UTF-8: transcode other text encodings and leave binary
+	// files out of the index
+	parseStart := time.Now()
+	encoding := utils.DetectEncoding(content)
+	if encoding == utils.EncodingBinary {
+		logger.Warnf("Skipping binary file: %s", relPath)
+		defer timer.write(time.Now())
+		if existingFile != nil {
+			if err := idx.db.DeleteFile(existingFile.ID); err != nil {
+				return err
+			}
+		}
+		skipped := []*types.FileParseError{{Message: "binary file skipped"}}
+		if err := idx.db.ReplaceParseErrors(idx.project.ID, relPath, skipped); err != nil {
+			logger.Warnf("Failed to record binary file %s: %v", relPath, err)
+		}
 		return nil
 	}
+	if content, err = utils.ToUTF8(content, encoding); err != nil {
+		return err
+	}
 
 	// Parse file
 	parser, err := idx.parsers.GetParserForFile(filePath)
@@ -336,36 +753,62 @@ func (idx *Indexer) IndexFile(filePath string) error {
 		return err
 	}
 
-	parseResult, err := parser.Parse(content, filePath)
-	if err != nil {
-		idx.logger.Warnf("Failed to parse %s: %v", relPath, err)
-		return nil // Don't fail on parse errors
+	// Content seen before (e.g. the database was rebuilt) needn't be re-parsed
+	parseResult, cached := idx.cachedParseResult(parser, hash)
+	if !cached {
+		parseResult, err = parser.Parse(content, filePath)
+		if err != nil {
+			timer.parse(parseStart, parser.Language())
+			defer timer.write(time.Now())
+			logger.Warnf("Failed to parse %s: %v", relPath, err)
+			// Record the failure so it isn't mistaken for an empty file
+			failure := []*types.FileParseError{{Message: err.Error()}}
+			if err := idx.db.ReplaceParseErrors(idx.project.ID, relPath, failure); err != nil {
+				logger.Warnf("Failed to record parse error for %s: %v", relPath, err)
+			}
+			return nil // Don't fail on parse errors
+		}
+		idx.cacheParseResult(parser, hash, parseResult)
 	}
 
+	// Not every parser sets visibility; infer it from language conventions
+	backfillVisibility(parser.Language(), parseResult.Symbols)
+	timer.parse(parseStart, parser.Language())
+
 	// Count lines
+	hashStart = time.Now()
 	lines, _ := utils.CountLines(filePath)
+	timer.hash(hashStart)
+	defer timer.write(time.Now())
+
+	file := &types.File{
+		ProjectID:    idx.project.ID,
+		Path:         filePath,
+		RelativePath: relPath,
+		Language:     parser.Language(),
+		Size:         fileInfo.Size(),
+		LinesOfCode:  lines,
+		Hash:         hash,
+		LastModified: fileInfo.ModTime(),
+		LastIndexed:  time.Now(),
+		Encoding:     encoding,
+	}
 
 	// Save to database in transaction
 	err = idx.db.Transaction(func(tx *database.DB) error {
 		// Save file
-		file := &types.File{
-			ProjectID:    idx.project.ID,
-			Path:         filePath,
-			RelativePath: relPath,
-			Language:     parser.Language(),
-			Size:         fileInfo.Size(),
-			LinesOfCode:  lines,
-			Hash:         hash,
-			LastModified: fileInfo.ModTime(),
-			LastIndexed:  time.Now(),
-		}
-
 		if err := idx.db.SaveFile(file); err != nil {
 			return err
 		}
 
-		// Delete old symbols/imports for this file
+		// Delete old symbols/imports for this file, keeping any workflow
+		// state so it survives the re-index
+		var tracked []*types.TrackedSymbol
 		if existingFile != nil {
+			var err error
+			if tracked, err = idx.db.GetTrackedSymbolsByFile(file.ID); err != nil {
+				return err
+			}
 			idx.db.DeleteSymbolsByFile(file.ID)
 			idx.db.DeleteImportsByFile(file.ID)
 		}
@@ -378,6 +821,10 @@ func (idx *Indexer) IndexFile(filePath string) error {
 			}
 		}
 
+		if err := idx.restoreTracking(tracked, parseResult.Symbols); err != nil {
+			return err
+		}
+
 		// Save imports
 		for _, imp := range parseResult.Imports {
 			imp.FileID = file.ID
@@ -400,7 +847,27 @@ func (idx *Indexer) IndexFile(filePath string) error {
 		return fmt.Errorf("failed to save parse results: %w", err)
 	}
 
-	idx.logger.Debugf("Indexed file: %s (%d symbols, %d imports)",
+	// Record partial-parse diagnostics, clearing any from previous runs
+	parseErrors := make([]*types.FileParseError, 0, len(parseResult.Errors))
+	for _, pe := range parseResult.Errors {
+		parseErrors = append(parseErrors, &types.FileParseError{
+			Line:             pe.Line,
+			Column:           pe.Column,
+			Message:          pe.Message,
+			SymbolsRecovered: len(parseResult.Symbols),
+			Recovered:        true,
+		})
+	}
+	if err := idx.db.ReplaceParseErrors(idx.project.ID, relPath, parseErrors); err != nil {
+		return fmt.Errorf("failed to record parse errors: %w", err)
+	}
+
+	// Keep a history of how each function has changed
+	if err := idx.recordSymbolVersions(file, parseResult.Symbols, content); err != nil {
+		return fmt.Errorf("failed to record symbol versions: %w", err)
+	}
+
+	logger.Debugf("Indexed file: %s (%d symbols, %d imports)",
 		relPath, len(parseResult.Symbols), len(parseResult.Imports))
 
 	return nil
@@ -432,7 +899,7 @@ func (idx *Indexer) scanFiles() ([]string, error) {
 		}
 
 		// Check if we can parse this file
-		if idx.parsers.CanParse(path) {
+		if idx.parsers.CanParse(path) && !idx.skipFile(path, relPath) {
 			files = append(files, path)
 		}
 
@@ -442,8 +909,9 @@ func (idx *Indexer) scanFiles() ([]string, error) {
 	return files, err
 }
 
-// indexFiles indexes multiple files concurrently
-func (idx *Indexer) indexFiles(files []string) error {
+// indexFiles indexes multiple files concurrently, timing them with timer if
+// not nil. Files not yet started when ctx is cancelled are skipped.
+func (idx *Indexer) indexFiles(ctx context.Context, files []string, timer *indexTimer) error {
 	numWorkers := idx.config.WorkerCount
 	jobs := make(chan string, len(files))
 	errors := make(chan error, len(files))
@@ -452,10 +920,14 @@ func (idx *Indexer) indexFiles(files []string) error {
 	// Start workers
 	for w := 0; w < numWorkers; w++ {
 		wg.Add(1)
+		logger := idx.logger.WithWorker(w)
 		go func() {
 			defer wg.Done()
 			for filePath := range jobs {
-				if err := idx.IndexFile(filePath); err != nil {
+				if ctx.Err() != nil {
+					continue // Drain the remaining jobs
+				}
+				if err := idx.indexFile(filePath, logger, timer); err != nil {
 					errors <- fmt.Errorf("failed to index %s: %w", filePath, err)
 				}
 			}
@@ -486,14 +958,117 @@ func (idx *Indexer) indexFiles(files []string) error {
 	return nil
 }
 
+// backfillVisibility fills in visibility for symbols the parser left unset
+func backfillVisibility(language string, symbols []*types.Symbol) {
+	for _, symbol := range symbols {
+		if symbol.Visibility == "" {
+			symbol.Visibility = parser.DetermineVisibility(language, symbol.Name, symbol.Signature)
+		}
+	}
+}
+
 // SearchSymbols searches for symbols
 func (idx *Indexer) SearchSymbols(opts types.SearchOptions) ([]*types.Symbol, error) {
+	if err := checkSearchRanking(opts.Ranking); err != nil {
+		return nil, err
+	}
+	opts.Language = parser.CanonicalLanguage(opts.Language)
+	opts.ProjectID = idx.project.ID
+
 	return idx.db.SearchSymbols(opts)
 }
 
-// GetFileStructure returns the structure of a file
-func (idx *Indexer) GetFileStructure(filePath string) (*types.FileStructure, error) {
-	relPath, err := filepath.Rel(idx.projectPath, filePath)
+// SearchSymbolsStream searches for symbols, passing each to fn as it is read
+// rather than collecting them; an error from fn stops the search
+func (idx *Indexer) SearchSymbolsStream(opts types.SearchOptions, fn func(*types.Symbol) error) error {
+	if err := checkSearchRanking(opts.Ranking); err != nil {
+		return err
+	}
+	opts.Language = parser.CanonicalLanguage(opts.Language)
+	opts.ProjectID = idx.project.ID
+
+	return idx.db.SearchSymbolsStream(opts, fn)
+}
+
+// checkSearchRanking rejects unknown search result orders
+func checkSearchRanking(ranking types.SearchRanking) error {
+	switch ranking {
+	case "", types.SearchRankingRelevance, types.SearchRankingName:
+		return nil
+	}
+	return fmt.Errorf("unknown search ranking: %s", ranking)
+}
+
+// QuerySymbols finds symbols matching structural predicates such as kind,
+// visibility, length, documentation and file path
+func (idx *Indexer) QuerySymbols(filter *types.SymbolFilter) ([]*types.Symbol, error) {
+	if filter == nil {
+		filter = &types.SymbolFilter{}
+	}
+	if filter.MinLines < 0 || filter.MaxLines < 0 {
+		return nil, fmt.Errorf("line bounds must not be negative")
+	}
+	if filter.MaxLines > 0 && filter.MinLines > filter.MaxLines {
+		return nil, fmt.Errorf("min_lines (%d) is greater than max_lines (%d)", filter.MinLines, filter.MaxLines)
+	}
+
+	return idx.db.QuerySymbols(idx.project.ID, filter)
+}
+
+// SearchSymbolsInFile searches for symbols defined in a single file
+func (idx *Indexer) SearchSymbolsInFile(filePath, query string) ([]*types.Symbol, error) {
+	file, err := idx.lookupFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	return idx.db.SearchSymbols(types.SearchOptions{
+		Query:  query,
+		FileID: file.ID,
+	})
+}
+
+// resolveFilePath converts an absolute, cwd-relative or project-relative path
+// into the canonical project-relative path used as the database key
+func (idx *Indexer) resolveFilePath(filePath string) (string, error) {
+	cleaned := filepath.Clean(filePath)
+
+	if !filepath.IsAbs(cleaned) {
+		// Prefer the project-relative reading when it names an existing file
+		if _, err := os.Stat(filepath.Join(idx.projectPath, cleaned)); err == nil {
+			return cleaned, nil
+		}
+
+		abs, err := filepath.Abs(cleaned)
+		if err != nil {
+			return "", err
+		}
+		if _, err := os.Stat(abs); err != nil {
+			// Possibly deleted since indexing; treat as project-relative
+			return idx.checkInProject(filePath, cleaned)
+		}
+		cleaned = abs
+	}
+
+	relPath, err := filepath.Rel(idx.projectPath, cleaned)
+	if err != nil {
+		return "", err
+	}
+
+	return idx.checkInProject(filePath, relPath)
+}
+
+// checkInProject rejects relative paths that escape the project directory
+func (idx *Indexer) checkInProject(filePath, relPath string) (string, error) {
+	if relPath == ".." || strings.HasPrefix(relPath, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("path is outside the project: %s", filePath)
+	}
+	return relPath, nil
+}
+
+// lookupFile resolves a path and returns its indexed file record
+func (idx *Indexer) lookupFile(filePath string) (*types.File, error) {
+	relPath, err := idx.resolveFilePath(filePath)
 	if err != nil {
 		return nil, err
 	}
@@ -506,6 +1081,21 @@ func (idx *Indexer) GetFileStructure(filePath string) (*types.FileStructure, err
 		return nil, fmt.Errorf("file not found: %s", relPath)
 	}
 
+	return file, nil
+}
+
+// GetFileStructure returns the structure of a file in source order
+func (idx *Indexer) GetFileStructure(filePath string) (*types.FileStructure, error) {
+	return idx.GetFileStructureWithOptions(filePath, types.FileStructureOptions{})
+}
+
+// GetFileStructureWithOptions returns the structure of a file with sorted and optionally nested symbols
+func (idx *Indexer) GetFileStructureWithOptions(filePath string, opts types.FileStructureOptions) (*types.FileStructure, error) {
+	file, err := idx.lookupFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+
 	symbols, err := idx.db.GetSymbolsByFile(file.ID)
 	if err != nil {
 		return nil, err
@@ -516,25 +1106,59 @@ func (idx *Indexer) GetFileStructure(filePath string) (*types.FileStructure, err
 		return nil, err
 	}
 
-	return &types.FileStructure{
-		FilePath: filePath,
+	if err := sortSymbols(symbols, opts.Order); err != nil {
+		return nil, err
+	}
+
+	structure := &types.FileStructure{
+		FilePath: file.RelativePath,
 		Language: file.Language,
 		Symbols:  symbols,
 		Imports:  imports,
-	}, nil
+	}
+
+	if opts.Nested {
+		structure.Tree = NestSymbols(symbols)
+	}
+
+	return structure, nil
+}
+
+// GetSymbolsInRange returns symbols in a file overlapping the given lines
+func (idx *Indexer) GetSymbolsInRange(filePath string, startLine, endLine int) ([]*types.SymbolInRange, error) {
+	if startLine > endLine {
+		return nil, fmt.Errorf("invalid range: start line %d is after end line %d", startLine, endLine)
+	}
+
+	file, err := idx.lookupFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	return idx.db.GetSymbolsInRange(file.ID, startLine, endLine)
 }
 
 // GetProjectOverview returns project overview
 func (idx *Indexer) GetProjectOverview() (*types.ProjectOverview, error) {
-	stats, err := idx.db.Stats()
+	return idx.GetProjectOverviewWithOptions(types.ProjectOverviewOptions{})
+}
+
+// GetProjectOverviewWithOptions returns project statistics. By default only
+// aggregate counts are queried; Detailed walks every file as well.
+func (idx *Indexer) GetProjectOverviewWithOptions(opts types.ProjectOverviewOptions) (*types.ProjectOverview, error) {
+	if opts.Detailed {
+		return idx.detailedProjectOverview()
+	}
+
+	files, symbols, err := idx.db.CountProjectTotals(idx.project.ID)
 	if err != nil {
 		return nil, err
 	}
 
 	return &types.ProjectOverview{
 		Project:       idx.project,
-		TotalFiles:    stats["files"],
-		TotalSymbols:  stats["symbols"],
+		TotalFiles:    files,
+		TotalSymbols:  symbols,
 		LanguageStats: idx.project.LanguageStats,
 	}, nil
 }
@@ -571,6 +1195,18 @@ func (idx *Indexer) StopWatch() error {
 	return err
 }
 
+// GetSignatures returns the signature of every symbol with the given name
+func (idx *Indexer) GetSignatures(symbolName string) ([]*types.SymbolSignature, error) {
+	signatures, err := idx.db.GetSignaturesByName(idx.project.ID, symbolName)
+	if err != nil {
+		return nil, err
+	}
+	if len(signatures) == 0 {
+		return nil, fmt.Errorf("symbol not found: %s", symbolName)
+	}
+	return signatures, nil
+}
+
 // GetSymbolDetails returns detailed information about a symbol
 func (idx *Indexer) GetSymbolDetails(symbolName string) (*types.SymbolDetails, error) {
 	symbol, err := idx.db.GetSymbolByName(symbolName)
@@ -581,6 +1217,78 @@ func (idx *Indexer) GetSymbolDetails(symbolName string) (*types.SymbolDetails, e
 		return nil, fmt.Errorf("symbol not found: %s", symbolName)
 	}
 
+	return idx.symbolDetails(symbol)
+}
+
+// MaxBulkSymbols is the most names BulkGetSymbols accepts in one call
+const MaxBulkSymbols = 100
+
+// BulkGetSymbols gets detailed information about several symbols at once,
+// keyed by name. Names that aren't defined map to nil; a name defined more
+// than once maps to its first definition, as with GetSymbolDetails.
+func (idx *Indexer) BulkGetSymbols(names []string) (map[string]*types.SymbolDetails, error) {
+	if len(names) > MaxBulkSymbols {
+		return nil, fmt.Errorf("too many symbols requested: %d (limit %d)", len(names), MaxBulkSymbols)
+	}
+
+	details := make(map[string]*types.SymbolDetails, len(names))
+	var unique []string
+	for _, name := range names {
+		if _, ok := details[name]; !ok {
+			details[name] = nil
+			unique = append(unique, name)
+		}
+	}
+
+	symbols, err := idx.db.GetSymbolsByNames(idx.project.ID, unique)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, symbol := range symbols {
+		if details[symbol.Name] != nil {
+			continue
+		}
+		if details[symbol.Name], err = idx.symbolDetails(symbol); err != nil {
+			return nil, err
+		}
+	}
+
+	return details, nil
+}
+
+// GetSymbolDetailsByID gets detailed information about the symbol with the
+// given ID, which unlike a name always identifies a single symbol. IDs are
+// row IDs: re-indexing a file gives its symbols new ones and old IDs are
+// never reused, so a stale ID is reported as not found.
+func (idx *Indexer) GetSymbolDetailsByID(id int64) (*types.SymbolDetails, error) {
+	symbol, err := idx.db.GetSymbolByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if symbol == nil {
+		return nil, fmt.Errorf("symbol not found: %d (its file may have been re-indexed since)", id)
+	}
+
+	return idx.symbolDetails(symbol)
+}
+
+// GetFunctionDetails returns the parsed parameters and return type of the
+// named function, or nil if its parser doesn't extract them
+func (idx *Indexer) GetFunctionDetails(symbolName string) (*types.FunctionDetails, error) {
+	symbol, err := idx.db.GetSymbolByName(symbolName)
+	if err != nil {
+		return nil, err
+	}
+	if symbol == nil {
+		return nil, fmt.Errorf("symbol not found: %s", symbolName)
+	}
+
+	return idx.db.GetFunctionDetails(symbol.ID)
+}
+
+// symbolDetails gathers the file, references and relationships of a symbol
+func (idx *Indexer) symbolDetails(symbol *types.Symbol) (*types.SymbolDetails, error) {
 	file, err := idx.db.GetFile(symbol.FileID)
 	if err != nil {
 		return nil, err
@@ -618,41 +1326,204 @@ func (idx *Indexer) FindReferences(symbolName string) ([]*types.Reference, error
 	return idx.db.GetReferencesBySymbol(symbol.ID)
 }
 
-// GetDependencies returns dependencies for a file
-func (idx *Indexer) GetDependencies(filePath string) (*types.DependencyGraph, error) {
-	relPath, err := filepath.Rel(idx.projectPath, filePath)
+// GetReferencesGrouped returns the references to a symbol keyed by the
+// relative path of the file they're in, each file's ordered by position
+func (idx *Indexer) GetReferencesGrouped(name string) (map[string][]*types.Reference, error) {
+	references, err := idx.FindReferences(name)
 	if err != nil {
 		return nil, err
 	}
 
-	file, err := idx.db.GetFileByPath(idx.project.ID, relPath)
+	paths := make(map[int64]string)
+	grouped := make(map[string][]*types.Reference)
+	for _, ref := range references {
+		path, ok := paths[ref.FileID]
+		if !ok {
+			file, err := idx.db.GetFile(ref.FileID)
+			if err != nil {
+				return nil, err
+			}
+			if file != nil {
+				path = file.RelativePath
+			}
+			paths[ref.FileID] = path
+		}
+		grouped[path] = append(grouped[path], ref)
+	}
+
+	for _, refs := range grouped {
+		sort.Slice(refs, func(i, j int) bool {
+			if refs[i].LineNumber != refs[j].LineNumber {
+				return refs[i].LineNumber < refs[j].LineNumber
+			}
+			return refs[i].ColumnNumber < refs[j].ColumnNumber
+		})
+	}
+
+	return grouped, nil
+}
+
+// GetFilesTouchingSymbol returns every file defining or referencing the
+// named symbol, once each: the defining files first, then the referencing
+// files ordered by path
+func (idx *Indexer) GetFilesTouchingSymbol(name string) ([]*types.File, error) {
+	symbols, err := idx.db.GetSymbolsByName(idx.project.ID, name)
 	if err != nil {
 		return nil, err
 	}
-	if file == nil {
-		return nil, fmt.Errorf("file not found: %s", relPath)
+	if len(symbols) == 0 {
+		return nil, fmt.Errorf("symbol not found: %s", name)
 	}
 
-	imports, err := idx.db.GetImportsByFile(file.ID)
+	seen := make(map[int64]bool)
+	var files, referencing []*types.File
+	add := func(fileID int64, list *[]*types.File) error {
+		if seen[fileID] {
+			return nil
+		}
+		seen[fileID] = true
+
+		file, err := idx.db.GetFile(fileID)
+		if err != nil {
+			return err
+		}
+		if file != nil {
+			*list = append(*list, file)
+		}
+		return nil
+	}
+
+	for _, symbol := range symbols {
+		if err := add(symbol.FileID, &files); err != nil {
+			return nil, err
+		}
+	}
+	for _, symbol := range symbols {
+		refs, err := idx.db.GetReferencesBySymbol(symbol.ID)
+		if err != nil {
+			return nil, err
+		}
+		for _, ref := range refs {
+			if err := add(ref.FileID, &referencing); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	sort.Slice(referencing, func(i, j int) bool {
+		return referencing[i].RelativePath < referencing[j].RelativePath
+	})
+	return append(files, referencing...), nil
+}
+
+// GetFileReferences returns all references originating in a file, ordered by line
+func (idx *Indexer) GetFileReferences(filePath string) ([]*types.Reference, error) {
+	relPath, err := idx.resolveFilePath(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	return idx.db.GetReferencesByFilePath(idx.project.ID, relPath)
+}
+
+// GetParseErrors returns files the parser failed on or only partially parsed
+func (idx *Indexer) GetParseErrors() ([]*types.FileParseError, error) {
+	return idx.db.GetParseErrors(idx.project.ID)
+}
+
+// FindOrphanFiles returns indexed files that produced no symbols
+func (idx *Indexer) FindOrphanFiles() ([]*types.File, error) {
+	return idx.db.GetOrphanFiles(idx.project.ID)
+}
+
+// GetStaleFiles re-hashes each indexed file and returns those whose content
+// no longer matches the index, including files deleted from disk
+func (idx *Indexer) GetStaleFiles() ([]*types.File, error) {
+	files, err := idx.db.GetAllFilesForProject(idx.project.ID)
 	if err != nil {
 		return nil, err
 	}
 
-	// Build dependency graph
-	deps := make(map[string][]string)
-	deps[relPath] = []string{}
+	stale := []*types.File{}
+	for _, file := range files {
+		hash, err := utils.HashFile(file.Path)
+		if err != nil || hash != file.Hash {
+			stale = append(stale, file)
+		}
+	}
+
+	return stale, nil
+}
+
+// RepairIndex removes any duplicate relationship rows in the project and reports what was fixed
+func (idx *Indexer) RepairIndex() (map[string]interface{}, error) {
+	removed, err := idx.db.DeduplicateRelationships(idx.project.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to deduplicate relationships: %w", err)
+	}
+
+	count, err := idx.db.CountRelationships(idx.project.ID)
+	if err != nil {
+		return nil, err
+	}
 
-	for _, imp := range imports {
-		deps[relPath] = append(deps[relPath], imp.Source)
+	if removed > 0 {
+		idx.logger.Infof("Removed %d duplicate relationships", removed)
 	}
 
-	return &types.DependencyGraph{
-		Root:         relPath,
-		Dependencies: deps,
-		Imports:      imports,
+	return map[string]interface{}{
+		"duplicate_relationships_removed": removed,
+		"relationships":                   count,
 	}, nil
 }
 
+// Vacuum prunes references left behind by deleted symbols and compacts the
+// database, returning the number of references removed
+func (idx *Indexer) Vacuum() (int, error) {
+	removed, err := idx.db.PruneOrphanReferences()
+	if err != nil {
+		return 0, fmt.Errorf("failed to prune orphan references: %w", err)
+	}
+
+	if err := idx.db.Vacuum(); err != nil {
+		return removed, fmt.Errorf("failed to vacuum database: %w", err)
+	}
+
+	if removed > 0 {
+		idx.logger.Infof("Pruned %d orphan references", removed)
+	}
+
+	return removed, nil
+}
+
+// ScanSecrets scans string literals in indexed files for hardcoded credentials
+func (idx *Indexer) ScanSecrets() ([]*types.SecretFinding, error) {
+	return idx.secretScanner.ScanProject(idx.project.ID)
+}
+
+// DetectAntiPatterns runs the anti-pattern rules over every indexed function:
+// too many parameters, deeply nested blocks and empty error handlers, plus
+// any added with AddAntiPatternRule
+func (idx *Indexer) DetectAntiPatterns() ([]*types.AntiPatternFinding, error) {
+	return idx.antiPatterns.DetectProject(idx.project.ID)
+}
+
+// AddAntiPatternRule adds a rule run by DetectAntiPatterns
+func (idx *Indexer) AddAntiPatternRule(rule ai.AntiPatternRule) {
+	idx.antiPatterns.AddRule(rule)
+}
+
+// RegisterParser adds a custom language parser. A parser claiming an extension
+// that is already handled only takes it over if its Priority is higher.
+func (idx *Indexer) RegisterParser(p types.Parser) error {
+	return idx.parsers.Register(p)
+}
+
+// ListParsers returns information about the registered language parsers
+func (idx *Indexer) ListParsers() []types.ParserInfo {
+	return idx.parsers.ListParsers()
+}
+
 // GetAllFiles returns all indexed files
 func (idx *Indexer) GetAllFiles() ([]*types.File, error) {
 	return idx.db.GetAllFilesForProject(idx.project.ID)
@@ -660,6 +1531,29 @@ func (idx *Indexer) GetAllFiles() ([]*types.File, error) {
 
 // AI Helper Methods
 
+// GetFileSummaries returns all indexed files with their symbol counts
+func (idx *Indexer) GetFileSummaries() ([]*types.FileSummary, error) {
+	files, err := idx.db.GetAllFilesForProject(idx.project.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	counts, err := idx.db.CountSymbolsByFile(idx.project.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	summaries := make([]*types.FileSummary, 0, len(files))
+	for _, file := range files {
+		summaries = append(summaries, &types.FileSummary{
+			File:        file,
+			SymbolCount: counts[file.ID],
+		})
+	}
+
+	return summaries, nil
+}
+
 // GetCodeContext extracts comprehensive context for a symbol
 func (idx *Indexer) GetCodeContext(symbolName string, depth int) (*types.CodeContext, error) {
 	return idx.contextExtractor.ExtractContext(symbolName, depth)
@@ -675,6 +1569,11 @@ func (idx *Indexer) GetCodeMetrics(symbolName string) (*types.CodeMetrics, error
 	return idx.metricsCalc.CalculateMetrics(symbolName)
 }
 
+// RankByComplexity returns the most complex functions and methods in the project
+func (idx *Indexer) RankByComplexity(limit int) ([]*types.CodeMetrics, error) {
+	return idx.metricsCalc.RankFunctions(idx.project.ID, limit, 0)
+}
+
 // ExtractSmartSnippet extracts a self-contained code snippet
 func (idx *Indexer) ExtractSmartSnippet(symbolName string) (*types.SmartSnippet, error) {
 	return idx.snippetExtractor.ExtractSmartSnippet(symbolName, false)
@@ -692,7 +1591,25 @@ func (idx *Indexer) SuggestRefactorings(symbolName string) ([]*types.Refactoring
 
 // FindUnusedSymbols finds unused symbols in the project
 func (idx *Indexer) FindUnusedSymbols() ([]*types.Symbol, error) {
-	return idx.usageAnalyzer.FindUnusedSymbols(idx.project.ID)
+	return idx.FindUnusedSymbolsContext(context.Background(), false)
+}
+
+// FindUnusedSymbolsExcludingTests finds unused symbols, ignoring symbols
+// declared in test files and references made from them
+func (idx *Indexer) FindUnusedSymbolsExcludingTests() ([]*types.Symbol, error) {
+	return idx.FindUnusedSymbolsContext(context.Background(), true)
+}
+
+// FindUnusedSymbolsContext finds unused symbols, optionally excluding test
+// files, stopping when ctx is done
+func (idx *Indexer) FindUnusedSymbolsContext(ctx context.Context, excludeTests bool) ([]*types.Symbol, error) {
+	var exclude func(file *types.File) bool
+	if excludeTests {
+		exclude = func(file *types.File) bool {
+			return isTestFile(file.RelativePath, file.Language)
+		}
+	}
+	return idx.usageAnalyzer.FindUnusedSymbolsContext(ctx, idx.project.ID, exclude)
 }
 
 // FindMostUsedSymbols finds the most used symbols
@@ -700,6 +1617,12 @@ func (idx *Indexer) FindMostUsedSymbols(limit int) ([]*types.SymbolUsageStats, e
 	return idx.usageAnalyzer.FindMostUsedSymbols(idx.project.ID, limit)
 }
 
+// AnalyzeProject runs semantic analysis over the whole project, validating
+// files on Config.WorkerCount workers
+func (idx *Indexer) AnalyzeProject() (*types.SemanticAnalysisResult, error) {
+	return idx.semanticAnalyzer.AnalyzeProject(idx.project.ID)
+}
+
 // Change Tracking Methods
 
 // SimulateSymbolChange simulates a change without applying it
@@ -724,6 +1647,11 @@ func (idx *Indexer) BuildDependencyGraph(symbolName string, maxDepth int) (*type
 	return idx.depGraphBuilder.BuildSymbolDependencyGraph(symbolName, maxDepth)
 }
 
+// BuildDependencyGraphContext builds a dependency graph for a symbol, honoring cancellation
+func (idx *Indexer) BuildDependencyGraphContext(ctx context.Context, symbolName string, maxDepth int) (*types.DependencyGraph, error) {
+	return idx.depGraphBuilder.BuildSymbolDependencyGraphContext(ctx, symbolName, maxDepth)
+}
+
 // GetDependencies gets all dependencies for a symbol
 func (idx *Indexer) GetDependencies(symbolName string) ([]*types.Symbol, error) {
 	return idx.depGraphBuilder.GetDependenciesFor(symbolName)
@@ -734,27 +1662,49 @@ func (idx *Indexer) GetDependents(symbolName string) ([]*types.Symbol, error) {
 	return idx.depGraphBuilder.GetDependentsFor(symbolName)
 }
 
+// GetPublicCallers gets the exported symbols that depend on a symbol, the
+// callers through which a change to it reaches other packages
+func (idx *Indexer) GetPublicCallers(symbolName string) ([]*types.Symbol, error) {
+	dependents, err := idx.GetDependents(symbolName)
+	if err != nil {
+		return nil, err
+	}
+
+	public := []*types.Symbol{}
+	for _, symbol := range dependents {
+		if symbol.IsExported || symbol.Visibility == types.VisibilityPublic {
+			public = append(public, symbol)
+		}
+	}
+	return public, nil
+}
+
 // AnalyzeDependencyChain analyzes the full dependency chain
 func (idx *Indexer) AnalyzeDependencyChain(symbolName string) (map[string]interface{}, error) {
 	return idx.depGraphBuilder.AnalyzeDependencyChain(symbolName)
 }
 
+// GetCouplingHotspots returns the most coupled symbols in the project
+func (idx *Indexer) GetCouplingHotspots(limit int) ([]*types.CouplingHotspot, error) {
+	return idx.depGraphBuilder.FindCouplingHotspots(idx.project.ID, limit)
+}
+
 // Type Validation Methods
 
 // ValidateFileTypes validates all types in a file
 func (idx *Indexer) ValidateFileTypes(filePath string) (*types.TypeValidation, error) {
-	file, err := idx.db.GetFileByPath(filePath, idx.project.ID)
+	file, err := idx.lookupFile(filePath)
 	if err != nil {
-		return nil, fmt.Errorf("file not found: %w", err)
+		return nil, err
 	}
 	return idx.typeValidator.ValidateFile(file.ID)
 }
 
 // FindUndefinedUsages finds all undefined symbol usages in a file
 func (idx *Indexer) FindUndefinedUsages(filePath string) ([]*types.UndefinedUsage, error) {
-	file, err := idx.db.GetFileByPath(filePath, idx.project.ID)
+	file, err := idx.lookupFile(filePath)
 	if err != nil {
-		return nil, fmt.Errorf("file not found: %w", err)
+		return nil, err
 	}
 	return idx.typeValidator.FindUndefinedUsages(file.ID)
 }
@@ -764,11 +1714,16 @@ func (idx *Indexer) CheckMethodExists(typeName, methodName string) (*types.Missi
 	return idx.typeValidator.CheckMethodExists(typeName, methodName, idx.project.ID)
 }
 
+// CheckConformance reports which interface methods a type implements
+func (idx *Indexer) CheckConformance(typeName, interfaceName string) (*types.ConformanceReport, error) {
+	return idx.typeValidator.CheckConformance(typeName, interfaceName, idx.project.ID)
+}
+
 // CalculateTypeSafetyScore calculates type safety score for a file
 func (idx *Indexer) CalculateTypeSafetyScore(filePath string) (*types.TypeSafetyScore, error) {
-	file, err := idx.db.GetFileByPath(filePath, idx.project.ID)
+	file, err := idx.lookupFile(filePath)
 	if err != nil {
-		return nil, fmt.Errorf("file not found: %w", err)
+		return nil, err
 	}
 	return idx.typeValidator.CalculateTypeSafetyScore(file.ID)
 }
@@ -0,0 +1,77 @@
+package core
+
+import (
+	"sync"
+	"time"
+
+	"github.com/aaamil13/CodeIndexerMCP/pkg/types"
+)
+
+// indexTimer accumulates the time spent in each indexing phase across
+// workers. A nil timer records nothing, so single-file indexing skips it.
+type indexTimer struct {
+	mu    sync.Mutex
+	stats types.IndexStats
+}
+
+// newIndexTimer creates an empty timer
+func newIndexTimer() *indexTimer {
+	return &indexTimer{stats: types.IndexStats{ParseByLanguage: make(map[string]time.Duration)}}
+}
+
+// add adds the time since start to a phase
+func (t *indexTimer) add(start time.Time, phase func(stats *types.IndexStats, d time.Duration)) {
+	if t == nil {
+		return
+	}
+	d := time.Since(start)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	phase(&t.stats, d)
+}
+
+// scan records time spent walking the project
+func (t *indexTimer) scan(start time.Time) {
+	t.add(start, func(stats *types.IndexStats, d time.Duration) { stats.Scan += d })
+}
+
+// hash records time spent reading and hashing a file
+func (t *indexTimer) hash(start time.Time) {
+	t.add(start, func(stats *types.IndexStats, d time.Duration) { stats.Hash += d })
+}
+
+// parse records time spent parsing a file of the given language
+func (t *indexTimer) parse(start time.Time, language string) {
+	t.add(start, func(stats *types.IndexStats, d time.Duration) {
+		stats.Parse += d
+		stats.ParseByLanguage[language] += d
+	})
+}
+
+// write records time spent saving to the database
+func (t *indexTimer) write(start time.Time) {
+	t.add(start, func(stats *types.IndexStats, d time.Duration) { stats.Write += d })
+}
+
+// link records time spent linking symbols across files
+func (t *indexTimer) link(start time.Time) {
+	t.add(start, func(stats *types.IndexStats, d time.Duration) { stats.Link += d })
+}
+
+// finish returns the accumulated stats for an index that began at start
+func (t *indexTimer) finish(start time.Time, files int) *types.IndexStats {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	stats := t.stats
+	stats.Files = files
+	stats.Total = time.Since(start)
+	return &stats
+}
+
+// LastIndexStats returns the phase timings of the most recent IndexAll or
+// IndexChangedSince, or nil if this indexer has run neither
+func (idx *Indexer) LastIndexStats() *types.IndexStats {
+	return idx.lastStats
+}
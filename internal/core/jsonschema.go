@@ -0,0 +1,593 @@
+package core
+
+import (
+	"fmt"
+	"go/ast"
+	goparser "go/parser"
+	"go/token"
+	gotypes "go/types"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strings"
+
+	"github.com/aaamil13/CodeIndexerMCP/internal/utils"
+	"github.com/aaamil13/CodeIndexerMCP/pkg/types"
+)
+
+// JSONSchemaDialect is the JSON Schema version GenerateJSONSchema emits
+const JSONSchemaDialect = "https://json-schema.org/draft/2020-12/schema"
+
+// schemaField is a field of a type as declared in its source
+type schemaField struct {
+	name     string // Name in JSON
+	typ      string // Type as written in the source
+	required bool
+	embedded bool // A Go embedded struct, whose fields are promoted
+}
+
+// jsonSchemaBuilder collects the definitions of the types a schema refers to
+type jsonSchemaBuilder struct {
+	idx      *Indexer
+	root     int64 // ID of the type the document describes
+	defs     map[string]interface{}
+	defIDs   map[string]int64 // ID of the type each definition describes
+	inlining map[int64]bool   // Named types whose underlying type is being described
+}
+
+// GenerateJSONSchema returns a JSON Schema document describing how the named
+// Go struct or Python class serializes, read from its field declarations:
+// Go json tags rename, omit and make fields optional, and Python fields with
+// defaults or Optional types are optional. Project types used by its fields
+// are defined under $defs and referenced with $ref; named Go types that
+// aren't structs are described by their underlying type, and Go pointers
+// may also be null.
+func (idx *Indexer) GenerateJSONSchema(typeName string) (map[string]interface{}, error) {
+	symbol, file, err := idx.lookupSerializableType(typeName)
+	if err != nil {
+		return nil, err
+	}
+
+	builder := &jsonSchemaBuilder{
+		idx:      idx,
+		root:     symbol.ID,
+		defs:     make(map[string]interface{}),
+		defIDs:   make(map[string]int64),
+		inlining: make(map[int64]bool),
+	}
+	schema, err := builder.objectSchema(symbol, file)
+	if err != nil {
+		return nil, err
+	}
+
+	schema["$schema"] = JSONSchemaDialect
+	schema["title"] = symbol.Name
+	if len(builder.defs) > 0 {
+		schema["$defs"] = builder.defs
+	}
+	return schema, nil
+}
+
+// lookupSerializableType returns the first struct or class in the project
+// with the given name, and its file
+func (idx *Indexer) lookupSerializableType(name string) (*types.Symbol, *types.File, error) {
+	symbols, err := idx.db.GetSymbolsByName(idx.project.ID, name)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for _, symbol := range symbols {
+		if symbol.Type != types.SymbolTypeStruct && symbol.Type != types.SymbolTypeClass {
+			continue
+		}
+		file, err := idx.db.GetFile(symbol.FileID)
+		if err != nil {
+			return nil, nil, err
+		}
+		if file != nil {
+			return symbol, file, nil
+		}
+	}
+	if len(symbols) > 0 {
+		return nil, nil, fmt.Errorf("symbol is not a struct or class: %s", name)
+	}
+	return nil, nil, fmt.Errorf("symbol not found: %s", name)
+}
+
+// lookupGoType returns the Go struct or named type that a field declared in
+// the package in pkgDir refers to as name or qualifier.name. Unqualified
+// names are the package's own types, and a qualifier names a project package
+// by its directory, so types of other modules such as uuid.UUID aren't
+// mistaken for a project type of the same name.
+func (idx *Indexer) lookupGoType(pkgDir, qualifier, name string) (*types.Symbol, *types.File, error) {
+	symbols, err := idx.db.GetSymbolsByName(idx.project.ID, name)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for _, symbol := range symbols {
+		if symbol.Type != types.SymbolTypeStruct && symbol.Type != types.SymbolTypeType {
+			continue
+		}
+		file, err := idx.db.GetFile(symbol.FileID)
+		if err != nil {
+			return nil, nil, err
+		}
+		if file == nil || file.Language != "go" {
+			continue
+		}
+		dir := filepath.Dir(file.RelativePath)
+		if (qualifier == "" && dir == pkgDir) || (qualifier != "" && filepath.Base(dir) == qualifier) {
+			return symbol, file, nil
+		}
+	}
+	return nil, nil, fmt.Errorf("symbol not found: %s", name)
+}
+
+// declarationLines returns the lines of file from the symbol's declaration
+// to the end of the file
+func declarationLines(symbol *types.Symbol, file *types.File) ([]string, error) {
+	content, err := utils.ReadFileUTF8(file.Path, file.Encoding)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+	lines := strings.Split(string(content), "\n")
+	if symbol.StartLine < 1 || symbol.StartLine > len(lines) {
+		return nil, fmt.Errorf("symbol %s is outside %s (line %d)", symbol.Name, file.RelativePath, symbol.StartLine)
+	}
+	return lines[symbol.StartLine-1:], nil
+}
+
+// goDeclaration returns the source of a Go type declaration from its lines
+func goDeclaration(symbol *types.Symbol, lines []string) string {
+	end := symbol.EndLine - symbol.StartLine + 1
+	if end < 1 || end > len(lines) {
+		end = len(lines)
+	}
+	return strings.Join(lines[:end], "\n")
+}
+
+// objectSchema describes a struct or class as a JSON object
+func (b *jsonSchemaBuilder) objectSchema(symbol *types.Symbol, file *types.File) (map[string]interface{}, error) {
+	lines, err := declarationLines(symbol, file)
+	if err != nil {
+		return nil, err
+	}
+
+	var fields []*schemaField
+	switch file.Language {
+	case "go":
+		fields, err = goStructFields(goDeclaration(symbol, lines))
+	case "python":
+		fields = pythonClassFields(lines)
+	default:
+		return nil, fmt.Errorf("JSON schema generation is not supported for %s files", file.Language)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read fields of %s: %w", symbol.Name, err)
+	}
+
+	schema := map[string]interface{}{"type": "object"}
+	if symbol.Documentation != "" {
+		summary, _, _ := strings.Cut(symbol.Documentation, "\n")
+		schema["description"] = summary
+	}
+
+	properties := make(map[string]interface{})
+	required := []string{}
+	var embedded []interface{}
+	for _, field := range fields {
+		fieldSchema := b.typeSchema(file, field.typ)
+		if field.embedded {
+			embedded = append(embedded, fieldSchema)
+			continue
+		}
+		properties[field.name] = fieldSchema
+		if field.required {
+			required = append(required, field.name)
+		}
+	}
+	schema["properties"] = properties
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	// Promoted fields come from the embedded structs' own schemas
+	if len(embedded) > 0 {
+		schema["allOf"] = embedded
+	}
+
+	return schema, nil
+}
+
+// typeSchema describes the type of a field declared in file, defining any
+// project struct or class it names under $defs
+func (b *jsonSchemaBuilder) typeSchema(file *types.File, typ string) map[string]interface{} {
+	typ = strings.TrimSpace(typ)
+	if file.Language == "go" {
+		return b.goTypeSchema(filepath.Dir(file.RelativePath), typ)
+	}
+	return b.pythonTypeSchema(typ)
+}
+
+// goTypeSchema describes a Go type used in the package in pkgDir as
+// encoding/json serializes it
+func (b *jsonSchemaBuilder) goTypeSchema(pkgDir, typ string) map[string]interface{} {
+	switch {
+	case strings.HasPrefix(typ, "*"):
+		// Nil pointers encode as null
+		inner := b.goTypeSchema(pkgDir, typ[1:])
+		if len(inner) == 0 {
+			return inner
+		}
+		return map[string]interface{}{"anyOf": []interface{}{inner, map[string]interface{}{"type": "null"}}}
+	case typ == "[]byte":
+		return map[string]interface{}{"type": "string", "contentEncoding": "base64"}
+	case strings.HasPrefix(typ, "["):
+		if end := strings.IndexByte(typ, ']'); end >= 0 {
+			return map[string]interface{}{"type": "array", "items": b.goTypeSchema(pkgDir, typ[end+1:])}
+		}
+	case strings.HasPrefix(typ, "map["):
+		if value, ok := goMapValue(typ); ok {
+			return map[string]interface{}{"type": "object", "additionalProperties": b.goTypeSchema(pkgDir, value)}
+		}
+	}
+
+	switch typ {
+	case "string":
+		return map[string]interface{}{"type": "string"}
+	case "bool":
+		return map[string]interface{}{"type": "boolean"}
+	case "int", "int8", "int16", "int32", "int64", "uint", "uint8", "uint16", "uint32", "uint64", "uintptr", "byte", "rune", "time.Duration":
+		return map[string]interface{}{"type": "integer"}
+	case "float32", "float64":
+		return map[string]interface{}{"type": "number"}
+	case "time.Time":
+		return map[string]interface{}{"type": "string", "format": "date-time"}
+	case "interface{}", "any", "json.RawMessage":
+		return map[string]interface{}{}
+	}
+
+	// Generic instantiations are described by their base type
+	if i := strings.IndexByte(typ, '['); i > 0 {
+		typ = typ[:i]
+	}
+	qualifier, name, qualified := strings.Cut(typ, ".")
+	if !qualified {
+		qualifier, name = "", typ
+	}
+	symbol, file, err := b.idx.lookupGoType(pkgDir, qualifier, name)
+	if err != nil {
+		return map[string]interface{}{}
+	}
+	if symbol.Type == types.SymbolTypeType {
+		return b.underlyingSchema(symbol, file)
+	}
+	return b.define(symbol, file)
+}
+
+// underlyingSchema describes a named Go type that isn't a struct, such as
+// "type Status string", by its underlying type, which is how encoding/json
+// serializes it
+func (b *jsonSchemaBuilder) underlyingSchema(symbol *types.Symbol, file *types.File) map[string]interface{} {
+	if b.inlining[symbol.ID] {
+		return map[string]interface{}{}
+	}
+	lines, err := declarationLines(symbol, file)
+	if err != nil {
+		return map[string]interface{}{}
+	}
+	underlying, err := goUnderlyingType(goDeclaration(symbol, lines))
+	if err != nil {
+		return map[string]interface{}{}
+	}
+
+	b.inlining[symbol.ID] = true
+	defer delete(b.inlining, symbol.ID)
+	return b.goTypeSchema(filepath.Dir(file.RelativePath), underlying)
+}
+
+// pythonTypeSchema describes a Python type annotation
+func (b *jsonSchemaBuilder) pythonTypeSchema(typ string) map[string]interface{} {
+	typ = strings.Trim(typ, `"'`)
+
+	if members := splitTopLevelUnion(typ); len(members) > 1 {
+		var anyOf []interface{}
+		for _, member := range members {
+			if member != "None" {
+				anyOf = append(anyOf, b.pythonTypeSchema(member))
+			}
+		}
+		switch len(anyOf) {
+		case 0:
+			return map[string]interface{}{"type": "null"}
+		case 1:
+			return anyOf[0].(map[string]interface{})
+		}
+		return map[string]interface{}{"anyOf": anyOf}
+	}
+
+	if base, args, ok := splitSubscript(typ); ok {
+		if i := strings.LastIndexByte(base, '.'); i >= 0 {
+			base = base[i+1:] // typing.List, t.Optional
+		}
+		params := splitParameters(args)
+		last := strings.TrimSpace(params[len(params)-1])
+		switch base {
+		case "Optional":
+			return b.pythonTypeSchema(args)
+		case "Union":
+			return b.pythonTypeSchema(strings.Join(params, " | "))
+		case "list", "List", "set", "Set", "frozenset", "FrozenSet", "Sequence", "Iterable", "tuple", "Tuple":
+			return map[string]interface{}{"type": "array", "items": b.pythonTypeSchema(strings.TrimSpace(params[0]))}
+		case "dict", "Dict", "Mapping", "MutableMapping":
+			return map[string]interface{}{"type": "object", "additionalProperties": b.pythonTypeSchema(last)}
+		case "Annotated", "Required", "NotRequired", "Final":
+			return b.pythonTypeSchema(strings.TrimSpace(params[0]))
+		}
+		return map[string]interface{}{}
+	}
+
+	switch typ {
+	case "str":
+		return map[string]interface{}{"type": "string"}
+	case "bool":
+		return map[string]interface{}{"type": "boolean"}
+	case "int":
+		return map[string]interface{}{"type": "integer"}
+	case "float", "Decimal", "decimal.Decimal":
+		return map[string]interface{}{"type": "number"}
+	case "bytes":
+		return map[string]interface{}{"type": "string", "contentEncoding": "base64"}
+	case "datetime", "datetime.datetime":
+		return map[string]interface{}{"type": "string", "format": "date-time"}
+	case "date", "datetime.date":
+		return map[string]interface{}{"type": "string", "format": "date"}
+	case "list", "List":
+		return map[string]interface{}{"type": "array"}
+	case "dict", "Dict":
+		return map[string]interface{}{"type": "object"}
+	case "", "Any", "object":
+		return map[string]interface{}{}
+	}
+
+	if i := strings.LastIndexByte(typ, '.'); i >= 0 {
+		typ = typ[i+1:]
+	}
+	return b.ref(typ)
+}
+
+// ref refers to the project class with the given name. Types not found in
+// the project accept any value.
+func (b *jsonSchemaBuilder) ref(name string) map[string]interface{} {
+	symbol, file, err := b.idx.lookupSerializableType(name)
+	if err != nil {
+		return map[string]interface{}{}
+	}
+	return b.define(symbol, file)
+}
+
+// define refers to a project struct or class, defining it under $defs the
+// first time
+func (b *jsonSchemaBuilder) define(symbol *types.Symbol, file *types.File) map[string]interface{} {
+	if symbol.ID == b.root {
+		return map[string]interface{}{"$ref": "#"}
+	}
+	key := symbol.Name
+	if id, ok := b.defIDs[key]; ok && id != symbol.ID {
+		// A type of the same name from another package
+		key = filepath.Base(filepath.Dir(file.RelativePath)) + "." + symbol.Name
+	}
+	ref := map[string]interface{}{"$ref": "#/$defs/" + key}
+	if _, ok := b.defs[key]; ok {
+		return ref
+	}
+
+	// Reserve the name first so self-referencing types terminate
+	b.defs[key] = map[string]interface{}{}
+	b.defIDs[key] = symbol.ID
+	schema, err := b.objectSchema(symbol, file)
+	if err != nil {
+		delete(b.defs, key)
+		delete(b.defIDs, key)
+		return map[string]interface{}{}
+	}
+	b.defs[key] = schema
+	return ref
+}
+
+// goStructFields reads the fields of a Go struct type declaration, following
+// encoding/json: unexported fields and those tagged "-" are left out, tags
+// rename fields and omitempty makes them optional
+func goStructFields(source string) ([]*schemaField, error) {
+	file, err := parseGoTypeDecl(source)
+	if err != nil {
+		return nil, err
+	}
+
+	var structType *ast.StructType
+	ast.Inspect(file, func(n ast.Node) bool {
+		if s, ok := n.(*ast.StructType); ok && structType == nil {
+			structType = s
+		}
+		return structType == nil
+	})
+	if structType == nil {
+		return nil, fmt.Errorf("not a struct")
+	}
+
+	fields := []*schemaField{}
+	for _, field := range structType.Fields.List {
+		typ := gotypes.ExprString(field.Type)
+		name, options := "", ""
+		if field.Tag != nil {
+			tag := reflect.StructTag(strings.Trim(field.Tag.Value, "`")).Get("json")
+			name, options, _ = strings.Cut(tag, ",")
+		}
+		if name == "-" && options == "" {
+			continue
+		}
+		optional := strings.Contains(","+options+",", ",omitempty,") || strings.Contains(","+options+",", ",omitzero,")
+
+		if len(field.Names) == 0 {
+			if name == "" {
+				fields = append(fields, &schemaField{typ: typ, embedded: true})
+				continue
+			}
+			base := strings.TrimLeft(typ, "*")
+			if i := strings.LastIndexByte(base, '.'); i >= 0 {
+				base = base[i+1:]
+			}
+			field.Names = []*ast.Ident{ast.NewIdent(base)}
+		}
+
+		for _, ident := range field.Names {
+			if !ident.IsExported() {
+				continue
+			}
+			jsonName := name
+			if jsonName == "" {
+				jsonName = ident.Name
+			}
+			fields = append(fields, &schemaField{name: jsonName, typ: typ, required: !optional})
+		}
+	}
+
+	return fields, nil
+}
+
+// goUnderlyingType returns the underlying type of a Go type declaration as
+// written in the source
+func goUnderlyingType(source string) (string, error) {
+	file, err := parseGoTypeDecl(source)
+	if err != nil {
+		return "", err
+	}
+	for _, decl := range file.Decls {
+		if gen, ok := decl.(*ast.GenDecl); ok && len(gen.Specs) > 0 {
+			if spec, ok := gen.Specs[0].(*ast.TypeSpec); ok {
+				return gotypes.ExprString(spec.Type), nil
+			}
+		}
+	}
+	return "", fmt.Errorf("not a type declaration")
+}
+
+// parseGoTypeDecl parses the source of a Go type declaration
+func parseGoTypeDecl(source string) (*ast.File, error) {
+	file, err := goparser.ParseFile(token.NewFileSet(), "", "package p\n"+source, 0)
+	if err != nil {
+		// A spec from a grouped "type ( ... )" declaration
+		file, err = goparser.ParseFile(token.NewFileSet(), "", "package p\ntype "+source, 0)
+	}
+	return file, err
+}
+
+// pythonField matches an annotated class attribute: "name: type = default"
+var pythonField = regexp.MustCompile(`^(\w+)\s*:\s*([^=]+?)\s*(=.*)?$`)
+
+// pythonClassFields reads the annotated attributes declared directly in a
+// Python class body, as dataclasses, pydantic models and TypedDicts do.
+// lines start at the class header. Fields with a default or an Optional
+// type are optional.
+func pythonClassFields(lines []string) []*schemaField {
+	fields := []*schemaField{}
+	if len(lines) == 0 {
+		return fields
+	}
+	header := lines[0]
+	classIndent := len(header) - len(strings.TrimLeft(header, " \t"))
+	bodyIndent := -1
+	var docQuote string
+
+	for _, line := range lines[1:] {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" {
+			continue
+		}
+
+		// Skip docstrings
+		if docQuote != "" {
+			if strings.Contains(trimmed, docQuote) {
+				docQuote = ""
+			}
+			continue
+		}
+		indent := len(line) - len(strings.TrimLeft(line, " \t"))
+		if indent <= classIndent {
+			break // End of the class body
+		}
+		if bodyIndent < 0 {
+			bodyIndent = indent
+		}
+		if indent != bodyIndent {
+			continue // Inside a method or nested block
+		}
+		for _, quote := range []string{`"""`, `'''`} {
+			if strings.HasPrefix(trimmed, quote) && !strings.Contains(trimmed[3:], quote) {
+				docQuote = quote
+			}
+		}
+
+		m := pythonField.FindStringSubmatch(trimmed)
+		if m == nil || strings.HasPrefix(m[1], "_") {
+			continue
+		}
+		typ := strings.TrimSpace(m[2])
+		if strings.HasPrefix(typ, "ClassVar") {
+			continue
+		}
+		optional := m[3] != "" || strings.HasPrefix(typ, "Optional[") || strings.HasPrefix(typ, "NotRequired[")
+		for _, member := range splitTopLevelUnion(typ) {
+			optional = optional || member == "None"
+		}
+		fields = append(fields, &schemaField{name: m[1], typ: typ, required: !optional})
+	}
+
+	return fields
+}
+
+// goMapValue returns the value type of a Go map type
+func goMapValue(typ string) (string, bool) {
+	depth := 0
+	for i := len("map"); i < len(typ); i++ {
+		switch typ[i] {
+		case '[':
+			depth++
+		case ']':
+			if depth--; depth == 0 {
+				return typ[i+1:], true
+			}
+		}
+	}
+	return "", false
+}
+
+// splitSubscript splits a subscripted Python type "base[args]"
+func splitSubscript(typ string) (string, string, bool) {
+	start := strings.IndexByte(typ, '[')
+	if start <= 0 || !strings.HasSuffix(typ, "]") {
+		return "", "", false
+	}
+	return typ[:start], typ[start+1 : len(typ)-1], true
+}
+
+// splitTopLevelUnion splits a "A | B" union at bars that aren't nested in
+// brackets
+func splitTopLevelUnion(typ string) []string {
+	var members []string
+	depth, start := 0, 0
+	for i := 0; i < len(typ); i++ {
+		switch typ[i] {
+		case '[':
+			depth++
+		case ']':
+			depth--
+		case '|':
+			if depth == 0 {
+				members = append(members, strings.TrimSpace(typ[start:i]))
+				start = i + 1
+			}
+		}
+	}
+	return append(members, strings.TrimSpace(typ[start:]))
+}
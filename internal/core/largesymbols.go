@@ -0,0 +1,103 @@
+package core
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/aaamil13/CodeIndexerMCP/internal/utils"
+	"github.com/aaamil13/CodeIndexerMCP/pkg/types"
+)
+
+// DefaultLargeSymbolLines is the threshold used by FindLargeSymbols when
+// none is given
+const DefaultLargeSymbolLines = 100
+
+// FindLargeSymbols returns symbols spanning more than minLines lines,
+// longest first. Some parsers record only where a symbol starts; those
+// ranges are completed from the source, which leaves out symbols whose end
+// can't be found.
+func (idx *Indexer) FindLargeSymbols(minLines int) ([]*types.Symbol, error) {
+	if minLines <= 0 {
+		minLines = DefaultLargeSymbolLines
+	}
+
+	files, err := idx.db.GetAllFilesForProject(idx.project.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	large := []*types.Symbol{}
+	for _, file := range files {
+		symbols, err := idx.db.GetSymbolsByFile(file.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		var lines []string // Read once a symbol needs its end found
+		for _, symbol := range symbols {
+			if symbol.StartLine > 0 && symbol.EndLine <= symbol.StartLine {
+				if lines == nil {
+					content, err := utils.ReadFileUTF8(file.Path, file.Encoding)
+					if err != nil {
+						continue
+					}
+					lines = strings.Split(utils.StripCommentsAndStrings(string(content), file.Language), "\n")
+				}
+				symbol.EndLine = symbolEndLine(symbol, lines, file.Language)
+			}
+			if symbolLineCount(symbol) > minLines {
+				large = append(large, symbol)
+			}
+		}
+	}
+
+	sort.SliceStable(large, func(i, j int) bool {
+		return symbolLineCount(large[i]) > symbolLineCount(large[j])
+	})
+
+	return large, nil
+}
+
+// symbolLineCount returns the number of lines a symbol spans, or 0 when its
+// range is collapsed
+func symbolLineCount(symbol *types.Symbol) int {
+	if symbol.StartLine <= 0 || symbol.EndLine < symbol.StartLine {
+		return 0
+	}
+	return symbol.EndLine - symbol.StartLine + 1
+}
+
+// symbolEndLine finds the last line of a symbol the parser recorded only
+// the start of: the end of its indented body in Python, otherwise the line
+// closing the first brace it opens. lines must have comments and strings
+// blanked. It returns the start line when no end is found.
+func symbolEndLine(symbol *types.Symbol, lines []string, language string) int {
+	if symbol.StartLine > len(lines) {
+		return symbol.StartLine
+	}
+
+	if language == "python" {
+		body := indentedBody(symbol, lines)
+		if body == "" {
+			return symbol.StartLine
+		}
+		return symbol.StartLine + strings.Count(body, "\n") + 1
+	}
+
+	depth, opened := 0, false
+	for i := symbol.StartLine - 1; i < len(lines); i++ {
+		for _, c := range lines[i] {
+			switch c {
+			case '{':
+				depth++
+				opened = true
+			case '}':
+				depth--
+			}
+			if opened && depth == 0 {
+				return i + 1
+			}
+		}
+	}
+	return symbol.StartLine
+}
@@ -0,0 +1,169 @@
+package core
+
+import (
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/aaamil13/CodeIndexerMCP/internal/utils"
+	"github.com/aaamil13/CodeIndexerMCP/pkg/types"
+)
+
+// goPackageClause matches a Go file's package clause
+var goPackageClause = regexp.MustCompile(`(?m)^package\s+(\w+)`)
+
+// rootNamespace holds files that belong to no package, module or namespace
+const rootNamespace = "."
+
+// GetNamespaceTree groups the project's files and top-level symbols by Go
+// package (directory), Python module (dotted file path) or declared
+// package or namespace (Java, Kotlin, C#, PHP, C++), nesting
+// namespaces by their path segments. Other files group by directory.
+func (idx *Indexer) GetNamespaceTree() (*types.NamespaceTree, error) {
+	files, err := idx.db.GetAllFilesForProject(idx.project.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	tree := &types.NamespaceTree{Roots: []*types.NamespaceNode{}}
+	nodes := make(map[string]*types.NamespaceNode)
+
+	for _, file := range files {
+		symbols, err := idx.db.GetSymbolsByFile(file.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		node := namespaceNode(tree, nodes, fileNamespace(file, symbols))
+		node.Files = append(node.Files, &types.NamespaceFile{
+			Path:     file.RelativePath,
+			Language: file.Language,
+			Symbols:  topLevelSymbols(symbols),
+		})
+		tree.Files++
+	}
+
+	tree.Namespaces = len(nodes)
+	sortNamespaceNodes(tree.Roots)
+
+	return tree, nil
+}
+
+// fileNamespace returns the package, module or namespace a file belongs to
+func fileNamespace(file *types.File, symbols []*types.Symbol) string {
+	relPath := filepath.ToSlash(file.RelativePath)
+	dir := path.Dir(relPath)
+
+	switch file.Language {
+	case "go":
+		// A Go package is a directory; at the root, use the declared name
+		if dir != rootNamespace {
+			return dir
+		}
+		if content, err := utils.ReadFileUTF8(file.Path, file.Encoding); err == nil {
+			if match := goPackageClause.FindSubmatch(content); match != nil {
+				return string(match[1])
+			}
+		}
+		return rootNamespace
+
+	case "python":
+		module := strings.TrimSuffix(relPath, path.Ext(relPath))
+		module = strings.TrimSuffix(module, "/__init__")
+		return strings.ReplaceAll(module, "/", ".")
+	}
+
+	for _, symbol := range symbols {
+		switch symbol.Type {
+		case types.SymbolTypePackage, types.SymbolTypeNamespace:
+			if symbol.Name != "" {
+				return symbol.Name
+			}
+		}
+	}
+
+	return dir
+}
+
+// namespaceNode returns the node for a namespace, creating it and any
+// missing ancestors
+func namespaceNode(tree *types.NamespaceTree, nodes map[string]*types.NamespaceNode, namespace string) *types.NamespaceNode {
+	segments, sep := splitNamespace(namespace)
+	if len(segments) == 0 {
+		segments = []string{rootNamespace}
+	}
+
+	var parent *types.NamespaceNode
+	for i, segment := range segments {
+		nsPath := strings.Join(segments[:i+1], sep)
+		node, ok := nodes[nsPath]
+		if !ok {
+			node = &types.NamespaceNode{Name: segment, Path: nsPath}
+			nodes[nsPath] = node
+			if parent == nil {
+				tree.Roots = append(tree.Roots, node)
+			} else {
+				parent.Children = append(parent.Children, node)
+			}
+		}
+		parent = node
+	}
+
+	return parent
+}
+
+// splitNamespace splits a namespace into its segments and the separator
+// joining them: / for directories, :: for C++ and Rust, \ for PHP, else .
+func splitNamespace(namespace string) ([]string, string) {
+	sep := "."
+	for _, candidate := range []string{"/", "::", `\`} {
+		if strings.Contains(namespace, candidate) {
+			sep = candidate
+			break
+		}
+	}
+
+	var segments []string
+	for _, segment := range strings.Split(namespace, sep) {
+		if segment != "" && segment != rootNamespace {
+			segments = append(segments, segment)
+		}
+	}
+	return segments, sep
+}
+
+// topLevelSymbols returns the symbols not nested in a class or type,
+// leaving out the package or namespace declaration itself
+func topLevelSymbols(symbols []*types.Symbol) []*types.Symbol {
+	byID := make(map[int64]*types.Symbol, len(symbols))
+	for _, symbol := range symbols {
+		byID[symbol.ID] = symbol
+	}
+
+	top := []*types.Symbol{}
+	for _, symbol := range symbols {
+		switch symbol.Type {
+		case types.SymbolTypePackage, types.SymbolTypeNamespace:
+			continue
+		}
+		if findParentSymbol(symbol, symbols, byID) == nil {
+			top = append(top, symbol)
+		}
+	}
+	return top
+}
+
+// sortNamespaceNodes sorts nodes, their children and their files by name
+func sortNamespaceNodes(nodes []*types.NamespaceNode) {
+	sort.Slice(nodes, func(i, j int) bool {
+		return nodes[i].Name < nodes[j].Name
+	})
+	for _, node := range nodes {
+		sort.Slice(node.Files, func(i, j int) bool {
+			return node.Files[i].Path < node.Files[j].Path
+		})
+		sortNamespaceNodes(node.Children)
+	}
+}
@@ -0,0 +1,184 @@
+package core
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/aaamil13/CodeIndexerMCP/internal/utils"
+	"github.com/aaamil13/CodeIndexerMCP/pkg/types"
+)
+
+// NamingRule is a naming convention for some kinds of symbol in a language
+type NamingRule struct {
+	Convention string                   // Reported in issues, e.g. "snake_case"
+	Types      []types.SymbolType       // Symbol types the rule applies to
+	Expected   func(name string) string // Returns name in the conventional form
+}
+
+// appliesTo reports whether the rule covers a symbol type
+func (r NamingRule) appliesTo(symbolType types.SymbolType) bool {
+	for _, t := range r.Types {
+		if t == symbolType {
+			return true
+		}
+	}
+	return false
+}
+
+// defaultNamingRules returns the built-in conventions, keyed by language
+func defaultNamingRules() map[string][]NamingRule {
+	return map[string][]NamingRule{
+		"go": {
+			{
+				Convention: "mixedCaps",
+				Types: []types.SymbolType{
+					types.SymbolTypeFunction, types.SymbolTypeMethod, types.SymbolTypeStruct,
+					types.SymbolTypeInterface, types.SymbolTypeType, types.SymbolTypeConstant,
+					types.SymbolTypeVariable,
+				},
+				Expected: toMixedCaps,
+			},
+		},
+		"python": {
+			{
+				Convention: "snake_case",
+				Types:      []types.SymbolType{types.SymbolTypeFunction, types.SymbolTypeMethod},
+				Expected:   toSnakeCase,
+			},
+			{
+				Convention: "PascalCase",
+				Types:      []types.SymbolType{types.SymbolTypeClass},
+				Expected:   toPascalCase,
+			},
+		},
+	}
+}
+
+// namingRules returns the built-in conventions followed by the
+// configuration's own
+func namingRules(cfg *Config) map[string][]NamingRule {
+	rules := defaultNamingRules()
+	for language, extra := range cfg.NamingRules {
+		rules[language] = append(rules[language], extra...)
+	}
+	return rules
+}
+
+// AddNamingRule adds a naming convention checked by CheckNamingConventions
+func (idx *Indexer) AddNamingRule(language string, rule NamingRule) {
+	idx.namingRules[language] = append(idx.namingRules[language], rule)
+}
+
+// CheckNamingConventions reports symbols whose names don't follow the naming
+// rules of their language. Test files are skipped.
+func (idx *Indexer) CheckNamingConventions() ([]*types.NamingIssue, error) {
+	files, err := idx.db.GetAllFilesForProject(idx.project.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].RelativePath < files[j].RelativePath
+	})
+
+	issues := []*types.NamingIssue{}
+	for _, file := range files {
+		rules := idx.namingRules[file.Language]
+		if len(rules) == 0 || isTestFile(file.RelativePath, file.Language) {
+			continue
+		}
+
+		symbols, err := idx.db.GetSymbolsByFile(file.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, symbol := range symbols {
+			for _, rule := range rules {
+				if !rule.appliesTo(symbol.Type) {
+					continue
+				}
+				if expected := rule.Expected(symbol.Name); expected != symbol.Name {
+					issues = append(issues, &types.NamingIssue{
+						Symbol:     symbol,
+						FilePath:   file.RelativePath,
+						Convention: rule.Convention,
+						Expected:   expected,
+					})
+				}
+			}
+		}
+	}
+
+	return issues, nil
+}
+
+// toMixedCaps removes underscores Go-style, keeping the case of the first
+// letter so exported names stay exported ("get_user_id" -> "getUserId").
+// UPPER_CASE words are title-cased ("MAX_SIZE" -> "MaxSize").
+func toMixedCaps(name string) string {
+	if name == "_" || !strings.Contains(name, "_") {
+		return name
+	}
+	upper := name == strings.ToUpper(name)
+
+	var b strings.Builder
+	for _, part := range strings.Split(name, "_") {
+		if upper {
+			part = strings.ToLower(part)
+		}
+		if b.Len() == 0 && !upper {
+			b.WriteString(part)
+			continue
+		}
+		b.WriteString(upperFirst(part))
+	}
+	return b.String()
+}
+
+// toSnakeCase lowercases and underscore-joins the words of name, keeping
+// leading and trailing underscores and leaving dunder names alone
+func toSnakeCase(name string) string {
+	if strings.HasPrefix(name, "__") && strings.HasSuffix(name, "__") {
+		return name
+	}
+
+	base := strings.Trim(name, "_")
+	if base == strings.ToUpper(base) {
+		return name // UPPER_CASE constants are conventional too
+	}
+
+	prefix := name[:strings.Index(name, base)]
+	suffix := name[len(prefix)+len(base):]
+	return prefix + strings.Join(utils.SplitIdentifier(base), "_") + suffix
+}
+
+// toPascalCase capitalises the words of name, keeping leading underscores.
+// Names that are already PascalCase are returned as-is so acronyms survive.
+func toPascalCase(name string) string {
+	rest := strings.TrimLeft(name, "_")
+	prefix := name[:len(name)-len(rest)]
+
+	r, _ := utf8.DecodeRuneInString(rest)
+	if unicode.IsUpper(r) && !strings.Contains(rest, "_") {
+		return name
+	}
+
+	var b strings.Builder
+	b.WriteString(prefix)
+	for _, word := range utils.SplitIdentifier(rest) {
+		b.WriteString(upperFirst(word))
+	}
+	return b.String()
+}
+
+// upperFirst uppercases the first letter of s
+func upperFirst(s string) string {
+	r, size := utf8.DecodeRuneInString(s)
+	if r == utf8.RuneError {
+		return s
+	}
+	return string(unicode.ToUpper(r)) + s[size:]
+}
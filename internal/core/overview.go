@@ -0,0 +1,63 @@
+package core
+
+import (
+	"sort"
+
+	"github.com/aaamil13/CodeIndexerMCP/pkg/types"
+)
+
+const (
+	overviewTopLevelLimit = 50 // Exported top-level symbols listed in a detailed overview
+	overviewRecentLimit   = 10 // Recently modified files listed in a detailed overview
+)
+
+// detailedProjectOverview builds the overview from the file records, adding
+// per-language file counts, exported top-level symbols and recent changes
+func (idx *Indexer) detailedProjectOverview() (*types.ProjectOverview, error) {
+	files, err := idx.db.GetAllFilesForProject(idx.project.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	counts, err := idx.db.CountSymbolsByFile(idx.project.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	overview := &types.ProjectOverview{
+		Project:          idx.project,
+		TotalFiles:       len(files),
+		LanguageStats:    make(map[string]int),
+		TopLevelSymbols:  []*types.Symbol{},
+		RecentlyModified: []*types.File{},
+	}
+
+	for _, file := range files {
+		overview.LanguageStats[file.Language]++
+		overview.TotalSymbols += counts[file.ID]
+
+		if counts[file.ID] == 0 || len(overview.TopLevelSymbols) >= overviewTopLevelLimit {
+			continue
+		}
+
+		symbols, err := idx.db.GetSymbolsByFile(file.ID)
+		if err != nil {
+			return nil, err
+		}
+		for _, symbol := range symbols {
+			if symbol.ParentID == nil && symbol.IsExported && len(overview.TopLevelSymbols) < overviewTopLevelLimit {
+				overview.TopLevelSymbols = append(overview.TopLevelSymbols, symbol)
+			}
+		}
+	}
+
+	recent := make([]*types.File, len(files))
+	copy(recent, files)
+	sort.Slice(recent, func(i, j int) bool { return recent[i].LastModified.After(recent[j].LastModified) })
+	if len(recent) > overviewRecentLimit {
+		recent = recent[:overviewRecentLimit]
+	}
+	overview.RecentlyModified = recent
+
+	return overview, nil
+}
@@ -0,0 +1,186 @@
+package core
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"os/exec"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aaamil13/CodeIndexerMCP/internal/utils"
+	"github.com/aaamil13/CodeIndexerMCP/pkg/types"
+)
+
+// GetSymbolOwnership runs git blame over the named symbol's lines and
+// aggregates who wrote them, most lines first, along with who touched them
+// last. filePath picks the declaration when several files declare the name.
+// When git blame fails, for instance outside a git repository or for files
+// git doesn't track, the ownership has no contributors and git's error as
+// its warning.
+func (idx *Indexer) GetSymbolOwnership(name, filePath string) (*types.Ownership, error) {
+	symbol, file, err := idx.ownershipTarget(name, filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	end := symbol.EndLine
+	if end < symbol.StartLine {
+		// The Python parser records only where functions start
+		end = symbol.StartLine
+		if content, err := utils.ReadFileUTF8(file.Path, file.Encoding); err == nil {
+			if body := indentedBody(symbol, strings.Split(string(content), "\n")); body != "" {
+				end += strings.Count(body, "\n") + 1
+			}
+		}
+	}
+
+	ownership := &types.Ownership{
+		Symbol:       symbol.Name,
+		FilePath:     file.RelativePath,
+		StartLine:    symbol.StartLine,
+		EndLine:      end,
+		Contributors: []*types.Contributor{},
+	}
+
+	lines, err := gitBlame(idx.projectPath, file.RelativePath, symbol.StartLine, end)
+	if err != nil {
+		ownership.Warning = err.Error()
+		return ownership, nil
+	}
+
+	byAuthor := make(map[string]*types.Contributor)
+	committed := 0
+	for _, line := range lines {
+		if line.uncommitted {
+			continue
+		}
+		committed++
+
+		contributor, ok := byAuthor[line.email]
+		if !ok {
+			contributor = &types.Contributor{Name: line.author, Email: line.email}
+			byAuthor[line.email] = contributor
+			ownership.Contributors = append(ownership.Contributors, contributor)
+		}
+		contributor.Lines++
+
+		if line.authored.After(ownership.LastModified) {
+			ownership.LastModified = line.authored
+			ownership.LastModifiedBy = line.author
+		}
+	}
+
+	for _, contributor := range ownership.Contributors {
+		contributor.Share = float64(contributor.Lines) / float64(committed)
+	}
+	sort.SliceStable(ownership.Contributors, func(i, j int) bool {
+		return ownership.Contributors[i].Lines > ownership.Contributors[j].Lines
+	})
+	if committed == 0 && len(lines) > 0 {
+		ownership.Warning = "the symbol's lines are not committed yet"
+	}
+
+	return ownership, nil
+}
+
+// ownershipTarget returns the symbol GetSymbolOwnership blames and its
+// file: the only one with the given name, in filePath when it is given
+func (idx *Indexer) ownershipTarget(name, filePath string) (*types.Symbol, *types.File, error) {
+	relPath := ""
+	if filePath != "" {
+		var err error
+		if relPath, err = idx.resolveFilePath(filePath); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	symbols, err := idx.db.GetSymbolsByName(idx.project.ID, name)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var matching []*types.Symbol
+	files := make(map[int64]*types.File)
+	for _, symbol := range symbols {
+		file, err := idx.db.GetFile(symbol.FileID)
+		if err != nil {
+			return nil, nil, err
+		}
+		if file == nil || (relPath != "" && file.RelativePath != relPath) {
+			continue
+		}
+		files[symbol.ID] = file
+		matching = append(matching, symbol)
+	}
+
+	switch len(matching) {
+	case 0:
+		return nil, nil, fmt.Errorf("symbol not found: %s", name)
+	case 1:
+		return matching[0], files[matching[0].ID], nil
+	}
+
+	locations := make([]string, len(matching))
+	for i, symbol := range matching {
+		locations[i] = fmt.Sprintf("%s:%d (%s)", files[symbol.ID].RelativePath, symbol.StartLine, symbol.Type)
+	}
+	return nil, nil, fmt.Errorf("symbol %s is declared at %s; specify a file path", name, strings.Join(locations, ", "))
+}
+
+// blameLine is the authorship of one line as reported by git blame
+type blameLine struct {
+	author      string
+	email       string
+	authored    time.Time
+	uncommitted bool
+}
+
+// gitBlame blames lines start through end of a file relative to dir
+func gitBlame(dir, relPath string, start, end int) ([]*blameLine, error) {
+	if _, err := exec.LookPath("git"); err != nil {
+		return nil, fmt.Errorf("git is not installed")
+	}
+
+	cmd := exec.Command("git", "blame", "--line-porcelain", "-L", fmt.Sprintf("%d,%d", start, end), "--", relPath)
+	cmd.Dir = dir
+	output, err := cmd.Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && len(bytes.TrimSpace(exitErr.Stderr)) > 0 {
+			return nil, fmt.Errorf("git blame failed for %s: %s", relPath, bytes.TrimSpace(exitErr.Stderr))
+		}
+		return nil, fmt.Errorf("git blame failed for %s: %w", relPath, err)
+	}
+
+	// Each line's block starts with its commit hash and ends with its
+	// tab-prefixed content
+	var lines []*blameLine
+	var current *blameLine
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		text := scanner.Text()
+		switch {
+		case current == nil:
+			hash, _, _ := strings.Cut(text, " ")
+			current = &blameLine{uncommitted: strings.Trim(hash, "0") == ""}
+		case strings.HasPrefix(text, "\t"):
+			lines = append(lines, current)
+			current = nil
+		case strings.HasPrefix(text, "author "):
+			current.author = strings.TrimPrefix(text, "author ")
+		case strings.HasPrefix(text, "author-mail "):
+			current.email = strings.Trim(strings.TrimPrefix(text, "author-mail "), "<>")
+		case strings.HasPrefix(text, "author-time "):
+			if seconds, err := strconv.ParseInt(strings.TrimPrefix(text, "author-time "), 10, 64); err == nil {
+				current.authored = time.Unix(seconds, 0).UTC()
+			}
+		}
+	}
+
+	return lines, scanner.Err()
+}
@@ -0,0 +1,199 @@
+package core
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/aaamil13/CodeIndexerMCP/pkg/types"
+)
+
+// GetParameters returns the parameters of the named function: name, type,
+// default and whether it's variadic. They come from the details its parser
+// recorded at indexing time, or failing that are parsed from its signature.
+func (idx *Indexer) GetParameters(name string) ([]*types.Parameter, error) {
+	symbol, err := idx.lookupFunction(name)
+	if err != nil {
+		return nil, err
+	}
+
+	params, _, err := idx.functionSignature(symbol)
+	return params, err
+}
+
+// lookupFunction returns the first function, method or constructor in the
+// project with the given name
+func (idx *Indexer) lookupFunction(name string) (*types.Symbol, error) {
+	symbols, err := idx.db.GetSymbolsByName(idx.project.ID, name)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, symbol := range symbols {
+		if isCallable(symbol) {
+			return symbol, nil
+		}
+	}
+	if len(symbols) > 0 {
+		return nil, fmt.Errorf("symbol is not a function: %s", name)
+	}
+	return nil, fmt.Errorf("symbol not found: %s", name)
+}
+
+// functionSignature returns a function's parameters and return type as
+// recorded by its parser, or failing that its parameters parsed from the
+// signature and no return type
+func (idx *Indexer) functionSignature(symbol *types.Symbol) ([]*types.Parameter, string, error) {
+	details, err := idx.db.GetFunctionDetails(symbol.ID)
+	if err != nil {
+		return nil, "", err
+	}
+	if details != nil {
+		return details.Parameters, details.ReturnType, nil
+	}
+
+	language := ""
+	if file, err := idx.db.GetFile(symbol.FileID); err != nil {
+		return nil, "", err
+	} else if file != nil {
+		language = file.Language
+	}
+
+	return signatureParameters(symbol.Signature, symbol.Name, language), "", nil
+}
+
+// colonTypedLanguages write parameters as "name: Type"
+var colonTypedLanguages = map[string]bool{
+	"typescript": true, "javascript": true, "python": true, "rust": true,
+	"kotlin": true, "swift": true, "scala": true,
+}
+
+// signatureParameters splits the parameter list of a function signature,
+// reading each parameter by its language's convention: "name Type" in Go,
+// "name: Type" in TypeScript, Python, Rust and Kotlin, and "Type name"
+// elsewhere
+func signatureParameters(signature, name, language string) []*types.Parameter {
+	if i := strings.Index(signature, name+"("); i >= 0 && name != "" {
+		signature = signature[i+len(name):]
+	}
+	open := strings.Index(signature, "(")
+	if open < 0 {
+		return []*types.Parameter{}
+	}
+
+	// Find the matching close paren
+	list, depth := signature[open+1:], 1
+	for i, r := range list {
+		if r == '(' {
+			depth++
+		} else if r == ')' {
+			if depth--; depth == 0 {
+				list = list[:i]
+				break
+			}
+		}
+	}
+
+	params := []*types.Parameter{}
+	for _, raw := range splitParameters(list) {
+		param := strings.TrimSpace(raw)
+		if param == "" || param == "*" || param == "/" {
+			continue
+		}
+
+		parameter := &types.Parameter{}
+		if i := strings.Index(param, "="); i >= 0 && !strings.HasPrefix(param[i:], "=>") {
+			parameter.Default = strings.TrimSpace(param[i+1:])
+			param = strings.TrimSpace(param[:i])
+		}
+
+		switch {
+		case language == "go":
+			fields := strings.Fields(param)
+			parameter.Name = fields[0]
+			if len(fields) > 1 {
+				parameter.Type = strings.Join(fields[1:], " ")
+			}
+			if strings.HasPrefix(parameter.Type, "...") {
+				parameter.Type, parameter.Variadic = strings.TrimPrefix(parameter.Type, "..."), true
+			}
+		case colonTypedLanguages[language]:
+			if i := strings.Index(param, ":"); i >= 0 {
+				parameter.Type = strings.TrimSpace(param[i+1:])
+				param = strings.TrimSpace(param[:i])
+			}
+			for _, modifier := range []string{"vararg ", "mut "} {
+				if strings.HasPrefix(param, modifier) {
+					parameter.Variadic = parameter.Variadic || modifier == "vararg "
+					param = strings.TrimSpace(strings.TrimPrefix(param, modifier))
+				}
+			}
+			if strings.HasPrefix(param, "...") || strings.HasPrefix(param, "*") {
+				parameter.Variadic = true
+			}
+			parameter.Name = strings.TrimLeft(param, ".*")
+		default:
+			if strings.HasPrefix(param, "params ") {
+				parameter.Variadic = true // C#
+				param = strings.TrimPrefix(param, "params ")
+			}
+			cut := strings.LastIndexAny(param, " \t*&")
+			parameter.Name = strings.TrimSpace(param[cut+1:])
+			parameter.Type = strings.TrimSpace(param[:cut+1])
+			if strings.HasPrefix(parameter.Name, "...") {
+				parameter.Name = strings.TrimPrefix(parameter.Name, "...") // PHP
+				parameter.Variadic = true
+			}
+			if strings.HasSuffix(parameter.Type, "...") {
+				parameter.Type, parameter.Variadic = strings.TrimSpace(strings.TrimSuffix(parameter.Type, "...")), true
+			}
+		}
+		params = append(params, parameter)
+	}
+
+	// Go's "a, b int" shares the type of the last name in the group
+	if language == "go" {
+		for i := len(params) - 2; i >= 0; i-- {
+			if params[i].Type == "" {
+				params[i].Type = params[i+1].Type
+			}
+		}
+	}
+
+	return params
+}
+
+// splitParameters splits a parameter list at commas that aren't nested in
+// brackets, generics or quotes. Arrows (=>, ->, <-) and comparisons in
+// default values aren't generic brackets: a < only opens one when a type
+// could follow it, and a > only closes one that is open.
+func splitParameters(list string) []string {
+	var parts []string
+	depth, angles, start := 0, 0, 0
+	var quote rune
+	for i, r := range list {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case r == '\'' || r == '"' || r == '`':
+			quote = r
+		case strings.ContainsRune("([{", r):
+			depth++
+		case strings.ContainsRune(")]}", r):
+			depth--
+		case r == '<':
+			if next := list[i+1:]; next != "" && !strings.ContainsAny(next[:1], "-= \t") {
+				angles++
+			}
+		case r == '>':
+			if angles > 0 && (i == 0 || !strings.ContainsAny(list[i-1:i], "=-")) {
+				angles--
+			}
+		case r == ',' && depth == 0 && angles == 0:
+			parts = append(parts, list[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, list[start:])
+}
@@ -0,0 +1,209 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime/debug"
+	"sync"
+
+	"github.com/aaamil13/CodeIndexerMCP/internal/parser"
+	"github.com/aaamil13/CodeIndexerMCP/internal/utils"
+	"github.com/aaamil13/CodeIndexerMCP/pkg/types"
+)
+
+// cacheVersion is bumped whenever the cached form of a parse result changes,
+// so entries written by older builds are ignored
+const cacheVersion = 1
+
+// parseCacheKey identifies a cached parse result: the content hash and the
+// parser that produced it
+type parseCacheKey struct {
+	Language string `json:"language"`
+	Version  string `json:"version,omitempty"` // Of the build and the parser
+	Hash     string `json:"hash"`
+}
+
+// buildVersion identifies the running build, so that parser changes shipped
+// in a new build drop results cached by an older one even when the parser
+// has no version of its own. It is the module version of a released build,
+// the VCS revision of a clean checkout, and otherwise a hash of the
+// executable.
+var buildVersion = sync.OnceValue(func() string {
+	if info, ok := debug.ReadBuildInfo(); ok {
+		if v := info.Main.Version; v != "" && v != "(devel)" {
+			return v
+		}
+		var revision, modified string
+		for _, setting := range info.Settings {
+			switch setting.Key {
+			case "vcs.revision":
+				revision = setting.Value
+			case "vcs.modified":
+				modified = setting.Value
+			}
+		}
+		if revision != "" && modified == "false" {
+			return revision
+		}
+	}
+
+	if path, err := os.Executable(); err == nil {
+		if hash, err := utils.HashFile(path); err == nil {
+			return hash
+		}
+	}
+	return ""
+})
+
+// newParseCacheKey returns the key the result of parsing content with the
+// given hash is cached under
+func newParseCacheKey(p types.Parser, hash string) parseCacheKey {
+	return parseCacheKey{Language: p.Language(), Version: buildVersion() + "/" + parser.Version(p), Hash: hash}
+}
+
+// parseCacheEntry is the on-disk form of a cached parse result
+type parseCacheEntry struct {
+	parseCacheKey
+	CacheVersion int                `json:"cache_version"`
+	Result       *types.ParseResult `json:"result"`
+}
+
+// parseCache stores parse results on disk keyed by file content hash, so
+// unchanged files needn't be re-parsed when the database is rebuilt
+type parseCache struct {
+	dir string
+}
+
+// newParseCache creates a parse cache in dir
+func newParseCache(dir string) (*parseCache, error) {
+	if err := utils.EnsureDir(dir); err != nil {
+		return nil, err
+	}
+	return &parseCache{dir: dir}, nil
+}
+
+// name returns the file name the result for key is stored under. The
+// version is hashed since it may contain characters not allowed in names.
+func (c *parseCache) name(key parseCacheKey) string {
+	return fmt.Sprintf("%s-v%d-%s-%s.json", key.Language, cacheVersion, utils.HashBytes([]byte(key.Version))[:8], key.Hash)
+}
+
+// get returns the cached result for key, if any
+func (c *parseCache) get(key parseCacheKey) (*types.ParseResult, bool) {
+	data, err := os.ReadFile(filepath.Join(c.dir, c.name(key)))
+	if err != nil {
+		return nil, false
+	}
+
+	var entry parseCacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil || entry.Result == nil {
+		return nil, false
+	}
+
+	// A mismatch means the entry is corrupt or from another file; ignore it
+	if entry.parseCacheKey != key || entry.CacheVersion != cacheVersion {
+		return nil, false
+	}
+
+	return entry.Result, true
+}
+
+// put stores a parse result under key
+func (c *parseCache) put(key parseCacheKey, result *types.ParseResult) error {
+	data, err := json.Marshal(&parseCacheEntry{parseCacheKey: key, CacheVersion: cacheVersion, Result: result})
+	if err != nil {
+		return err
+	}
+
+	// Write to a temp file first so concurrent readers never see a partial entry
+	tmp, err := os.CreateTemp(c.dir, "entry-*.tmp")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+
+	return os.Rename(tmp.Name(), filepath.Join(c.dir, c.name(key)))
+}
+
+// prune removes every entry whose key isn't in keep and returns how many
+// were removed
+func (c *parseCache) prune(keep []parseCacheKey) (int, error) {
+	kept := make(map[string]bool, len(keep))
+	for _, key := range keep {
+		kept[c.name(key)] = true
+	}
+
+	entries, err := filepath.Glob(filepath.Join(c.dir, "*.json"))
+	if err != nil {
+		return 0, err
+	}
+
+	removed := 0
+	for _, entry := range entries {
+		if kept[filepath.Base(entry)] {
+			continue
+		}
+		if err := os.Remove(entry); err != nil && !os.IsNotExist(err) {
+			return removed, err
+		}
+		removed++
+	}
+	return removed, nil
+}
+
+// cachedParseResult looks up a parse result for content with the given hash
+func (idx *Indexer) cachedParseResult(p types.Parser, hash string) (*types.ParseResult, bool) {
+	if idx.parseCache == nil {
+		return nil, false
+	}
+	return idx.parseCache.get(newParseCacheKey(p, hash))
+}
+
+// cacheParseResult stores a fresh parse result. It must be called before the
+// result's symbols are saved, while they are still exactly as parsed.
+func (idx *Indexer) cacheParseResult(p types.Parser, hash string, result *types.ParseResult) {
+	if idx.parseCache == nil {
+		return
+	}
+	if err := idx.parseCache.put(newParseCacheKey(p, hash), result); err != nil {
+		idx.logger.Warnf("Failed to cache parse result: %v", err)
+	}
+}
+
+// pruneParseCache removes cached results that no indexed file's content and
+// current parser would look up
+func (idx *Indexer) pruneParseCache() error {
+	if idx.parseCache == nil {
+		return nil
+	}
+
+	files, err := idx.db.GetAllFilesForProject(idx.project.ID)
+	if err != nil {
+		return err
+	}
+
+	keep := make([]parseCacheKey, 0, len(files))
+	for _, file := range files {
+		p, err := idx.parsers.GetParser(file.Language)
+		if err != nil {
+			continue
+		}
+		keep = append(keep, newParseCacheKey(p, file.Hash))
+	}
+
+	removed, err := idx.parseCache.prune(keep)
+	if removed > 0 {
+		idx.logger.Debugf("Pruned %d stale parse cache entries", removed)
+	}
+	return err
+}
@@ -0,0 +1,224 @@
+package core
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/aaamil13/CodeIndexerMCP/internal/utils"
+	"github.com/aaamil13/CodeIndexerMCP/pkg/types"
+)
+
+// DefaultPromptBundleCallees is the number of callees bundled by default
+const DefaultPromptBundleCallees = 5
+
+// PromptBundleMaxChars is the character budget of a prompt bundle. The
+// target's own source always counts toward it, even past the limit.
+const PromptBundleMaxChars = 16000
+
+// GetPromptBundle assembles the context for working on the named symbol: its
+// source, the source of up to maxCallees functions it calls, in order of
+// first call, and the signatures of the functions calling it. Calls come
+// from the project's call graph. Callees and callers that would take the
+// bundle past PromptBundleMaxChars are left out. filePath is optional, but
+// required when several files declare a function of that name.
+func (idx *Indexer) GetPromptBundle(name, filePath string, maxCallees int) (*types.PromptBundle, error) {
+	if maxCallees <= 0 {
+		maxCallees = DefaultPromptBundleCallees
+	}
+
+	target, err := idx.promptBundleTarget(name, filePath)
+	if err != nil {
+		return nil, err
+	}
+	targetFile, err := idx.db.GetFile(target.FileID)
+	if err != nil {
+		return nil, err
+	}
+	if targetFile == nil {
+		return nil, fmt.Errorf("file not found for symbol: %s", name)
+	}
+
+	graph, _, err := idx.buildCallGraph()
+	if err != nil {
+		return nil, err
+	}
+
+	sources := make(map[int64][]string) // file ID -> source lines
+	linesOf := func(file *types.File) []string {
+		if lines, ok := sources[file.ID]; ok {
+			return lines
+		}
+		var lines []string
+		if content, err := utils.ReadFileUTF8(file.Path, file.Encoding); err == nil {
+			lines = strings.Split(string(content), "\n")
+		}
+		sources[file.ID] = lines
+		return lines
+	}
+
+	targetLines := linesOf(targetFile)
+	bundle := &types.PromptBundle{
+		Symbol:   bundledSymbol(target, targetFile, targetLines, true),
+		Callees:  []*types.BundledSymbol{},
+		Callers:  []*types.BundledSymbol{},
+		MaxChars: PromptBundleMaxChars,
+	}
+	bundle.TotalChars = len(bundle.Symbol.Source)
+
+	self := -1
+	for i, n := range graph.nodes {
+		if n.symbol.ID == target.ID {
+			self = i
+			break
+		}
+	}
+	if self < 0 {
+		return bundle, nil // Not a function, so it calls nothing
+	}
+
+	// Callees in the order the target first calls them
+	type callee struct {
+		node   *callNode
+		offset int
+	}
+	body := symbolBody(target, strings.Split(utils.StripCommentsAndStrings(strings.Join(targetLines, "\n"), targetFile.Language), "\n"))
+	firstCall := make(map[string]int)
+	for _, match := range callExpression.FindAllStringSubmatchIndex(body, -1) {
+		if _, ok := firstCall[body[match[2]:match[3]]]; !ok {
+			firstCall[body[match[2]:match[3]]] = match[2]
+		}
+	}
+	var callees []callee
+	for _, t := range graph.calls[self] {
+		offset, ok := firstCall[graph.nodes[t].symbol.Name]
+		if !ok {
+			offset = len(body) // Recorded by the parser rather than found in the body
+		}
+		callees = append(callees, callee{node: graph.nodes[t], offset: offset})
+	}
+
+	var callers []*callNode
+	for i, calls := range graph.calls {
+		for _, t := range calls {
+			if t == self {
+				callers = append(callers, graph.nodes[i])
+				break
+			}
+		}
+	}
+
+	sort.SliceStable(callees, func(i, j int) bool {
+		return callees[i].offset < callees[j].offset
+	})
+	if len(callees) > maxCallees {
+		bundle.Omitted += len(callees) - maxCallees
+		callees = callees[:maxCallees]
+	}
+
+	for _, c := range callees {
+		entry := bundledSymbol(c.node.symbol, c.node.file, linesOf(c.node.file), true)
+		if bundle.TotalChars+len(entry.Source) > bundle.MaxChars {
+			bundle.Omitted++
+			continue
+		}
+		bundle.TotalChars += len(entry.Source)
+		bundle.Callees = append(bundle.Callees, entry)
+	}
+
+	for _, c := range callers {
+		entry := bundledSymbol(c.symbol, c.file, nil, false)
+		if bundle.TotalChars+len(entry.Signature) > bundle.MaxChars {
+			bundle.Omitted++
+			continue
+		}
+		bundle.TotalChars += len(entry.Signature)
+		bundle.Callers = append(bundle.Callers, entry)
+	}
+
+	return bundle, nil
+}
+
+// isCallable reports whether a symbol is something that can be called
+func isCallable(symbol *types.Symbol) bool {
+	switch symbol.Type {
+	case types.SymbolTypeFunction, types.SymbolTypeMethod, types.SymbolTypeConstructor:
+		return symbol.Name != ""
+	}
+	return false
+}
+
+// promptBundleTarget picks the symbol a prompt bundle is for among those
+// with the given name, narrowed to filePath when given. A function or
+// method wins over other kinds of symbol; several left is an error.
+func (idx *Indexer) promptBundleTarget(name, filePath string) (*types.Symbol, error) {
+	relPath := ""
+	if filePath != "" {
+		var err error
+		if relPath, err = idx.resolveFilePath(filePath); err != nil {
+			return nil, err
+		}
+	}
+
+	symbols, err := idx.db.GetSymbolsByName(idx.project.ID, name)
+	if err != nil {
+		return nil, err
+	}
+
+	var matching, callable []*types.Symbol
+	paths := make(map[int64]string)
+	for _, symbol := range symbols {
+		file, err := idx.db.GetFile(symbol.FileID)
+		if err != nil {
+			return nil, err
+		}
+		if file == nil || (relPath != "" && file.RelativePath != relPath) {
+			continue
+		}
+		paths[symbol.ID] = file.RelativePath
+		matching = append(matching, symbol)
+		if isCallable(symbol) {
+			callable = append(callable, symbol)
+		}
+	}
+	if len(callable) > 0 {
+		matching = callable
+	}
+
+	switch len(matching) {
+	case 0:
+		return nil, fmt.Errorf("symbol not found: %s", name)
+	case 1:
+		return matching[0], nil
+	}
+
+	locations := make([]string, len(matching))
+	for i, symbol := range matching {
+		locations[i] = fmt.Sprintf("%s:%d (%s)", paths[symbol.ID], symbol.StartLine, symbol.Type)
+	}
+	return nil, fmt.Errorf("symbol %s is declared at %s; specify a file path", name, strings.Join(locations, ", "))
+}
+
+// bundledSymbol describes a symbol for a prompt bundle with either its full
+// source or its signature, falling back to the signature for the source
+// when the file can't be read
+func bundledSymbol(symbol *types.Symbol, file *types.File, lines []string, withSource bool) *types.BundledSymbol {
+	entry := &types.BundledSymbol{
+		Name:      symbol.Name,
+		Type:      symbol.Type,
+		FilePath:  file.RelativePath,
+		StartLine: symbol.StartLine,
+		EndLine:   symbol.EndLine,
+	}
+
+	inRange := symbol.StartLine > 0 && symbol.StartLine <= symbol.EndLine && symbol.EndLine <= len(lines)
+	switch {
+	case !withSource:
+		entry.Signature = symbol.Signature
+	case inRange:
+		entry.Source = strings.Join(lines[symbol.StartLine-1:symbol.EndLine], "\n")
+	default:
+		entry.Source = symbol.Signature
+	}
+	return entry
+}
@@ -0,0 +1,40 @@
+package core
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/aaamil13/CodeIndexerMCP/internal/database"
+	"github.com/aaamil13/CodeIndexerMCP/pkg/types"
+)
+
+// DatabaseFile is the name of the index database in a project's index directory
+const DatabaseFile = "index.db"
+
+// PurgeProject deletes the project indexed from projectPath, with all its
+// files and symbols, from the index database at dbPath and returns it.
+// Databases keep the project of a repository's old path after it moves.
+func PurgeProject(dbPath, projectPath string) (*types.Project, error) {
+	if _, err := os.Stat(dbPath); err != nil {
+		return nil, fmt.Errorf("index database not found: %s", dbPath)
+	}
+
+	db, err := database.Open(dbPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+	defer db.Close()
+
+	project, err := db.GetProject(projectPath)
+	if err != nil {
+		return nil, err
+	}
+	if project == nil {
+		return nil, fmt.Errorf("project not found: %s", projectPath)
+	}
+
+	if err := db.DeleteProject(project.ID); err != nil {
+		return nil, fmt.Errorf("failed to delete project: %w", err)
+	}
+	return project, nil
+}
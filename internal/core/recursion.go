@@ -0,0 +1,124 @@
+package core
+
+import (
+	"sort"
+
+	"github.com/aaamil13/CodeIndexerMCP/pkg/types"
+)
+
+// Recursion kinds recorded in a symbol's "recursion" metadata
+const (
+	RecursionDirect = "direct" // The function calls itself
+	RecursionMutual = "mutual" // The function is part of a cycle of calls between functions
+)
+
+// FindRecursiveFunctions returns the functions that call themselves, either
+// directly or through other functions. Each symbol's "recursion" metadata
+// holds the kind of recursion; mutually recursive functions also carry the
+// names of their group's members in "recursion_group".
+func (idx *Indexer) FindRecursiveFunctions() ([]*types.Symbol, error) {
+	graph, files, err := idx.buildCallGraph()
+	if err != nil {
+		return nil, err
+	}
+
+	var recursive []*types.Symbol
+	for _, component := range stronglyConnected(graph.calls) {
+		if len(component) == 1 {
+			n := graph.nodes[component[0]]
+			if graph.selfCalls[component[0]] {
+				setMetadata(n.symbol, "recursion", RecursionDirect)
+				recursive = append(recursive, n.symbol)
+			}
+			continue
+		}
+
+		group := make([]string, len(component))
+		for i, member := range component {
+			group[i] = graph.nodes[member].symbol.Name
+		}
+		sort.Strings(group)
+
+		for _, member := range component {
+			symbol := graph.nodes[member].symbol
+			setMetadata(symbol, "recursion", RecursionMutual)
+			setMetadata(symbol, "recursion_group", group)
+			recursive = append(recursive, symbol)
+		}
+	}
+
+	paths := make(map[int64]string, len(files))
+	for _, file := range files {
+		paths[file.ID] = file.RelativePath
+	}
+	sort.Slice(recursive, func(i, j int) bool {
+		a, b := recursive[i], recursive[j]
+		if paths[a.FileID] != paths[b.FileID] {
+			return paths[a.FileID] < paths[b.FileID]
+		}
+		return a.StartLine < b.StartLine
+	})
+
+	return recursive, nil
+}
+
+// stronglyConnected returns the strongly connected components of a graph
+// given as adjacency lists, using Tarjan's algorithm
+func stronglyConnected(edges [][]int) [][]int {
+	index := make([]int, len(edges))
+	lowlink := make([]int, len(edges))
+	onStack := make([]bool, len(edges))
+	for i := range index {
+		index[i] = -1
+	}
+
+	var stack []int
+	var components [][]int
+	next := 0
+
+	var visit func(v int)
+	visit = func(v int) {
+		index[v], lowlink[v] = next, next
+		next++
+		stack = append(stack, v)
+		onStack[v] = true
+
+		for _, w := range edges[v] {
+			if index[w] < 0 {
+				visit(w)
+				lowlink[v] = min(lowlink[v], lowlink[w])
+			} else if onStack[w] {
+				lowlink[v] = min(lowlink[v], index[w])
+			}
+		}
+
+		if lowlink[v] == index[v] {
+			var component []int
+			for {
+				w := stack[len(stack)-1]
+				stack = stack[:len(stack)-1]
+				onStack[w] = false
+				component = append(component, w)
+				if w == v {
+					break
+				}
+			}
+			components = append(components, component)
+		}
+	}
+
+	for v := range edges {
+		if index[v] < 0 {
+			visit(v)
+		}
+	}
+	return components
+}
+
+// setMetadata sets a metadata value, creating the map if needed
+func setMetadata(symbol *types.Symbol, key string, value interface{}) {
+	if symbol.Metadata == nil {
+		symbol.Metadata = make(map[string]interface{})
+	}
+	symbol.Metadata[key] = value
+}
@@ -0,0 +1,170 @@
+package core
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/aaamil13/CodeIndexerMCP/internal/utils"
+	"github.com/aaamil13/CodeIndexerMCP/pkg/types"
+)
+
+// Weights of the signals combined by GetRelatedSymbols
+const (
+	relatedWeightRelationship = 3.0 // Direct relationship recorded in the index
+	relatedWeightCall         = 2.0 // One symbol's body mentions the other
+	relatedWeightSameFile     = 1.0 // Defined in the same file
+	relatedWeightTokens       = 2.0 // Scaled by name/doc token similarity (0..1)
+)
+
+// DefaultRelatedLimit is the number of related symbols returned by default
+const DefaultRelatedLimit = 10
+
+// GetRelatedSymbols ranks symbols by how closely they relate to the named one,
+// combining direct relationships, co-location and name/doc similarity
+func (idx *Indexer) GetRelatedSymbols(name string, limit int) ([]*types.ScoredSymbol, error) {
+	if limit <= 0 {
+		limit = DefaultRelatedLimit
+	}
+
+	targets, err := idx.db.GetSymbolsByName(idx.project.ID, name)
+	if err != nil {
+		return nil, err
+	}
+	if len(targets) == 0 {
+		return nil, fmt.Errorf("symbol not found: %s", name)
+	}
+	target := targets[0]
+
+	related := make(map[int64]bool)
+	rels, err := idx.db.GetRelationshipsForSymbol(target.ID)
+	if err != nil {
+		return nil, err
+	}
+	for _, rel := range rels {
+		related[rel.FromSymbolID] = true
+		related[rel.ToSymbolID] = true
+	}
+
+	files, err := idx.db.GetAllFilesForProject(idx.project.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Collect candidates first so the target's body is known before scoring
+	type candidate struct {
+		symbol *types.Symbol
+		file   *types.File
+		body   string
+	}
+	var candidates []candidate
+	var targetBody string
+
+	for _, file := range files {
+		symbols, err := idx.db.GetSymbolsByFile(file.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		var lines []string
+		if content, err := utils.ReadFileUTF8(file.Path, file.Encoding); err == nil {
+			lines = strings.Split(string(content), "\n")
+		}
+
+		for _, symbol := range symbols {
+			body := symbolBody(symbol, lines)
+			if symbol.ID == target.ID {
+				targetBody = body
+				continue
+			}
+			candidates = append(candidates, candidate{symbol: symbol, file: file, body: body})
+		}
+	}
+
+	mentionsTarget := mentionPattern(target.Name)
+	targetTokens := symbolTokens(target)
+
+	scored := []*types.ScoredSymbol{}
+	for _, c := range candidates {
+		result := &types.ScoredSymbol{Symbol: c.symbol, FilePath: c.file.RelativePath, Reasons: []string{}}
+
+		if related[c.symbol.ID] {
+			result.Score += relatedWeightRelationship
+			result.Reasons = append(result.Reasons, "relationship")
+		}
+
+		if mentionsTarget.MatchString(c.body) || mentionPattern(c.symbol.Name).MatchString(targetBody) {
+			result.Score += relatedWeightCall
+			result.Reasons = append(result.Reasons, "calls")
+		}
+
+		if c.symbol.FileID == target.FileID {
+			result.Score += relatedWeightSameFile
+			result.Reasons = append(result.Reasons, "same_file")
+		}
+
+		if similarity := tokenSimilarity(targetTokens, symbolTokens(c.symbol)); similarity > 0 {
+			result.Score += relatedWeightTokens * similarity
+			result.Reasons = append(result.Reasons, "similar_name")
+		}
+
+		if result.Score > 0 {
+			scored = append(scored, result)
+		}
+	}
+
+	sort.SliceStable(scored, func(i, j int) bool {
+		if scored[i].Score != scored[j].Score {
+			return scored[i].Score > scored[j].Score
+		}
+		return scored[i].Symbol.Name < scored[j].Symbol.Name
+	})
+
+	if len(scored) > limit {
+		scored = scored[:limit]
+	}
+
+	return scored, nil
+}
+
+// symbolBody returns the source lines of a symbol, excluding its declaration line
+// so a symbol does not count as mentioning itself
+func symbolBody(symbol *types.Symbol, lines []string) string {
+	if symbol.StartLine <= 0 || symbol.EndLine <= symbol.StartLine || symbol.EndLine > len(lines) {
+		return ""
+	}
+	return strings.Join(lines[symbol.StartLine:symbol.EndLine], "\n")
+}
+
+// mentionPattern matches a call or reference to name as a whole word
+func mentionPattern(name string) *regexp.Regexp {
+	return regexp.MustCompile(`\b` + regexp.QuoteMeta(name) + `\b`)
+}
+
+// symbolTokens returns the set of words in a symbol's name and documentation
+func symbolTokens(symbol *types.Symbol) map[string]bool {
+	tokens := make(map[string]bool)
+	for _, word := range utils.SplitIdentifier(symbol.Name + " " + symbol.Documentation) {
+		if len(word) > 2 { // Skip noise like "a", "to", "of"
+			tokens[word] = true
+		}
+	}
+	return tokens
+}
+
+// tokenSimilarity is the Jaccard similarity of two token sets
+func tokenSimilarity(a, b map[string]bool) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+
+	shared := 0
+	for token := range a {
+		if b[token] {
+			shared++
+		}
+	}
+
+	return float64(shared) / float64(len(a)+len(b)-shared)
+}
@@ -0,0 +1,130 @@
+package core
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/aaamil13/CodeIndexerMCP/internal/utils"
+	"github.com/aaamil13/CodeIndexerMCP/pkg/types"
+)
+
+// identifier matches the words of a line, e.g. the name under a cursor
+var identifier = regexp.MustCompile(`[A-Za-z_$][\w$]*`)
+
+// ResolveReference returns the definition of the symbol used at a position
+// in a file, with 1-based line and column. A reference recorded at the
+// position wins; otherwise the identifier under the cursor is resolved by
+// name, preferring definitions in the same file, then in files it imports,
+// then in the same directory and then in the same language.
+func (idx *Indexer) ResolveReference(filePath string, line, column int) (*types.Symbol, error) {
+	if line < 1 || column < 1 {
+		return nil, fmt.Errorf("invalid position: %d:%d", line, column)
+	}
+
+	file, err := idx.lookupFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	refs, err := idx.db.GetReferencesByFile(file.ID)
+	if err != nil {
+		return nil, err
+	}
+	for _, ref := range refs {
+		if ref.LineNumber != line {
+			continue
+		}
+		symbol, err := idx.db.GetSymbolByID(ref.SymbolID)
+		if err != nil {
+			return nil, err
+		}
+		if symbol != nil && (ref.ColumnNumber == 0 || (column >= ref.ColumnNumber && column < ref.ColumnNumber+len(symbol.Name))) {
+			return symbol, nil
+		}
+	}
+
+	content, err := utils.ReadFileUTF8(file.Path, file.Encoding)
+	if err != nil {
+		return nil, err
+	}
+	lines := strings.Split(string(content), "\n")
+	if line > len(lines) {
+		return nil, fmt.Errorf("line %d is past the end of %s", line, file.RelativePath)
+	}
+
+	var name string
+	for _, loc := range identifier.FindAllStringIndex(lines[line-1], -1) {
+		if column-1 >= loc[0] && column-1 < loc[1] {
+			name = lines[line-1][loc[0]:loc[1]]
+			break
+		}
+	}
+	if name == "" {
+		return nil, fmt.Errorf("no identifier at %s:%d:%d", file.RelativePath, line, column)
+	}
+
+	candidates, err := idx.db.GetSymbolsByName(idx.project.ID, name)
+	if err != nil {
+		return nil, err
+	}
+	if len(candidates) == 0 {
+		return nil, fmt.Errorf("symbol not found: %s", name)
+	}
+
+	imports, err := idx.db.GetImportsByFile(file.ID)
+	if err != nil {
+		return nil, err
+	}
+	imported := make(map[string]bool)
+	for _, imp := range imports {
+		source := strings.TrimRight(imp.Source, "/")
+		if i := strings.LastIndexAny(source, "/.\\"); i >= 0 {
+			source = source[i+1:]
+		}
+		imported[source] = true
+	}
+
+	var best *types.Symbol
+	bestScore := -1
+	files := map[int64]*types.File{file.ID: file}
+	for _, candidate := range candidates {
+		// A definition's own name resolves to itself
+		if candidate.FileID == file.ID && candidate.StartLine == line {
+			return candidate, nil
+		}
+
+		defFile, ok := files[candidate.FileID]
+		if !ok {
+			if defFile, err = idx.db.GetFile(candidate.FileID); err != nil {
+				return nil, err
+			}
+			files[candidate.FileID] = defFile
+		}
+		if defFile == nil {
+			continue
+		}
+
+		score := 0
+		switch {
+		case defFile.ID == file.ID:
+			score = 4
+		case imported[strings.TrimSuffix(filepath.Base(defFile.RelativePath), filepath.Ext(defFile.RelativePath))],
+			imported[filepath.Base(filepath.Dir(defFile.RelativePath))]:
+			score = 3
+		case filepath.Dir(defFile.RelativePath) == filepath.Dir(file.RelativePath):
+			score = 2
+		case defFile.Language == file.Language:
+			score = 1
+		}
+		if score > bestScore {
+			best, bestScore = candidate, score
+		}
+	}
+	if best == nil {
+		return nil, fmt.Errorf("symbol not found: %s", name)
+	}
+
+	return best, nil
+}
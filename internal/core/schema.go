@@ -0,0 +1,164 @@
+package core
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/aaamil13/CodeIndexerMCP/internal/parsers/sql"
+	"github.com/aaamil13/CodeIndexerMCP/pkg/types"
+)
+
+// isTable reports whether a symbol is a table parsed from CREATE TABLE
+func isTable(symbol *types.Symbol) bool {
+	return symbol.Type == types.SymbolTypeStruct && symbol.Metadata[sql.TableKey] == true
+}
+
+// linkForeignKeys records a "references" relationship from each table to the
+// tables its foreign key columns point at, matched by name across the
+// project. Each carries the column and its target as metadata.
+func (idx *Indexer) linkForeignKeys() error {
+	files, err := idx.db.GetAllFilesForProject(idx.project.ID)
+	if err != nil {
+		return err
+	}
+
+	for _, file := range files {
+		if file.Language != "sql" {
+			continue
+		}
+		symbols, err := idx.db.GetSymbolsByFile(file.ID)
+		if err != nil {
+			return err
+		}
+
+		tables := make(map[string]*types.Symbol)
+		for _, symbol := range symbols {
+			if isTable(symbol) {
+				tables[symbol.Name] = symbol
+			}
+		}
+
+		for _, column := range symbols {
+			ref, _ := column.Metadata[sql.ReferencesKey].(string)
+			tableName, _ := column.Metadata[sql.TableKey].(string)
+			from, ok := tables[tableName]
+			if ref == "" || !ok {
+				continue
+			}
+
+			target, _, _ := strings.Cut(ref, ".")
+			candidates, err := idx.db.GetSymbolsByName(idx.project.ID, target)
+			if err != nil {
+				return err
+			}
+			for _, candidate := range candidates {
+				if !isTable(candidate) {
+					continue
+				}
+				rel := &types.Relationship{
+					FromSymbolID: from.ID,
+					ToSymbolID:   candidate.ID,
+					Type:         types.RelationshipReferences,
+					Metadata: map[string]interface{}{
+						"column":          column.Name,
+						sql.ReferencesKey: ref,
+					},
+				}
+				if err := idx.db.SaveRelationship(rel); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// GetSchema returns the tables, views and indexes declared in the project's
+// SQL files. Tables are sorted by name and list the tables whose foreign
+// keys point at them.
+func (idx *Indexer) GetSchema() (*types.Schema, error) {
+	files, err := idx.db.GetAllFilesForProject(idx.project.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	schema := &types.Schema{
+		Tables:  []*types.SchemaTable{},
+		Views:   []*types.SchemaView{},
+		Indexes: []*types.SchemaIndex{},
+	}
+	for _, file := range files {
+		if file.Language != "sql" {
+			continue
+		}
+		symbols, err := idx.db.GetSymbolsByFile(file.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		tables := make(map[string]*types.SchemaTable)
+		for _, symbol := range symbols {
+			switch {
+			case isTable(symbol):
+				table := &types.SchemaTable{
+					Name:         symbol.Name,
+					FilePath:     file.RelativePath,
+					StartLine:    symbol.StartLine,
+					Columns:      []*types.SchemaColumn{},
+					ReferencedBy: []string{},
+				}
+				sources, err := idx.db.GetRelationshipSources(symbol.ID, types.RelationshipReferences)
+				if err != nil {
+					return nil, err
+				}
+				for _, source := range sources {
+					table.ReferencedBy = append(table.ReferencedBy, source.Name)
+				}
+				tables[symbol.Name] = table
+				schema.Tables = append(schema.Tables, table)
+
+			case symbol.Metadata[sql.ColumnKey] == true:
+				tableName, _ := symbol.Metadata[sql.TableKey].(string)
+				table, ok := tables[tableName]
+				if !ok {
+					continue
+				}
+				column := &types.SchemaColumn{
+					Name:       symbol.Name,
+					PrimaryKey: symbol.Metadata[sql.PrimaryKeyKey] == true,
+					NotNull:    symbol.Metadata[sql.NotNullKey] == true,
+				}
+				column.Type, _ = symbol.Metadata[sql.DataTypeKey].(string)
+				column.References, _ = symbol.Metadata[sql.ReferencesKey].(string)
+				table.Columns = append(table.Columns, column)
+
+			case symbol.Metadata[sql.IndexKey] == true:
+				index := &types.SchemaIndex{
+					Name:     symbol.Name,
+					FilePath: file.RelativePath,
+					Columns:  metadataStrings(symbol.Metadata, sql.ColumnsKey),
+					Unique:   symbol.Metadata[sql.UniqueKey] == true,
+				}
+				index.Table, _ = symbol.Metadata[sql.TableKey].(string)
+				if index.Columns == nil {
+					index.Columns = []string{}
+				}
+				schema.Indexes = append(schema.Indexes, index)
+
+			case symbol.Metadata[sql.ViewKey] == true:
+				schema.Views = append(schema.Views, &types.SchemaView{
+					Name:      symbol.Name,
+					FilePath:  file.RelativePath,
+					StartLine: symbol.StartLine,
+				})
+			}
+		}
+	}
+
+	sort.SliceStable(schema.Tables, func(i, j int) bool {
+		return schema.Tables[i].Name < schema.Tables[j].Name
+	})
+
+	return schema, nil
+}
@@ -0,0 +1,224 @@
+package core
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/aaamil13/CodeIndexerMCP/internal/utils"
+	"github.com/aaamil13/CodeIndexerMCP/pkg/types"
+)
+
+// DefaultSignatureSimilarity is the threshold the find_similar_signatures
+// tool uses when none is given
+const DefaultSignatureSimilarity = 0.8
+
+// FindSimilarSignatures returns functions whose parameter and return types
+// and bodies resemble the named function's, scored from 0 to 1 and keeping
+// those at or above threshold, best first; a threshold of 0 keeps them all.
+// Names don't count, so functions of the same shape are candidates for
+// extracting a shared interface.
+func (idx *Indexer) FindSimilarSignatures(name string, threshold float64) ([]*types.ScoredSymbol, error) {
+	if threshold < 0 || threshold > 1 {
+		return nil, fmt.Errorf("threshold must be between 0 and 1: %g", threshold)
+	}
+
+	target, err := idx.lookupFunction(name)
+	if err != nil {
+		return nil, err
+	}
+	targetParams, targetReturn, err := idx.functionSignature(target)
+	if err != nil {
+		return nil, err
+	}
+	targetParams = withoutReceiver(targetParams)
+	targetFile, targetLines, err := idx.symbolSource(target)
+	if err != nil {
+		return nil, err
+	}
+	targetBody := bodyTokens(targetLines, targetFile.Language, targetParams)
+
+	files, err := idx.db.GetAllFilesForProject(idx.project.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	similar := []*types.ScoredSymbol{}
+	for _, file := range files {
+		symbols, err := idx.db.GetSymbolsByFile(file.ID)
+		if err != nil {
+			return nil, err
+		}
+		var lines []string
+		if content, err := utils.ReadFileUTF8(file.Path, file.Encoding); err == nil {
+			lines = strings.Split(string(content), "\n")
+		}
+
+		for _, symbol := range symbols {
+			if symbol.ID == target.ID || !isCallable(symbol) {
+				continue
+			}
+			params, returnType, err := idx.functionSignature(symbol)
+			if err != nil {
+				return nil, err
+			}
+			params = withoutReceiver(params)
+
+			var body map[string]int
+			if symbol.StartLine > 0 && symbol.StartLine <= symbol.EndLine && symbol.EndLine <= len(lines) {
+				body = bodyTokens(lines[symbol.StartLine-1:symbol.EndLine], file.Language, params)
+			}
+
+			score, reasons := signatureSimilarity(
+				&functionShape{targetParams, targetReturn, targetBody},
+				&functionShape{params, returnType, body},
+			)
+			if score < threshold {
+				continue
+			}
+			similar = append(similar, &types.ScoredSymbol{
+				Symbol:   symbol,
+				FilePath: file.RelativePath,
+				Score:    score,
+				Reasons:  reasons,
+			})
+		}
+	}
+
+	sort.SliceStable(similar, func(i, j int) bool {
+		if similar[i].Score != similar[j].Score {
+			return similar[i].Score > similar[j].Score
+		}
+		if similar[i].FilePath != similar[j].FilePath {
+			return similar[i].FilePath < similar[j].FilePath
+		}
+		return similar[i].Symbol.StartLine < similar[j].Symbol.StartLine
+	})
+
+	return similar, nil
+}
+
+// withoutReceiver drops Python's explicit self or cls parameter
+func withoutReceiver(params []*types.Parameter) []*types.Parameter {
+	if len(params) > 0 && (params[0].Name == "self" || params[0].Name == "cls") && params[0].Type == "" {
+		return params[1:]
+	}
+	return params
+}
+
+// functionShape is what FindSimilarSignatures compares of a function
+type functionShape struct {
+	params     []*types.Parameter
+	returnType string
+	body       map[string]int // Tokens counted by bodyTokens
+}
+
+// signatureSimilarity compares two functions' shapes position by position.
+// Each parameter position and the return type scores 1 when the types match,
+// half when either is unknown and 0 otherwise, and the body scores by
+// bodySimilarity; the result is the average over the longer parameter list
+// plus the return type and the body. Counting the body keeps functions with
+// few parameters, such as every func() error, from matching on their
+// signatures alone.
+func signatureSimilarity(a, b *functionShape) (float64, []string) {
+	typeScore := func(x, y string) float64 {
+		x, y = normalizeType(x), normalizeType(y)
+		switch {
+		case x == "" || y == "":
+			return 0.5
+		case x == y:
+			return 1
+		}
+		return 0
+	}
+
+	positions := len(a.params)
+	if len(b.params) > positions {
+		positions = len(b.params)
+	}
+	shared := len(a.params) + len(b.params) - positions
+
+	var total float64
+	matched := 0
+	for i := 0; i < shared; i++ {
+		score := typeScore(a.params[i].Type, b.params[i].Type)
+		if a.params[i].Variadic != b.params[i].Variadic {
+			score /= 2
+		}
+		if score == 1 {
+			matched++
+		}
+		total += score
+	}
+
+	reasons := []string{}
+	if len(a.params) == len(b.params) {
+		reasons = append(reasons, "same_arity")
+		if matched == shared {
+			reasons = append(reasons, "same_parameter_types")
+		}
+	}
+
+	returnScore := 1.0
+	if normalizeType(a.returnType) != normalizeType(b.returnType) {
+		returnScore = typeScore(a.returnType, b.returnType)
+	} else {
+		reasons = append(reasons, "same_return_type")
+	}
+	total += returnScore + bodySimilarity(a.body, b.body)
+
+	return total / float64(positions+2), reasons
+}
+
+// bodyToken matches a word or a single punctuation character
+var bodyToken = regexp.MustCompile(`\w+|[^\s\w]`)
+
+// bodyTokens counts the tokens of a function's body, the lines after its
+// declaration without comments and strings. Parameters are counted as
+// "param" whatever their names, so bodies doing the same with differently
+// named parameters match.
+func bodyTokens(lines []string, language string, params []*types.Parameter) map[string]int {
+	if len(lines) < 2 {
+		return nil
+	}
+	names := make(map[string]bool, len(params))
+	for _, param := range params {
+		names[param.Name] = true
+	}
+
+	tokens := make(map[string]int)
+	body := utils.StripCommentsAndStrings(strings.Join(lines[1:], "\n"), language)
+	for _, token := range bodyToken.FindAllString(body, -1) {
+		if names[token] {
+			token = "param"
+		}
+		tokens[token]++
+	}
+	return tokens
+}
+
+// bodySimilarity scores how much two bodies' tokens overlap from 0 to 1, or
+// half when either body is unknown, as an unknown type is
+func bodySimilarity(a, b map[string]int) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0.5
+	}
+	shared, total := 0, 0
+	for token, n := range a {
+		m := b[token]
+		shared += min(n, m)
+		total += max(n, m)
+	}
+	for token, m := range b {
+		if _, ok := a[token]; !ok {
+			total += m
+		}
+	}
+	return float64(shared) / float64(total)
+}
+
+// normalizeType makes type names comparable by dropping whitespace
+func normalizeType(typ string) string {
+	return strings.Join(strings.Fields(typ), "")
+}
@@ -0,0 +1,175 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aaamil13/CodeIndexerMCP/internal/utils"
+	"github.com/aaamil13/CodeIndexerMCP/pkg/types"
+)
+
+// DefaultSnapshotFile is the snapshot file name inside the index directory
+const DefaultSnapshotFile = "snapshot.json"
+
+// SnapshotPath returns the default snapshot location for the project
+func (idx *Indexer) SnapshotPath() string {
+	return filepath.Join(idx.projectPath, idx.config.IndexDir, DefaultSnapshotFile)
+}
+
+// SnapshotSymbols records the current state of every indexed symbol
+func (idx *Indexer) SnapshotSymbols() (*types.IndexSnapshot, error) {
+	files, err := idx.db.GetAllFilesForProject(idx.project.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	snap := &types.IndexSnapshot{
+		ProjectPath: idx.projectPath,
+		CreatedAt:   time.Now(),
+		Symbols:     make(map[string]*types.SymbolSnapshot),
+	}
+
+	for _, file := range files {
+		symbols, err := idx.db.GetSymbolsByFile(file.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		// Source is optional; without it the hash covers signature and docs only
+		var lines []string
+		if content, err := utils.ReadFileUTF8(file.Path, file.Encoding); err == nil {
+			lines = strings.Split(string(content), "\n")
+		}
+
+		byID := make(map[int64]*types.Symbol, len(symbols))
+		for _, symbol := range symbols {
+			byID[symbol.ID] = symbol
+		}
+
+		for _, symbol := range symbols {
+			key := snapshotKey(file.RelativePath, symbol, byID)
+			// Disambiguate overloads and redeclarations by occurrence
+			for n := 2; snap.Symbols[key] != nil; n++ {
+				key = fmt.Sprintf("%s#%d", snapshotKey(file.RelativePath, symbol, byID), n)
+			}
+
+			snap.Symbols[key] = &types.SymbolSnapshot{
+				Key:       key,
+				ID:        symbol.ID,
+				Name:      symbol.Name,
+				Type:      symbol.Type,
+				FilePath:  file.RelativePath,
+				StartLine: symbol.StartLine,
+				Hash:      symbolContentHash(symbol, lines),
+			}
+		}
+	}
+
+	return snap, nil
+}
+
+// DiffAgainstSnapshot compares the current index with a saved snapshot
+func (idx *Indexer) DiffAgainstSnapshot(snap *types.IndexSnapshot) (*types.SymbolDiff, error) {
+	current, err := idx.SnapshotSymbols()
+	if err != nil {
+		return nil, err
+	}
+
+	diff := &types.SymbolDiff{
+		Added:    []*types.SymbolSnapshot{},
+		Removed:  []*types.SymbolSnapshot{},
+		Modified: []*types.SymbolSnapshot{},
+	}
+
+	for key, now := range current.Symbols {
+		before, ok := snap.Symbols[key]
+		switch {
+		case !ok:
+			diff.Added = append(diff.Added, now)
+		case before.Hash != now.Hash:
+			diff.Modified = append(diff.Modified, now)
+		}
+	}
+
+	for key, before := range snap.Symbols {
+		if _, ok := current.Symbols[key]; !ok {
+			diff.Removed = append(diff.Removed, before)
+		}
+	}
+
+	for _, list := range [][]*types.SymbolSnapshot{diff.Added, diff.Removed, diff.Modified} {
+		sort.Slice(list, func(i, j int) bool {
+			return list[i].Key < list[j].Key
+		})
+	}
+
+	return diff, nil
+}
+
+// SaveSnapshot writes a snapshot to a JSON file
+func SaveSnapshot(path string, snap *types.IndexSnapshot) error {
+	data, err := json.MarshalIndent(snap, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, data, 0644)
+}
+
+// LoadSnapshot reads a snapshot from a JSON file
+func LoadSnapshot(path string) (*types.IndexSnapshot, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var snap types.IndexSnapshot
+	if err := json.Unmarshal(data, &snap); err != nil {
+		return nil, fmt.Errorf("invalid snapshot %s: %w", path, err)
+	}
+	if snap.Symbols == nil {
+		snap.Symbols = make(map[string]*types.SymbolSnapshot)
+	}
+
+	return &snap, nil
+}
+
+// snapshotKey builds an identity for a symbol that survives re-indexing,
+// since database IDs change whenever a file is re-indexed. Members are
+// qualified by their parent, and Go methods, which have none, by the
+// receiver type in their signature.
+func snapshotKey(relPath string, symbol *types.Symbol, byID map[int64]*types.Symbol) string {
+	name := symbol.Name
+	if symbol.ParentID != nil {
+		if parent, ok := byID[*symbol.ParentID]; ok {
+			name = parent.Name + "." + name
+		}
+	} else if _, receiver := goReceiver(symbol.Signature); receiver != "" {
+		name = receiver + "." + name
+	}
+	return fmt.Sprintf("%s:%s:%s", relPath, symbol.Type, name)
+}
+
+// symbolContentHash hashes the parts of a symbol that constitute a change
+func symbolContentHash(symbol *types.Symbol, lines []string) string {
+	var content strings.Builder
+	content.WriteString(symbol.Signature)
+	content.WriteString("\x00")
+	content.WriteString(symbol.Documentation)
+	content.WriteString("\x00")
+
+	if symbol.StartLine > 0 && symbol.EndLine >= symbol.StartLine && symbol.EndLine <= len(lines) {
+		content.WriteString(strings.Join(lines[symbol.StartLine-1:symbol.EndLine], "\n"))
+	}
+
+	return utils.HashBytes([]byte(content.String()))
+}
@@ -0,0 +1,73 @@
+package core
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/aaamil13/CodeIndexerMCP/internal/utils"
+	"github.com/aaamil13/CodeIndexerMCP/pkg/types"
+)
+
+// GetSymbolSource returns the exact source text of the named symbol
+func (idx *Indexer) GetSymbolSource(name string) (string, error) {
+	source, err := idx.GetSymbolSourceDetails(name)
+	if err != nil {
+		return "", err
+	}
+	return source.Source, nil
+}
+
+// GetSymbolSourceDetails reads the named symbol's lines from disk, flagging
+// the result as stale when the file has changed since it was indexed
+func (idx *Indexer) GetSymbolSourceDetails(name string) (*types.SymbolSource, error) {
+	symbols, err := idx.db.GetSymbolsByName(idx.project.ID, name)
+	if err != nil {
+		return nil, err
+	}
+	if len(symbols) == 0 {
+		return nil, fmt.Errorf("symbol not found: %s", name)
+	}
+	symbol := symbols[0]
+
+	file, err := idx.db.GetFile(symbol.FileID)
+	if err != nil {
+		return nil, err
+	}
+
+	content, err := os.ReadFile(file.Path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+
+	result := &types.SymbolSource{
+		Name:      symbol.Name,
+		FilePath:  file.RelativePath,
+		StartLine: symbol.StartLine,
+		EndLine:   symbol.EndLine,
+	}
+
+	if utils.HashBytes(content) != file.Hash {
+		result.Stale = true
+		result.Warning = fmt.Sprintf("%s has changed since it was indexed; the source may not match the symbol", file.RelativePath)
+	}
+	if content, err = utils.ToUTF8(content, file.Encoding); err != nil {
+		return nil, err
+	}
+
+	lines := strings.Split(string(content), "\n")
+	if symbol.StartLine < 1 || symbol.StartLine > len(lines) {
+		return nil, fmt.Errorf("symbol %s is outside %s (line %d)", name, file.RelativePath, symbol.StartLine)
+	}
+
+	end := symbol.EndLine
+	if end < symbol.StartLine {
+		end = symbol.StartLine
+	}
+	if end > len(lines) {
+		end = len(lines)
+	}
+
+	result.Source = strings.Join(lines[symbol.StartLine-1:end], "\n")
+	return result, nil
+}
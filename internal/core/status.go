@@ -0,0 +1,139 @@
+package core
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/aaamil13/CodeIndexerMCP/pkg/types"
+)
+
+// lookupSymbol returns the first symbol in the project with the given name
+func (idx *Indexer) lookupSymbol(name string) (*types.Symbol, error) {
+	symbols, err := idx.db.GetSymbolsByName(idx.project.ID, name)
+	if err != nil {
+		return nil, err
+	}
+	if len(symbols) == 0 {
+		return nil, fmt.Errorf("symbol not found: %s", name)
+	}
+	return symbols[0], nil
+}
+
+// parseDevelopmentStatus validates a status name against the known statuses
+func parseDevelopmentStatus(status string) (types.DevelopmentStatus, error) {
+	s := types.DevelopmentStatus(status)
+	if !s.IsValid() {
+		valid := make([]string, len(types.DevelopmentStatuses))
+		for i, v := range types.DevelopmentStatuses {
+			valid[i] = string(v)
+		}
+		return "", fmt.Errorf("invalid status: %s (expected one of %s)", status, strings.Join(valid, ", "))
+	}
+	return s, nil
+}
+
+// SetSymbolStatus sets the development status of the named symbol
+func (idx *Indexer) SetSymbolStatus(name, status string) error {
+	s, err := parseDevelopmentStatus(status)
+	if err != nil {
+		return err
+	}
+
+	symbol, err := idx.lookupSymbol(name)
+	if err != nil {
+		return err
+	}
+
+	return idx.db.SetSymbolStatus(symbol.ID, s)
+}
+
+// AssignSymbol records the agent working on the named symbol; an empty agent
+// clears the assignment
+func (idx *Indexer) AssignSymbol(name, agent string) error {
+	symbol, err := idx.lookupSymbol(name)
+	if err != nil {
+		return err
+	}
+
+	return idx.db.SetSymbolAssignee(symbol.ID, agent)
+}
+
+// Bounds of a symbol's priority; symbols start at MinSymbolPriority
+const (
+	MinSymbolPriority = 0
+	MaxSymbolPriority = 100
+)
+
+// DefaultWorkQueueLimit is the number of symbols GetWorkQueue returns by default
+const DefaultWorkQueueLimit = 20
+
+// SetSymbolPriority sets the priority of the named symbol, which orders the
+// work queue
+func (idx *Indexer) SetSymbolPriority(name string, priority int) error {
+	if priority < MinSymbolPriority || priority > MaxSymbolPriority {
+		return fmt.Errorf("invalid priority: %d (expected %d to %d)", priority, MinSymbolPriority, MaxSymbolPriority)
+	}
+
+	symbol, err := idx.lookupSymbol(name)
+	if err != nil {
+		return err
+	}
+
+	return idx.db.SetSymbolPriority(symbol.ID, priority)
+}
+
+// GetWorkQueue returns up to limit symbols with the given status for agents
+// to pick up, highest priority first. The status defaults to not_started.
+func (idx *Indexer) GetWorkQueue(status string, limit int) ([]*types.TrackedSymbol, error) {
+	if status == "" {
+		status = string(types.StatusNotStarted)
+	}
+	if limit <= 0 {
+		limit = DefaultWorkQueueLimit
+	}
+
+	queue, err := idx.GetSymbolsByStatus(status)
+	if err != nil {
+		return nil, err
+	}
+	if len(queue) > limit {
+		queue = queue[:limit]
+	}
+	return queue, nil
+}
+
+// GetSymbolsByStatus returns the project's symbols with the given status,
+// highest priority first
+func (idx *Indexer) GetSymbolsByStatus(status string) ([]*types.TrackedSymbol, error) {
+	s, err := parseDevelopmentStatus(status)
+	if err != nil {
+		return nil, err
+	}
+
+	return idx.db.GetSymbolsByStatus(idx.project.ID, s)
+}
+
+// restoreTracking reapplies workflow state saved before a re-index to the
+// new symbols with the same name and type
+func (idx *Indexer) restoreTracking(tracked []*types.TrackedSymbol, symbols []*types.Symbol) error {
+	if len(tracked) == 0 {
+		return nil
+	}
+
+	byKey := make(map[string]types.SymbolTracking, len(tracked))
+	for _, t := range tracked {
+		byKey[string(t.Symbol.Type)+":"+t.Symbol.Name] = t.SymbolTracking
+	}
+
+	for _, symbol := range symbols {
+		tracking, ok := byKey[string(symbol.Type)+":"+symbol.Name]
+		if !ok {
+			continue
+		}
+		if err := idx.db.SaveSymbolTracking(symbol.ID, tracking); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
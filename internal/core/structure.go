@@ -0,0 +1,129 @@
+package core
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/aaamil13/CodeIndexerMCP/pkg/types"
+)
+
+// symbolKindRank orders symbol types for SymbolOrderKind; unlisted types sort last
+var symbolKindRank = map[types.SymbolType]int{
+	types.SymbolTypePackage:   0,
+	types.SymbolTypeModule:    1,
+	types.SymbolTypeConstant:  2,
+	types.SymbolTypeVariable:  3,
+	types.SymbolTypeInterface: 4,
+	types.SymbolTypeClass:     5,
+	types.SymbolTypeStruct:    6,
+	types.SymbolTypeEnum:      7,
+	types.SymbolTypeType:      8,
+	types.SymbolTypeFunction:  9,
+	types.SymbolTypeMethod:    10,
+}
+
+// sortSymbols sorts symbols in place by the requested order
+func sortSymbols(symbols []*types.Symbol, order types.SymbolOrder) error {
+	var less func(a, b *types.Symbol) bool
+
+	switch order {
+	case "", types.SymbolOrderLine:
+		less = func(a, b *types.Symbol) bool {
+			return a.StartLine < b.StartLine
+		}
+	case types.SymbolOrderName:
+		less = func(a, b *types.Symbol) bool {
+			an, bn := strings.ToLower(a.Name), strings.ToLower(b.Name)
+			if an != bn {
+				return an < bn
+			}
+			return a.StartLine < b.StartLine
+		}
+	case types.SymbolOrderKind:
+		less = func(a, b *types.Symbol) bool {
+			ar, br := kindRank(a.Type), kindRank(b.Type)
+			if ar != br {
+				return ar < br
+			}
+			return a.StartLine < b.StartLine
+		}
+	default:
+		return fmt.Errorf("unknown symbol order: %s", order)
+	}
+
+	sort.SliceStable(symbols, func(i, j int) bool {
+		return less(symbols[i], symbols[j])
+	})
+
+	return nil
+}
+
+// kindRank returns the sort rank of a symbol type
+func kindRank(t types.SymbolType) int {
+	if rank, ok := symbolKindRank[t]; ok {
+		return rank
+	}
+	return len(symbolKindRank)
+}
+
+// isContainerSymbol reports whether a symbol can hold nested members
+func isContainerSymbol(symbol *types.Symbol) bool {
+	switch symbol.Type {
+	case types.SymbolTypeClass, types.SymbolTypeStruct, types.SymbolTypeInterface, types.SymbolTypeEnum:
+		return true
+	}
+	return false
+}
+
+// NestSymbols groups already sorted symbols of one file into a tree. A symbol
+// is placed under its ParentID when set, otherwise under the innermost class
+// or type whose line range encloses it.
+func NestSymbols(symbols []*types.Symbol) []*types.SymbolNode {
+	nodes := make(map[*types.Symbol]*types.SymbolNode, len(symbols))
+	byID := make(map[int64]*types.Symbol, len(symbols))
+	for _, symbol := range symbols {
+		nodes[symbol] = &types.SymbolNode{Symbol: symbol}
+		byID[symbol.ID] = symbol
+	}
+
+	roots := []*types.SymbolNode{}
+	for _, symbol := range symbols {
+		parent := findParentSymbol(symbol, symbols, byID)
+		if parent == nil {
+			roots = append(roots, nodes[symbol])
+			continue
+		}
+		nodes[parent].Children = append(nodes[parent].Children, nodes[symbol])
+	}
+
+	return roots
+}
+
+// findParentSymbol returns the symbol that encloses symbol, if any
+func findParentSymbol(symbol *types.Symbol, symbols []*types.Symbol, byID map[int64]*types.Symbol) *types.Symbol {
+	if symbol.ParentID != nil {
+		if parent, ok := byID[*symbol.ParentID]; ok && parent != symbol {
+			return parent
+		}
+	}
+
+	var parent *types.Symbol
+	for _, candidate := range symbols {
+		if candidate == symbol || !isContainerSymbol(candidate) {
+			continue
+		}
+		if candidate.EndLine <= candidate.StartLine {
+			continue // No usable range
+		}
+		if symbol.StartLine <= candidate.StartLine || symbol.StartLine > candidate.EndLine {
+			continue
+		}
+		// Prefer the innermost container
+		if parent == nil || candidate.StartLine > parent.StartLine {
+			parent = candidate
+		}
+	}
+
+	return parent
+}
@@ -0,0 +1,169 @@
+package core
+
+import (
+	"path/filepath"
+	"strings"
+	"unicode"
+
+	"github.com/aaamil13/CodeIndexerMCP/pkg/types"
+)
+
+// isTestFile reports whether a file holds tests by its language's naming convention
+func isTestFile(relPath, language string) bool {
+	base := filepath.Base(relPath)
+	stem := strings.TrimSuffix(base, filepath.Ext(base))
+	dir := "/" + filepath.ToSlash(filepath.Dir(relPath)) + "/"
+
+	switch language {
+	case "go":
+		return strings.HasSuffix(base, "_test.go")
+	case "python":
+		return strings.HasPrefix(base, "test_") || strings.HasSuffix(base, "_test.py") || base == "conftest.py"
+	case "typescript", "javascript":
+		return strings.Contains(base, ".test.") || strings.Contains(base, ".spec.") || strings.Contains(dir, "/__tests__/")
+	case "java", "kotlin":
+		return strings.HasSuffix(stem, "Test") || strings.HasSuffix(stem, "Tests") || strings.Contains(dir, "/src/test/")
+	case "csharp", "swift", "php":
+		return strings.HasSuffix(stem, "Test") || strings.HasSuffix(stem, "Tests")
+	case "ruby":
+		return strings.HasSuffix(stem, "_spec") || strings.HasSuffix(stem, "_test")
+	case "rust":
+		return strings.Contains(dir, "/tests/")
+	case "c", "cpp":
+		return strings.HasPrefix(stem, "test_") || strings.HasSuffix(stem, "_test")
+	}
+
+	return false
+}
+
+// skipTestFile reports whether a file should be left out of the index
+// because it holds tests and Config.IndexTests is off
+func (idx *Indexer) skipTestFile(path, relPath string) bool {
+	if idx.config.IndexTests {
+		return false
+	}
+
+	p, err := idx.parsers.GetParserForFile(path)
+	if err != nil {
+		return false
+	}
+	return isTestFile(relPath, p.Language())
+}
+
+// testTargetName returns the name of the symbol a test function exercises,
+// e.g. TestAdd -> Add, TestUser_Save -> User, test_add -> add
+func testTargetName(language, name string) (string, bool) {
+	switch language {
+	case "go":
+		rest := strings.TrimPrefix(name, "Test")
+		if rest == name || rest == "" {
+			return "", false
+		}
+		if rest[0] == '_' {
+			// Test_unexported
+			rest = rest[1:]
+		} else if !unicode.IsUpper(rune(rest[0])) {
+			return "", false
+		}
+		if i := strings.Index(rest, "_"); i > 0 {
+			rest = rest[:i]
+		}
+		return rest, rest != ""
+
+	case "python":
+		rest := strings.TrimPrefix(name, "test_")
+		if rest == name || rest == "" {
+			return "", false
+		}
+		return rest, true
+	}
+
+	return "", false
+}
+
+// linkTests records a "tests" relationship from each test function in a test
+// file to the symbols it exercises, matched by name
+func (idx *Indexer) linkTests(file *types.File) error {
+	if !isTestFile(file.RelativePath, file.Language) {
+		return nil
+	}
+
+	symbols, err := idx.db.GetSymbolsByFile(file.ID)
+	if err != nil {
+		return err
+	}
+
+	for _, symbol := range symbols {
+		if symbol.Type != types.SymbolTypeFunction && symbol.Type != types.SymbolTypeMethod {
+			continue
+		}
+
+		target, ok := testTargetName(file.Language, symbol.Name)
+		if !ok {
+			continue
+		}
+
+		candidates, err := idx.db.GetSymbolsByName(idx.project.ID, target)
+		if err != nil {
+			return err
+		}
+
+		for _, candidate := range candidates {
+			if candidate.FileID == file.ID {
+				continue // Helpers defined next to the test aren't what it exercises
+			}
+
+			rel := &types.Relationship{
+				FromSymbolID: symbol.ID,
+				ToSymbolID:   candidate.ID,
+				Type:         types.RelationshipTests,
+			}
+			if err := idx.db.SaveRelationship(rel); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// linkAllTests links tests to symbols across the whole project
+func (idx *Indexer) linkAllTests() error {
+	files, err := idx.db.GetAllFilesForProject(idx.project.ID)
+	if err != nil {
+		return err
+	}
+
+	for _, file := range files {
+		if err := idx.linkTests(file); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// GetTestsForSymbol returns the test functions linked to a symbol
+func (idx *Indexer) GetTestsForSymbol(name string) ([]*types.Symbol, error) {
+	targets, err := idx.db.GetSymbolsByName(idx.project.ID, name)
+	if err != nil {
+		return nil, err
+	}
+
+	tests := []*types.Symbol{}
+	seen := make(map[int64]bool)
+	for _, target := range targets {
+		linked, err := idx.db.GetRelationshipSources(target.ID, types.RelationshipTests)
+		if err != nil {
+			return nil, err
+		}
+		for _, test := range linked {
+			if !seen[test.ID] {
+				seen[test.ID] = true
+				tests = append(tests, test)
+			}
+		}
+	}
+
+	return tests, nil
+}
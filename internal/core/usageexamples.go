@@ -0,0 +1,163 @@
+package core
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/aaamil13/CodeIndexerMCP/internal/utils"
+	"github.com/aaamil13/CodeIndexerMCP/pkg/types"
+)
+
+// DefaultUsageExamples is the number of examples GetUsageExamples returns
+// when no limit is given
+const DefaultUsageExamples = 10
+
+// usageContextLines is how many lines either side of a call site an
+// example's context includes
+const usageContextLines = 2
+
+// GetUsageExamples returns up to limit examples of the named symbol in use
+// at its recorded references and at the calls the call graph resolves to
+// it: the source line of each call site, with the lines around it as
+// context. References reading the same as an earlier one are left out, so
+// each example shows a different use, and files that can't be read are
+// skipped.
+func (idx *Indexer) GetUsageExamples(name string, limit int) ([]*types.UsageExample, error) {
+	if limit <= 0 {
+		limit = DefaultUsageExamples
+	}
+
+	symbol, err := idx.lookupSymbol(name)
+	if err != nil {
+		return nil, err
+	}
+	references, err := idx.db.GetReferencesBySymbol(symbol.ID)
+	if err != nil {
+		return nil, err
+	}
+	calls, err := idx.callSiteReferences(symbol)
+	if err != nil {
+		return nil, err
+	}
+	references = append(append([]*types.Reference(nil), references...), calls...)
+
+	files := make(map[int64]*types.File)
+	for _, ref := range references {
+		if _, ok := files[ref.FileID]; ok {
+			continue
+		}
+		file, err := idx.db.GetFile(ref.FileID)
+		if err != nil {
+			return nil, err
+		}
+		files[ref.FileID] = file
+	}
+	sort.SliceStable(references, func(i, j int) bool {
+		a, b := files[references[i].FileID], files[references[j].FileID]
+		if a != nil && b != nil && a.RelativePath != b.RelativePath {
+			return a.RelativePath < b.RelativePath
+		}
+		return references[i].LineNumber < references[j].LineNumber
+	})
+
+	examples := []*types.UsageExample{}
+	seen := make(map[string]bool)
+	sources := make(map[int64][]string)
+	for _, ref := range references {
+		file := files[ref.FileID]
+		if file == nil {
+			continue
+		}
+
+		lines, ok := sources[file.ID]
+		if !ok {
+			if content, err := utils.ReadFileUTF8(file.Path, file.Encoding); err == nil {
+				lines = strings.Split(string(content), "\n")
+			}
+			sources[file.ID] = lines
+		}
+		if ref.LineNumber < 1 || ref.LineNumber > len(lines) {
+			continue // Reference from before the file last changed
+		}
+
+		code := strings.TrimSpace(lines[ref.LineNumber-1])
+		if code == "" || seen[code] {
+			continue
+		}
+		seen[code] = true
+
+		start := max(ref.LineNumber-usageContextLines, 1)
+		end := min(ref.LineNumber+usageContextLines, len(lines))
+		examples = append(examples, &types.UsageExample{
+			FilePath:    file.RelativePath,
+			LineNumber:  ref.LineNumber,
+			Code:        code,
+			Context:     strings.Join(lines[start-1:end], "\n"),
+			Description: fmt.Sprintf("%s at %s:%d", ref.ReferenceType, file.RelativePath, ref.LineNumber),
+		})
+		if len(examples) == limit {
+			break
+		}
+	}
+
+	return examples, nil
+}
+
+// callSiteReferences returns a reference for each line calling symbol in the
+// functions the call graph links to it, as parsers that record no call
+// references of their own would
+func (idx *Indexer) callSiteReferences(symbol *types.Symbol) ([]*types.Reference, error) {
+	if !isCallable(symbol) {
+		return nil, nil
+	}
+	graph, _, err := idx.buildCallGraph()
+	if err != nil {
+		return nil, err
+	}
+
+	target := -1
+	for i, n := range graph.nodes {
+		if n.symbol.ID == symbol.ID {
+			target = i
+		}
+	}
+	if target < 0 {
+		return nil, nil
+	}
+
+	var references []*types.Reference
+	sources := make(map[int64][]string) // file ID -> lines without comments and strings
+	for i, n := range graph.nodes {
+		calls := i == target && graph.selfCalls[i]
+		for _, t := range graph.calls[i] {
+			calls = calls || t == target
+		}
+		if !calls {
+			continue
+		}
+
+		lines, ok := sources[n.file.ID]
+		if !ok {
+			if content, err := utils.ReadFileUTF8(n.file.Path, n.file.Encoding); err == nil {
+				lines = strings.Split(utils.StripCommentsAndStrings(string(content), n.file.Language), "\n")
+			}
+			sources[n.file.ID] = lines
+		}
+		for line := n.symbol.StartLine + 1; line <= n.symbol.EndLine && line <= len(lines); line++ {
+			for _, m := range callExpression.FindAllStringSubmatch(lines[line-1], -1) {
+				if m[1] == symbol.Name {
+					references = append(references, &types.Reference{
+						SymbolID:      symbol.ID,
+						FileID:        n.file.ID,
+						LineNumber:    line,
+						ReferenceType: "call",
+					})
+					break
+				}
+			}
+		}
+	}
+
+	return references, nil
+}
@@ -0,0 +1,103 @@
+package core
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aaamil13/CodeIndexerMCP/internal/ai"
+	"github.com/aaamil13/CodeIndexerMCP/internal/utils"
+	"github.com/aaamil13/CodeIndexerMCP/pkg/types"
+)
+
+// recordSymbolVersions records a new version of each function in a file
+// whose source differs from its last recorded version
+func (idx *Indexer) recordSymbolVersions(file *types.File, symbols []*types.Symbol, content []byte) error {
+	lines := strings.Split(string(content), "\n")
+	now := time.Now()
+
+	for _, symbol := range symbols {
+		switch symbol.Type {
+		case types.SymbolTypeFunction, types.SymbolTypeMethod, types.SymbolTypeConstructor:
+		default:
+			continue
+		}
+		if symbol.StartLine < 1 || symbol.EndLine < symbol.StartLine || symbol.EndLine > len(lines) {
+			continue
+		}
+
+		code := strings.Join(lines[symbol.StartLine-1:symbol.EndLine], "\n")
+		hash := utils.HashBytes([]byte(code))
+
+		latest, err := idx.db.LatestSymbolVersionHash(idx.project.ID, file.RelativePath, symbol.Name, symbol.Type)
+		if err != nil {
+			return err
+		}
+		if latest == hash {
+			continue
+		}
+
+		point := &types.ComplexityPoint{
+			FilePath:             file.RelativePath,
+			Hash:                 hash,
+			LinesOfCode:          symbol.EndLine - symbol.StartLine + 1,
+			CyclomaticComplexity: ai.CyclomaticComplexity(code, file.Language),
+			RecordedAt:           now,
+		}
+		if err := idx.db.SaveSymbolVersion(idx.project.ID, symbol.Name, symbol.Type, point); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// GetComplexityTrend returns a function's complexity at each recorded
+// version, oldest first. A version is recorded whenever indexing finds the
+// function's source changed. filePath is optional, but required when
+// functions of the same name in several files have recorded versions.
+func (idx *Indexer) GetComplexityTrend(symbolName, filePath string) ([]*types.ComplexityPoint, error) {
+	if symbolName == "" {
+		return nil, fmt.Errorf("symbol name is required")
+	}
+
+	relPath := ""
+	if filePath != "" {
+		var err error
+		if relPath, err = idx.resolveFilePath(filePath); err != nil {
+			return nil, err
+		}
+	}
+
+	keys, err := idx.db.GetSymbolVersionKeys(idx.project.ID, symbolName)
+	if err != nil {
+		return nil, err
+	}
+	var matching []*types.SymbolVersionKey
+	for _, key := range keys {
+		if relPath == "" || key.FilePath == relPath {
+			matching = append(matching, key)
+		}
+	}
+	if len(matching) == 0 {
+		return nil, fmt.Errorf("no recorded versions for symbol: %s", symbolName)
+	}
+	if len(matching) > 1 {
+		locations := make([]string, len(matching))
+		for i, key := range matching {
+			locations[i] = fmt.Sprintf("%s (%s)", key.FilePath, key.Type)
+		}
+		return nil, fmt.Errorf("symbol %s has recorded versions in %s; specify a file path", symbolName, strings.Join(locations, ", "))
+	}
+
+	points, err := idx.db.GetSymbolVersions(idx.project.ID, matching[0].FilePath, symbolName, matching[0].Type)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, point := range points {
+		point.Version = i + 1
+	}
+
+	return points, nil
+}
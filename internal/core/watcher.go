@@ -1,7 +1,9 @@
 package core
 
 import (
+	"os"
 	"path/filepath"
+	"strings"
 	"sync"
 	"time"
 
@@ -10,11 +12,17 @@ import (
 	"github.com/aaamil13/CodeIndexerMCP/internal/utils"
 )
 
+// watchDebounce is how long the watcher waits for a burst of events to
+// settle unless Config.WatchDebounce says otherwise
+const watchDebounce = 300 * time.Millisecond
+
 // Watcher watches for file system changes and triggers re-indexing
 type Watcher struct {
 	indexer       *Indexer
 	watcher       *fsnotify.Watcher
-	debounceMap   map[string]*time.Timer
+	pending       map[string]bool // Files waiting to be indexed
+	pendingDirs   map[string]bool // New directories waiting to be watched and scanned
+	debounceTimer *time.Timer
 	debounceMutex sync.Mutex
 	stopChan      chan struct{}
 	logger        *utils.Logger
@@ -30,7 +38,8 @@ func NewWatcher(indexer *Indexer) (*Watcher, error) {
 	return &Watcher{
 		indexer:     indexer,
 		watcher:     fsWatcher,
-		debounceMap: make(map[string]*time.Timer),
+		pending:     make(map[string]bool),
+		pendingDirs: make(map[string]bool),
 		stopChan:    make(chan struct{}),
 		logger:      utils.NewLogger("[Watcher]"),
 	}, nil
@@ -55,6 +64,13 @@ func (w *Watcher) Start() error {
 func (w *Watcher) Stop() error {
 	w.logger.Info("Stopping file watcher")
 	close(w.stopChan)
+
+	w.debounceMutex.Lock()
+	if w.debounceTimer != nil {
+		w.debounceTimer.Stop()
+	}
+	w.debounceMutex.Unlock()
+
 	return w.watcher.Close()
 }
 
@@ -93,8 +109,32 @@ func (w *Watcher) handleEvent(event fsnotify.Event) {
 		return
 	}
 
+	// A new directory (created, or moved into place) needs watches of its
+	// own, and may already hold files whose events we never saw
+	if event.Op&fsnotify.Create == fsnotify.Create {
+		if info, err := os.Stat(event.Name); err == nil && info.IsDir() {
+			w.logger.Debugf("Directory created: %s", relPath)
+			w.handleNewDirectory(event.Name)
+			return
+		}
+	}
+
+	// A removed path can't be inspected, and may have been a directory, so
+	// removals are handled before the parser check
+	switch {
+	case event.Op&fsnotify.Remove == fsnotify.Remove:
+		w.logger.Debugf("Path removed: %s", relPath)
+		w.handleRemoval(event.Name)
+		return
+
+	case event.Op&fsnotify.Rename == fsnotify.Rename:
+		w.logger.Debugf("Path renamed: %s", relPath)
+		w.handleRemoval(event.Name)
+		return
+	}
+
 	// Check if we can parse this file
-	if !w.indexer.parsers.CanParse(event.Name) {
+	if !w.indexer.parsers.CanParse(event.Name) || w.indexer.skipFile(event.Name, relPath) {
 		return
 	}
 
@@ -105,77 +145,148 @@ func (w *Watcher) handleEvent(event fsnotify.Event) {
 
 	case event.Op&fsnotify.Create == fsnotify.Create:
 		w.logger.Debugf("File created: %s", relPath)
+		w.debounceIndex(event.Name)
+	}
+}
 
-		// If it's a directory, add it to watch list
-		info, err := filepath.Glob(event.Name)
-		if err == nil && len(info) > 0 {
-			w.watcher.Add(event.Name)
-		}
+// handleNewDirectory watches a new directory tree and queues it to be
+// scanned for files once events settle
+func (w *Watcher) handleNewDirectory(dir string) {
+	if err := w.addDirectoryRecursive(dir); err != nil {
+		w.logger.Warnf("Failed to watch directory %s: %v", dir, err)
+	}
 
-		w.debounceIndex(event.Name)
+	w.debounceMutex.Lock()
+	defer w.debounceMutex.Unlock()
 
-	case event.Op&fsnotify.Remove == fsnotify.Remove:
-		w.logger.Debugf("File removed: %s", relPath)
-		w.handleFileRemoval(event.Name)
+	w.pendingDirs[dir] = true
+	w.resetDebounce()
+}
 
-	case event.Op&fsnotify.Rename == fsnotify.Rename:
-		w.logger.Debugf("File renamed: %s", relPath)
-		w.handleFileRemoval(event.Name)
-	}
+// indexableFiles returns the files under dir the indexer would index
+func (w *Watcher) indexableFiles(dir string) []string {
+	var files []string
+	filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return nil // Vanished mid-walk; keep going
+		}
+
+		relPath, _ := filepath.Rel(w.indexer.projectPath, path)
+		if w.indexer.ignoreMatcher.ShouldIgnore(relPath) {
+			if info.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if !info.IsDir() && w.indexer.parsers.CanParse(path) && !w.indexer.skipFile(path, relPath) {
+			files = append(files, path)
+		}
+		return nil
+	})
+	return files
 }
 
-// debounceIndex debounces file indexing to avoid multiple rapid updates
+// debounceIndex queues a file for indexing. The queue is flushed once events
+// stop arriving for the debounce period, so a burst such as a large checkout is
+// indexed in one pass instead of file by file.
 func (w *Watcher) debounceIndex(filePath string) {
 	w.debounceMutex.Lock()
 	defer w.debounceMutex.Unlock()
 
-	// Cancel existing timer
-	if timer, exists := w.debounceMap[filePath]; exists {
-		timer.Stop()
-	}
+	w.pending[filePath] = true
+	w.resetDebounce()
+}
 
-	// Create new timer
-	w.debounceMap[filePath] = time.AfterFunc(300*time.Millisecond, func() {
-		w.debounceMutex.Lock()
-		delete(w.debounceMap, filePath)
-		w.debounceMutex.Unlock()
+// resetDebounce restarts the debounce period. The caller holds debounceMutex.
+func (w *Watcher) resetDebounce() {
+	if w.debounceTimer != nil {
+		w.debounceTimer.Stop()
+	}
+	debounce := w.indexer.config.WatchDebounce
+	if debounce <= 0 {
+		debounce = watchDebounce
+	}
+	w.debounceTimer = time.AfterFunc(debounce, w.flushPending)
+}
 
-		// Index the file
-		if err := w.indexer.IndexFile(filePath); err != nil {
-			w.logger.Errorf("Failed to index file %s: %v", filePath, err)
-		} else {
-			w.logger.Infof("Re-indexed: %s", filePath)
+// flushPending indexes every queued file, and the files in every queued
+// directory, as one batch
+func (w *Watcher) flushPending() {
+	w.debounceMutex.Lock()
+	queued := w.pending
+	dirs := w.pendingDirs
+	w.pending = make(map[string]bool)
+	w.pendingDirs = make(map[string]bool)
+	w.debounceTimer = nil
+	w.debounceMutex.Unlock()
+
+	for dir := range dirs {
+		// A directory moved into place is watched under its old path too, and
+		// fsnotify drops that watch when the directory's own rename event
+		// arrives, after the create event that added the new one. Watch it
+		// again now that events have settled.
+		if err := w.addDirectoryRecursive(dir); err != nil {
+			w.logger.Warnf("Failed to watch directory %s: %v", dir, err)
+			continue
 		}
-	})
-}
+		for _, filePath := range w.indexableFiles(dir) {
+			queued[filePath] = true
+		}
+	}
 
-// handleFileRemoval handles file removal
-func (w *Watcher) handleFileRemoval(filePath string) {
-	relPath, err := filepath.Rel(w.indexer.projectPath, filePath)
-	if err != nil {
+	files := make([]string, 0, len(queued))
+	for filePath := range queued {
+		files = append(files, filePath)
+	}
+	if len(files) == 0 {
 		return
 	}
 
-	// Get file from database
-	file, err := w.indexer.db.GetFileByPath(w.indexer.project.ID, relPath)
+	if err := w.indexer.IndexFiles(files); err != nil {
+		w.logger.Errorf("Failed to index files: %v", err)
+		return
+	}
+	w.logger.Infof("Re-indexed %d file(s)", len(files))
+}
+
+// handleRemoval drops a deleted file, or every file under a deleted
+// directory, from the index and from the queue of files to index
+func (w *Watcher) handleRemoval(path string) {
+	w.dropPending(path)
+
+	removed, err := w.indexer.RemovePath(path)
 	if err != nil {
-		w.logger.Errorf("Failed to get file: %v", err)
+		w.logger.Errorf("Failed to remove %s from index: %v", path, err)
 		return
 	}
+	if removed > 0 {
+		w.logger.Infof("Removed %d file(s) from index: %s", removed, path)
+	}
+}
 
-	if file != nil {
-		// Delete file from database (cascades to symbols, imports, etc.)
-		if err := w.indexer.db.DeleteFile(file.ID); err != nil {
-			w.logger.Errorf("Failed to delete file: %v", err)
-		} else {
-			w.logger.Infof("Removed from index: %s", relPath)
+// dropPending removes a path, and anything under it, from the index queues
+func (w *Watcher) dropPending(path string) {
+	prefix := path + string(filepath.Separator)
+
+	w.debounceMutex.Lock()
+	defer w.debounceMutex.Unlock()
+
+	for pending := range w.pending {
+		if pending == path || strings.HasPrefix(pending, prefix) {
+			delete(w.pending, pending)
+		}
+	}
+	for dir := range w.pendingDirs {
+		if dir == path || strings.HasPrefix(dir, prefix) {
+			delete(w.pendingDirs, dir)
 		}
 	}
 }
 
 // addDirectoryRecursive adds a directory and all subdirectories to the watch list
 func (w *Watcher) addDirectoryRecursive(dir string) error {
-	return filepath.Walk(dir, func(path string, info any, err error) error {
+	return filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
 			return err
 		}
@@ -183,14 +294,14 @@ func (w *Watcher) addDirectoryRecursive(dir string) error {
 		// Check if should ignore
 		relPath, _ := filepath.Rel(w.indexer.projectPath, path)
 		if relPath != "." && w.indexer.ignoreMatcher.ShouldIgnore(relPath) {
-			if info != nil && isDir(info) {
+			if info.IsDir() {
 				return filepath.SkipDir
 			}
 			return nil
 		}
 
 		// Only watch directories
-		if info != nil && isDir(info) {
+		if info.IsDir() {
 			if err := w.watcher.Add(path); err != nil {
 				w.logger.Warnf("Failed to watch directory %s: %v", path, err)
 			} else {
@@ -201,15 +312,3 @@ func (w *Watcher) addDirectoryRecursive(dir string) error {
 		return nil
 	})
 }
-
-// Helper function to check if FileInfo represents a directory
-func isDir(info any) bool {
-	// Type assertion to handle os.FileInfo interface
-	type fileInfo interface {
-		IsDir() bool
-	}
-	if fi, ok := info.(fileInfo); ok {
-		return fi.IsDir()
-	}
-	return false
-}
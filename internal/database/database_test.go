@@ -1,8 +1,12 @@
 package database
 
 import (
+	"context"
+	"fmt"
 	"os"
 	"path/filepath"
+	"reflect"
+	"sync"
 	"testing"
 
 	"github.com/aaamil13/CodeIndexerMCP/pkg/types"
@@ -52,6 +56,53 @@ func TestCreateProject(t *testing.T) {
 	}
 }
 
+func TestSetSymbolDependencies(t *testing.T) {
+	db, file := openTestDB(t)
+
+	caller := &types.Symbol{FileID: file.ID, Name: "Caller", Type: types.SymbolTypeFunction, Dependencies: []string{"Old"}}
+	other := &types.Symbol{FileID: file.ID, Name: "Other", Type: types.SymbolTypeFunction, Metadata: map[string]interface{}{"kept": "yes"}}
+	for _, sym := range []*types.Symbol{caller, other} {
+		if err := db.SaveSymbol(sym); err != nil {
+			t.Fatalf("SaveSymbol failed: %v", err)
+		}
+	}
+
+	err := db.SetSymbolDependencies(map[int64][]string{
+		caller.ID: nil,
+		other.ID:  {"Callee", "Helper"},
+	})
+	if err != nil {
+		t.Fatalf("SetSymbolDependencies failed: %v", err)
+	}
+
+	got, err := db.GetSymbolByID(caller.ID)
+	if err != nil || got == nil {
+		t.Fatalf("GetSymbolByID failed: %v", err)
+	}
+	if len(got.Dependencies) != 0 {
+		t.Errorf("Expected Caller's dependencies cleared, got %v", got.Dependencies)
+	}
+	got, err = db.GetSymbolByID(other.ID)
+	if err != nil || got == nil {
+		t.Fatalf("GetSymbolByID failed: %v", err)
+	}
+	if !reflect.DeepEqual(got.Dependencies, []string{"Callee", "Helper"}) || got.Metadata["kept"] != "yes" {
+		t.Errorf("Expected Other's dependencies set and metadata kept, got %v and %v", got.Dependencies, got.Metadata)
+	}
+
+	// A missing symbol fails the whole batch
+	if err := db.SetSymbolDependencies(map[int64][]string{other.ID: nil, -1: {"X"}}); err == nil {
+		t.Error("Expected an error for a missing symbol")
+	}
+	got, err = db.GetSymbolByID(other.ID)
+	if err != nil || got == nil {
+		t.Fatalf("GetSymbolByID failed: %v", err)
+	}
+	if len(got.Dependencies) != 2 {
+		t.Errorf("Expected the failed batch rolled back, got %v", got.Dependencies)
+	}
+}
+
 func TestCreateFile(t *testing.T) {
 	db, _ := setupTestDB(t)
 	defer db.Close()
@@ -464,3 +515,1249 @@ func TestDatabasePersistence(t *testing.T) {
 		t.Error("Database file should exist")
 	}
 }
+
+// openTestDB opens a fresh database with a single project and file
+func openTestDB(tb testing.TB) (*DB, *types.File) {
+	tb.Helper()
+
+	db, err := Open(filepath.Join(tb.TempDir(), "test.db"))
+	if err != nil {
+		tb.Fatalf("Failed to open test database: %v", err)
+	}
+	tb.Cleanup(func() { db.Close() })
+
+	project := &types.Project{Name: "test", Path: "/test"}
+	if err := db.CreateProject(project); err != nil {
+		tb.Fatalf("CreateProject failed: %v", err)
+	}
+
+	file := &types.File{
+		ProjectID:    project.ID,
+		Path:         "/test/file.go",
+		RelativePath: "file.go",
+		Language:     "go",
+	}
+	if err := db.SaveFile(file); err != nil {
+		tb.Fatalf("SaveFile failed: %v", err)
+	}
+
+	return db, file
+}
+
+func saveTestSymbols(tb testing.TB, db *DB, fileID int64, count int) {
+	tb.Helper()
+
+	for i := 0; i < count; i++ {
+		symbol := &types.Symbol{
+			FileID:        fileID,
+			Name:          fmt.Sprintf("Symbol%d", i),
+			Type:          types.SymbolTypeFunction,
+			Signature:     fmt.Sprintf("func Symbol%d()", i),
+			Documentation: fmt.Sprintf("Symbol%d does thing %d", i, i%10),
+			StartLine:     i + 1,
+			EndLine:       i + 1,
+		}
+		if err := db.SaveSymbol(symbol); err != nil {
+			tb.Fatalf("SaveSymbol failed: %v", err)
+		}
+	}
+}
+
+func ftsMatches(t *testing.T, db *DB, query string) []int64 {
+	t.Helper()
+
+	rows, err := db.conn.Query("SELECT rowid FROM symbols_fts WHERE symbols_fts MATCH ? ORDER BY rowid", query)
+	if err != nil {
+		t.Fatalf("FTS query failed: %v", err)
+	}
+	defer rows.Close()
+
+	var ids []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			t.Fatalf("Scan failed: %v", err)
+		}
+		ids = append(ids, id)
+	}
+
+	return ids
+}
+
+func TestBulkFTSRebuildMatchesTriggers(t *testing.T) {
+	triggerDB, triggerFile := openTestDB(t)
+	saveTestSymbols(t, triggerDB, triggerFile.ID, 200)
+
+	bulkDB, bulkFile := openTestDB(t)
+	if err := bulkDB.BeginBulkFTS(); err != nil {
+		t.Fatalf("BeginBulkFTS failed: %v", err)
+	}
+	saveTestSymbols(t, bulkDB, bulkFile.ID, 200)
+
+	// Nothing is searchable until the rebuild runs
+	if ids := ftsMatches(t, bulkDB, "Symbol42"); len(ids) != 0 {
+		t.Errorf("Expected no FTS rows before rebuild, got %d", len(ids))
+	}
+
+	if err := bulkDB.EndBulkFTS(); err != nil {
+		t.Fatalf("EndBulkFTS failed: %v", err)
+	}
+
+	for _, query := range []string{"Symbol42", "thing", "func"} {
+		want := ftsMatches(t, triggerDB, query)
+		got := ftsMatches(t, bulkDB, query)
+		if len(want) == 0 {
+			t.Fatalf("Expected trigger path to match %q", query)
+		}
+		if fmt.Sprint(got) != fmt.Sprint(want) {
+			t.Errorf("Query %q: bulk results %v differ from trigger results %v", query, got, want)
+		}
+	}
+
+	// Triggers are active again for incremental updates
+	saveTestSymbols(t, bulkDB, bulkFile.ID, 201)
+	if ids := ftsMatches(t, bulkDB, "Symbol200"); len(ids) != 1 {
+		t.Errorf("Expected incremental insert to be searchable, got %d matches", len(ids))
+	}
+}
+
+func TestBulkFTSInterruptedRebuildsOnOpen(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "test.db")
+	db, err := Open(dbPath)
+	if err != nil {
+		t.Fatalf("Failed to open test database: %v", err)
+	}
+	project := &types.Project{Name: "test", Path: "/test"}
+	if err := db.CreateProject(project); err != nil {
+		t.Fatalf("CreateProject failed: %v", err)
+	}
+	file := &types.File{ProjectID: project.ID, Path: "/test/file.go", RelativePath: "file.go", Language: "go"}
+	if err := db.SaveFile(file); err != nil {
+		t.Fatalf("SaveFile failed: %v", err)
+	}
+
+	// The process dies between BeginBulkFTS and EndBulkFTS
+	if err := db.BeginBulkFTS(); err != nil {
+		t.Fatalf("BeginBulkFTS failed: %v", err)
+	}
+	saveTestSymbols(t, db, file.ID, 50)
+	db.Close()
+
+	if db, err = Open(dbPath); err != nil {
+		t.Fatalf("Failed to reopen test database: %v", err)
+	}
+	defer db.Close()
+	if ids := ftsMatches(t, db, "Symbol42"); len(ids) != 1 {
+		t.Errorf("Expected the reopened index to be rebuilt, got %d matches", len(ids))
+	}
+
+	// The triggers are back for incremental updates
+	saveTestSymbols(t, db, file.ID, 51)
+	if ids := ftsMatches(t, db, "Symbol50"); len(ids) != 1 {
+		t.Errorf("Expected incremental insert to be searchable, got %d matches", len(ids))
+	}
+}
+
+func BenchmarkIndexSymbols_Triggers(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		db, file := openTestDB(b)
+		b.StartTimer()
+
+		saveTestSymbols(b, db, file.ID, 10000)
+	}
+}
+
+func BenchmarkIndexSymbols_BulkRebuild(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		db, file := openTestDB(b)
+		b.StartTimer()
+
+		if err := db.BeginBulkFTS(); err != nil {
+			b.Fatalf("BeginBulkFTS failed: %v", err)
+		}
+		saveTestSymbols(b, db, file.ID, 10000)
+		if err := db.EndBulkFTS(); err != nil {
+			b.Fatalf("EndBulkFTS failed: %v", err)
+		}
+	}
+}
+
+func TestSearchSymbolsRanking(t *testing.T) {
+	for _, splitting := range []bool{false, true} {
+		t.Run(fmt.Sprintf("splitting=%v", splitting), func(t *testing.T) {
+			db, file := openTestDB(t)
+			db.SetNameSplitting(splitting)
+
+			// Saved worst match first so rowid order would be wrong
+			for _, name := range []string{"QuickAddHandler", "AddItem", "add", "Add"} {
+				if err := db.SaveSymbol(&types.Symbol{FileID: file.ID, Name: name, Type: types.SymbolTypeFunction}); err != nil {
+					t.Fatalf("SaveSymbol failed: %v", err)
+				}
+			}
+
+			results, err := db.SearchSymbols(types.SearchOptions{Query: "Add"})
+			if err != nil {
+				t.Fatalf("SearchSymbols failed: %v", err)
+			}
+			want := []string{"Add", "add", "AddItem", "QuickAddHandler"}
+			if got := symbolNames(results); !reflect.DeepEqual(got, want) {
+				t.Errorf("Expected %v, got %v", want, got)
+			}
+
+			results, err = db.SearchSymbols(types.SearchOptions{Query: "Add", Ranking: types.SearchRankingName})
+			if err != nil {
+				t.Fatalf("SearchSymbols failed: %v", err)
+			}
+			want = []string{"Add", "AddItem", "QuickAddHandler", "add"}
+			if got := symbolNames(results); !reflect.DeepEqual(got, want) {
+				t.Errorf("Expected %v ordered by name, got %v", want, got)
+			}
+		})
+	}
+}
+
+func TestSearchSymbolsLanguage(t *testing.T) {
+	db, file := openTestDB(t)
+
+	other := &types.File{ProjectID: file.ProjectID, Path: "/test/app.ts", RelativePath: "app.ts", Language: "typescript"}
+	if err := db.SaveFile(other); err != nil {
+		t.Fatalf("SaveFile failed: %v", err)
+	}
+	for _, f := range []*types.File{file, other} {
+		if err := db.SaveSymbol(&types.Symbol{FileID: f.ID, Name: "start", Type: types.SymbolTypeFunction}); err != nil {
+			t.Fatalf("SaveSymbol failed: %v", err)
+		}
+	}
+
+	results, err := db.SearchSymbols(types.SearchOptions{Query: "start", Language: "typescript"})
+	if err != nil {
+		t.Fatalf("SearchSymbols failed: %v", err)
+	}
+	if len(results) != 1 || results[0].FileID != other.ID {
+		t.Errorf("Expected only the typescript symbol, got %+v", results)
+	}
+}
+
+func TestSearchSymbolsStream(t *testing.T) {
+	db, file := openTestDB(t)
+
+	for _, name := range []string{"QuickAddHandler", "AddItem", "add", "Add", "AddAll", "Remove"} {
+		if err := db.SaveSymbol(&types.Symbol{FileID: file.ID, Name: name, Type: types.SymbolTypeFunction}); err != nil {
+			t.Fatalf("SaveSymbol failed: %v", err)
+		}
+	}
+
+	opts := types.SearchOptions{Query: "Add"}
+	want, err := db.SearchSymbols(opts)
+	if err != nil {
+		t.Fatalf("SearchSymbols failed: %v", err)
+	}
+
+	var streamed []*types.Symbol
+	err = db.SearchSymbolsStream(opts, func(symbol *types.Symbol) error {
+		streamed = append(streamed, symbol)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("SearchSymbolsStream failed: %v", err)
+	}
+	if got := symbolNames(streamed); !reflect.DeepEqual(got, symbolNames(want)) {
+		t.Errorf("Expected streamed results %v to match %v", got, symbolNames(want))
+	}
+
+	// An error from the callback stops the stream and is returned
+	errStop := fmt.Errorf("stop")
+	visited := 0
+	err = db.SearchSymbolsStream(opts, func(symbol *types.Symbol) error {
+		visited++
+		if visited == 2 {
+			return errStop
+		}
+		return nil
+	})
+	if err != errStop {
+		t.Errorf("Expected the callback's error, got %v", err)
+	}
+	if visited != 2 {
+		t.Errorf("Expected the stream to stop after 2 symbols, visited %d", visited)
+	}
+}
+
+func TestSearchSymbolsSplitNames(t *testing.T) {
+	db, file := openTestDB(t)
+	db.SetNameSplitting(true)
+
+	for _, name := range []string{"handleHTTPRequest", "HTTP", "parse_http_header", "Unrelated"} {
+		if err := db.SaveSymbol(&types.Symbol{FileID: file.ID, Name: name, Type: types.SymbolTypeFunction}); err != nil {
+			t.Fatalf("SaveSymbol failed: %v", err)
+		}
+	}
+
+	// Split words match regardless of order and casing style
+	results, err := db.SearchSymbols(types.SearchOptions{Query: "request handle"})
+	if err != nil {
+		t.Fatalf("SearchSymbols failed: %v", err)
+	}
+	if len(results) != 1 || results[0].Name != "handleHTTPRequest" {
+		t.Errorf("Expected handleHTTPRequest for split query, got %v", symbolNames(results))
+	}
+
+	results, err = db.SearchSymbols(types.SearchOptions{Query: "httpHeader"})
+	if err != nil {
+		t.Fatalf("SearchSymbols failed: %v", err)
+	}
+	if len(results) != 1 || results[0].Name != "parse_http_header" {
+		t.Errorf("Expected parse_http_header for camelCase query, got %v", symbolNames(results))
+	}
+
+	// Exact name still ranks first
+	results, err = db.SearchSymbols(types.SearchOptions{Query: "HTTP"})
+	if err != nil {
+		t.Fatalf("SearchSymbols failed: %v", err)
+	}
+	if len(results) != 3 {
+		t.Fatalf("Expected 3 results for HTTP, got %v", symbolNames(results))
+	}
+	if results[0].Name != "HTTP" {
+		t.Errorf("Expected exact match HTTP first, got %v", symbolNames(results))
+	}
+}
+
+func symbolNames(symbols []*types.Symbol) []string {
+	names := make([]string, 0, len(symbols))
+	for _, s := range symbols {
+		names = append(names, s.Name)
+	}
+	return names
+}
+
+func TestGetReferencesByFilePath(t *testing.T) {
+	db, file := openTestDB(t)
+
+	symbol := &types.Symbol{FileID: file.ID, Name: "Helper", Type: types.SymbolTypeFunction}
+	if err := db.SaveSymbol(symbol); err != nil {
+		t.Fatalf("SaveSymbol failed: %v", err)
+	}
+
+	// Insert out of order to verify ordering
+	for _, line := range []int{20, 5} {
+		ref := &types.Reference{SymbolID: symbol.ID, FileID: file.ID, LineNumber: line, ReferenceType: "call"}
+		if err := db.SaveReference(ref); err != nil {
+			t.Fatalf("SaveReference failed: %v", err)
+		}
+	}
+
+	refs, err := db.GetReferencesByFilePath(file.ProjectID, "file.go")
+	if err != nil {
+		t.Fatalf("GetReferencesByFilePath failed: %v", err)
+	}
+
+	if len(refs) != 2 {
+		t.Fatalf("Expected 2 references, got %d", len(refs))
+	}
+	if refs[0].LineNumber != 5 || refs[1].LineNumber != 20 {
+		t.Errorf("Expected references ordered by line, got %d, %d", refs[0].LineNumber, refs[1].LineNumber)
+	}
+
+	refs, err = db.GetReferencesByFilePath(file.ProjectID, "other.go")
+	if err != nil {
+		t.Fatalf("GetReferencesByFilePath failed: %v", err)
+	}
+	if len(refs) != 0 {
+		t.Errorf("Expected no references for unknown file, got %d", len(refs))
+	}
+}
+
+func TestOpen_MigratesOlderSchema(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "old.db")
+
+	db, err := Open(dbPath)
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	project := &types.Project{Path: "/src/app", Name: "app"}
+	if err := db.CreateProject(project); err != nil {
+		t.Fatalf("CreateProject failed: %v", err)
+	}
+	file := &types.File{ProjectID: project.ID, Path: "/src/app/main.go", RelativePath: "main.go", Language: "go"}
+	if err := db.SaveFile(file); err != nil {
+		t.Fatalf("SaveFile failed: %v", err)
+	}
+	if err := db.SaveSymbol(&types.Symbol{FileID: file.ID, Name: "oldSymbol", Type: types.SymbolTypeFunction}); err != nil {
+		t.Fatalf("SaveSymbol failed: %v", err)
+	}
+
+	// Downgrade to the layout before the added columns, with an empty FTS
+	// table and triggers that don't know about name_tokens
+	downgrade := dropFTSTriggers + `
+DROP TABLE symbols_fts;
+DROP INDEX idx_symbols_status;
+CREATE VIRTUAL TABLE symbols_fts USING fts5(name, signature, documentation, content='symbols', content_rowid='id');
+`
+	for _, added := range addedColumns {
+		downgrade += fmt.Sprintf("ALTER TABLE %s DROP COLUMN %s;\n", added.table, added.column)
+	}
+	downgrade += `
+CREATE TRIGGER symbols_ai AFTER INSERT ON symbols BEGIN
+    INSERT INTO symbols_fts(rowid, name, signature, documentation)
+    VALUES (new.id, new.name, new.signature, new.documentation);
+END;
+CREATE TRIGGER symbols_ad AFTER DELETE ON symbols BEGIN
+    DELETE FROM symbols_fts WHERE rowid = old.id;
+END;
+`
+	if _, err := db.conn.Exec(downgrade); err != nil {
+		t.Fatalf("Downgrade failed: %v", err)
+	}
+	db.Close()
+
+	db, err = Open(dbPath)
+	if err != nil {
+		t.Fatalf("Open of an older database failed: %v", err)
+	}
+	defer db.Close()
+
+	for _, added := range addedColumns {
+		columns, err := db.tableColumns(added.table)
+		if err != nil {
+			t.Fatalf("tableColumns failed: %v", err)
+		}
+		if !columns[added.column] {
+			t.Errorf("Expected %s.%s to be added", added.table, added.column)
+		}
+	}
+
+	if got := ftsMatches(t, db, "oldSymbol"); len(got) != 1 {
+		t.Errorf("Expected the existing symbol to be rebuilt into FTS, got %v", got)
+	}
+
+	db.SetNameSplitting(true)
+	symbol := &types.Symbol{FileID: file.ID, Name: "handleRequest", Type: types.SymbolTypeFunction}
+	if err := db.SaveSymbol(symbol); err != nil {
+		t.Fatalf("SaveSymbol after migration failed: %v", err)
+	}
+	if got := ftsMatches(t, db, "name_tokens:request"); len(got) != 1 || got[0] != symbol.ID {
+		t.Errorf("Expected the new symbol's name tokens to be searchable, got %v", got)
+	}
+}
+
+func TestGetOrphanFiles(t *testing.T) {
+	db, file := openTestDB(t)
+
+	if err := db.SaveSymbol(&types.Symbol{FileID: file.ID, Name: "Main", Type: types.SymbolTypeFunction}); err != nil {
+		t.Fatalf("SaveSymbol failed: %v", err)
+	}
+
+	empty := &types.File{ProjectID: file.ProjectID, Path: "/test/empty.go", RelativePath: "empty.go", Language: "go"}
+	if err := db.SaveFile(empty); err != nil {
+		t.Fatalf("SaveFile failed: %v", err)
+	}
+
+	orphans, err := db.GetOrphanFiles(file.ProjectID)
+	if err != nil {
+		t.Fatalf("GetOrphanFiles failed: %v", err)
+	}
+
+	if len(orphans) != 1 {
+		t.Fatalf("Expected 1 orphan file, got %d", len(orphans))
+	}
+	if orphans[0].RelativePath != "empty.go" {
+		t.Errorf("Expected empty.go, got %s", orphans[0].RelativePath)
+	}
+}
+
+func TestSaveRelationshipDeduplicates(t *testing.T) {
+	db, file := openTestDB(t)
+
+	caller := &types.Symbol{FileID: file.ID, Name: "Caller", Type: types.SymbolTypeFunction}
+	callee := &types.Symbol{FileID: file.ID, Name: "Callee", Type: types.SymbolTypeFunction}
+	for _, sym := range []*types.Symbol{caller, callee} {
+		if err := db.SaveSymbol(sym); err != nil {
+			t.Fatalf("SaveSymbol failed: %v", err)
+		}
+	}
+
+	for i := 0; i < 2; i++ {
+		rel := &types.Relationship{FromSymbolID: caller.ID, ToSymbolID: callee.ID, Type: types.RelationshipCalls}
+		if err := db.SaveRelationship(rel); err != nil {
+			t.Fatalf("SaveRelationship failed: %v", err)
+		}
+	}
+
+	count, err := db.CountRelationships(file.ProjectID)
+	if err != nil {
+		t.Fatalf("CountRelationships failed: %v", err)
+	}
+	if count != 1 {
+		t.Errorf("Expected 1 relationship, got %d", count)
+	}
+
+	removed, err := db.DeduplicateRelationships(file.ProjectID)
+	if err != nil {
+		t.Fatalf("DeduplicateRelationships failed: %v", err)
+	}
+	if removed != 0 {
+		t.Errorf("Expected nothing to deduplicate, removed %d", removed)
+	}
+}
+
+func TestUpsertRelationshipReplacesMetadata(t *testing.T) {
+	db, file := openTestDB(t)
+
+	caller := &types.Symbol{FileID: file.ID, Name: "Caller", Type: types.SymbolTypeFunction}
+	callee := &types.Symbol{FileID: file.ID, Name: "Callee", Type: types.SymbolTypeFunction}
+	for _, sym := range []*types.Symbol{caller, callee} {
+		if err := db.SaveSymbol(sym); err != nil {
+			t.Fatalf("SaveSymbol failed: %v", err)
+		}
+	}
+
+	plain := &types.Relationship{FromSymbolID: caller.ID, ToSymbolID: callee.ID, Type: types.RelationshipCalls}
+	if err := db.SaveRelationship(plain); err != nil {
+		t.Fatalf("SaveRelationship failed: %v", err)
+	}
+	tagged := &types.Relationship{
+		FromSymbolID: caller.ID,
+		ToSymbolID:   callee.ID,
+		Type:         types.RelationshipCalls,
+		Metadata:     map[string]interface{}{"binding": "cgo"},
+	}
+	if err := db.UpsertRelationship(tagged); err != nil {
+		t.Fatalf("UpsertRelationship failed: %v", err)
+	}
+	if tagged.ID != plain.ID {
+		t.Errorf("Expected the existing row %d to be updated, got %d", plain.ID, tagged.ID)
+	}
+
+	rels, err := db.GetRelationshipsForSymbol(caller.ID)
+	if err != nil {
+		t.Fatalf("GetRelationshipsForSymbol failed: %v", err)
+	}
+	if len(rels) != 1 || rels[0].Metadata["binding"] != "cgo" {
+		t.Errorf("Expected one relationship with the binding metadata, got %+v", rels)
+	}
+}
+
+func TestSaveReferenceRoundTrip(t *testing.T) {
+	db, file := openTestDB(t)
+
+	symbol := &types.Symbol{FileID: file.ID, Name: "Target", Type: types.SymbolTypeFunction}
+	if err := db.SaveSymbol(symbol); err != nil {
+		t.Fatalf("SaveSymbol failed: %v", err)
+	}
+
+	ref := &types.Reference{
+		SymbolID:      symbol.ID,
+		FileID:        file.ID,
+		LineNumber:    42,
+		ColumnNumber:  7,
+		ReferenceType: "call",
+	}
+	if err := db.SaveReference(ref); err != nil {
+		t.Fatalf("SaveReference failed: %v", err)
+	}
+	if ref.ID == 0 {
+		t.Error("Expected reference ID to be set")
+	}
+
+	refs, err := db.GetReferencesBySymbol(symbol.ID)
+	if err != nil {
+		t.Fatalf("GetReferencesBySymbol failed: %v", err)
+	}
+	if len(refs) != 1 {
+		t.Fatalf("Expected 1 reference, got %d", len(refs))
+	}
+
+	if *refs[0] != *ref {
+		t.Errorf("Expected reference %+v, got %+v", *ref, *refs[0])
+	}
+}
+
+func TestGetSymbolsInRange(t *testing.T) {
+	db, file := openTestDB(t)
+
+	for _, sym := range []*types.Symbol{
+		{FileID: file.ID, Name: "First", Type: types.SymbolTypeFunction, StartLine: 1, EndLine: 10},
+		{FileID: file.ID, Name: "Second", Type: types.SymbolTypeFunction, StartLine: 12, EndLine: 15},
+		{FileID: file.ID, Name: "Third", Type: types.SymbolTypeFunction, StartLine: 30, EndLine: 40},
+	} {
+		if err := db.SaveSymbol(sym); err != nil {
+			t.Fatalf("SaveSymbol failed: %v", err)
+		}
+	}
+
+	matches, err := db.GetSymbolsInRange(file.ID, 8, 20)
+	if err != nil {
+		t.Fatalf("GetSymbolsInRange failed: %v", err)
+	}
+
+	if len(matches) != 2 {
+		t.Fatalf("Expected 2 symbols in range, got %d", len(matches))
+	}
+	if matches[0].Symbol.Name != "First" || matches[0].Overlap != types.RangeOverlapOverlapping {
+		t.Errorf("Expected First to overlap, got %s (%s)", matches[0].Symbol.Name, matches[0].Overlap)
+	}
+	if matches[1].Symbol.Name != "Second" || matches[1].Overlap != types.RangeOverlapContained {
+		t.Errorf("Expected Second to be contained, got %s (%s)", matches[1].Symbol.Name, matches[1].Overlap)
+	}
+}
+
+func TestGetOrCreateProjectConcurrent(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "test.db")
+
+	// Two handles on the same file stand in for two processes starting up
+	handles := make([]*DB, 2)
+	for i := range handles {
+		db, err := Open(dbPath)
+		if err != nil {
+			t.Fatalf("Failed to open test database: %v", err)
+		}
+		defer db.Close()
+		handles[i] = db
+	}
+
+	ids := make([]int64, len(handles))
+	errs := make([]error, len(handles))
+	var wg sync.WaitGroup
+	for i, db := range handles {
+		wg.Add(1)
+		go func(i int, db *DB) {
+			defer wg.Done()
+			project, err := db.GetOrCreateProject("/test", "test")
+			if err == nil {
+				ids[i] = project.ID
+			}
+			errs[i] = err
+		}(i, db)
+	}
+	wg.Wait()
+
+	for i, err := range errs {
+		if err != nil {
+			t.Fatalf("GetOrCreateProject call %d failed: %v", i, err)
+		}
+	}
+	if ids[0] == 0 || ids[0] != ids[1] {
+		t.Errorf("Expected one stable project ID, got %v", ids)
+	}
+
+	var count int
+	if err := handles[0].conn.QueryRow(`SELECT COUNT(*) FROM projects WHERE path = ?`, "/test").Scan(&count); err != nil {
+		t.Fatalf("Failed to count projects: %v", err)
+	}
+	if count != 1 {
+		t.Errorf("Expected 1 project row, got %d", count)
+	}
+
+	// A later call keeps returning the same project
+	project, err := handles[1].GetOrCreateProject("/test", "test")
+	if err != nil {
+		t.Fatalf("GetOrCreateProject failed: %v", err)
+	}
+	if project.ID != ids[0] {
+		t.Errorf("Expected project ID %d, got %d", ids[0], project.ID)
+	}
+}
+
+func TestCountSymbolsByFile(t *testing.T) {
+	db, first := openTestDB(t)
+
+	second := &types.File{
+		ProjectID:    first.ProjectID,
+		Path:         "/test/other.go",
+		RelativePath: "other.go",
+		Language:     "go",
+	}
+	empty := &types.File{
+		ProjectID:    first.ProjectID,
+		Path:         "/test/empty.go",
+		RelativePath: "empty.go",
+		Language:     "go",
+	}
+	for _, file := range []*types.File{second, empty} {
+		if err := db.SaveFile(file); err != nil {
+			t.Fatalf("SaveFile failed: %v", err)
+		}
+	}
+
+	saveTestSymbols(t, db, first.ID, 3)
+	saveTestSymbols(t, db, second.ID, 1)
+
+	files, err := db.GetAllFilesForProject(first.ProjectID)
+	if err != nil {
+		t.Fatalf("GetAllFilesForProject failed: %v", err)
+	}
+	if len(files) != 3 {
+		t.Fatalf("Expected 3 files, got %d", len(files))
+	}
+
+	counts, err := db.CountSymbolsByFile(first.ProjectID)
+	if err != nil {
+		t.Fatalf("CountSymbolsByFile failed: %v", err)
+	}
+
+	expected := map[string]int{"file.go": 3, "other.go": 1, "empty.go": 0}
+	for _, file := range files {
+		if got := counts[file.ID]; got != expected[file.RelativePath] {
+			t.Errorf("Expected %d symbols in %s, got %d", expected[file.RelativePath], file.RelativePath, got)
+		}
+	}
+}
+
+func TestReplaceParseErrors(t *testing.T) {
+	db, file := openTestDB(t)
+
+	errs := []*types.FileParseError{
+		{Line: 4, Column: 1, Message: "malformed definition", SymbolsRecovered: 2, Recovered: true},
+	}
+	if err := db.ReplaceParseErrors(file.ProjectID, file.RelativePath, errs); err != nil {
+		t.Fatalf("ReplaceParseErrors failed: %v", err)
+	}
+
+	got, err := db.GetParseErrors(file.ProjectID)
+	if err != nil {
+		t.Fatalf("GetParseErrors failed: %v", err)
+	}
+	if len(got) != 1 || got[0].FilePath != "file.go" || got[0].Line != 4 || !got[0].Recovered || got[0].SymbolsRecovered != 2 {
+		t.Fatalf("Unexpected parse errors: %+v", got)
+	}
+
+	// Replacing with nothing clears the file's errors
+	if err := db.ReplaceParseErrors(file.ProjectID, file.RelativePath, nil); err != nil {
+		t.Fatalf("ReplaceParseErrors failed: %v", err)
+	}
+	got, err = db.GetParseErrors(file.ProjectID)
+	if err != nil {
+		t.Fatalf("GetParseErrors failed: %v", err)
+	}
+	if len(got) != 0 {
+		t.Errorf("Expected no parse errors, got %d", len(got))
+	}
+
+	// Deleting the file deletes its errors too
+	if err := db.ReplaceParseErrors(file.ProjectID, file.RelativePath, errs); err != nil {
+		t.Fatalf("ReplaceParseErrors failed: %v", err)
+	}
+	if err := db.DeleteFile(file.ID); err != nil {
+		t.Fatalf("DeleteFile failed: %v", err)
+	}
+	got, err = db.GetParseErrors(file.ProjectID)
+	if err != nil {
+		t.Fatalf("GetParseErrors failed: %v", err)
+	}
+	if len(got) != 0 {
+		t.Errorf("Expected the deleted file's parse errors to be gone, got %d", len(got))
+	}
+}
+
+func TestSearchSymbolsProject(t *testing.T) {
+	db, file := openTestDB(t)
+
+	project := &types.Project{Path: "/other", Name: "other"}
+	if err := db.CreateProject(project); err != nil {
+		t.Fatalf("CreateProject failed: %v", err)
+	}
+	other := &types.File{ProjectID: project.ID, Path: "/other/main.go", RelativePath: "main.go", Language: "go"}
+	if err := db.SaveFile(other); err != nil {
+		t.Fatalf("SaveFile failed: %v", err)
+	}
+	for _, f := range []*types.File{file, other} {
+		if err := db.SaveSymbol(&types.Symbol{FileID: f.ID, Name: "start", Type: types.SymbolTypeFunction}); err != nil {
+			t.Fatalf("SaveSymbol failed: %v", err)
+		}
+	}
+
+	results, err := db.SearchSymbols(types.SearchOptions{Query: "start", ProjectID: project.ID})
+	if err != nil {
+		t.Fatalf("SearchSymbols failed: %v", err)
+	}
+	if len(results) != 1 || results[0].FileID != other.ID {
+		t.Errorf("Expected only the other project's symbol, got %+v", results)
+	}
+}
+
+func TestDeleteFilesWithPrefix(t *testing.T) {
+	db, file := openTestDB(t)
+
+	for _, relPath := range []string{"données/a.go", "données/sub/b.go", "donnéesX/c.go", "autres/d.go"} {
+		f := &types.File{ProjectID: file.ProjectID, Path: "/test/" + relPath, RelativePath: relPath, Language: "go"}
+		if err := db.SaveFile(f); err != nil {
+			t.Fatalf("SaveFile failed: %v", err)
+		}
+		point := &types.ComplexityPoint{FilePath: relPath, Hash: "h"}
+		if err := db.SaveSymbolVersion(file.ProjectID, "Run", types.SymbolTypeFunction, point); err != nil {
+			t.Fatalf("SaveSymbolVersion failed: %v", err)
+		}
+	}
+
+	removed, err := db.DeleteFilesWithPrefix(file.ProjectID, "données/")
+	if err != nil {
+		t.Fatalf("DeleteFilesWithPrefix failed: %v", err)
+	}
+	if removed != 2 {
+		t.Errorf("Expected 2 files under données/ deleted, got %d", removed)
+	}
+
+	files, err := db.GetAllFilesForProject(file.ProjectID)
+	if err != nil {
+		t.Fatalf("GetAllFilesForProject failed: %v", err)
+	}
+	var paths []string
+	for _, f := range files {
+		paths = append(paths, f.RelativePath)
+	}
+	if want := []string{"autres/d.go", "donnéesX/c.go", file.RelativePath}; !reflect.DeepEqual(paths, want) {
+		t.Errorf("Expected %v left, got %v", want, paths)
+	}
+
+	keys, err := db.GetSymbolVersionKeys(file.ProjectID, "Run")
+	if err != nil {
+		t.Fatalf("GetSymbolVersionKeys failed: %v", err)
+	}
+	paths = nil
+	for _, key := range keys {
+		paths = append(paths, key.FilePath)
+	}
+	if want := []string{"autres/d.go", "donnéesX/c.go"}; !reflect.DeepEqual(paths, want) {
+		t.Errorf("Expected symbol versions left for %v, got %v", want, paths)
+	}
+}
+
+func TestPruneOrphanReferences(t *testing.T) {
+	db, file := openTestDB(t)
+
+	kept := &types.Symbol{FileID: file.ID, Name: "Kept", Type: types.SymbolTypeFunction}
+	gone := &types.Symbol{FileID: file.ID, Name: "Gone", Type: types.SymbolTypeFunction}
+	for _, sym := range []*types.Symbol{kept, gone} {
+		if err := db.SaveSymbol(sym); err != nil {
+			t.Fatalf("SaveSymbol failed: %v", err)
+		}
+		for line := 1; line <= 2; line++ {
+			ref := &types.Reference{SymbolID: sym.ID, FileID: file.ID, LineNumber: line, ReferenceType: "call"}
+			if err := db.SaveReference(ref); err != nil {
+				t.Fatalf("SaveReference failed: %v", err)
+			}
+		}
+	}
+
+	// Orphans only appear when symbols are deleted without the cascade,
+	// e.g. in databases written before foreign keys were enforced
+	ctx := context.Background()
+	conn, err := db.conn.Conn(ctx)
+	if err != nil {
+		t.Fatalf("Conn failed: %v", err)
+	}
+	if _, err := conn.ExecContext(ctx, "PRAGMA foreign_keys = OFF"); err != nil {
+		t.Fatalf("Disabling foreign keys failed: %v", err)
+	}
+	if _, err := conn.ExecContext(ctx, "DELETE FROM symbols WHERE id = ?", gone.ID); err != nil {
+		t.Fatalf("Deleting symbol failed: %v", err)
+	}
+	if _, err := conn.ExecContext(ctx, "PRAGMA foreign_keys = ON"); err != nil {
+		t.Fatalf("Enabling foreign keys failed: %v", err)
+	}
+	conn.Close()
+
+	removed, err := db.PruneOrphanReferences()
+	if err != nil {
+		t.Fatalf("PruneOrphanReferences failed: %v", err)
+	}
+	if removed != 2 {
+		t.Errorf("Expected 2 orphan references removed, got %d", removed)
+	}
+
+	orphans, err := db.GetReferencesBySymbol(gone.ID)
+	if err != nil {
+		t.Fatalf("GetReferencesBySymbol failed: %v", err)
+	}
+	if len(orphans) != 0 {
+		t.Errorf("Expected orphan references to be gone, got %d", len(orphans))
+	}
+
+	refs, err := db.GetReferencesBySymbol(kept.ID)
+	if err != nil {
+		t.Fatalf("GetReferencesBySymbol failed: %v", err)
+	}
+	if len(refs) != 2 {
+		t.Errorf("Expected 2 references to remain, got %d", len(refs))
+	}
+
+	if err := db.Vacuum(); err != nil {
+		t.Fatalf("Vacuum failed: %v", err)
+	}
+}
+
+func TestGetSignaturesByName(t *testing.T) {
+	db, file := openTestDB(t)
+
+	for _, sym := range []*types.Symbol{
+		{FileID: file.ID, Name: "Parse", Type: types.SymbolTypeFunction, Signature: "func Parse(s string) error", StartLine: 3,
+			Documentation: "\nParse parses s.\nIt returns an error on failure."},
+		{FileID: file.ID, Name: "Parse", Type: types.SymbolTypeMethod, Signature: "func (p *P) Parse() error", StartLine: 20},
+		{FileID: file.ID, Name: "Other", Type: types.SymbolTypeFunction, Signature: "func Other()", StartLine: 30},
+	} {
+		if err := db.SaveSymbol(sym); err != nil {
+			t.Fatalf("SaveSymbol failed: %v", err)
+		}
+	}
+
+	signatures, err := db.GetSignaturesByName(file.ProjectID, "Parse")
+	if err != nil {
+		t.Fatalf("GetSignaturesByName failed: %v", err)
+	}
+	if len(signatures) != 2 {
+		t.Fatalf("Expected 2 signatures, got %d", len(signatures))
+	}
+
+	first := signatures[0]
+	if first.Signature != "func Parse(s string) error" || first.Type != types.SymbolTypeFunction {
+		t.Errorf("Unexpected first signature: %+v", first)
+	}
+	if first.Summary != "Parse parses s." {
+		t.Errorf("Expected one-line summary, got %q", first.Summary)
+	}
+	if first.FilePath != "file.go" || first.StartLine != 3 {
+		t.Errorf("Expected file.go:3, got %s:%d", first.FilePath, first.StartLine)
+	}
+	if signatures[1].Signature != "func (p *P) Parse() error" {
+		t.Errorf("Unexpected second signature: %+v", signatures[1])
+	}
+}
+
+func TestSymbolStatusTracking(t *testing.T) {
+	db, file := openTestDB(t)
+
+	working := &types.Symbol{FileID: file.ID, Name: "Working", Type: types.SymbolTypeFunction, StartLine: 1}
+	idle := &types.Symbol{FileID: file.ID, Name: "Idle", Type: types.SymbolTypeFunction, StartLine: 10}
+	for _, sym := range []*types.Symbol{working, idle} {
+		if err := db.SaveSymbol(sym); err != nil {
+			t.Fatalf("SaveSymbol failed: %v", err)
+		}
+	}
+
+	if err := db.SetSymbolStatus(working.ID, types.StatusInProgress); err != nil {
+		t.Fatalf("SetSymbolStatus failed: %v", err)
+	}
+	if err := db.SetSymbolAssignee(working.ID, "agent-1"); err != nil {
+		t.Fatalf("SetSymbolAssignee failed: %v", err)
+	}
+
+	inProgress, err := db.GetSymbolsByStatus(file.ProjectID, types.StatusInProgress)
+	if err != nil {
+		t.Fatalf("GetSymbolsByStatus failed: %v", err)
+	}
+	if len(inProgress) != 1 || inProgress[0].Symbol.Name != "Working" {
+		t.Fatalf("Expected only Working to be in progress, got %v", inProgress)
+	}
+	if inProgress[0].AssignedAgent != "agent-1" || inProgress[0].FilePath != "file.go" {
+		t.Errorf("Unexpected tracking state: %+v", inProgress[0])
+	}
+
+	notStarted, err := db.GetSymbolsByStatus(file.ProjectID, types.StatusNotStarted)
+	if err != nil {
+		t.Fatalf("GetSymbolsByStatus failed: %v", err)
+	}
+	if len(notStarted) != 1 || notStarted[0].Symbol.Name != "Idle" {
+		t.Errorf("Expected only Idle to be not started, got %v", notStarted)
+	}
+
+	tracked, err := db.GetTrackedSymbolsByFile(file.ID)
+	if err != nil {
+		t.Fatalf("GetTrackedSymbolsByFile failed: %v", err)
+	}
+	if len(tracked) != 1 || tracked[0].Symbol.ID != working.ID {
+		t.Errorf("Expected only Working to carry tracking state, got %v", tracked)
+	}
+}
+
+func TestGetSymbolByID(t *testing.T) {
+	db, file := openTestDB(t)
+
+	symbol := &types.Symbol{FileID: file.ID, Name: "Lookup", Type: types.SymbolTypeFunction, Signature: "func Lookup()"}
+	if err := db.SaveSymbol(symbol); err != nil {
+		t.Fatalf("SaveSymbol failed: %v", err)
+	}
+
+	found, err := db.GetSymbolByID(symbol.ID)
+	if err != nil {
+		t.Fatalf("GetSymbolByID failed: %v", err)
+	}
+	if found == nil || found.Name != "Lookup" || found.Signature != "func Lookup()" {
+		t.Errorf("Expected Lookup, got %+v", found)
+	}
+
+	missing, err := db.GetSymbolByID(symbol.ID + 100)
+	if err != nil {
+		t.Fatalf("GetSymbolByID failed: %v", err)
+	}
+	if missing != nil {
+		t.Errorf("Expected nil for an unknown id, got %+v", missing)
+	}
+}
+
+func TestGetDecoratedSymbols(t *testing.T) {
+	db, file := openTestDB(t)
+
+	for _, sym := range []*types.Symbol{
+		{FileID: file.ID, Name: "getUser", Type: types.SymbolTypeMethod, StartLine: 5,
+			Metadata: map[string]interface{}{"decorators": []string{"@GetMapping"}}},
+		{FileID: file.ID, Name: "plain", Type: types.SymbolTypeMethod, StartLine: 10,
+			Metadata: map[string]interface{}{"modifier": "static"}},
+		{FileID: file.ID, Name: "GetMappingHelper", Type: types.SymbolTypeMethod, StartLine: 15},
+	} {
+		if err := db.SaveSymbol(sym); err != nil {
+			t.Fatalf("SaveSymbol failed: %v", err)
+		}
+	}
+
+	symbols, err := db.GetDecoratedSymbols(file.ProjectID, "GetMapping")
+	if err != nil {
+		t.Fatalf("GetDecoratedSymbols failed: %v", err)
+	}
+	if len(symbols) != 1 || symbols[0].Name != "getUser" {
+		t.Fatalf("Expected only getUser, got %+v", symbols)
+	}
+	if symbols[0].Metadata["decorators"] == nil {
+		t.Error("Expected decorators to be loaded with the symbol")
+	}
+}
+
+func TestQuerySymbols(t *testing.T) {
+	db, file := openTestDB(t)
+
+	other := &types.File{ProjectID: file.ProjectID, Path: "/test/internal/api/handlers.go", RelativePath: "internal/api/handlers.go", Language: "go"}
+	if err := db.SaveFile(other); err != nil {
+		t.Fatalf("SaveFile failed: %v", err)
+	}
+
+	fixtures := []*types.Symbol{
+		// Long, public, undocumented methods: the ones we're after
+		{FileID: other.ID, Name: "HandleUpload", Type: types.SymbolTypeMethod, Visibility: types.VisibilityPublic, StartLine: 10, EndLine: 80},
+		{FileID: file.ID, Name: "Process", Type: types.SymbolTypeMethod, Visibility: types.VisibilityPublic, StartLine: 1, EndLine: 60},
+		// Each of these fails exactly one predicate
+		{FileID: other.ID, Name: "HandleList", Type: types.SymbolTypeMethod, Visibility: types.VisibilityPublic, StartLine: 100, EndLine: 120},
+		{FileID: other.ID, Name: "HandleDelete", Type: types.SymbolTypeMethod, Visibility: types.VisibilityPublic, StartLine: 200, EndLine: 260,
+			Documentation: "HandleDelete removes a record"},
+		{FileID: other.ID, Name: "handleAuth", Type: types.SymbolTypeMethod, Visibility: types.VisibilityPrivate, StartLine: 300, EndLine: 380},
+		{FileID: other.ID, Name: "BuildRouter", Type: types.SymbolTypeFunction, Visibility: types.VisibilityPublic, StartLine: 400, EndLine: 470},
+	}
+	for _, sym := range fixtures {
+		if err := db.SaveSymbol(sym); err != nil {
+			t.Fatalf("SaveSymbol failed: %v", err)
+		}
+	}
+
+	names := func(symbols []*types.Symbol) []string {
+		var result []string
+		for _, s := range symbols {
+			result = append(result, s.Name)
+		}
+		return result
+	}
+
+	noDocs := false
+	filter := &types.SymbolFilter{
+		Types:      []types.SymbolType{types.SymbolTypeMethod},
+		Visibility: []types.Visibility{types.VisibilityPublic},
+		MinLines:   50,
+		HasDocs:    &noDocs,
+	}
+
+	symbols, err := db.QuerySymbols(file.ProjectID, filter)
+	if err != nil {
+		t.Fatalf("QuerySymbols failed: %v", err)
+	}
+	if got := names(symbols); !reflect.DeepEqual(got, []string{"Process", "HandleUpload"}) {
+		t.Errorf("Expected [Process HandleUpload], got %v", got)
+	}
+
+	// Narrowing by path and an upper bound on length
+	filter.FilePattern = "internal/*"
+	filter.MaxLines = 70
+	filter.MinLines = 0
+	symbols, err = db.QuerySymbols(file.ProjectID, filter)
+	if err != nil {
+		t.Fatalf("QuerySymbols failed: %v", err)
+	}
+	if got := names(symbols); !reflect.DeepEqual(got, []string{"HandleList"}) {
+		t.Errorf("Expected [HandleList], got %v", got)
+	}
+
+	// Patterns use GLOB syntax: classes, and "*" crossing directories
+	filter = &types.SymbolFilter{FilePattern: "[a-z]*/api/*.go"}
+	symbols, err = db.QuerySymbols(file.ProjectID, filter)
+	if err != nil {
+		t.Fatalf("QuerySymbols failed: %v", err)
+	}
+	if len(symbols) != 5 {
+		t.Errorf("Expected the 5 symbols of internal/api/handlers.go, got %v", names(symbols))
+	}
+
+	filter.FilePattern = "internal/[api"
+	if _, err := db.QuerySymbols(file.ProjectID, filter); err == nil {
+		t.Error("Expected an error for an unterminated character class")
+	}
+}
+
+func TestOpenInMemory(t *testing.T) {
+	db, err := OpenInMemory()
+	if err != nil {
+		t.Fatalf("OpenInMemory failed: %v", err)
+	}
+	defer db.Close()
+
+	project := &types.Project{Name: "scratch", Path: "/scratch"}
+	if err := db.CreateProject(project); err != nil {
+		t.Fatalf("CreateProject failed: %v", err)
+	}
+	file := &types.File{ProjectID: project.ID, Path: "/scratch/main.go", RelativePath: "main.go", Language: "go"}
+	if err := db.SaveFile(file); err != nil {
+		t.Fatalf("SaveFile failed: %v", err)
+	}
+
+	// Concurrent writers use different pooled connections, which must all
+	// see the same database
+	var wg sync.WaitGroup
+	errs := make(chan error, 20)
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs <- db.SaveSymbol(&types.Symbol{FileID: file.ID, Name: fmt.Sprintf("Handler%d", i), Type: types.SymbolTypeFunction})
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("SaveSymbol failed: %v", err)
+		}
+	}
+
+	results, err := db.SearchSymbols(types.SearchOptions{Query: "Handler", Limit: 50})
+	if err != nil {
+		t.Fatalf("SearchSymbols failed: %v", err)
+	}
+	if len(results) != 20 {
+		t.Errorf("Expected 20 symbols from the full-text index, got %d", len(results))
+	}
+
+	// Each in-memory database is separate
+	other, err := OpenInMemory()
+	if err != nil {
+		t.Fatalf("OpenInMemory failed: %v", err)
+	}
+	defer other.Close()
+	found, err := other.GetProject("/scratch")
+	if err != nil {
+		t.Fatalf("GetProject failed: %v", err)
+	}
+	if found != nil {
+		t.Errorf("Expected a fresh in-memory database to be empty, got %+v", found)
+	}
+}
+
+func TestGetOrCreateProjectSameBaseName(t *testing.T) {
+	db, err := Open(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("Failed to open test database: %v", err)
+	}
+	defer db.Close()
+
+	upstream, err := db.GetOrCreateProject("/src/upstream/app", "app-upstream")
+	if err != nil {
+		t.Fatalf("GetOrCreateProject failed: %v", err)
+	}
+	fork, err := db.GetOrCreateProject("/src/fork/app", "app-fork")
+	if err != nil {
+		t.Fatalf("GetOrCreateProject failed: %v", err)
+	}
+
+	if upstream.ID == fork.ID {
+		t.Fatalf("Expected distinct projects, both got ID %d", upstream.ID)
+	}
+	for path, want := range map[string]string{"/src/upstream/app": "app-upstream", "/src/fork/app": "app-fork"} {
+		project, err := db.GetProject(path)
+		if err != nil {
+			t.Fatalf("GetProject failed: %v", err)
+		}
+		if project == nil || project.Name != want {
+			t.Errorf("Expected project %s to be named %q, got %+v", path, want, project)
+		}
+	}
+}
+
+func TestDeleteProject(t *testing.T) {
+	db, err := Open(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("Failed to open test database: %v", err)
+	}
+	defer db.Close()
+
+	// Each project gets a file with a caller and callee, a call reference
+	// and a relationship between them
+	populate := func(path string) (*types.Project, []int64) {
+		project, err := db.GetOrCreateProject(path, filepath.Base(path))
+		if err != nil {
+			t.Fatalf("GetOrCreateProject failed: %v", err)
+		}
+		file := &types.File{ProjectID: project.ID, Path: path + "/main.go", RelativePath: "main.go", Language: "go"}
+		if err := db.SaveFile(file); err != nil {
+			t.Fatalf("SaveFile failed: %v", err)
+		}
+
+		var ids []int64
+		for _, name := range []string{"caller", "callee"} {
+			symbol := &types.Symbol{FileID: file.ID, Name: name, Type: types.SymbolTypeFunction, Documentation: "purgeable " + name}
+			if err := db.SaveSymbol(symbol); err != nil {
+				t.Fatalf("SaveSymbol failed: %v", err)
+			}
+			ids = append(ids, symbol.ID)
+		}
+		if err := db.SaveReference(&types.Reference{SymbolID: ids[1], FileID: file.ID, LineNumber: 3, ReferenceType: "call"}); err != nil {
+			t.Fatalf("SaveReference failed: %v", err)
+		}
+		if err := db.SaveRelationship(&types.Relationship{FromSymbolID: ids[0], ToSymbolID: ids[1], Type: types.RelationshipCalls}); err != nil {
+			t.Fatalf("SaveRelationship failed: %v", err)
+		}
+		return project, ids
+	}
+
+	moved, movedIDs := populate("/src/old/app")
+	kept, keptIDs := populate("/src/new/app")
+
+	if err := db.DeleteProject(moved.ID); err != nil {
+		t.Fatalf("DeleteProject failed: %v", err)
+	}
+
+	if project, err := db.GetProject("/src/old/app"); err != nil || project != nil {
+		t.Errorf("Expected the purged project to be gone, got %+v (%v)", project, err)
+	}
+	for _, id := range movedIDs {
+		if symbol, _ := db.GetSymbolByID(id); symbol != nil {
+			t.Errorf("Expected purged symbol %d to be gone", id)
+		}
+	}
+	if got := ftsMatches(t, db, "purgeable"); !reflect.DeepEqual(got, keptIDs) {
+		t.Errorf("Expected FTS entries only for the kept symbols %v, got %v", keptIDs, got)
+	}
+
+	files, err := db.GetAllFilesForProject(kept.ID)
+	if err != nil || len(files) != 1 {
+		t.Fatalf("Expected the kept project's file, got %v (%v)", files, err)
+	}
+	refs, err := db.GetReferencesBySymbol(keptIDs[1])
+	if err != nil || len(refs) != 1 {
+		t.Errorf("Expected the kept project's reference, got %v (%v)", refs, err)
+	}
+	rels, err := db.GetRelationshipsByProject(kept.ID)
+	if err != nil || len(rels) != 1 {
+		t.Errorf("Expected the kept project's relationship, got %v (%v)", rels, err)
+	}
+
+	if err := db.DeleteProject(moved.ID); err == nil {
+		t.Error("Expected an error deleting a project twice")
+	}
+}
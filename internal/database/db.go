@@ -4,7 +4,10 @@ import (
 	"database/sql"
 	"encoding/json"
 	"fmt"
+	"os"
 	"path/filepath"
+	"strings"
+	"sync/atomic"
 	"time"
 
 	_ "modernc.org/sqlite" // Pure Go SQLite driver
@@ -14,20 +17,40 @@ import (
 
 // DB represents the database connection
 type DB struct {
-	conn *sql.DB
-	path string
+	conn       *sql.DB
+	path       string
+	splitNames bool // Index camelCase/snake_case-split names for FTS
 }
 
-// Open opens or creates a database at the given path
+// MemoryPath is the path Open takes to create an in-memory database
+const MemoryPath = ":memory:"
+
+// memoryDBs numbers in-memory databases so each Open gets its own
+var memoryDBs atomic.Int64
+
+// Open opens or creates a database at the given path, or an in-memory
+// database if the path is MemoryPath
 func Open(dbPath string) (*DB, error) {
-	// Ensure directory exists
-	dir := filepath.Dir(dbPath)
-	if err := ensureDir(dir); err != nil {
-		return nil, fmt.Errorf("failed to create db directory: %w", err)
+	inMemory := dbPath == MemoryPath
+
+	var dsn string
+	if inMemory {
+		// Every connection to ":memory:" gets its own empty database, so the
+		// pool's connections share a named one instead. It lives as long as
+		// any connection to it stays open.
+		dsn = fmt.Sprintf("file:memdb%d?mode=memory&cache=shared&_pragma=foreign_keys(ON)&_pragma=busy_timeout(5000)", memoryDBs.Add(1))
+	} else {
+		// Ensure directory exists
+		dir := filepath.Dir(dbPath)
+		if err := ensureDir(dir); err != nil {
+			return nil, fmt.Errorf("failed to create db directory: %w", err)
+		}
+
+		// Open database with pragmas for performance; busy_timeout makes writers
+		// from other processes wait for the lock instead of failing immediately
+		dsn = fmt.Sprintf("%s?_pragma=journal_mode(WAL)&_pragma=synchronous(NORMAL)&_pragma=foreign_keys(ON)&_pragma=busy_timeout(5000)", dbPath)
 	}
 
-	// Open database with pragmas for performance
-	dsn := fmt.Sprintf("%s?_pragma=journal_mode(WAL)&_pragma=synchronous(NORMAL)&_pragma=foreign_keys(ON)", dbPath)
 	conn, err := sql.Open("sqlite", dsn)
 	if err != nil {
 		return nil, fmt.Errorf("failed to open database: %w", err)
@@ -36,7 +59,12 @@ func Open(dbPath string) (*DB, error) {
 	// Set connection pool settings
 	conn.SetMaxOpenConns(10)
 	conn.SetMaxIdleConns(5)
-	conn.SetConnMaxLifetime(time.Hour)
+	if inMemory {
+		// Recycling every connection at once would drop the database
+		conn.SetConnMaxLifetime(0)
+	} else {
+		conn.SetConnMaxLifetime(time.Hour)
+	}
 
 	db := &DB{
 		conn: conn,
@@ -52,6 +80,38 @@ func Open(dbPath string) (*DB, error) {
 	return db, nil
 }
 
+// OpenReadOnly opens an existing database for reading only: writes through
+// it fail, and the schema is used as found rather than migrated
+func OpenReadOnly(dbPath string) (*DB, error) {
+	if _, err := os.Stat(dbPath); err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+
+	dsn := fmt.Sprintf("file:%s?mode=ro&_pragma=query_only(1)&_pragma=busy_timeout(5000)", dbPath)
+	conn, err := sql.Open("sqlite", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+	if err := conn.Ping(); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+
+	return &DB{conn: conn, path: dbPath}, nil
+}
+
+// OpenInMemory creates a database that lives in memory and is gone once
+// closed, for tests and short-lived analyses
+func OpenInMemory() (*DB, error) {
+	return Open(MemoryPath)
+}
+
+// SetNameSplitting enables indexing of split identifier words (e.g. "handleHTTPRequest"
+// as "handle http request") so multi-word queries match individual name parts
+func (db *DB) SetNameSplitting(enabled bool) {
+	db.splitNames = enabled
+}
+
 // Close closes the database connection
 func (db *DB) Close() error {
 	if db.conn != nil {
@@ -60,15 +120,120 @@ func (db *DB) Close() error {
 	return nil
 }
 
+// addedColumns lists columns added to existing tables since their first
+// release. CREATE TABLE IF NOT EXISTS leaves an older database's tables as
+// they were, so migrate adds these where they're missing.
+var addedColumns = []struct {
+	table, column, definition string
+}{
+	{"files", "encoding", "TEXT"},
+	{"symbols", "name_tokens", "TEXT"},
+	{"symbols", "status", "TEXT DEFAULT 'not_started'"},
+	{"symbols", "priority", "INTEGER DEFAULT 0"},
+	{"symbols", "assigned_agent", "TEXT"},
+	{"relationships", "metadata", "TEXT"},
+	{"imports", "alias", "TEXT"},
+}
+
 // migrate runs database migrations
 func (db *DB) migrate() error {
-	_, err := db.conn.Exec(Schema)
+	// Older tables are brought up to date first, since the schema's indexes
+	// refer to the added columns
+	for _, added := range addedColumns {
+		columns, err := db.tableColumns(added.table)
+		if err != nil {
+			return err
+		}
+		if len(columns) == 0 || columns[added.column] {
+			continue
+		}
+		stmt := fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s", added.table, added.column, added.definition)
+		if _, err := db.conn.Exec(stmt); err != nil {
+			return fmt.Errorf("failed to add %s.%s: %w", added.table, added.column, err)
+		}
+	}
+
+	// FTS tables can't be altered; one without name_tokens is dropped along
+	// with its triggers and recreated by the schema below
+	ftsColumns, err := db.tableColumns("symbols_fts")
+	if err != nil {
+		return err
+	}
+	rebuildFTS := len(ftsColumns) > 0 && !ftsColumns["name_tokens"]
+	if rebuildFTS {
+		if _, err := db.conn.Exec(dropFTSTriggers + "DROP TABLE symbols_fts;"); err != nil {
+			return fmt.Errorf("failed to drop FTS table: %w", err)
+		}
+	}
+
+	interrupted, err := db.bulkFTSInterrupted()
+	if err != nil {
+		return err
+	}
+
+	_, err = db.conn.Exec(Schema)
 	if err != nil {
 		return fmt.Errorf("failed to execute schema: %w", err)
 	}
+	if rebuildFTS || interrupted {
+		return db.RebuildFTS()
+	}
+
+	// Older databases' delete trigger left index entries behind for deleted
+	// symbols; replace the triggers and rebuild the index to drop them
+	var triggerSQL string
+	err = db.conn.QueryRow("SELECT sql FROM sqlite_master WHERE type = 'trigger' AND name = 'symbols_ad'").Scan(&triggerSQL)
+	if err == nil && strings.Contains(triggerSQL, "DELETE FROM symbols_fts") {
+		if _, err := db.conn.Exec(dropFTSTriggers + ftsTriggers); err != nil {
+			return fmt.Errorf("failed to update FTS triggers: %w", err)
+		}
+		return db.RebuildFTS()
+	}
 	return nil
 }
 
+// bulkFTSInterrupted reports whether a bulk load dropped the FTS sync
+// triggers and never got to EndBulkFTS, leaving the index behind the symbols
+// table. The missing triggers are the record of it: EndBulkFTS restores them
+// in the same transaction as its rebuild.
+func (db *DB) bulkFTSInterrupted() (bool, error) {
+	var tables, triggers int
+	err := db.conn.QueryRow(`SELECT
+		COUNT(CASE WHEN type = 'table' AND name = 'symbols_fts' THEN 1 END),
+		COUNT(CASE WHEN type = 'trigger' AND name = 'symbols_ai' THEN 1 END)
+		FROM sqlite_master`).Scan(&tables, &triggers)
+	if err != nil {
+		return false, fmt.Errorf("failed to check FTS triggers: %w", err)
+	}
+	return tables > 0 && triggers == 0, nil
+}
+
+// tableColumns returns the set of a table's column names, empty if the table
+// doesn't exist
+func (db *DB) tableColumns(table string) (map[string]bool, error) {
+	rows, err := db.conn.Query(fmt.Sprintf("PRAGMA table_info(%s)", table))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read columns of %s: %w", table, err)
+	}
+	defer rows.Close()
+
+	columns := make(map[string]bool)
+	for rows.Next() {
+		var (
+			cid        int
+			name, typ  string
+			notNull    bool
+			defaultVal sql.NullString
+			pk         int
+		)
+		if err := rows.Scan(&cid, &name, &typ, &notNull, &defaultVal, &pk); err != nil {
+			return nil, err
+		}
+		columns[name] = true
+	}
+	return columns, rows.Err()
+}
+
 // Transaction executes a function within a transaction
 func (db *DB) Transaction(fn func(*sql.Tx) error) error {
 	tx, err := db.conn.Begin()
@@ -91,6 +256,42 @@ func (db *DB) Transaction(fn func(*sql.Tx) error) error {
 	return tx.Commit()
 }
 
+// BeginBulkFTS drops the FTS sync triggers ahead of a bulk load.
+// EndBulkFTS must be called afterwards to restore them; if the process dies
+// first, the next Open rebuilds the index.
+func (db *DB) BeginBulkFTS() error {
+	if _, err := db.conn.Exec(dropFTSTriggers); err != nil {
+		return fmt.Errorf("failed to drop FTS triggers: %w", err)
+	}
+	return nil
+}
+
+// EndBulkFTS rebuilds the FTS index in a single pass and recreates the FTS
+// sync triggers, in one transaction so that a crash part way leaves the
+// triggers missing for the next Open to notice
+func (db *DB) EndBulkFTS() error {
+	return db.Transaction(func(tx *sql.Tx) error {
+		if _, err := tx.Exec(ftsRebuild); err != nil {
+			return fmt.Errorf("failed to rebuild FTS index: %w", err)
+		}
+		if _, err := tx.Exec(ftsTriggers); err != nil {
+			return fmt.Errorf("failed to create FTS triggers: %w", err)
+		}
+		return nil
+	})
+}
+
+// ftsRebuild refills symbols_fts from the symbols table
+const ftsRebuild = "INSERT INTO symbols_fts(symbols_fts) VALUES('rebuild')"
+
+// RebuildFTS rebuilds the full-text index from the symbols table
+func (db *DB) RebuildFTS() error {
+	if _, err := db.conn.Exec(ftsRebuild); err != nil {
+		return fmt.Errorf("failed to rebuild FTS index: %w", err)
+	}
+	return nil
+}
+
 // ensureDir creates a directory if it doesn't exist
 func ensureDir(dir string) error {
 	// This will be implemented in utils
@@ -131,6 +332,11 @@ func nullString(s string) sql.NullString {
 	return sql.NullString{String: s, Valid: true}
 }
 
+// placeholders returns n comma-separated SQL parameter placeholders
+func placeholders(n int) string {
+	return strings.TrimSuffix(strings.Repeat("?, ", n), ", ")
+}
+
 // Ping checks database connectivity
 func (db *DB) Ping() error {
 	return db.conn.Ping()
@@ -146,7 +352,7 @@ func (db *DB) Stats() (map[string]int, error) {
 		"symbols":       "SELECT COUNT(*) FROM symbols",
 		"imports":       "SELECT COUNT(*) FROM imports",
 		"relationships": "SELECT COUNT(*) FROM relationships",
-		"references":    "SELECT COUNT(*) FROM references",
+		"references":    `SELECT COUNT(*) FROM "references"`,
 	}
 
 	for name, query := range queries {
@@ -3,8 +3,11 @@ package database
 import (
 	"database/sql"
 	"fmt"
+	"sort"
+	"strings"
 	"time"
 
+	"github.com/aaamil13/CodeIndexerMCP/internal/utils"
 	"github.com/aaamil13/CodeIndexerMCP/pkg/types"
 )
 
@@ -42,6 +45,45 @@ func (db *DB) CreateProject(project *types.Project) error {
 	return nil
 }
 
+// GetOrCreateProject returns the project at path, creating it if needed.
+// It runs as a single upsert so concurrent callers get the same row.
+func (db *DB) GetOrCreateProject(path, name string) (*types.Project, error) {
+	langStatsJSON, err := toJSON(map[string]int{})
+	if err != nil {
+		return nil, err
+	}
+
+	// The no-op update makes RETURNING yield the existing row on conflict
+	query := `
+		INSERT INTO projects (path, name, language_stats, last_indexed, created_at)
+		VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT(path) DO UPDATE SET path = excluded.path
+		RETURNING id, path, name, language_stats, last_indexed, created_at
+	`
+
+	var project types.Project
+	err = db.conn.QueryRow(query, path, name, langStatsJSON, time.Time{}, time.Now()).Scan(
+		&project.ID,
+		&project.Path,
+		&project.Name,
+		&langStatsJSON,
+		&project.LastIndexed,
+		&project.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := fromJSON(langStatsJSON, &project.LanguageStats); err != nil {
+		return nil, err
+	}
+	if project.LanguageStats == nil {
+		project.LanguageStats = make(map[string]int)
+	}
+
+	return &project, nil
+}
+
 // GetProject retrieves a project by path
 func (db *DB) GetProject(path string) (*types.Project, error) {
 	query := `SELECT id, path, name, language_stats, last_indexed, created_at FROM projects WHERE path = ?`
@@ -89,13 +131,60 @@ func (db *DB) UpdateProject(project *types.Project) error {
 	return err
 }
 
+// DeleteProject deletes a project with its files, symbols, imports,
+// references, relationships, parse errors and complexity history in one
+// transaction. The symbols' FTS entries go with them through the delete
+// trigger.
+func (db *DB) DeleteProject(projectID int64) error {
+	const projectFiles = "SELECT id FROM files WHERE project_id = ?"
+	const projectSymbols = "SELECT id FROM symbols WHERE file_id IN (" + projectFiles + ")"
+
+	// Children first, so nothing relies on foreign key cascades being enabled
+	deletes := []struct {
+		query string
+		args  int // Number of projectID placeholders
+	}{
+		{`DELETE FROM "references" WHERE symbol_id IN (` + projectSymbols + ") OR file_id IN (" + projectFiles + ")", 2},
+		{"DELETE FROM relationships WHERE from_symbol_id IN (" + projectSymbols + ") OR to_symbol_id IN (" + projectSymbols + ")", 2},
+		{"DELETE FROM function_details WHERE symbol_id IN (" + projectSymbols + ")", 1},
+		{"DELETE FROM symbols WHERE file_id IN (" + projectFiles + ")", 1},
+		{"DELETE FROM imports WHERE file_id IN (" + projectFiles + ")", 1},
+		{"DELETE FROM files WHERE project_id = ?", 1},
+		{"DELETE FROM parse_errors WHERE project_id = ?", 1},
+		{"DELETE FROM symbol_versions WHERE project_id = ?", 1},
+	}
+
+	return db.Transaction(func(tx *sql.Tx) error {
+		for _, d := range deletes {
+			args := make([]interface{}, d.args)
+			for i := range args {
+				args[i] = projectID
+			}
+			if _, err := tx.Exec(d.query, args...); err != nil {
+				return err
+			}
+		}
+
+		result, err := tx.Exec("DELETE FROM projects WHERE id = ?", projectID)
+		if err != nil {
+			return err
+		}
+		if deleted, err := result.RowsAffected(); err != nil {
+			return err
+		} else if deleted == 0 {
+			return fmt.Errorf("project not found: %d", projectID)
+		}
+		return nil
+	})
+}
+
 // File operations
 
 // SaveFile creates or updates a file
 func (db *DB) SaveFile(file *types.File) error {
 	query := `
-		INSERT INTO files (project_id, path, relative_path, language, size, lines_of_code, hash, last_modified, last_indexed)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+		INSERT INTO files (project_id, path, relative_path, language, size, lines_of_code, hash, last_modified, last_indexed, encoding)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 		ON CONFLICT(project_id, relative_path) DO UPDATE SET
 			path = excluded.path,
 			language = excluded.language,
@@ -103,7 +192,8 @@ func (db *DB) SaveFile(file *types.File) error {
 			lines_of_code = excluded.lines_of_code,
 			hash = excluded.hash,
 			last_modified = excluded.last_modified,
-			last_indexed = excluded.last_indexed
+			last_indexed = excluded.last_indexed,
+			encoding = excluded.encoding
 		RETURNING id
 	`
 
@@ -117,6 +207,7 @@ func (db *DB) SaveFile(file *types.File) error {
 		file.Hash,
 		file.LastModified,
 		file.LastIndexed,
+		nullString(file.Encoding),
 	).Scan(&file.ID)
 
 	return err
@@ -124,7 +215,7 @@ func (db *DB) SaveFile(file *types.File) error {
 
 // GetFile retrieves a file by ID
 func (db *DB) GetFile(id int64) (*types.File, error) {
-	query := `SELECT id, project_id, path, relative_path, language, size, lines_of_code, hash, last_modified, last_indexed FROM files WHERE id = ?`
+	query := `SELECT id, project_id, path, relative_path, language, size, lines_of_code, hash, last_modified, last_indexed, COALESCE(encoding, '') FROM files WHERE id = ?`
 
 	var file types.File
 	err := db.conn.QueryRow(query, id).Scan(
@@ -138,6 +229,7 @@ func (db *DB) GetFile(id int64) (*types.File, error) {
 		&file.Hash,
 		&file.LastModified,
 		&file.LastIndexed,
+		&file.Encoding,
 	)
 
 	if err == sql.ErrNoRows {
@@ -152,7 +244,7 @@ func (db *DB) GetFile(id int64) (*types.File, error) {
 
 // GetFileByPath retrieves a file by its relative path
 func (db *DB) GetFileByPath(projectID int64, relativePath string) (*types.File, error) {
-	query := `SELECT id, project_id, path, relative_path, language, size, lines_of_code, hash, last_modified, last_indexed FROM files WHERE project_id = ? AND relative_path = ?`
+	query := `SELECT id, project_id, path, relative_path, language, size, lines_of_code, hash, last_modified, last_indexed, COALESCE(encoding, '') FROM files WHERE project_id = ? AND relative_path = ?`
 
 	var file types.File
 	err := db.conn.QueryRow(query, projectID, relativePath).Scan(
@@ -166,6 +258,7 @@ func (db *DB) GetFileByPath(projectID int64, relativePath string) (*types.File,
 		&file.Hash,
 		&file.LastModified,
 		&file.LastIndexed,
+		&file.Encoding,
 	)
 
 	if err == sql.ErrNoRows {
@@ -178,17 +271,103 @@ func (db *DB) GetFileByPath(projectID int64, relativePath string) (*types.File,
 	return &file, nil
 }
 
-// DeleteFile deletes a file and all its symbols
+// DeleteFile deletes a file and all its symbols, along with the parse
+// errors and symbol versions recorded for its path
 func (db *DB) DeleteFile(id int64) error {
-	_, err := db.conn.Exec("DELETE FROM files WHERE id = ?", id)
-	return err
+	return db.Transaction(func(tx *sql.Tx) error {
+		for _, table := range []string{"parse_errors", "symbol_versions"} {
+			if _, err := tx.Exec(`
+				DELETE FROM `+table+` WHERE EXISTS (
+					SELECT 1 FROM files
+					WHERE files.id = ? AND files.project_id = `+table+`.project_id AND files.relative_path = `+table+`.relative_path
+				)
+			`, id); err != nil {
+				return err
+			}
+		}
+		_, err := tx.Exec("DELETE FROM files WHERE id = ?", id)
+		return err
+	})
+}
+
+// DeleteFilesWithPrefix deletes every file of a project whose relative path
+// starts with prefix, and the parse errors and symbol versions recorded under
+// it, and returns how many files were deleted
+func (db *DB) DeleteFilesWithPrefix(projectID int64, prefix string) (int64, error) {
+	var removed int64
+	err := db.Transaction(func(tx *sql.Tx) error {
+		// Paths compare bytewise, and no UTF-8 byte is 0xff, so the paths that
+		// start with prefix are exactly those in [prefix, prefix+"\xff")
+		for _, table := range []string{"parse_errors", "symbol_versions"} {
+			if _, err := tx.Exec(
+				"DELETE FROM "+table+" WHERE project_id = ? AND relative_path >= ? AND relative_path < ?",
+				projectID, prefix, prefix+"\xff",
+			); err != nil {
+				return err
+			}
+		}
+		result, err := tx.Exec(
+			"DELETE FROM files WHERE project_id = ? AND relative_path >= ? AND relative_path < ?",
+			projectID, prefix, prefix+"\xff",
+		)
+		if err != nil {
+			return err
+		}
+		removed, err = result.RowsAffected()
+		return err
+	})
+	return removed, err
+}
+
+// CountFilesByLanguage returns the number of files in a project per language
+func (db *DB) CountFilesByLanguage(projectID int64) (map[string]int, error) {
+	rows, err := db.conn.Query(
+		"SELECT COALESCE(language, ''), COUNT(*) FROM files WHERE project_id = ? GROUP BY language",
+		projectID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	counts := make(map[string]int)
+	for rows.Next() {
+		var language string
+		var count int
+		if err := rows.Scan(&language, &count); err != nil {
+			return nil, err
+		}
+		counts[language] = count
+	}
+
+	return counts, rows.Err()
 }
 
 // Symbol operations
 
+// Metadata keys symbol fields without their own column are persisted under
+const (
+	typeParamsKey   = "type_params"
+	dependenciesKey = "dependencies"
+)
+
 // SaveSymbol creates a new symbol
 func (db *DB) SaveSymbol(symbol *types.Symbol) error {
-	metadataJSON, err := toJSON(symbol.Metadata)
+	metadata := symbol.Metadata
+	if len(symbol.TypeParams) > 0 || len(symbol.Dependencies) > 0 {
+		metadata = make(map[string]interface{}, len(symbol.Metadata)+2)
+		for key, value := range symbol.Metadata {
+			metadata[key] = value
+		}
+		if len(symbol.TypeParams) > 0 {
+			metadata[typeParamsKey] = symbol.TypeParams
+		}
+		if len(symbol.Dependencies) > 0 {
+			metadata[dependenciesKey] = symbol.Dependencies
+		}
+	}
+
+	metadataJSON, err := toJSON(metadata)
 	if err != nil {
 		return err
 	}
@@ -198,11 +377,16 @@ func (db *DB) SaveSymbol(symbol *types.Symbol) error {
 			file_id, name, type, signature, parent_id,
 			start_line, end_line, start_column, end_column,
 			visibility, is_exported, is_async, is_static, is_abstract,
-			documentation, metadata
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+			documentation, metadata, name_tokens
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 		RETURNING id
 	`
 
+	var nameTokens string
+	if db.splitNames {
+		nameTokens = strings.Join(utils.SplitIdentifier(symbol.Name), " ")
+	}
+
 	err = db.conn.QueryRow(query,
 		symbol.FileID,
 		symbol.Name,
@@ -220,11 +404,61 @@ func (db *DB) SaveSymbol(symbol *types.Symbol) error {
 		symbol.IsAbstract,
 		nullString(symbol.Documentation),
 		metadataJSON,
+		nullString(nameTokens),
 	).Scan(&symbol.ID)
+	if err != nil {
+		return err
+	}
+
+	if len(symbol.Parameters) > 0 || symbol.ReturnType != "" {
+		return db.saveFunctionDetails(symbol)
+	}
+	return nil
+}
+
+// saveFunctionDetails stores a function's parsed parameters and return type
+func (db *DB) saveFunctionDetails(symbol *types.Symbol) error {
+	parameters := symbol.Parameters
+	if parameters == nil {
+		parameters = []*types.Parameter{}
+	}
+	parametersJSON, err := toJSON(parameters)
+	if err != nil {
+		return err
+	}
 
+	_, err = db.conn.Exec(`
+		INSERT OR REPLACE INTO function_details (symbol_id, parameters, return_type)
+		VALUES (?, ?, ?)
+	`, symbol.ID, parametersJSON, nullString(symbol.ReturnType))
 	return err
 }
 
+// GetFunctionDetails returns the parsed parameters and return type of a
+// function, or nil if its parser didn't record any
+func (db *DB) GetFunctionDetails(symbolID int64) (*types.FunctionDetails, error) {
+	var parametersJSON, returnType sql.NullString
+	err := db.conn.QueryRow(`
+		SELECT parameters, return_type FROM function_details WHERE symbol_id = ?
+	`, symbolID).Scan(&parametersJSON, &returnType)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	details := &types.FunctionDetails{
+		SymbolID:   symbolID,
+		Parameters: []*types.Parameter{},
+		ReturnType: returnType.String,
+	}
+	if err := fromJSON(parametersJSON.String, &details.Parameters); err != nil {
+		return nil, err
+	}
+	return details, nil
+}
+
 // DeleteSymbolsByFile deletes all symbols for a file
 func (db *DB) DeleteSymbolsByFile(fileID int64) error {
 	_, err := db.conn.Exec("DELETE FROM symbols WHERE file_id = ?", fileID)
@@ -233,27 +467,205 @@ func (db *DB) DeleteSymbolsByFile(fileID int64) error {
 
 // SearchSymbols searches for symbols by name
 func (db *DB) SearchSymbols(opts types.SearchOptions) ([]*types.Symbol, error) {
+	var symbols []*types.Symbol
+	err := db.SearchSymbolsStream(opts, func(symbol *types.Symbol) error {
+		symbols = append(symbols, symbol)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return symbols, nil
+}
+
+// SearchSymbolsStream runs the same search as SearchSymbols but passes each
+// symbol to fn as its row is read, instead of collecting them all. It stops
+// at the first error fn returns and returns that error.
+func (db *DB) SearchSymbolsStream(opts types.SearchOptions, fn func(*types.Symbol) error) error {
+	query, args := searchSymbolsQuery(opts)
+
+	rows, err := db.conn.Query(query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	// Names equal to the query ignoring case come first; hold them back so
+	// the ones matching case too can be passed on ahead of them
+	var exact []*types.Symbol
+	holding := opts.Ranking != types.SearchRankingName
+	release := func() error {
+		holding = false
+		for _, symbol := range boostExactName(exact, opts.Query) {
+			if err := fn(symbol); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	for rows.Next() {
+		symbol, err := scanSymbol(rows)
+		if err != nil {
+			return err
+		}
+
+		if holding {
+			if strings.EqualFold(symbol.Name, opts.Query) {
+				exact = append(exact, symbol)
+				continue
+			}
+			if err := release(); err != nil {
+				return err
+			}
+		}
+
+		if err := fn(symbol); err != nil {
+			return err
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	if holding {
+		return release()
+	}
+	return nil
+}
+
+// searchSymbolsQuery builds the SQL for a symbol search
+func searchSymbolsQuery(opts types.SearchOptions) (string, []interface{}) {
 	query := `
-		SELECT id, file_id, name, type, signature, parent_id,
-			start_line, end_line, start_column, end_column,
-			visibility, is_exported, is_async, is_static, is_abstract,
-			documentation, metadata
-		FROM symbols
-		WHERE name LIKE ?
+		SELECT s.id, s.file_id, s.name, s.type, s.signature, s.parent_id,
+			s.start_line, s.end_line, s.start_column, s.end_column,
+			s.visibility, s.is_exported, s.is_async, s.is_static, s.is_abstract,
+			s.documentation, s.metadata
+		FROM symbols s
 	`
-	args := []interface{}{"%" + opts.Query + "%"}
+	var args []interface{}
+
+	// Also match split name words, so "http request" finds handleHTTPRequest;
+	// the FTS rank (bm25) orders matches of similar quality
+	where, rank := "s.name LIKE ?", "0"
+	if match := nameTokensMatch(opts.Query); match != "" {
+		query += " LEFT JOIN (SELECT rowid, rank FROM symbols_fts WHERE symbols_fts MATCH ?) fts ON fts.rowid = s.id"
+		args = append(args, match)
+		where += " OR fts.rowid IS NOT NULL"
+		rank = "COALESCE(fts.rank, 0)"
+	}
+	query += " WHERE (" + where + ")"
+	args = append(args, "%"+opts.Query+"%")
 
 	if opts.Type != nil {
-		query += " AND type = ?"
+		query += " AND s.type = ?"
 		args = append(args, *opts.Type)
 	}
 
+	if opts.FileID != 0 {
+		query += " AND s.file_id = ?"
+		args = append(args, opts.FileID)
+	}
+
+	if opts.ProjectID != 0 {
+		query += " AND s.file_id IN (SELECT id FROM files WHERE project_id = ?)"
+		args = append(args, opts.ProjectID)
+	}
+
+	if opts.Language != "" {
+		query += " AND s.file_id IN (SELECT id FROM files WHERE language = ?)"
+		args = append(args, opts.Language)
+	}
+
+	switch opts.Ranking {
+	case types.SearchRankingName:
+		query += " ORDER BY s.name"
+	default:
+		// Exact name matches first, then prefix matches, then by match quality
+		query += " ORDER BY CASE WHEN s.name = ? COLLATE NOCASE THEN 0 WHEN s.name LIKE ? THEN 1 ELSE 2 END, " + rank + ", s.name"
+		args = append(args, opts.Query, opts.Query+"%")
+	}
+
 	if opts.Limit > 0 {
 		query += fmt.Sprintf(" LIMIT %d", opts.Limit)
 	} else {
 		query += " LIMIT 100" // Default limit
 	}
 
+	return query, args
+}
+
+// boostExactName moves symbols whose name equals query, case included, ahead
+// of the others, keeping the order otherwise
+func boostExactName(symbols []*types.Symbol, query string) []*types.Symbol {
+	sort.SliceStable(symbols, func(i, j int) bool {
+		return symbols[i].Name == query && symbols[j].Name != query
+	})
+	return symbols
+}
+
+// QuerySymbols retrieves the project's symbols matching every predicate of
+// filter, ordered by file and line
+func (db *DB) QuerySymbols(projectID int64, filter *types.SymbolFilter) ([]*types.Symbol, error) {
+	query := `
+		SELECT s.id, s.file_id, s.name, s.type, s.signature, s.parent_id,
+			s.start_line, s.end_line, s.start_column, s.end_column,
+			s.visibility, s.is_exported, s.is_async, s.is_static, s.is_abstract,
+			s.documentation, s.metadata
+		FROM symbols s
+		JOIN files f ON s.file_id = f.id
+		WHERE f.project_id = ?
+	`
+	args := []interface{}{projectID}
+
+	if len(filter.Types) > 0 {
+		query += " AND s.type IN (" + placeholders(len(filter.Types)) + ")"
+		for _, t := range filter.Types {
+			args = append(args, t)
+		}
+	}
+
+	if len(filter.Visibility) > 0 {
+		query += " AND s.visibility IN (" + placeholders(len(filter.Visibility)) + ")"
+		for _, v := range filter.Visibility {
+			args = append(args, v)
+		}
+	}
+
+	if filter.MinLines > 0 {
+		query += " AND s.end_line - s.start_line + 1 >= ?"
+		args = append(args, filter.MinLines)
+	}
+
+	if filter.MaxLines > 0 {
+		query += " AND s.end_line - s.start_line + 1 <= ?"
+		args = append(args, filter.MaxLines)
+	}
+
+	if filter.HasDocs != nil {
+		if *filter.HasDocs {
+			query += " AND COALESCE(s.documentation, '') != ''"
+		} else {
+			query += " AND COALESCE(s.documentation, '') = ''"
+		}
+	}
+
+	if filter.FilePattern != "" {
+		if err := checkGlob(filter.FilePattern); err != nil {
+			return nil, err
+		}
+		query += " AND f.relative_path GLOB ?"
+		args = append(args, filter.FilePattern)
+	}
+
+	query += " ORDER BY f.relative_path, s.start_line"
+
+	if filter.Limit > 0 {
+		query += fmt.Sprintf(" LIMIT %d", filter.Limit)
+	} else {
+		query += " LIMIT 100" // Default limit
+	}
+
 	rows, err := db.conn.Query(query, args...)
 	if err != nil {
 		return nil, err
@@ -272,6 +684,82 @@ func (db *DB) SearchSymbols(opts types.SearchOptions) ([]*types.Symbol, error) {
 	return symbols, rows.Err()
 }
 
+// checkGlob reports patterns SQLite's GLOB can't match anything with: a
+// character class without its closing bracket. A "]" right after "[" or
+// "[^" belongs to the class, and backslashes are literal.
+func checkGlob(pattern string) error {
+	for i := 0; i < len(pattern); i++ {
+		if pattern[i] != '[' {
+			continue
+		}
+		j := i + 1
+		if j < len(pattern) && pattern[j] == '^' {
+			j++
+		}
+		if j < len(pattern) && pattern[j] == ']' {
+			j++
+		}
+		end := strings.IndexByte(pattern[j:], ']')
+		if end < 0 {
+			return fmt.Errorf("invalid file pattern %q: unterminated character class", pattern)
+		}
+		i = j + end
+	}
+	return nil
+}
+
+// GetSymbolsInRange retrieves symbols in a file whose lines overlap [startLine, endLine], ordered by start line
+func (db *DB) GetSymbolsInRange(fileID int64, startLine, endLine int) ([]*types.SymbolInRange, error) {
+	query := `
+		SELECT id, file_id, name, type, signature, parent_id,
+			start_line, end_line, start_column, end_column,
+			visibility, is_exported, is_async, is_static, is_abstract,
+			documentation, metadata
+		FROM symbols
+		WHERE file_id = ? AND start_line <= ? AND end_line >= ?
+		ORDER BY start_line
+	`
+
+	rows, err := db.conn.Query(query, fileID, endLine, startLine)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var matches []*types.SymbolInRange
+	for rows.Next() {
+		symbol, err := scanSymbol(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		overlap := types.RangeOverlapOverlapping
+		if symbol.StartLine >= startLine && symbol.EndLine <= endLine {
+			overlap = types.RangeOverlapContained
+		}
+
+		matches = append(matches, &types.SymbolInRange{Symbol: symbol, Overlap: overlap})
+	}
+
+	return matches, rows.Err()
+}
+
+// nameTokensMatch builds an FTS5 query matching every word of the query
+// against the split name column, or "" if the query has no words
+func nameTokensMatch(query string) string {
+	var terms []string
+	for _, field := range strings.Fields(query) {
+		for _, word := range utils.SplitIdentifier(field) {
+			terms = append(terms, fmt.Sprintf("%q*", word))
+		}
+	}
+
+	if len(terms) == 0 {
+		return ""
+	}
+	return "name_tokens : (" + strings.Join(terms, " AND ") + ")"
+}
+
 // GetSymbolsByFile retrieves all symbols for a file
 func (db *DB) GetSymbolsByFile(fileID int64) ([]*types.Symbol, error) {
 	query := `
@@ -312,8 +800,8 @@ func (db *DB) SaveImport(imp *types.Import) error {
 	}
 
 	query := `
-		INSERT INTO imports (file_id, source, imported_names, import_type, line_number, imported_symbol)
-		VALUES (?, ?, ?, ?, ?, ?)
+		INSERT INTO imports (file_id, source, imported_names, import_type, line_number, imported_symbol, alias)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
 		RETURNING id
 	`
 
@@ -324,6 +812,7 @@ func (db *DB) SaveImport(imp *types.Import) error {
 		imp.ImportType,
 		imp.LineNumber,
 		nullString(imp.ImportedSymbol),
+		nullString(imp.Alias),
 	).Scan(&imp.ID)
 
 	return err
@@ -337,7 +826,7 @@ func (db *DB) DeleteImportsByFile(fileID int64) error {
 
 // GetImportsByFile retrieves all imports for a file
 func (db *DB) GetImportsByFile(fileID int64) ([]*types.Import, error) {
-	query := `SELECT id, file_id, source, imported_names, import_type, line_number, imported_symbol FROM imports WHERE file_id = ?`
+	query := `SELECT id, file_id, source, imported_names, import_type, line_number, imported_symbol, alias FROM imports WHERE file_id = ?`
 
 	rows, err := db.conn.Query(query, fileID)
 	if err != nil {
@@ -357,18 +846,55 @@ func (db *DB) GetImportsByFile(fileID int64) ([]*types.Import, error) {
 	return imports, rows.Err()
 }
 
+// GetImportsByProject retrieves all imports across a project's files
+func (db *DB) GetImportsByProject(projectID int64) ([]*types.Import, error) {
+	query := `
+		SELECT i.id, i.file_id, i.source, i.imported_names, i.import_type, i.line_number, i.imported_symbol, i.alias
+		FROM imports i
+		JOIN files f ON f.id = i.file_id
+		WHERE f.project_id = ?
+		ORDER BY i.source, f.relative_path
+	`
+
+	rows, err := db.conn.Query(query, projectID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var imports []*types.Import
+	for rows.Next() {
+		imp, err := scanImport(rows)
+		if err != nil {
+			return nil, err
+		}
+		imports = append(imports, imp)
+	}
+
+	return imports, rows.Err()
+}
+
 // Relationship operations
 
 // SaveRelationship creates a relationship
 func (db *DB) SaveRelationship(rel *types.Relationship) error {
+	var metadataJSON sql.NullString
+	if len(rel.Metadata) > 0 {
+		data, err := toJSON(rel.Metadata)
+		if err != nil {
+			return err
+		}
+		metadataJSON = sql.NullString{String: data, Valid: true}
+	}
+
 	query := `
-		INSERT INTO relationships (from_symbol_id, to_symbol_id, relationship_type)
-		VALUES (?, ?, ?)
+		INSERT INTO relationships (from_symbol_id, to_symbol_id, relationship_type, metadata)
+		VALUES (?, ?, ?, ?)
 		ON CONFLICT DO NOTHING
 		RETURNING id
 	`
 
-	err := db.conn.QueryRow(query, rel.FromSymbolID, rel.ToSymbolID, rel.Type).Scan(&rel.ID)
+	err := db.conn.QueryRow(query, rel.FromSymbolID, rel.ToSymbolID, rel.Type, metadataJSON).Scan(&rel.ID)
 	if err != nil && err != sql.ErrNoRows {
 		return err
 	}
@@ -376,14 +902,234 @@ func (db *DB) SaveRelationship(rel *types.Relationship) error {
 	return nil
 }
 
-// Helper scanning functions
+// UpsertRelationship creates a relationship, or replaces the metadata of the
+// existing one with the same ends and type
+func (db *DB) UpsertRelationship(rel *types.Relationship) error {
+	var metadataJSON sql.NullString
+	if len(rel.Metadata) > 0 {
+		data, err := toJSON(rel.Metadata)
+		if err != nil {
+			return err
+		}
+		metadataJSON = sql.NullString{String: data, Valid: true}
+	}
 
-func scanSymbol(scanner interface {
-	Scan(dest ...interface{}) error
-}) (*types.Symbol, error) {
-	var symbol types.Symbol
-	var signature, documentation, metadataJSON sql.NullString
-	var parentID sql.NullInt64
+	query := `
+		INSERT INTO relationships (from_symbol_id, to_symbol_id, relationship_type, metadata)
+		VALUES (?, ?, ?, ?)
+		ON CONFLICT (from_symbol_id, to_symbol_id, relationship_type) DO UPDATE SET metadata = excluded.metadata
+		RETURNING id
+	`
+
+	return db.conn.QueryRow(query, rel.FromSymbolID, rel.ToSymbolID, rel.Type, metadataJSON).Scan(&rel.ID)
+}
+
+// CountRelationships counts relationships originating from symbols in a project
+func (db *DB) CountRelationships(projectID int64) (int, error) {
+	query := `
+		SELECT COUNT(*)
+		FROM relationships r
+		JOIN symbols s ON s.id = r.from_symbol_id
+		JOIN files f ON f.id = s.file_id
+		WHERE f.project_id = ?
+	`
+
+	var count int
+	err := db.conn.QueryRow(query, projectID).Scan(&count)
+	return count, err
+}
+
+// DeduplicateRelationships removes duplicate relationship rows originating in a
+// project, keeping the oldest, and returns the number of rows removed. The
+// UNIQUE constraint on relationships already prevents duplicates, so this
+// only guards against rows that bypassed it and normally removes nothing.
+func (db *DB) DeduplicateRelationships(projectID int64) (int64, error) {
+	query := `
+		DELETE FROM relationships
+		WHERE from_symbol_id IN (
+			SELECT s.id FROM symbols s
+			JOIN files f ON f.id = s.file_id
+			WHERE f.project_id = ?
+		)
+		AND id NOT IN (
+			SELECT MIN(id)
+			FROM relationships
+			GROUP BY from_symbol_id, to_symbol_id, relationship_type
+		)
+	`
+
+	result, err := db.conn.Exec(query, projectID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// PruneOrphanReferences deletes references whose symbol no longer exists
+// and returns the number of rows removed
+func (db *DB) PruneOrphanReferences() (int, error) {
+	query := `
+		DELETE FROM "references"
+		WHERE symbol_id NOT IN (SELECT id FROM symbols)
+	`
+
+	result, err := db.conn.Exec(query)
+	if err != nil {
+		return 0, err
+	}
+
+	removed, err := result.RowsAffected()
+	return int(removed), err
+}
+
+// Vacuum rebuilds the database file, reclaiming space left by deleted rows
+func (db *DB) Vacuum() error {
+	_, err := db.conn.Exec("VACUUM")
+	return err
+}
+
+// SetSymbolStatus updates a symbol's development status
+func (db *DB) SetSymbolStatus(symbolID int64, status types.DevelopmentStatus) error {
+	_, err := db.conn.Exec("UPDATE symbols SET status = ? WHERE id = ?", status, symbolID)
+	return err
+}
+
+// SetSymbolAssignee records which agent is working on a symbol; an empty
+// agent clears the assignment
+func (db *DB) SetSymbolAssignee(symbolID int64, agent string) error {
+	_, err := db.conn.Exec("UPDATE symbols SET assigned_agent = ? WHERE id = ?", nullString(agent), symbolID)
+	return err
+}
+
+// SetSymbolPriority updates a symbol's priority
+func (db *DB) SetSymbolPriority(symbolID int64, priority int) error {
+	_, err := db.conn.Exec("UPDATE symbols SET priority = ? WHERE id = ?", priority, symbolID)
+	return err
+}
+
+// SetSymbolDependencies replaces the names of the symbols each symbol depends
+// on, keyed by symbol ID, in one transaction
+func (db *DB) SetSymbolDependencies(dependencies map[int64][]string) error {
+	return db.Transaction(func(tx *sql.Tx) error {
+		for symbolID, deps := range dependencies {
+			var metadataJSON sql.NullString
+			if err := tx.QueryRow("SELECT metadata FROM symbols WHERE id = ?", symbolID).Scan(&metadataJSON); err != nil {
+				return err
+			}
+
+			var metadata map[string]interface{}
+			if metadataJSON.Valid {
+				fromJSON(metadataJSON.String, &metadata)
+			}
+			if metadata == nil {
+				metadata = make(map[string]interface{})
+			}
+			if len(deps) > 0 {
+				metadata[dependenciesKey] = deps
+			} else {
+				delete(metadata, dependenciesKey)
+			}
+
+			data, err := toJSON(metadata)
+			if err != nil {
+				return err
+			}
+			if _, err := tx.Exec("UPDATE symbols SET metadata = ? WHERE id = ?", data, symbolID); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// SaveSymbolTracking overwrites all of a symbol's workflow state
+func (db *DB) SaveSymbolTracking(symbolID int64, tracking types.SymbolTracking) error {
+	_, err := db.conn.Exec(
+		"UPDATE symbols SET status = ?, priority = ?, assigned_agent = ? WHERE id = ?",
+		tracking.Status, tracking.Priority, nullString(tracking.AssignedAgent), symbolID,
+	)
+	return err
+}
+
+// trackedSymbolColumns is the projection scanned by scanTrackedSymbol
+const trackedSymbolColumns = `
+	s.id, s.file_id, s.name, s.type, s.signature, s.start_line, s.end_line, s.visibility,
+	s.status, s.priority, s.assigned_agent, f.relative_path`
+
+// GetSymbolsByStatus retrieves the symbols of a project with a given status,
+// highest priority first
+func (db *DB) GetSymbolsByStatus(projectID int64, status types.DevelopmentStatus) ([]*types.TrackedSymbol, error) {
+	query := `SELECT ` + trackedSymbolColumns + `
+		FROM symbols s
+		JOIN files f ON s.file_id = f.id
+		WHERE f.project_id = ? AND COALESCE(s.status, 'not_started') = ?
+		ORDER BY s.priority DESC, f.relative_path, s.start_line
+	`
+
+	return db.queryTrackedSymbols(query, projectID, status)
+}
+
+// GetTrackedSymbolsByFile retrieves the symbols of a file that carry any
+// workflow state, so it can be restored after the file is re-indexed
+func (db *DB) GetTrackedSymbolsByFile(fileID int64) ([]*types.TrackedSymbol, error) {
+	query := `SELECT ` + trackedSymbolColumns + `
+		FROM symbols s
+		JOIN files f ON s.file_id = f.id
+		WHERE s.file_id = ?
+			AND (COALESCE(s.status, 'not_started') != 'not_started'
+				OR COALESCE(s.priority, 0) != 0
+				OR s.assigned_agent IS NOT NULL)
+	`
+
+	return db.queryTrackedSymbols(query, fileID)
+}
+
+// queryTrackedSymbols runs a query selecting trackedSymbolColumns
+func (db *DB) queryTrackedSymbols(query string, args ...interface{}) ([]*types.TrackedSymbol, error) {
+	rows, err := db.conn.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tracked []*types.TrackedSymbol
+	for rows.Next() {
+		var symbol types.Symbol
+		var signature, visibility, status, agent sql.NullString
+		var priority sql.NullInt64
+		ts := &types.TrackedSymbol{Symbol: &symbol}
+
+		if err := rows.Scan(
+			&symbol.ID, &symbol.FileID, &symbol.Name, &symbol.Type, &signature,
+			&symbol.StartLine, &symbol.EndLine, &visibility,
+			&status, &priority, &agent, &ts.FilePath,
+		); err != nil {
+			return nil, err
+		}
+
+		symbol.Signature = signature.String
+		symbol.Visibility = types.Visibility(visibility.String)
+		ts.Status = types.StatusNotStarted
+		if status.Valid {
+			ts.Status = types.DevelopmentStatus(status.String)
+		}
+		ts.Priority = int(priority.Int64)
+		ts.AssignedAgent = agent.String
+
+		tracked = append(tracked, ts)
+	}
+
+	return tracked, rows.Err()
+}
+
+// Helper scanning functions
+
+func scanSymbol(scanner interface {
+	Scan(dest ...interface{}) error
+}) (*types.Symbol, error) {
+	var symbol types.Symbol
+	var signature, documentation, metadataJSON sql.NullString
+	var parentID sql.NullInt64
 
 	err := scanner.Scan(
 		&symbol.ID,
@@ -422,6 +1168,22 @@ func scanSymbol(scanner interface {
 	if metadataJSON.Valid {
 		fromJSON(metadataJSON.String, &symbol.Metadata)
 	}
+	if params, ok := symbol.Metadata[typeParamsKey].([]interface{}); ok {
+		for _, param := range params {
+			if s, ok := param.(string); ok {
+				symbol.TypeParams = append(symbol.TypeParams, s)
+			}
+		}
+		delete(symbol.Metadata, typeParamsKey)
+	}
+	if deps, ok := symbol.Metadata[dependenciesKey].([]interface{}); ok {
+		for _, dep := range deps {
+			if s, ok := dep.(string); ok {
+				symbol.Dependencies = append(symbol.Dependencies, s)
+			}
+		}
+		delete(symbol.Metadata, dependenciesKey)
+	}
 
 	return &symbol, nil
 }
@@ -431,7 +1193,7 @@ func scanImport(scanner interface {
 }) (*types.Import, error) {
 	var imp types.Import
 	var namesJSON string
-	var importedSymbol sql.NullString
+	var importedSymbol, alias sql.NullString
 
 	err := scanner.Scan(
 		&imp.ID,
@@ -441,6 +1203,7 @@ func scanImport(scanner interface {
 		&imp.ImportType,
 		&imp.LineNumber,
 		&importedSymbol,
+		&alias,
 	)
 
 	if err != nil {
@@ -454,6 +1217,7 @@ func scanImport(scanner interface {
 	if importedSymbol.Valid {
 		imp.ImportedSymbol = importedSymbol.String
 	}
+	imp.Alias = alias.String
 
 	return &imp, nil
 }
@@ -477,6 +1241,204 @@ func (db *DB) GetSymbolByName(name string) (*types.Symbol, error) {
 	return symbol, err
 }
 
+// GetSymbolByID retrieves a symbol by its ID
+func (db *DB) GetSymbolByID(id int64) (*types.Symbol, error) {
+	query := `
+		SELECT id, file_id, name, type, signature, parent_id,
+			start_line, end_line, start_column, end_column,
+			visibility, is_exported, is_async, is_static, is_abstract,
+			documentation, metadata
+		FROM symbols
+		WHERE id = ?
+	`
+
+	symbol, err := scanSymbol(db.conn.QueryRow(query, id))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return symbol, err
+}
+
+// GetSymbolsByName retrieves all symbols with an exact name in a project
+func (db *DB) GetSymbolsByName(projectID int64, name string) ([]*types.Symbol, error) {
+	query := `
+		SELECT s.id, s.file_id, s.name, s.type, s.signature, s.parent_id,
+			s.start_line, s.end_line, s.start_column, s.end_column,
+			s.visibility, s.is_exported, s.is_async, s.is_static, s.is_abstract,
+			s.documentation, s.metadata
+		FROM symbols s
+		JOIN files f ON s.file_id = f.id
+		WHERE f.project_id = ? AND s.name = ?
+		ORDER BY f.relative_path, s.start_line
+	`
+
+	rows, err := db.conn.Query(query, projectID, name)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var symbols []*types.Symbol
+	for rows.Next() {
+		symbol, err := scanSymbol(rows)
+		if err != nil {
+			return nil, err
+		}
+		symbols = append(symbols, symbol)
+	}
+
+	return symbols, rows.Err()
+}
+
+// GetSymbolsByNames retrieves the project's symbols with any of the given
+// names in one query, ordered by file path and line
+func (db *DB) GetSymbolsByNames(projectID int64, names []string) ([]*types.Symbol, error) {
+	if len(names) == 0 {
+		return nil, nil
+	}
+
+	query := `
+		SELECT s.id, s.file_id, s.name, s.type, s.signature, s.parent_id,
+			s.start_line, s.end_line, s.start_column, s.end_column,
+			s.visibility, s.is_exported, s.is_async, s.is_static, s.is_abstract,
+			s.documentation, s.metadata
+		FROM symbols s
+		JOIN files f ON s.file_id = f.id
+		WHERE f.project_id = ? AND s.name IN (` + placeholders(len(names)) + `)
+		ORDER BY f.relative_path, s.start_line
+	`
+
+	args := make([]interface{}, 0, len(names)+1)
+	args = append(args, projectID)
+	for _, name := range names {
+		args = append(args, name)
+	}
+
+	rows, err := db.conn.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var symbols []*types.Symbol
+	for rows.Next() {
+		symbol, err := scanSymbol(rows)
+		if err != nil {
+			return nil, err
+		}
+		symbols = append(symbols, symbol)
+	}
+
+	return symbols, rows.Err()
+}
+
+// GetDecoratedSymbols retrieves the project's symbols that have decorators,
+// annotations or attributes recorded in their metadata and whose metadata
+// mentions fragment
+func (db *DB) GetDecoratedSymbols(projectID int64, fragment string) ([]*types.Symbol, error) {
+	query := `
+		SELECT s.id, s.file_id, s.name, s.type, s.signature, s.parent_id,
+			s.start_line, s.end_line, s.start_column, s.end_column,
+			s.visibility, s.is_exported, s.is_async, s.is_static, s.is_abstract,
+			s.documentation, s.metadata
+		FROM symbols s
+		JOIN files f ON s.file_id = f.id
+		WHERE f.project_id = ? AND s.metadata LIKE '%"decorators"%' AND s.metadata LIKE ?
+		ORDER BY f.relative_path, s.start_line
+	`
+
+	rows, err := db.conn.Query(query, projectID, "%"+fragment+"%")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var symbols []*types.Symbol
+	for rows.Next() {
+		symbol, err := scanSymbol(rows)
+		if err != nil {
+			return nil, err
+		}
+		symbols = append(symbols, symbol)
+	}
+
+	return symbols, rows.Err()
+}
+
+// GetDeprecatedSymbols retrieves the symbols of a project flagged deprecated
+// in their metadata, ordered by file and line
+func (db *DB) GetDeprecatedSymbols(projectID int64) ([]*types.Symbol, error) {
+	query := `
+		SELECT s.id, s.file_id, s.name, s.type, s.signature, s.parent_id,
+			s.start_line, s.end_line, s.start_column, s.end_column,
+			s.visibility, s.is_exported, s.is_async, s.is_static, s.is_abstract,
+			s.documentation, s.metadata
+		FROM symbols s
+		JOIN files f ON s.file_id = f.id
+		WHERE f.project_id = ? AND s.metadata LIKE '%"deprecated":true%'
+		ORDER BY f.relative_path, s.start_line
+	`
+
+	rows, err := db.conn.Query(query, projectID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var symbols []*types.Symbol
+	for rows.Next() {
+		symbol, err := scanSymbol(rows)
+		if err != nil {
+			return nil, err
+		}
+		symbols = append(symbols, symbol)
+	}
+
+	return symbols, rows.Err()
+}
+
+// GetSignaturesByName retrieves just the signature, kind and doc summary of
+// every symbol with an exact name in a project
+func (db *DB) GetSignaturesByName(projectID int64, name string) ([]*types.SymbolSignature, error) {
+	query := `
+		SELECT s.name, s.type, s.signature, s.documentation, f.relative_path, s.start_line
+		FROM symbols s
+		JOIN files f ON s.file_id = f.id
+		WHERE f.project_id = ? AND s.name = ?
+		ORDER BY f.relative_path, s.start_line
+	`
+
+	rows, err := db.conn.Query(query, projectID, name)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var signatures []*types.SymbolSignature
+	for rows.Next() {
+		var sig types.SymbolSignature
+		var signature, documentation sql.NullString
+		if err := rows.Scan(&sig.Name, &sig.Type, &signature, &documentation, &sig.FilePath, &sig.StartLine); err != nil {
+			return nil, err
+		}
+		sig.Signature = signature.String
+		sig.Summary = docSummary(documentation.String)
+		signatures = append(signatures, &sig)
+	}
+
+	return signatures, rows.Err()
+}
+
+// docSummary returns the first non-empty line of a doc comment
+func docSummary(doc string) string {
+	for _, line := range strings.Split(doc, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			return line
+		}
+	}
+	return ""
+}
+
 // GetSymbolWithFile retrieves a symbol with its file information
 func (db *DB) GetSymbolWithFile(symbolID int64) (*types.Symbol, *types.File, error) {
 	query := `
@@ -485,7 +1447,7 @@ func (db *DB) GetSymbolWithFile(symbolID int64) (*types.Symbol, *types.File, err
 			s.visibility, s.is_exported, s.is_async, s.is_static, s.is_abstract,
 			s.documentation, s.metadata,
 			f.id, f.project_id, f.path, f.relative_path, f.language, f.size,
-			f.lines_of_code, f.hash, f.last_modified, f.last_indexed
+			f.lines_of_code, f.hash, f.last_modified, f.last_indexed, COALESCE(f.encoding, '')
 		FROM symbols s
 		JOIN files f ON s.file_id = f.id
 		WHERE s.id = ?
@@ -504,7 +1466,7 @@ func (db *DB) GetSymbolWithFile(symbolID int64) (*types.Symbol, *types.File, err
 		&symbol.Visibility, &symbol.IsExported, &symbol.IsAsync, &symbol.IsStatic, &symbol.IsAbstract,
 		&documentation, &metadataJSON,
 		&file.ID, &file.ProjectID, &file.Path, &file.RelativePath, &file.Language, &file.Size,
-		&file.LinesOfCode, &file.Hash, &file.LastModified, &file.LastIndexed,
+		&file.LinesOfCode, &file.Hash, &file.LastModified, &file.LastIndexed, &file.Encoding,
 	)
 
 	if err == sql.ErrNoRows {
@@ -534,12 +1496,31 @@ func (db *DB) GetSymbolWithFile(symbolID int64) (*types.Symbol, *types.File, err
 // GetRelationshipsForSymbol retrieves relationships for a symbol
 func (db *DB) GetRelationshipsForSymbol(symbolID int64) ([]*types.Relationship, error) {
 	query := `
-		SELECT id, from_symbol_id, to_symbol_id, relationship_type
+		SELECT id, from_symbol_id, to_symbol_id, relationship_type, metadata
 		FROM relationships
 		WHERE from_symbol_id = ? OR to_symbol_id = ?
 	`
 
-	rows, err := db.conn.Query(query, symbolID, symbolID)
+	return db.queryRelationships(query, symbolID, symbolID)
+}
+
+// GetRelationshipsByProject retrieves all relationships between a project's symbols
+func (db *DB) GetRelationshipsByProject(projectID int64) ([]*types.Relationship, error) {
+	query := `
+		SELECT r.id, r.from_symbol_id, r.to_symbol_id, r.relationship_type, r.metadata
+		FROM relationships r
+		JOIN symbols s ON s.id = r.from_symbol_id
+		JOIN files f ON f.id = s.file_id
+		WHERE f.project_id = ?
+	`
+
+	return db.queryRelationships(query, projectID)
+}
+
+// queryRelationships runs a query selecting a relationship's id, endpoints,
+// type and metadata
+func (db *DB) queryRelationships(query string, args ...interface{}) ([]*types.Relationship, error) {
+	rows, err := db.conn.Query(query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -548,19 +1529,54 @@ func (db *DB) GetRelationshipsForSymbol(symbolID int64) ([]*types.Relationship,
 	var relationships []*types.Relationship
 	for rows.Next() {
 		var rel types.Relationship
-		if err := rows.Scan(&rel.ID, &rel.FromSymbolID, &rel.ToSymbolID, &rel.Type); err != nil {
+		var metadataJSON sql.NullString
+		if err := rows.Scan(&rel.ID, &rel.FromSymbolID, &rel.ToSymbolID, &rel.Type, &metadataJSON); err != nil {
 			return nil, err
 		}
+		if metadataJSON.Valid {
+			fromJSON(metadataJSON.String, &rel.Metadata)
+		}
 		relationships = append(relationships, &rel)
 	}
 
 	return relationships, rows.Err()
 }
 
+// GetRelationshipSources retrieves the symbols that have a relationship of the given type to a symbol
+func (db *DB) GetRelationshipSources(symbolID int64, relType types.RelationshipType) ([]*types.Symbol, error) {
+	query := `
+		SELECT s.id, s.file_id, s.name, s.type, s.signature, s.parent_id,
+			s.start_line, s.end_line, s.start_column, s.end_column,
+			s.visibility, s.is_exported, s.is_async, s.is_static, s.is_abstract,
+			s.documentation, s.metadata
+		FROM relationships r
+		JOIN symbols s ON s.id = r.from_symbol_id
+		WHERE r.to_symbol_id = ? AND r.relationship_type = ?
+		ORDER BY s.file_id, s.start_line
+	`
+
+	rows, err := db.conn.Query(query, symbolID, relType)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var symbols []*types.Symbol
+	for rows.Next() {
+		symbol, err := scanSymbol(rows)
+		if err != nil {
+			return nil, err
+		}
+		symbols = append(symbols, symbol)
+	}
+
+	return symbols, rows.Err()
+}
+
 // SaveReference creates a reference
 func (db *DB) SaveReference(ref *types.Reference) error {
 	query := `
-		INSERT INTO references (symbol_id, file_id, line_number, column_number, reference_type)
+		INSERT INTO "references" (symbol_id, file_id, line_number, column_number, reference_type)
 		VALUES (?, ?, ?, ?, ?)
 		RETURNING id
 	`
@@ -580,7 +1596,7 @@ func (db *DB) SaveReference(ref *types.Reference) error {
 func (db *DB) GetReferencesBySymbol(symbolID int64) ([]*types.Reference, error) {
 	query := `
 		SELECT id, symbol_id, file_id, line_number, column_number, reference_type
-		FROM references
+		FROM "references"
 		WHERE symbol_id = ?
 	`
 
@@ -605,7 +1621,7 @@ func (db *DB) GetReferencesBySymbol(symbolID int64) ([]*types.Reference, error)
 // GetAllFilesForProject retrieves all files in a project
 func (db *DB) GetAllFilesForProject(projectID int64) ([]*types.File, error) {
 	query := `
-		SELECT id, project_id, path, relative_path, language, size, lines_of_code, hash, last_modified, last_indexed
+		SELECT id, project_id, path, relative_path, language, size, lines_of_code, hash, last_modified, last_indexed, COALESCE(encoding, '')
 		FROM files
 		WHERE project_id = ?
 		ORDER BY relative_path
@@ -623,7 +1639,81 @@ func (db *DB) GetAllFilesForProject(projectID int64) ([]*types.File, error) {
 		if err := rows.Scan(
 			&file.ID, &file.ProjectID, &file.Path, &file.RelativePath,
 			&file.Language, &file.Size, &file.LinesOfCode, &file.Hash,
-			&file.LastModified, &file.LastIndexed,
+			&file.LastModified, &file.LastIndexed, &file.Encoding,
+		); err != nil {
+			return nil, err
+		}
+		files = append(files, &file)
+	}
+
+	return files, rows.Err()
+}
+
+// CountProjectTotals returns the number of files and symbols in a project
+func (db *DB) CountProjectTotals(projectID int64) (files int, symbols int, err error) {
+	query := `
+		SELECT
+			(SELECT COUNT(*) FROM files WHERE project_id = ?),
+			(SELECT COUNT(*) FROM symbols s JOIN files f ON f.id = s.file_id WHERE f.project_id = ?)
+	`
+
+	err = db.conn.QueryRow(query, projectID, projectID).Scan(&files, &symbols)
+	return files, symbols, err
+}
+
+// CountSymbolsByFile returns the number of symbols in each file of a project, keyed by file ID.
+// Files without symbols are absent from the map.
+func (db *DB) CountSymbolsByFile(projectID int64) (map[int64]int, error) {
+	query := `
+		SELECT s.file_id, COUNT(*)
+		FROM symbols s
+		JOIN files f ON f.id = s.file_id
+		WHERE f.project_id = ?
+		GROUP BY s.file_id
+	`
+
+	rows, err := db.conn.Query(query, projectID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	counts := make(map[int64]int)
+	for rows.Next() {
+		var fileID int64
+		var count int
+		if err := rows.Scan(&fileID, &count); err != nil {
+			return nil, err
+		}
+		counts[fileID] = count
+	}
+
+	return counts, rows.Err()
+}
+
+// GetOrphanFiles retrieves files in a project that have no symbols
+func (db *DB) GetOrphanFiles(projectID int64) ([]*types.File, error) {
+	query := `
+		SELECT f.id, f.project_id, f.path, f.relative_path, f.language, f.size, f.lines_of_code, f.hash, f.last_modified, f.last_indexed, COALESCE(f.encoding, '')
+		FROM files f
+		LEFT JOIN symbols s ON s.file_id = f.id
+		WHERE f.project_id = ? AND s.id IS NULL
+		ORDER BY f.relative_path
+	`
+
+	rows, err := db.conn.Query(query, projectID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var files []*types.File
+	for rows.Next() {
+		var file types.File
+		if err := rows.Scan(
+			&file.ID, &file.ProjectID, &file.Path, &file.RelativePath,
+			&file.Language, &file.Size, &file.LinesOfCode, &file.Hash,
+			&file.LastModified, &file.LastIndexed, &file.Encoding,
 		); err != nil {
 			return nil, err
 		}
@@ -637,7 +1727,7 @@ func (db *DB) GetAllFilesForProject(projectID int64) ([]*types.File, error) {
 func (db *DB) GetReferencesByFile(fileID int64) ([]*types.Reference, error) {
 	query := `
 		SELECT id, symbol_id, file_id, line_number, column_number, reference_type
-		FROM references
+		FROM "references"
 		WHERE file_id = ?
 		ORDER BY line_number
 	`
@@ -660,6 +1750,34 @@ func (db *DB) GetReferencesByFile(fileID int64) ([]*types.Reference, error) {
 	return references, rows.Err()
 }
 
+// GetReferencesByFilePath retrieves all references originating in a file, looked up by relative path
+func (db *DB) GetReferencesByFilePath(projectID int64, relativePath string) ([]*types.Reference, error) {
+	query := `
+		SELECT r.id, r.symbol_id, r.file_id, r.line_number, r.column_number, r.reference_type
+		FROM "references" r
+		JOIN files f ON f.id = r.file_id
+		WHERE f.project_id = ? AND f.relative_path = ?
+		ORDER BY r.line_number, r.column_number
+	`
+
+	rows, err := db.conn.Query(query, projectID, relativePath)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var references []*types.Reference
+	for rows.Next() {
+		var ref types.Reference
+		if err := rows.Scan(&ref.ID, &ref.SymbolID, &ref.FileID, &ref.LineNumber, &ref.ColumnNumber, &ref.ReferenceType); err != nil {
+			return nil, err
+		}
+		references = append(references, &ref)
+	}
+
+	return references, rows.Err()
+}
+
 // GetMethodsForType retrieves all methods for a given type (struct/class)
 func (db *DB) GetMethodsForType(typeSymbolID int64) ([]*types.Symbol, error) {
 	query := `
@@ -689,3 +1807,142 @@ func (db *DB) GetMethodsForType(typeSymbolID int64) ([]*types.Symbol, error) {
 
 	return methods, rows.Err()
 }
+
+// Parse error operations
+
+// ReplaceParseErrors replaces the recorded parse errors for a file; an empty
+// list clears them after a successful parse
+func (db *DB) ReplaceParseErrors(projectID int64, relativePath string, errs []*types.FileParseError) error {
+	return db.Transaction(func(tx *sql.Tx) error {
+		if _, err := tx.Exec(`DELETE FROM parse_errors WHERE project_id = ? AND relative_path = ?`, projectID, relativePath); err != nil {
+			return err
+		}
+
+		query := `
+			INSERT INTO parse_errors (project_id, relative_path, line_number, column_number, message, symbols_recovered, recovered)
+			VALUES (?, ?, ?, ?, ?, ?, ?)
+			RETURNING id
+		`
+		for _, pe := range errs {
+			pe.FilePath = relativePath
+			err := tx.QueryRow(query, projectID, relativePath, pe.Line, pe.Column, pe.Message, pe.SymbolsRecovered, pe.Recovered).Scan(&pe.ID)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// GetParseErrors retrieves all recorded parse errors for a project
+func (db *DB) GetParseErrors(projectID int64) ([]*types.FileParseError, error) {
+	query := `
+		SELECT id, relative_path, line_number, column_number, message, symbols_recovered, recovered
+		FROM parse_errors
+		WHERE project_id = ?
+		ORDER BY relative_path, line_number
+	`
+
+	rows, err := db.conn.Query(query, projectID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var errs []*types.FileParseError
+	for rows.Next() {
+		var pe types.FileParseError
+		if err := rows.Scan(&pe.ID, &pe.FilePath, &pe.Line, &pe.Column, &pe.Message, &pe.SymbolsRecovered, &pe.Recovered); err != nil {
+			return nil, err
+		}
+		errs = append(errs, &pe)
+	}
+
+	return errs, rows.Err()
+}
+
+// LatestSymbolVersionHash returns the source hash of the most recently
+// recorded version of a symbol, or "" if none has been recorded
+func (db *DB) LatestSymbolVersionHash(projectID int64, relativePath, name string, symbolType types.SymbolType) (string, error) {
+	query := `
+		SELECT hash FROM symbol_versions
+		WHERE project_id = ? AND relative_path = ? AND name = ? AND type = ?
+		ORDER BY id DESC
+		LIMIT 1
+	`
+
+	var hash string
+	err := db.conn.QueryRow(query, projectID, relativePath, name, symbolType).Scan(&hash)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	return hash, err
+}
+
+// SaveSymbolVersion records a new version of a symbol
+func (db *DB) SaveSymbolVersion(projectID int64, name string, symbolType types.SymbolType, point *types.ComplexityPoint) error {
+	query := `
+		INSERT INTO symbol_versions (project_id, relative_path, name, type, hash, lines_of_code, cyclomatic_complexity, recorded_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+	`
+
+	_, err := db.conn.Exec(query, projectID, point.FilePath, name, symbolType, point.Hash,
+		point.LinesOfCode, point.CyclomaticComplexity, point.RecordedAt)
+	return err
+}
+
+// GetSymbolVersionKeys lists the files and symbol types that have recorded
+// versions of a symbol with the given name
+func (db *DB) GetSymbolVersionKeys(projectID int64, name string) ([]*types.SymbolVersionKey, error) {
+	query := `
+		SELECT DISTINCT relative_path, type
+		FROM symbol_versions
+		WHERE project_id = ? AND name = ?
+		ORDER BY relative_path, type
+	`
+
+	rows, err := db.conn.Query(query, projectID, name)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var keys []*types.SymbolVersionKey
+	for rows.Next() {
+		var key types.SymbolVersionKey
+		if err := rows.Scan(&key.FilePath, &key.Type); err != nil {
+			return nil, err
+		}
+		keys = append(keys, &key)
+	}
+
+	return keys, rows.Err()
+}
+
+// GetSymbolVersions retrieves every recorded version of a symbol, oldest first
+func (db *DB) GetSymbolVersions(projectID int64, relativePath, name string, symbolType types.SymbolType) ([]*types.ComplexityPoint, error) {
+	query := `
+		SELECT relative_path, hash, lines_of_code, cyclomatic_complexity, recorded_at
+		FROM symbol_versions
+		WHERE project_id = ? AND relative_path = ? AND name = ? AND type = ?
+		ORDER BY id
+	`
+
+	rows, err := db.conn.Query(query, projectID, relativePath, name, symbolType)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var points []*types.ComplexityPoint
+	for rows.Next() {
+		var point types.ComplexityPoint
+		if err := rows.Scan(&point.FilePath, &point.Hash, &point.LinesOfCode, &point.CyclomaticComplexity, &point.RecordedAt); err != nil {
+			return nil, err
+		}
+		points = append(points, &point)
+	}
+
+	return points, rows.Err()
+}
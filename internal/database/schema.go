@@ -24,6 +24,7 @@ CREATE TABLE IF NOT EXISTS files (
     hash TEXT,
     last_modified DATETIME,
     last_indexed DATETIME,
+    encoding TEXT, -- Source encoding the file was transcoded from, e.g. utf-16le
     FOREIGN KEY (project_id) REFERENCES projects(id) ON DELETE CASCADE,
     UNIQUE(project_id, relative_path)
 );
@@ -47,6 +48,10 @@ CREATE TABLE IF NOT EXISTS symbols (
     is_abstract BOOLEAN DEFAULT FALSE,
     documentation TEXT,
     metadata TEXT, -- JSON for additional information
+    name_tokens TEXT, -- Name split on camelCase/snake_case boundaries, for FTS
+    status TEXT DEFAULT 'not_started', -- Development status for AI-driven workflows
+    priority INTEGER DEFAULT 0,
+    assigned_agent TEXT, -- Agent currently working on the symbol
     FOREIGN KEY (file_id) REFERENCES files(id) ON DELETE CASCADE,
     FOREIGN KEY (parent_id) REFERENCES symbols(id) ON DELETE CASCADE
 );
@@ -60,6 +65,7 @@ CREATE TABLE IF NOT EXISTS imports (
     import_type TEXT, -- local, external, stdlib
     line_number INTEGER,
     imported_symbol TEXT, -- For specific symbol imports
+    alias TEXT, -- Local name of a renamed Go import
     FOREIGN KEY (file_id) REFERENCES files(id) ON DELETE CASCADE
 );
 
@@ -69,13 +75,14 @@ CREATE TABLE IF NOT EXISTS relationships (
     from_symbol_id INTEGER NOT NULL,
     to_symbol_id INTEGER NOT NULL,
     relationship_type TEXT NOT NULL, -- extends, implements, calls, uses
+    metadata TEXT, -- JSON, e.g. language_boundary on cross-language bindings
     FOREIGN KEY (from_symbol_id) REFERENCES symbols(id) ON DELETE CASCADE,
     FOREIGN KEY (to_symbol_id) REFERENCES symbols(id) ON DELETE CASCADE,
     UNIQUE(from_symbol_id, to_symbol_id, relationship_type)
 );
 
 -- References table (where symbols are used)
-CREATE TABLE IF NOT EXISTS references (
+CREATE TABLE IF NOT EXISTS "references" (
     id INTEGER PRIMARY KEY AUTOINCREMENT,
     symbol_id INTEGER NOT NULL,
     file_id INTEGER NOT NULL,
@@ -86,6 +93,41 @@ CREATE TABLE IF NOT EXISTS references (
     FOREIGN KEY (file_id) REFERENCES files(id) ON DELETE CASCADE
 );
 
+-- Parse errors table (files the parser failed on or only partially parsed)
+CREATE TABLE IF NOT EXISTS parse_errors (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    project_id INTEGER NOT NULL,
+    relative_path TEXT NOT NULL,
+    line_number INTEGER,
+    column_number INTEGER,
+    message TEXT NOT NULL,
+    symbols_recovered INTEGER NOT NULL DEFAULT 0,
+    recovered BOOLEAN NOT NULL DEFAULT 0,
+    FOREIGN KEY (project_id) REFERENCES projects(id) ON DELETE CASCADE
+);
+
+-- Symbol versions table (complexity of each distinct body a function has had)
+CREATE TABLE IF NOT EXISTS symbol_versions (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    project_id INTEGER NOT NULL,
+    relative_path TEXT NOT NULL,
+    name TEXT NOT NULL,
+    type TEXT NOT NULL,
+    hash TEXT NOT NULL,
+    lines_of_code INTEGER,
+    cyclomatic_complexity INTEGER,
+    recorded_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (project_id) REFERENCES projects(id) ON DELETE CASCADE
+);
+
+-- Function details table (parameters and return types parsed from declarations)
+CREATE TABLE IF NOT EXISTS function_details (
+    symbol_id INTEGER PRIMARY KEY,
+    parameters TEXT, -- JSON
+    return_type TEXT,
+    FOREIGN KEY (symbol_id) REFERENCES symbols(id) ON DELETE CASCADE
+);
+
 -- Indexes for fast queries
 CREATE INDEX IF NOT EXISTS idx_files_project ON files(project_id);
 CREATE INDEX IF NOT EXISTS idx_files_path ON files(relative_path);
@@ -96,41 +138,60 @@ CREATE INDEX IF NOT EXISTS idx_symbols_file ON symbols(file_id);
 CREATE INDEX IF NOT EXISTS idx_symbols_name ON symbols(name);
 CREATE INDEX IF NOT EXISTS idx_symbols_type ON symbols(type);
 CREATE INDEX IF NOT EXISTS idx_symbols_parent ON symbols(parent_id);
+CREATE INDEX IF NOT EXISTS idx_symbols_status ON symbols(status);
 
 CREATE INDEX IF NOT EXISTS idx_imports_file ON imports(file_id);
 CREATE INDEX IF NOT EXISTS idx_imports_source ON imports(source);
 
+CREATE INDEX IF NOT EXISTS idx_parse_errors_file ON parse_errors(project_id, relative_path);
+
+CREATE INDEX IF NOT EXISTS idx_symbol_versions_name ON symbol_versions(project_id, name);
+
 CREATE INDEX IF NOT EXISTS idx_relationships_from ON relationships(from_symbol_id);
 CREATE INDEX IF NOT EXISTS idx_relationships_to ON relationships(to_symbol_id);
 CREATE INDEX IF NOT EXISTS idx_relationships_type ON relationships(relationship_type);
 
-CREATE INDEX IF NOT EXISTS idx_references_symbol ON references(symbol_id);
-CREATE INDEX IF NOT EXISTS idx_references_file ON references(file_id);
+CREATE INDEX IF NOT EXISTS idx_references_symbol ON "references"(symbol_id);
+CREATE INDEX IF NOT EXISTS idx_references_file ON "references"(file_id);
 
 -- Full-text search for symbols (for advanced queries)
 CREATE VIRTUAL TABLE IF NOT EXISTS symbols_fts USING fts5(
     name,
     signature,
     documentation,
+    name_tokens,
     content='symbols',
     content_rowid='id'
 );
 
--- Triggers to keep FTS in sync
+` + ftsTriggers
+
+// ftsTriggers keep symbols_fts in sync with the symbols table. They are kept
+// separate from Schema so bulk indexing can drop and recreate them. An
+// external content table can't look up a row's old values once it's gone
+// from symbols, so removals use the FTS 'delete' command with the old values.
+const ftsTriggers = `
 CREATE TRIGGER IF NOT EXISTS symbols_ai AFTER INSERT ON symbols BEGIN
-    INSERT INTO symbols_fts(rowid, name, signature, documentation)
-    VALUES (new.id, new.name, new.signature, new.documentation);
+    INSERT INTO symbols_fts(rowid, name, signature, documentation, name_tokens)
+    VALUES (new.id, new.name, new.signature, new.documentation, new.name_tokens);
 END;
 
 CREATE TRIGGER IF NOT EXISTS symbols_ad AFTER DELETE ON symbols BEGIN
-    DELETE FROM symbols_fts WHERE rowid = old.id;
+    INSERT INTO symbols_fts(symbols_fts, rowid, name, signature, documentation, name_tokens)
+    VALUES ('delete', old.id, old.name, old.signature, old.documentation, old.name_tokens);
 END;
 
 CREATE TRIGGER IF NOT EXISTS symbols_au AFTER UPDATE ON symbols BEGIN
-    UPDATE symbols_fts SET
-        name = new.name,
-        signature = new.signature,
-        documentation = new.documentation
-    WHERE rowid = new.id;
+    INSERT INTO symbols_fts(symbols_fts, rowid, name, signature, documentation, name_tokens)
+    VALUES ('delete', old.id, old.name, old.signature, old.documentation, old.name_tokens);
+    INSERT INTO symbols_fts(rowid, name, signature, documentation, name_tokens)
+    VALUES (new.id, new.name, new.signature, new.documentation, new.name_tokens);
 END;
 `
+
+// dropFTSTriggers removes the FTS sync triggers
+const dropFTSTriggers = `
+DROP TRIGGER IF EXISTS symbols_ai;
+DROP TRIGGER IF EXISTS symbols_ad;
+DROP TRIGGER IF EXISTS symbols_au;
+`
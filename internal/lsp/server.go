@@ -5,11 +5,13 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"log"
 	"sync"
 
 	"github.com/aaamil13/CodeIndexerMCP/internal/ai"
 	"github.com/aaamil13/CodeIndexerMCP/internal/core"
 	"github.com/aaamil13/CodeIndexerMCP/internal/database"
+	"github.com/aaamil13/CodeIndexerMCP/pkg/types"
 )
 
 // Server implements a Language Server Protocol server
@@ -22,8 +24,9 @@ type Server struct {
 	mu           sync.RWMutex
 
 	// Communication channels
-	reader io.Reader
-	writer io.Writer
+	reader  io.Reader
+	writer  io.Writer
+	writeMu sync.Mutex
 
 	// State
 	initialized bool
@@ -64,7 +67,6 @@ func (s *Server) Start(reader io.Reader, writer io.Writer) error {
 // handleMessages handles incoming LSP messages
 func (s *Server) handleMessages() error {
 	decoder := json.NewDecoder(s.reader)
-	encoder := json.NewEncoder(s.writer)
 
 	for {
 		var msg Message
@@ -86,7 +88,7 @@ func (s *Server) handleMessages() error {
 					Message: err.Error(),
 				},
 			}
-			if err := encoder.Encode(errResp); err != nil {
+			if err := s.write(errResp); err != nil {
 				return fmt.Errorf("encode error response: %w", err)
 			}
 			continue
@@ -94,13 +96,30 @@ func (s *Server) handleMessages() error {
 
 		// Send response if not a notification
 		if msg.ID != nil && response != nil {
-			if err := encoder.Encode(response); err != nil {
+			if err := s.write(response); err != nil {
 				return fmt.Errorf("encode response: %w", err)
 			}
 		}
 	}
 }
 
+// write encodes a message to the client; it is safe for concurrent use
+func (s *Server) write(v interface{}) error {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+
+	return json.NewEncoder(s.writer).Encode(v)
+}
+
+// sendNotification sends a notification to the client
+func (s *Server) sendNotification(method string, params interface{}) error {
+	return s.write(NotificationMessage{
+		JSONRPC: "2.0",
+		Method:  method,
+		Params:  params,
+	})
+}
+
 // handleMessage handles a single LSP message
 func (s *Server) handleMessage(msg *Message) (interface{}, error) {
 	switch msg.Method {
@@ -336,7 +355,7 @@ func (s *Server) handleDefinition(msg *Message) (interface{}, error) {
 	}
 
 	return Location{
-		URI: "file://" + file.Path,
+		URI: pathToURI(file.Path),
 		Range: Range{
 			Start: Position{Line: symbol.StartLine - 1, Character: symbol.StartColumn},
 			End:   Position{Line: symbol.EndLine - 1, Character: symbol.EndColumn},
@@ -372,7 +391,7 @@ func (s *Server) handleReferences(msg *Message) (interface{}, error) {
 		}
 
 		locations = append(locations, Location{
-			URI: "file://" + file.Path,
+			URI: pathToURI(file.Path),
 			Range: Range{
 				Start: Position{Line: ref.LineNumber - 1, Character: ref.ColumnNumber},
 				End:   Position{Line: ref.LineNumber - 1, Character: ref.ColumnNumber + len(symbol.Name)},
@@ -451,7 +470,7 @@ func (s *Server) handleWorkspaceSymbol(msg *Message) (interface{}, error) {
 			Name: symbol.Name,
 			Kind: symbolTypeToSymbolKind(symbol.Type),
 			Location: Location{
-				URI: "file://" + file.Path,
+				URI: pathToURI(file.Path),
 				Range: Range{
 					Start: Position{Line: symbol.StartLine - 1, Character: symbol.StartColumn},
 					End:   Position{Line: symbol.EndLine - 1, Character: symbol.EndColumn},
@@ -503,7 +522,7 @@ func (s *Server) handleRename(msg *Message) (interface{}, error) {
 	// Add edit for definition
 	file, _ := s.db.GetFile(symbol.FileID)
 	if file != nil {
-		uri := "file://" + file.Path
+		uri := pathToURI(file.Path)
 		changes[uri] = append(changes[uri], TextEdit{
 			Range: Range{
 				Start: Position{Line: symbol.StartLine - 1, Character: symbol.StartColumn},
@@ -520,7 +539,7 @@ func (s *Server) handleRename(msg *Message) (interface{}, error) {
 			continue
 		}
 
-		uri := "file://" + file.Path
+		uri := pathToURI(file.Path)
 		changes[uri] = append(changes[uri], TextEdit{
 			Range: Range{
 				Start: Position{Line: ref.LineNumber - 1, Character: ref.ColumnNumber},
@@ -648,12 +667,54 @@ func (s *Server) indexWorkspace(uri string) {
 	s.indexer.IndexDirectory(path, project.ID)
 }
 
+// indexDocument re-indexes a document and publishes its diagnostics. It runs
+// apart from the request that triggered it, so failures go to the client's
+// log.
 func (s *Server) indexDocument(uri string, content []byte) {
 	// Index single document
 	path := uriToPath(uri)
 
-	// This is simplified - in production would need to get/create project
-	s.indexer.IndexFile(path, 1) // Assuming project ID 1
+	if err := s.indexer.IndexFile(path); err != nil {
+		s.logError(fmt.Sprintf("failed to index %s: %v", uri, err))
+		return
+	}
+
+	if err := s.publishDiagnostics(uri); err != nil {
+		s.logError(fmt.Sprintf("failed to publish diagnostics for %s: %v", uri, err))
+	}
+}
+
+// publishDiagnostics validates an indexed document and pushes the results to the client
+func (s *Server) publishDiagnostics(uri string) error {
+	fileID, err := s.getFileIDFromURI(uri)
+	if err != nil {
+		return err
+	}
+
+	validation, err := ai.NewTypeValidator(s.db).ValidateFile(fileID)
+	if err != nil {
+		return fmt.Errorf("validate: %w", err)
+	}
+
+	return s.publishValidation(uri, validation)
+}
+
+// logError sends an error to the client's log with a window/logMessage
+// notification, falling back to the server's own log when the client can't
+// be written to
+func (s *Server) logError(message string) {
+	if err := s.sendNotification("window/logMessage", LogMessageParams{Type: MessageTypeError, Message: message}); err != nil {
+		log.Printf("%s (could not notify the client: %v)", message, err)
+	}
+}
+
+// publishValidation sends validation results as a publishDiagnostics notification.
+// A valid document produces an empty list, which clears previous diagnostics.
+func (s *Server) publishValidation(uri string, validation *types.TypeValidation) error {
+	return s.sendNotification("textDocument/publishDiagnostics", PublishDiagnosticsParams{
+		URI:         uri,
+		Diagnostics: diagnosticsFromValidation(validation),
+	})
 }
 
 func (s *Server) getSymbolAtPosition(uri string, pos Position) (*database.Symbol, error) {
@@ -705,14 +766,6 @@ func (s *Server) getFileIDFromURI(uri string) (int64, error) {
 	return files[0].ID, nil
 }
 
-func uriToPath(uri string) string {
-	// Simple conversion - would need proper URI parsing
-	if len(uri) > 7 && uri[:7] == "file://" {
-		return uri[7:]
-	}
-	return uri
-}
-
 // Workspace represents an LSP workspace
 type Workspace struct {
 	URI  string
@@ -0,0 +1,101 @@
+package lsp
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/aaamil13/CodeIndexerMCP/pkg/types"
+)
+
+func decodeNotification(t *testing.T, buf *bytes.Buffer) PublishDiagnosticsParams {
+	t.Helper()
+
+	var msg struct {
+		Method string                   `json:"method"`
+		Params PublishDiagnosticsParams `json:"params"`
+	}
+	if err := json.NewDecoder(buf).Decode(&msg); err != nil {
+		t.Fatalf("Failed to decode notification: %v", err)
+	}
+
+	if msg.Method != "textDocument/publishDiagnostics" {
+		t.Errorf("Expected publishDiagnostics notification, got %s", msg.Method)
+	}
+
+	return msg.Params
+}
+
+func TestPublishDiagnosticsUndefinedUsage(t *testing.T) {
+	var buf bytes.Buffer
+	s := &Server{writer: &buf}
+
+	validation := &types.TypeValidation{
+		IsValid: false,
+		UndefinedSymbols: []*types.UndefinedUsage{
+			{Name: "missingFunc", Line: 3, Column: 4, UsageType: "function", Severity: "error"},
+		},
+	}
+
+	if err := s.publishValidation("file:///tmp/main.go", validation); err != nil {
+		t.Fatalf("Failed to publish diagnostics: %v", err)
+	}
+
+	params := decodeNotification(t, &buf)
+	if params.URI != "file:///tmp/main.go" {
+		t.Errorf("Expected URI file:///tmp/main.go, got %s", params.URI)
+	}
+	if len(params.Diagnostics) != 1 {
+		t.Fatalf("Expected 1 diagnostic, got %d", len(params.Diagnostics))
+	}
+
+	diag := params.Diagnostics[0]
+	if diag.Severity != DiagnosticSeverityError {
+		t.Errorf("Expected error severity, got %d", diag.Severity)
+	}
+	if diag.Range.Start.Line != 2 || diag.Range.Start.Character != 4 {
+		t.Errorf("Unexpected range start: %+v", diag.Range.Start)
+	}
+	if diag.Range.End.Character != 4+len("missingFunc") {
+		t.Errorf("Unexpected range end: %+v", diag.Range.End)
+	}
+}
+
+func TestPublishDiagnosticsClearsValidDocument(t *testing.T) {
+	var buf bytes.Buffer
+	s := &Server{writer: &buf}
+
+	if err := s.publishValidation("file:///tmp/main.go", &types.TypeValidation{IsValid: true}); err != nil {
+		t.Fatalf("Failed to publish diagnostics: %v", err)
+	}
+
+	if !bytes.Contains(buf.Bytes(), []byte(`"diagnostics":[]`)) {
+		t.Errorf("Expected empty diagnostics list, got %s", buf.String())
+	}
+
+	params := decodeNotification(t, &buf)
+	if len(params.Diagnostics) != 0 {
+		t.Errorf("Expected no diagnostics, got %d", len(params.Diagnostics))
+	}
+}
+
+func TestLogError(t *testing.T) {
+	var buf bytes.Buffer
+	s := &Server{writer: &buf}
+
+	s.logError("failed to index file:///tmp/main.go: boom")
+
+	var msg struct {
+		Method string           `json:"method"`
+		Params LogMessageParams `json:"params"`
+	}
+	if err := json.NewDecoder(&buf).Decode(&msg); err != nil {
+		t.Fatalf("Failed to decode notification: %v", err)
+	}
+	if msg.Method != "window/logMessage" || msg.Params.Type != MessageTypeError {
+		t.Errorf("Expected an error window/logMessage, got %s type %d", msg.Method, msg.Params.Type)
+	}
+	if msg.Params.Message != "failed to index file:///tmp/main.go: boom" {
+		t.Errorf("Unexpected message %q", msg.Params.Message)
+	}
+}
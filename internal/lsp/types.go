@@ -12,6 +12,13 @@ type Message struct {
 	Error   *ResponseError  `json:"error,omitempty"`
 }
 
+// NotificationMessage represents a server-to-client notification
+type NotificationMessage struct {
+	JSONRPC string      `json:"jsonrpc"`
+	Method  string      `json:"method"`
+	Params  interface{} `json:"params,omitempty"`
+}
+
 // ResponseError represents an LSP error response
 type ResponseError struct {
 	Code    int    `json:"code"`
@@ -418,6 +425,29 @@ type Diagnostic struct {
 	Message  string             `json:"message"`
 }
 
+// PublishDiagnosticsParams represents textDocument/publishDiagnostics parameters
+type PublishDiagnosticsParams struct {
+	URI         string       `json:"uri"`
+	Version     *int         `json:"version,omitempty"`
+	Diagnostics []Diagnostic `json:"diagnostics"`
+}
+
+// LogMessageParams represents window/logMessage parameters
+type LogMessageParams struct {
+	Type    MessageType `json:"type"`
+	Message string      `json:"message"`
+}
+
+// MessageType represents the kind of a window/logMessage message
+type MessageType int
+
+const (
+	MessageTypeError   MessageType = 1
+	MessageTypeWarning MessageType = 2
+	MessageTypeInfo    MessageType = 3
+	MessageTypeLog     MessageType = 4
+)
+
 // DiagnosticSeverity represents the severity of a diagnostic
 type DiagnosticSeverity int
 
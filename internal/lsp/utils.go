@@ -1,6 +1,11 @@
 package lsp
 
 import (
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"strings"
+
 	"github.com/aaamil13/CodeIndexerMCP/pkg/types"
 )
 
@@ -137,5 +142,83 @@ func createDiagnosticFromMissingMethod(missing *types.MissingMethod) Diagnostic
 	}
 }
 
-// Import fmt for diagnostic functions
-import "fmt"
+// createDiagnosticFromInvalidCall creates an LSP diagnostic from an invalid call
+func createDiagnosticFromInvalidCall(call *types.InvalidCall) Diagnostic {
+	message := fmt.Sprintf("Invalid call to '%s': %s", call.SymbolName, call.Issue)
+	if call.Expected != "" || call.Actual != "" {
+		message += fmt.Sprintf(" (expected %s, got %s)", call.Expected, call.Actual)
+	}
+
+	return Diagnostic{
+		Range: Range{
+			Start: Position{Line: call.Line - 1, Character: call.Column},
+			End:   Position{Line: call.Line - 1, Character: call.Column + len(call.SymbolName)},
+		},
+		Severity: severityToDiagnosticSeverity(call.Severity),
+		Source:   "codeindexer",
+		Message:  message,
+	}
+}
+
+// diagnosticsFromValidation converts type validation results to LSP diagnostics
+func diagnosticsFromValidation(validation *types.TypeValidation) []Diagnostic {
+	// Always return a non-nil slice so an empty result clears the client's diagnostics
+	diagnostics := []Diagnostic{}
+	if validation == nil {
+		return diagnostics
+	}
+
+	for _, usage := range validation.UndefinedSymbols {
+		diagnostics = append(diagnostics, createDiagnosticFromUndefinedUsage(usage))
+	}
+	for _, call := range validation.InvalidCalls {
+		diagnostics = append(diagnostics, createDiagnosticFromInvalidCall(call))
+	}
+
+	return diagnostics
+}
+
+// uriToPath converts a file:// URI to a local file path, decoding
+// percent-escapes and handling Windows drive letters and UNC hosts
+func uriToPath(uri string) string {
+	u, err := url.Parse(uri)
+	if err != nil || u.Scheme != "file" {
+		return uri
+	}
+
+	path := u.Path
+	if hasDriveLetter(path) {
+		path = path[1:] // file:///C:/dir -> C:/dir
+	} else if u.Host != "" && u.Host != "localhost" {
+		path = "//" + u.Host + path // file://server/share -> //server/share
+	}
+
+	return filepath.FromSlash(path)
+}
+
+// pathToURI converts a local file path to a file:// URI
+func pathToURI(path string) string {
+	path = filepath.ToSlash(path)
+	if len(path) >= 2 && path[1] == ':' {
+		path = "/" + path // C:/dir -> /C:/dir
+	}
+
+	u := url.URL{Scheme: "file", Path: path}
+	if strings.HasPrefix(path, "//") {
+		// UNC path: the server becomes the URI host
+		rest := strings.TrimPrefix(path, "//")
+		host, share, _ := strings.Cut(rest, "/")
+		u = url.URL{Scheme: "file", Host: host, Path: "/" + share}
+	}
+
+	return u.String()
+}
+
+// hasDriveLetter reports whether a URI path starts with a Windows drive, e.g. /C:/
+func hasDriveLetter(path string) bool {
+	if len(path) < 3 || path[0] != '/' || path[2] != ':' {
+		return false
+	}
+	c := path[1]
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
@@ -0,0 +1,52 @@
+package lsp
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestURIToPath(t *testing.T) {
+	tests := []struct {
+		name string
+		uri  string
+		want string
+	}{
+		{"posix", "file:///home/dev/project/main.go", "/home/dev/project/main.go"},
+		{"spaces", "file:///home/dev/my%20project/main.go", "/home/dev/my project/main.go"},
+		{"windows drive", "file:///C:/Users/dev/main.go", "C:/Users/dev/main.go"},
+		{"encoded drive colon", "file:///c%3A/Users/dev/main.go", "c:/Users/dev/main.go"},
+		{"not a file uri", "untitled:Untitled-1", "untitled:Untitled-1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := uriToPath(tt.uri); got != filepath.FromSlash(tt.want) {
+				t.Errorf("uriToPath(%q) = %q, want %q", tt.uri, got, filepath.FromSlash(tt.want))
+			}
+		})
+	}
+}
+
+func TestPathToURIRoundTrip(t *testing.T) {
+	tests := []struct {
+		path string
+		uri  string
+	}{
+		{"/home/dev/project/main.go", "file:///home/dev/project/main.go"},
+		{"/home/dev/my project/main.go", "file:///home/dev/my%20project/main.go"},
+		{"C:/Users/dev/main.go", "file:///C:/Users/dev/main.go"},
+	}
+
+	for _, tt := range tests {
+		path := filepath.FromSlash(tt.path)
+
+		uri := pathToURI(path)
+		if uri != tt.uri {
+			t.Errorf("pathToURI(%q) = %q, want %q", path, uri, tt.uri)
+		}
+
+		if back := uriToPath(uri); back != path {
+			t.Errorf("uriToPath(pathToURI(%q)) = %q", path, back)
+		}
+	}
+}
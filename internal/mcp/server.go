@@ -1,22 +1,31 @@
 package mcp
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"os"
+	"sync"
+	"time"
 
 	"github.com/aaamil13/CodeIndexerMCP/internal/core"
+	"github.com/aaamil13/CodeIndexerMCP/internal/parser"
 	"github.com/aaamil13/CodeIndexerMCP/pkg/types"
 )
 
+// DefaultToolTimeout is the default time limit for a single tool call
+const DefaultToolTimeout = core.DefaultToolTimeout
+
 // Server is the MCP server
 type Server struct {
-	indexer *core.Indexer
-	tools   map[string]*Tool
-	stdin   io.Reader
-	stdout  io.Writer
+	indexer     *core.Indexer
+	tools       map[string]*Tool
+	stdin       io.Reader
+	stdout      io.Writer
+	writeMu     sync.Mutex // Serializes writes to stdout
+	toolTimeout time.Duration
 }
 
 // Tool represents an MCP tool
@@ -25,42 +34,66 @@ type Tool struct {
 	Description string                 `json:"description"`
 	InputSchema map[string]interface{} `json:"inputSchema"`
 	Handler     func(params json.RawMessage) (interface{}, error)
+	// ContextHandler is used instead of Handler for tools that support cancellation
+	ContextHandler func(ctx context.Context, params json.RawMessage) (interface{}, error)
 }
 
 // NewServer creates a new MCP server
 func NewServer(indexer *core.Indexer) *Server {
 	server := &Server{
-		indexer: indexer,
-		tools:   make(map[string]*Tool),
-		stdin:   os.Stdin,
-		stdout:  os.Stdout,
+		indexer:     indexer,
+		tools:       make(map[string]*Tool),
+		stdin:       os.Stdin,
+		stdout:      os.Stdout,
+		toolTimeout: DefaultToolTimeout,
 	}
 
 	server.registerTools()
 	return server
 }
 
+// SetToolTimeout sets the time limit for a single tool call (0 disables the limit)
+func (s *Server) SetToolTimeout(timeout time.Duration) {
+	s.toolTimeout = timeout
+}
+
 // Start starts the MCP server
 func (s *Server) Start(ctx context.Context) error {
 	decoder := json.NewDecoder(s.stdin)
-	encoder := json.NewEncoder(s.stdout)
 
 	for {
 		select {
 		case <-ctx.Done():
 			return ctx.Err()
 		default:
-			var request MCPRequest
-			if err := decoder.Decode(&request); err != nil {
+			var message json.RawMessage
+			if err := decoder.Decode(&message); err != nil {
 				if err == io.EOF {
 					return nil
 				}
-				s.sendError(encoder, "", fmt.Errorf("failed to decode request: %w", err))
+				s.sendError("", fmt.Errorf("failed to decode request: %w", err))
+				continue
+			}
+
+			if isBatch(message) {
+				responses := s.handleBatch(ctx, message)
+				if len(responses) == 0 {
+					continue // Batch of notifications only
+				}
+				if err := s.writeResponse(responses); err != nil {
+					return fmt.Errorf("failed to encode response: %w", err)
+				}
+				continue
+			}
+
+			var request MCPRequest
+			if err := json.Unmarshal(message, &request); err != nil {
+				s.sendError("", fmt.Errorf("failed to decode request: %w", err))
 				continue
 			}
 
-			response := s.handleRequest(&request)
-			if err := encoder.Encode(response); err != nil {
+			response := s.handleRequest(ctx, &request)
+			if err := s.writeResponse(response); err != nil {
 				return fmt.Errorf("failed to encode response: %w", err)
 			}
 		}
@@ -90,7 +123,7 @@ type MCPError struct {
 }
 
 // handleRequest handles an MCP request
-func (s *Server) handleRequest(req *MCPRequest) *MCPResponse {
+func (s *Server) handleRequest(ctx context.Context, req *MCPRequest) *MCPResponse {
 	resp := &MCPResponse{
 		JSONRPC: "2.0",
 		ID:      req.ID,
@@ -104,7 +137,7 @@ func (s *Server) handleRequest(req *MCPRequest) *MCPResponse {
 		resp.Result = s.handleToolsList()
 
 	case "tools/call":
-		result, err := s.handleToolCall(req.Params)
+		result, err := s.handleToolCall(ctx, req.Params)
 		if err != nil {
 			resp.Error = &MCPError{
 				Code:    -32603,
@@ -124,6 +157,50 @@ func (s *Server) handleRequest(req *MCPRequest) *MCPResponse {
 	return resp
 }
 
+// isBatch reports whether a JSON-RPC message is a batch (a JSON array)
+func isBatch(message json.RawMessage) bool {
+	trimmed := bytes.TrimLeft(message, " \t\r\n")
+	return len(trimmed) > 0 && trimmed[0] == '['
+}
+
+// handleBatch handles a JSON-RPC batch, returning responses in request order.
+// Each request is handled independently and notifications get no response.
+func (s *Server) handleBatch(ctx context.Context, message json.RawMessage) []*MCPResponse {
+	var batch []json.RawMessage
+	if err := json.Unmarshal(message, &batch); err != nil || len(batch) == 0 {
+		return []*MCPResponse{invalidRequest(nil, "invalid batch request")}
+	}
+
+	responses := make([]*MCPResponse, 0, len(batch))
+	for _, raw := range batch {
+		var request MCPRequest
+		if err := json.Unmarshal(raw, &request); err != nil {
+			responses = append(responses, invalidRequest(nil, fmt.Sprintf("invalid request: %v", err)))
+			continue
+		}
+
+		response := s.handleRequest(ctx, &request)
+		if request.ID == nil {
+			continue // Notification
+		}
+		responses = append(responses, response)
+	}
+
+	return responses
+}
+
+// invalidRequest builds a JSON-RPC "Invalid Request" error response
+func invalidRequest(id interface{}, message string) *MCPResponse {
+	return &MCPResponse{
+		JSONRPC: "2.0",
+		ID:      id,
+		Error: &MCPError{
+			Code:    -32600,
+			Message: message,
+		},
+	}
+}
+
 // handleInitialize handles the initialize request
 func (s *Server) handleInitialize(params json.RawMessage) interface{} {
 	return map[string]interface{}{
@@ -158,7 +235,7 @@ func (s *Server) handleToolsList() interface{} {
 }
 
 // handleToolCall handles a tool call
-func (s *Server) handleToolCall(params json.RawMessage) (interface{}, error) {
+func (s *Server) handleToolCall(ctx context.Context, params json.RawMessage) (interface{}, error) {
 	var req struct {
 		Name      string          `json:"name"`
 		Arguments json.RawMessage `json:"arguments"`
@@ -173,7 +250,7 @@ func (s *Server) handleToolCall(params json.RawMessage) (interface{}, error) {
 		return nil, fmt.Errorf("tool not found: %s", req.Name)
 	}
 
-	result, err := tool.Handler(req.Arguments)
+	result, err := s.callTool(ctx, tool, req.Arguments)
 	if err != nil {
 		return nil, err
 	}
@@ -188,8 +265,45 @@ func (s *Server) handleToolCall(params json.RawMessage) (interface{}, error) {
 	}, nil
 }
 
+// callTool runs a tool handler, enforcing the per-call timeout. The handler
+// runs on the calling goroutine, so a call that times out doesn't leave it
+// running in the background: tools that can take long have a ContextHandler
+// that stops once ctx is done, and Handler tools are quick lookups.
+func (s *Server) callTool(ctx context.Context, tool *Tool, args json.RawMessage) (interface{}, error) {
+	if s.toolTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.toolTimeout)
+		defer cancel()
+	}
+
+	var value interface{}
+	var err error
+	if tool.ContextHandler != nil {
+		value, err = tool.ContextHandler(ctx, args)
+	} else {
+		value, err = tool.Handler(args)
+	}
+
+	if ctx.Err() == context.DeadlineExceeded {
+		return nil, fmt.Errorf("tool %s timed out after %v", tool.Name, s.toolTimeout)
+	}
+	if err == nil && ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+	return value, err
+}
+
+// writeResponse encodes a message to stdout. All output goes through here
+// so concurrent writers cannot interleave bytes in the JSON stream.
+func (s *Server) writeResponse(v interface{}) error {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+
+	return json.NewEncoder(s.stdout).Encode(v)
+}
+
 // sendError sends an error response
-func (s *Server) sendError(encoder *json.Encoder, id interface{}, err error) {
+func (s *Server) sendError(id interface{}, err error) {
 	response := &MCPResponse{
 		JSONRPC: "2.0",
 		ID:      id,
@@ -198,11 +312,15 @@ func (s *Server) sendError(encoder *json.Encoder, id interface{}, err error) {
 			Message: err.Error(),
 		},
 	}
-	encoder.Encode(response)
+	s.writeResponse(response)
 }
 
 // formatResult formats a result as a string
 func formatResult(result interface{}) string {
+	if text, ok := result.(string); ok {
+		return text // Already formatted, e.g. markdown
+	}
+
 	data, err := json.MarshalIndent(result, "", "  ")
 	if err != nil {
 		return fmt.Sprintf("%v", result)
@@ -214,7 +332,7 @@ func formatResult(result interface{}) string {
 func (s *Server) registerTools() {
 	s.registerTool(&Tool{
 		Name:        "search_symbols",
-		Description: "Search for symbols (functions, classes, methods, variables) in the codebase",
+		Description: "Search for symbols (functions, classes, methods, variables) in the codebase; each result has an id usable with get_symbol_by_id until its file is re-indexed",
 		InputSchema: map[string]interface{}{
 			"type": "object",
 			"properties": map[string]interface{}{
@@ -234,12 +352,78 @@ func (s *Server) registerTools() {
 					"type":        "number",
 					"description": "Maximum number of results",
 				},
+				"ranking": map[string]interface{}{
+					"type":        "string",
+					"enum":        []string{"relevance", "name"},
+					"description": "Result order: relevance (exact name, then prefix, then best match; default) or name",
+				},
 			},
 			"required": []string{"query"},
 		},
 		Handler: s.handleSearchSymbols,
 	})
 
+	s.registerTool(&Tool{
+		Name:        "search_in_file",
+		Description: "Search for symbols defined in a specific file",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"file_path": map[string]interface{}{
+					"type":        "string",
+					"description": "Path to the file",
+				},
+				"query": map[string]interface{}{
+					"type":        "string",
+					"description": "Search query (symbol name)",
+				},
+			},
+			"required": []string{"file_path", "query"},
+		},
+		Handler: s.handleSearchInFile,
+	})
+
+	s.registerTool(&Tool{
+		Name:        "query_symbols",
+		Description: "Find symbols by structural predicates, e.g. public methods over 50 lines with no docs. All given predicates must match",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"types": map[string]interface{}{
+					"type":        "array",
+					"items":       map[string]interface{}{"type": "string"},
+					"description": "Symbol kinds to include (function, method, class, ...)",
+				},
+				"visibility": map[string]interface{}{
+					"type":        "array",
+					"items":       map[string]interface{}{"type": "string"},
+					"description": "Visibilities to include (public, private, protected, internal, package)",
+				},
+				"min_lines": map[string]interface{}{
+					"type":        "integer",
+					"description": "Minimum symbol length in lines (inclusive)",
+				},
+				"max_lines": map[string]interface{}{
+					"type":        "integer",
+					"description": "Maximum symbol length in lines (inclusive)",
+				},
+				"has_docs": map[string]interface{}{
+					"type":        "boolean",
+					"description": "Only documented (true) or undocumented (false) symbols",
+				},
+				"file_pattern": map[string]interface{}{
+					"type":        "string",
+					"description": "Glob on the file's relative path, e.g. internal/*.go (* also matches /)",
+				},
+				"limit": map[string]interface{}{
+					"type":        "integer",
+					"description": "Maximum number of results (default: 100)",
+				},
+			},
+		},
+		Handler: s.handleQuerySymbols,
+	})
+
 	s.registerTool(&Tool{
 		Name:        "get_file_structure",
 		Description: "Get the structure of a specific file (all symbols and imports)",
@@ -250,6 +434,15 @@ func (s *Server) registerTools() {
 					"type":        "string",
 					"description": "Path to the file (relative or absolute)",
 				},
+				"order": map[string]interface{}{
+					"type":        "string",
+					"enum":        []string{"line", "name", "kind"},
+					"description": "Symbol ordering (default: line)",
+				},
+				"nested": map[string]interface{}{
+					"type":        "boolean",
+					"description": "Group methods and members under their parent class or type",
+				},
 			},
 			"required": []string{"file_path"},
 		},
@@ -257,92 +450,103 @@ func (s *Server) registerTools() {
 	})
 
 	s.registerTool(&Tool{
-		Name:        "get_project_overview",
-		Description: "Get an overview of the entire project (statistics, languages, etc.)",
+		Name:        "get_namespace_tree",
+		Description: "Get the project's packages, modules and namespaces as a tree, with the files and top-level symbols in each",
 		InputSchema: map[string]interface{}{
 			"type":       "object",
 			"properties": map[string]interface{}{},
 		},
-		Handler: s.handleGetProjectOverview,
+		Handler: s.handleGetNamespaceTree,
 	})
 
 	s.registerTool(&Tool{
-		Name:        "index_project",
-		Description: "Trigger a full re-index of the project",
+		Name:        "get_entry_points",
+		Description: "Find the project's entry points: main functions, HTTP and framework route handlers, and CLI commands. Useful as roots for call-graph and dead-code analysis",
 		InputSchema: map[string]interface{}{
 			"type":       "object",
 			"properties": map[string]interface{}{},
 		},
-		Handler: s.handleIndexProject,
+		Handler: s.handleGetEntryPoints,
 	})
 
 	s.registerTool(&Tool{
-		Name:        "get_symbol_details",
-		Description: "Get detailed information about a specific symbol (including references and relationships)",
+		Name:        "find_recursive",
+		Description: "Find recursive functions: those calling themselves directly and groups of mutually recursive functions",
 		InputSchema: map[string]interface{}{
-			"type": "object",
-			"properties": map[string]interface{}{
-				"symbol_name": map[string]interface{}{
-					"type":        "string",
-					"description": "Name of the symbol",
-				},
-			},
-			"required": []string{"symbol_name"},
+			"type":       "object",
+			"properties": map[string]interface{}{},
 		},
-		Handler: s.handleGetSymbolDetails,
+		Handler: s.handleFindRecursive,
 	})
 
 	s.registerTool(&Tool{
-		Name:        "find_references",
-		Description: "Find all references to a symbol in the codebase",
+		Name:        "get_import_usage",
+		Description: "For each import in a file, list which of its members are actually used and flag imports with no used members",
 		InputSchema: map[string]interface{}{
 			"type": "object",
 			"properties": map[string]interface{}{
-				"symbol_name": map[string]interface{}{
+				"file_path": map[string]interface{}{
 					"type":        "string",
-					"description": "Name of the symbol to find references for",
+					"description": "Path to the file (relative or absolute)",
 				},
 			},
-			"required": []string{"symbol_name"},
+			"required": []string{"file_path"},
 		},
-		Handler: s.handleFindReferences,
+		Handler: s.handleGetImportUsage,
 	})
 
 	s.registerTool(&Tool{
-		Name:        "get_dependencies",
-		Description: "Get dependencies for a specific file",
+		Name:        "get_symbols_in_range",
+		Description: "Get the symbols overlapping a line range of a file, marking each as contained or overlapping",
 		InputSchema: map[string]interface{}{
 			"type": "object",
 			"properties": map[string]interface{}{
 				"file_path": map[string]interface{}{
 					"type":        "string",
-					"description": "Path to the file (relative or absolute)",
+					"description": "Path to the file",
+				},
+				"start_line": map[string]interface{}{
+					"type":        "number",
+					"description": "First line of the range (1-based)",
+				},
+				"end_line": map[string]interface{}{
+					"type":        "number",
+					"description": "Last line of the range (inclusive)",
 				},
 			},
-			"required": []string{"file_path"},
+			"required": []string{"file_path", "start_line", "end_line"},
 		},
-		Handler: s.handleGetDependencies,
+		Handler: s.handleGetSymbolsInRange,
 	})
 
 	s.registerTool(&Tool{
-		Name:        "list_files",
-		Description: "List all indexed files in the project",
+		Name:        "get_project_overview",
+		Description: "Get an overview of the entire project (statistics, languages, etc.)",
 		InputSchema: map[string]interface{}{
 			"type": "object",
 			"properties": map[string]interface{}{
-				"language": map[string]interface{}{
-					"type":        "string",
-					"description": "Filter by language (optional)",
+				"detailed": map[string]interface{}{
+					"type":        "boolean",
+					"description": "Also compute per-language stats, top-level symbols and recently modified files (slower on large projects)",
 				},
 			},
 		},
-		Handler: s.handleListFiles,
+		Handler: s.handleGetProjectOverview,
 	})
 
-	// AI-powered tools
 	s.registerTool(&Tool{
-		Name:        "get_code_context",
-		Description: "Get comprehensive context for a symbol including usage examples, dependencies, and relationships",
+		Name:        "index_project",
+		Description: "Trigger a full re-index of the project",
+		InputSchema: map[string]interface{}{
+			"type":       "object",
+			"properties": map[string]interface{}{},
+		},
+		ContextHandler: s.handleIndexProject,
+	})
+
+	s.registerTool(&Tool{
+		Name:        "get_symbol_details",
+		Description: "Get detailed information about a specific symbol (including references and relationships)",
 		InputSchema: map[string]interface{}{
 			"type": "object",
 			"properties": map[string]interface{}{
@@ -350,170 +554,178 @@ func (s *Server) registerTools() {
 					"type":        "string",
 					"description": "Name of the symbol",
 				},
-				"depth": map[string]interface{}{
-					"type":        "number",
-					"description": "Context depth (number of usage examples, default: 5)",
-				},
 			},
 			"required": []string{"symbol_name"},
 		},
-		Handler: s.handleGetCodeContext,
+		Handler: s.handleGetSymbolDetails,
 	})
 
 	s.registerTool(&Tool{
-		Name:        "analyze_change_impact",
-		Description: "Analyze the impact of changing or refactoring a symbol (risk level, affected files, suggestions)",
+		Name:        "bulk_get_symbols",
+		Description: "Get detailed information about several symbols in one call; names that aren't found map to null and are listed in not_found",
 		InputSchema: map[string]interface{}{
 			"type": "object",
 			"properties": map[string]interface{}{
-				"symbol_name": map[string]interface{}{
-					"type":        "string",
-					"description": "Name of the symbol to analyze",
+				"symbol_names": map[string]interface{}{
+					"type":        "array",
+					"items":       map[string]interface{}{"type": "string"},
+					"description": "Names of the symbols (at most 100)",
 				},
 			},
-			"required": []string{"symbol_name"},
+			"required": []string{"symbol_names"},
 		},
-		Handler: s.handleAnalyzeChangeImpact,
+		Handler: s.handleBulkGetSymbols,
 	})
 
 	s.registerTool(&Tool{
-		Name:        "get_code_metrics",
-		Description: "Calculate code quality metrics (complexity, maintainability, quality rating)",
+		Name:        "get_symbol_by_id",
+		Description: "Get detailed information about a symbol by its id (from search_symbols), to pick one of several symbols sharing a name. Ids are assigned when a file is indexed and never reused, so after the file changes an old id is not found: search again for a fresh one",
 		InputSchema: map[string]interface{}{
 			"type": "object",
 			"properties": map[string]interface{}{
-				"symbol_name": map[string]interface{}{
-					"type":        "string",
-					"description": "Name of the symbol (function/method)",
+				"id": map[string]interface{}{
+					"type":        "integer",
+					"description": "Symbol id from a search since the symbol's file was last indexed",
 				},
 			},
-			"required": []string{"symbol_name"},
+			"required": []string{"id"},
 		},
-		Handler: s.handleGetCodeMetrics,
+		Handler: s.handleGetSymbolByID,
 	})
 
 	s.registerTool(&Tool{
-		Name:        "extract_smart_snippet",
-		Description: "Extract a self-contained code snippet with all dependencies and usage hints",
+		Name:        "get_signature",
+		Description: "Get just the signature, kind and doc summary of a symbol (all overloads), without the full details payload",
 		InputSchema: map[string]interface{}{
 			"type": "object",
 			"properties": map[string]interface{}{
 				"symbol_name": map[string]interface{}{
 					"type":        "string",
-					"description": "Name of the symbol to extract",
+					"description": "Name of the symbol",
 				},
 			},
 			"required": []string{"symbol_name"},
 		},
-		Handler: s.handleExtractSmartSnippet,
+		Handler: s.handleGetSignature,
 	})
 
 	s.registerTool(&Tool{
-		Name:        "get_usage_statistics",
-		Description: "Get detailed usage statistics for a symbol (usage count, patterns, files)",
+		Name:        "get_parameters",
+		Description: "Get the parameters of a function with each one's name, type, default value and variadic flag, for generating calls",
 		InputSchema: map[string]interface{}{
 			"type": "object",
 			"properties": map[string]interface{}{
 				"symbol_name": map[string]interface{}{
 					"type":        "string",
-					"description": "Name of the symbol",
+					"description": "Name of the function or method",
 				},
 			},
 			"required": []string{"symbol_name"},
 		},
-		Handler: s.handleGetUsageStatistics,
+		Handler: s.handleGetParameters,
 	})
 
 	s.registerTool(&Tool{
-		Name:        "suggest_refactorings",
-		Description: "Get AI-powered refactoring suggestions for a symbol",
+		Name:        "find_symbols_by_annotation",
+		Description: "Find symbols carrying an annotation, attribute or decorator, e.g. RestController, GetMapping or HttpGet (Java, C#, Python)",
 		InputSchema: map[string]interface{}{
 			"type": "object",
 			"properties": map[string]interface{}{
-				"symbol_name": map[string]interface{}{
+				"annotation": map[string]interface{}{
 					"type":        "string",
-					"description": "Name of the symbol",
+					"description": "Annotation name, with or without @ / [] and arguments",
 				},
 			},
-			"required": []string{"symbol_name"},
+			"required": []string{"annotation"},
 		},
-		Handler: s.handleSuggestRefactorings,
+		Handler: s.handleFindSymbolsByAnnotation,
 	})
 
 	s.registerTool(&Tool{
-		Name:        "find_unused_symbols",
-		Description: "Find unused/dead code in the project",
+		Name:        "find_deprecated",
+		Description: "Find symbols marked deprecated (@Deprecated, [Obsolete], Go's Deprecated: paragraph, JSDoc @deprecated, Python deprecation notes) along with their remaining usages",
 		InputSchema: map[string]interface{}{
 			"type":       "object",
 			"properties": map[string]interface{}{},
 		},
-		Handler: s.handleFindUnusedSymbols,
+		Handler: s.handleFindDeprecated,
 	})
 
-	// Change tracking tools
 	s.registerTool(&Tool{
-		Name:        "simulate_change",
-		Description: "Simulate a code change and see its impact before applying it (shows affected files, broken references, auto-fix suggestions)",
+		Name:        "get_symbol_source",
+		Description: "Get the exact source code of a symbol as it is on disk; warns if the file changed since indexing",
 		InputSchema: map[string]interface{}{
 			"type": "object",
 			"properties": map[string]interface{}{
 				"symbol_name": map[string]interface{}{
 					"type":        "string",
-					"description": "Name of the symbol to change",
+					"description": "Name of the symbol",
 				},
-				"change_type": map[string]interface{}{
+			},
+			"required": []string{"symbol_name"},
+		},
+		Handler: s.handleGetSymbolSource,
+	})
+
+	s.registerTool(&Tool{
+		Name:        "resolve_reference",
+		Description: "Go to definition: resolve the symbol used at a file position to the location of its definition",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"file_path": map[string]interface{}{
 					"type":        "string",
-					"description": "Type of change: add, modify, delete, rename, move",
+					"description": "Path of the file containing the usage",
 				},
-				"new_value": map[string]interface{}{
-					"type":        "string",
-					"description": "New value (e.g., new name for rename, new signature for modify)",
+				"line": map[string]interface{}{
+					"type":        "integer",
+					"description": "Line of the usage (1-based)",
+				},
+				"column": map[string]interface{}{
+					"type":        "integer",
+					"description": "Column of the usage (1-based)",
 				},
 			},
-			"required": []string{"symbol_name", "change_type"},
+			"required": []string{"file_path", "line", "column"},
 		},
-		Handler: s.handleSimulateChange,
+		Handler: s.handleResolveReference,
 	})
 
 	s.registerTool(&Tool{
-		Name:        "build_dependency_graph",
-		Description: "Build a dependency graph for a symbol showing what it depends on and what depends on it",
+		Name:        "find_references",
+		Description: "Find all references to a symbol in the codebase",
 		InputSchema: map[string]interface{}{
 			"type": "object",
 			"properties": map[string]interface{}{
 				"symbol_name": map[string]interface{}{
 					"type":        "string",
-					"description": "Name of the symbol",
-				},
-				"max_depth": map[string]interface{}{
-					"type":        "number",
-					"description": "Maximum depth to traverse (default: 3)",
+					"description": "Name of the symbol to find references for",
 				},
 			},
 			"required": []string{"symbol_name"},
 		},
-		Handler: s.handleBuildDependencyGraph,
+		Handler: s.handleFindReferences,
 	})
 
 	s.registerTool(&Tool{
-		Name:        "get_symbol_dependencies",
-		Description: "Get all symbols that a given symbol depends on",
+		Name:        "find_references_grouped",
+		Description: "Find all references to a symbol grouped by file, with per-file and total counts",
 		InputSchema: map[string]interface{}{
 			"type": "object",
 			"properties": map[string]interface{}{
 				"symbol_name": map[string]interface{}{
 					"type":        "string",
-					"description": "Name of the symbol",
+					"description": "Name of the symbol to find references for",
 				},
 			},
 			"required": []string{"symbol_name"},
 		},
-		Handler: s.handleGetSymbolDependencies,
+		Handler: s.handleFindReferencesGrouped,
 	})
 
 	s.registerTool(&Tool{
-		Name:        "get_symbol_dependents",
-		Description: "Get all symbols that depend on a given symbol",
+		Name:        "get_files_touching_symbol",
+		Description: "List every file that defines or references a symbol, deduplicated; the file-level analog of find_references",
 		InputSchema: map[string]interface{}{
 			"type": "object",
 			"properties": map[string]interface{}{
@@ -524,141 +736,1848 @@ func (s *Server) registerTools() {
 			},
 			"required": []string{"symbol_name"},
 		},
-		Handler: s.handleGetSymbolDependents,
+		Handler: s.handleGetFilesTouchingSymbol,
 	})
 
-	// Type validation tools
 	s.registerTool(&Tool{
-		Name:        "validate_file_types",
-		Description: "Validate all types in a file and find undefined usages, type mismatches, and missing methods",
+		Name:        "get_file_references",
+		Description: "Get all symbol references originating in a file, ordered by line",
 		InputSchema: map[string]interface{}{
 			"type": "object",
 			"properties": map[string]interface{}{
 				"file_path": map[string]interface{}{
 					"type":        "string",
-					"description": "Path to the file to validate",
+					"description": "Path to the file",
 				},
 			},
 			"required": []string{"file_path"},
 		},
-		Handler: s.handleValidateFileTypes,
+		Handler: s.handleGetFileReferences,
 	})
 
 	s.registerTool(&Tool{
-		Name:        "find_undefined_usages",
-		Description: "Find all undefined symbol usages in a file (methods, functions, variables that don't exist)",
+		Name:        "get_tests_for_symbol",
+		Description: "Find the test functions that exercise a symbol, linked by naming convention (TestAdd -> Add, test_add -> add)",
 		InputSchema: map[string]interface{}{
 			"type": "object",
 			"properties": map[string]interface{}{
-				"file_path": map[string]interface{}{
+				"symbol_name": map[string]interface{}{
 					"type":        "string",
-					"description": "Path to the file to check",
+					"description": "Name of the symbol",
 				},
 			},
-			"required": []string{"file_path"},
+			"required": []string{"symbol_name"},
 		},
-		Handler: s.handleFindUndefinedUsages,
+		Handler: s.handleGetTestsForSymbol,
 	})
 
 	s.registerTool(&Tool{
-		Name:        "check_method_exists",
-		Description: "Check if a specific method exists on a type/class",
+		Name:        "get_related_symbols",
+		Description: "Find symbols related to a symbol, scored by direct relationships, calls, shared file and name/doc similarity",
 		InputSchema: map[string]interface{}{
 			"type": "object",
 			"properties": map[string]interface{}{
-				"type_name": map[string]interface{}{
+				"symbol_name": map[string]interface{}{
 					"type":        "string",
-					"description": "Name of the type/class",
+					"description": "Name of the symbol",
 				},
-				"method_name": map[string]interface{}{
-					"type":        "string",
-					"description": "Name of the method to check",
+				"limit": map[string]interface{}{
+					"type":        "number",
+					"description": "Maximum number of results (default: 10)",
 				},
 			},
-			"required": []string{"type_name", "method_name"},
+			"required": []string{"symbol_name"},
 		},
-		Handler: s.handleCheckMethodExists,
+		Handler: s.handleGetRelatedSymbols,
 	})
 
 	s.registerTool(&Tool{
-		Name:        "calculate_type_safety_score",
-		Description: "Calculate type safety score for a file (0-100, with type coverage and error metrics)",
+		Name:        "find_similar_signatures",
+		Description: "Find functions whose parameter and return types and body match a function's shape regardless of name, as candidates for extracting a shared interface",
 		InputSchema: map[string]interface{}{
 			"type": "object",
 			"properties": map[string]interface{}{
-				"file_path": map[string]interface{}{
+				"symbol_name": map[string]interface{}{
 					"type":        "string",
-					"description": "Path to the file",
+					"description": "Name of the function to compare against",
+				},
+				"threshold": map[string]interface{}{
+					"type":        "number",
+					"description": "Minimum similarity from 0 to 1, 0 to list every function (default: 0.8)",
 				},
 			},
-			"required": []string{"file_path"},
+			"required": []string{"symbol_name"},
 		},
-		Handler: s.handleCalculateTypeSafetyScore,
+		Handler: s.handleFindSimilarSignatures,
 	})
+
+	s.registerTool(&Tool{
+		Name:        "get_prompt_bundle",
+		Description: "Assemble LLM context for a symbol: its source, the source of the functions it calls and the signatures of its callers, within a character budget",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"symbol_name": map[string]interface{}{
+					"type":        "string",
+					"description": "Name of the symbol",
+				},
+				"file_path": map[string]interface{}{
+					"type":        "string",
+					"description": "File declaring the symbol, required when several files declare one with this name",
+				},
+				"max_callees": map[string]interface{}{
+					"type":        "number",
+					"description": "Maximum number of callees to include (default: 5)",
+				},
+			},
+			"required": []string{"symbol_name"},
+		},
+		Handler: s.handleGetPromptBundle,
+	})
+
+	s.registerTool(&Tool{
+		Name:        "compare_symbols",
+		Description: "Compare two symbols, such as parallel implementations: signature differences, a line-level diff of their source and a similarity score",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"symbol_a": map[string]interface{}{
+					"type":        "string",
+					"description": "Name of the first symbol",
+				},
+				"symbol_b": map[string]interface{}{
+					"type":        "string",
+					"description": "Name of the second symbol (the same name compares its first two definitions)",
+				},
+			},
+			"required": []string{"symbol_a", "symbol_b"},
+		},
+		Handler: s.handleCompareSymbols,
+	})
+
+	s.registerTool(&Tool{
+		Name:        "get_dependencies",
+		Description: "Get the project files a file imports and the files importing it, plus its imports from outside the project",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"file_path": map[string]interface{}{
+					"type":        "string",
+					"description": "Path to the file (relative or absolute)",
+				},
+			},
+			"required": []string{"file_path"},
+		},
+		Handler: s.handleGetDependencies,
+	})
+
+	s.registerTool(&Tool{
+		Name:        "get_schema",
+		Description: "Get the database schema declared in the project's SQL files: tables with their columns and foreign keys, views and indexes",
+		InputSchema: map[string]interface{}{
+			"type":       "object",
+			"properties": map[string]interface{}{},
+		},
+		Handler: s.handleGetSchema,
+	})
+
+	s.registerTool(&Tool{
+		Name:        "generate_json_schema",
+		Description: "Generate a JSON Schema describing how a Go struct or Python class serializes, from its field types and json tags; nested project types are referenced with $ref",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"type_name": map[string]interface{}{
+					"type":        "string",
+					"description": "Name of the struct or class",
+				},
+			},
+			"required": []string{"type_name"},
+		},
+		Handler: s.handleGenerateJSONSchema,
+	})
+
+	s.registerTool(&Tool{
+		Name:        "get_cross_language_bindings",
+		Description: "List calls across language boundaries: Go calling C through cgo, C calling exported Go functions, and Python calling C through ctypes or cffi",
+		InputSchema: map[string]interface{}{
+			"type":       "object",
+			"properties": map[string]interface{}{},
+		},
+		Handler: s.handleGetCrossLanguageBindings,
+	})
+
+	s.registerTool(&Tool{
+		Name:        "list_files",
+		Description: "List all indexed files in the project with their symbol counts",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"language": map[string]interface{}{
+					"type":        "string",
+					"description": "Filter by language (optional); aliases such as ts and typescriptreact are accepted",
+				},
+			},
+		},
+		Handler: s.handleListFiles,
+	})
+
+	s.registerTool(&Tool{
+		Name:        "find_orphan_files",
+		Description: "Find indexed files that produced no symbols (helps spot parser coverage gaps)",
+		InputSchema: map[string]interface{}{
+			"type":       "object",
+			"properties": map[string]interface{}{},
+		},
+		Handler: s.handleFindOrphanFiles,
+	})
+
+	s.registerTool(&Tool{
+		Name:        "get_stale_files",
+		Description: "Find indexed files whose content changed or that were deleted since they were indexed",
+		InputSchema: map[string]interface{}{
+			"type":       "object",
+			"properties": map[string]interface{}{},
+		},
+		Handler: s.handleGetStaleFiles,
+	})
+
+	s.registerTool(&Tool{
+		Name:        "get_api_surface",
+		Description: "Export the project's public API (exported symbols with signatures and documentation, grouped by file) for documentation generation",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"format": map[string]interface{}{
+					"type":        "string",
+					"enum":        []string{"json", "markdown"},
+					"description": "Output format (default: json)",
+				},
+			},
+		},
+		Handler: s.handleGetAPISurface,
+	})
+
+	s.registerTool(&Tool{
+		Name:        "get_parse_errors",
+		Description: "List files that failed to parse or were only partially parsed, with the number of symbols recovered",
+		InputSchema: map[string]interface{}{
+			"type":       "object",
+			"properties": map[string]interface{}{},
+		},
+		Handler: s.handleGetParseErrors,
+	})
+
+	s.registerTool(&Tool{
+		Name:        "get_external_dependencies",
+		Description: "List third-party packages imported by the project, with usage counts and importing files (stdlib and local imports are excluded)",
+		InputSchema: map[string]interface{}{
+			"type":       "object",
+			"properties": map[string]interface{}{},
+		},
+		Handler: s.handleGetExternalDependencies,
+	})
+
+	s.registerTool(&Tool{
+		Name:        "repair_index",
+		Description: "Repair the index by removing duplicate relationship rows",
+		InputSchema: map[string]interface{}{
+			"type":       "object",
+			"properties": map[string]interface{}{},
+		},
+		Handler: s.handleRepairIndex,
+	})
+
+	s.registerTool(&Tool{
+		Name:        "scan_secrets",
+		Description: "Scan string literals in indexed files for hardcoded credentials (AWS keys, private keys, high-entropy tokens)",
+		InputSchema: map[string]interface{}{
+			"type":       "object",
+			"properties": map[string]interface{}{},
+		},
+		Handler: s.handleScanSecrets,
+	})
+
+	s.registerTool(&Tool{
+		Name:        "detect_anti_patterns",
+		Description: "Find functions with too many parameters, deeply nested blocks or empty catch/except blocks, with a suggestion for each",
+		InputSchema: map[string]interface{}{
+			"type":       "object",
+			"properties": map[string]interface{}{},
+		},
+		Handler: s.handleDetectAntiPatterns,
+	})
+
+	s.registerTool(&Tool{
+		Name:        "diff_symbols",
+		Description: "Compare the current index against a saved snapshot, listing added, removed and modified symbols",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"snapshot_path": map[string]interface{}{
+					"type":        "string",
+					"description": "Snapshot file saved with 'code-indexer snapshot save' (default: .projectIndex/snapshot.json)",
+				},
+			},
+		},
+		Handler: s.handleDiffSymbols,
+	})
+
+	s.registerTool(&Tool{
+		Name:        "get_capabilities",
+		Description: "List the supported languages with their file extensions, parser priority and framework support",
+		InputSchema: map[string]interface{}{
+			"type":       "object",
+			"properties": map[string]interface{}{},
+		},
+		Handler: s.handleGetCapabilities,
+	})
+
+	// AI-powered tools
+	s.registerTool(&Tool{
+		Name:        "get_code_context",
+		Description: "Get comprehensive context for a symbol including usage examples, dependencies, and relationships",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"symbol_name": map[string]interface{}{
+					"type":        "string",
+					"description": "Name of the symbol",
+				},
+				"depth": map[string]interface{}{
+					"type":        "number",
+					"description": "Context depth (number of usage examples, default: 5)",
+				},
+			},
+			"required": []string{"symbol_name"},
+		},
+		Handler: s.handleGetCodeContext,
+	})
+
+	s.registerTool(&Tool{
+		Name:        "analyze_change_impact",
+		Description: "Analyze the impact of changing or refactoring a symbol (risk level, affected files, suggestions)",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"symbol_name": map[string]interface{}{
+					"type":        "string",
+					"description": "Name of the symbol to analyze",
+				},
+			},
+			"required": []string{"symbol_name"},
+		},
+		Handler: s.handleAnalyzeChangeImpact,
+	})
+
+	s.registerTool(&Tool{
+		Name:        "get_code_metrics",
+		Description: "Calculate code quality metrics (complexity, maintainability, quality rating)",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"symbol_name": map[string]interface{}{
+					"type":        "string",
+					"description": "Name of the symbol (function/method)",
+				},
+			},
+			"required": []string{"symbol_name"},
+		},
+		Handler: s.handleGetCodeMetrics,
+	})
+
+	s.registerTool(&Tool{
+		Name:        "get_complexity_trend",
+		Description: "Get a function's cyclomatic complexity at each recorded version, to see whether it is getting more complex over time",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"symbol_name": map[string]interface{}{
+					"type":        "string",
+					"description": "Name of the function or method",
+				},
+				"file_path": map[string]interface{}{
+					"type":        "string",
+					"description": "File declaring the function, required when several files declare one with this name",
+				},
+			},
+			"required": []string{"symbol_name"},
+		},
+		Handler: s.handleGetComplexityTrend,
+	})
+
+	s.registerTool(&Tool{
+		Name:        "rank_complexity",
+		Description: "List the most complex functions and methods in the project, ordered by cyclomatic complexity",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"limit": map[string]interface{}{
+					"type":        "number",
+					"description": "Maximum number of results (default: 25)",
+				},
+				"min_complexity": map[string]interface{}{
+					"type":        "number",
+					"description": "Only include functions with at least this cyclomatic complexity",
+				},
+			},
+		},
+		Handler: s.handleRankComplexity,
+	})
+
+	s.registerTool(&Tool{
+		Name:        "get_complexity_distribution",
+		Description: "Count the project's functions and methods by cyclomatic complexity (1-5, 6-10, 11-20, 21+), with the most complex examples in each range",
+		InputSchema: map[string]interface{}{
+			"type":       "object",
+			"properties": map[string]interface{}{},
+		},
+		Handler: s.handleGetComplexityDistribution,
+	})
+
+	s.registerTool(&Tool{
+		Name:        "get_churn",
+		Description: "Rank files by git commit frequency weighted by complexity, to find high-churn, high-complexity refactoring candidates (empty if the project is not a git repository)",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"limit": map[string]interface{}{
+					"type":        "number",
+					"description": "Maximum number of results (default: 25)",
+				},
+			},
+		},
+		Handler: s.handleGetChurn,
+	})
+
+	s.registerTool(&Tool{
+		Name:        "get_ownership",
+		Description: "Get who wrote a symbol's lines according to git blame: top contributors and the last author to modify it, for routing code review (empty with git's error as a warning if blame fails, e.g. outside a git repository)",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"symbol_name": map[string]interface{}{
+					"type":        "string",
+					"description": "Name of the symbol",
+				},
+				"file_path": map[string]interface{}{
+					"type":        "string",
+					"description": "File declaring the symbol, when several files declare the name",
+				},
+			},
+			"required": []string{"symbol_name"},
+		},
+		Handler: s.handleGetOwnership,
+	})
+
+	s.registerTool(&Tool{
+		Name:        "extract_smart_snippet",
+		Description: "Extract a self-contained code snippet with all dependencies and usage hints",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"symbol_name": map[string]interface{}{
+					"type":        "string",
+					"description": "Name of the symbol to extract",
+				},
+			},
+			"required": []string{"symbol_name"},
+		},
+		Handler: s.handleExtractSmartSnippet,
+	})
+
+	s.registerTool(&Tool{
+		Name:        "get_usage_statistics",
+		Description: "Get detailed usage statistics for a symbol (usage count, patterns, files)",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"symbol_name": map[string]interface{}{
+					"type":        "string",
+					"description": "Name of the symbol",
+				},
+			},
+			"required": []string{"symbol_name"},
+		},
+		Handler: s.handleGetUsageStatistics,
+	})
+
+	s.registerTool(&Tool{
+		Name:        "get_usage_examples",
+		Description: "Get examples of a symbol in use: the source line of each distinct call site, with the surrounding lines as context",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"symbol_name": map[string]interface{}{
+					"type":        "string",
+					"description": "Name of the symbol",
+				},
+				"limit": map[string]interface{}{
+					"type":        "number",
+					"description": "Maximum number of examples (default: 10)",
+				},
+			},
+			"required": []string{"symbol_name"},
+		},
+		Handler: s.handleGetUsageExamples,
+	})
+
+	s.registerTool(&Tool{
+		Name:        "suggest_refactorings",
+		Description: "Get AI-powered refactoring suggestions for a symbol, including extract-method ranges for long functions",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"symbol_name": map[string]interface{}{
+					"type":        "string",
+					"description": "Name of the symbol",
+				},
+			},
+			"required": []string{"symbol_name"},
+		},
+		Handler: s.handleSuggestRefactorings,
+	})
+
+	s.registerTool(&Tool{
+		Name:        "analyze_project",
+		Description: "Run semantic analysis over the whole project: type errors, undefined references, unused symbols and circular dependencies",
+		InputSchema: map[string]interface{}{
+			"type":       "object",
+			"properties": map[string]interface{}{},
+		},
+		Handler: s.handleAnalyzeProject,
+	})
+
+	s.registerTool(&Tool{
+		Name:        "find_unused_symbols",
+		Description: "Find unused/dead code in the project",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"exclude_tests": map[string]interface{}{
+					"type":        "boolean",
+					"description": "Don't count references from test files as uses, and skip symbols declared in tests",
+				},
+			},
+		},
+		ContextHandler: s.handleFindUnusedSymbols,
+	})
+
+	s.registerTool(&Tool{
+		Name:        "find_god_objects",
+		Description: "Find classes and structs with too many methods or fields",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"method_threshold": map[string]interface{}{
+					"type":        "number",
+					"description": "Flag types with more methods than this (default: 20)",
+				},
+				"field_threshold": map[string]interface{}{
+					"type":        "number",
+					"description": "Flag types with more fields than this (default: 15)",
+				},
+			},
+		},
+		Handler: s.handleFindGodObjects,
+	})
+
+	s.registerTool(&Tool{
+		Name:        "find_large_symbols",
+		Description: "Find functions, classes and other symbols spanning many lines, longest first, with each one's line count",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"min_lines": map[string]interface{}{
+					"type":        "number",
+					"description": "Flag symbols longer than this many lines (default: 100)",
+				},
+			},
+		},
+		Handler: s.handleFindLargeSymbols,
+	})
+
+	s.registerTool(&Tool{
+		Name:        "check_naming",
+		Description: "Find symbols whose names break their language's naming conventions (e.g. snake_case Go functions), with the expected name",
+		InputSchema: map[string]interface{}{
+			"type":       "object",
+			"properties": map[string]interface{}{},
+		},
+		Handler: s.handleCheckNaming,
+	})
+
+	// Development workflow tools
+	s.registerTool(&Tool{
+		Name:        "set_symbol_status",
+		Description: "Set the development status of a symbol (not_started, in_progress, needs_review, completed, blocked)",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"symbol_name": map[string]interface{}{
+					"type":        "string",
+					"description": "Name of the symbol",
+				},
+				"status": map[string]interface{}{
+					"type":        "string",
+					"description": "New status",
+					"enum":        []string{"not_started", "in_progress", "needs_review", "completed", "blocked"},
+				},
+			},
+			"required": []string{"symbol_name", "status"},
+		},
+		Handler: s.handleSetSymbolStatus,
+	})
+
+	s.registerTool(&Tool{
+		Name:        "assign_symbol",
+		Description: "Record which agent is working on a symbol (empty agent clears the assignment)",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"symbol_name": map[string]interface{}{
+					"type":        "string",
+					"description": "Name of the symbol",
+				},
+				"agent": map[string]interface{}{
+					"type":        "string",
+					"description": "Agent identifier",
+				},
+			},
+			"required": []string{"symbol_name", "agent"},
+		},
+		Handler: s.handleAssignSymbol,
+	})
+
+	s.registerTool(&Tool{
+		Name:        "get_symbols_by_status",
+		Description: "List symbols with a given development status, highest priority first",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"status": map[string]interface{}{
+					"type":        "string",
+					"description": "Status to filter by",
+					"enum":        []string{"not_started", "in_progress", "needs_review", "completed", "blocked"},
+				},
+			},
+			"required": []string{"status"},
+		},
+		Handler: s.handleGetSymbolsByStatus,
+	})
+
+	s.registerTool(&Tool{
+		Name:        "set_symbol_priority",
+		Description: "Set the priority of a symbol (0-100), which orders the work queue",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"symbol_name": map[string]interface{}{
+					"type":        "string",
+					"description": "Name of the symbol",
+				},
+				"priority": map[string]interface{}{
+					"type":        "integer",
+					"description": "Priority from 0 (lowest, the default) to 100",
+					"minimum":     0,
+					"maximum":     100,
+				},
+			},
+			"required": []string{"symbol_name", "priority"},
+		},
+		Handler: s.handleSetSymbolPriority,
+	})
+
+	s.registerTool(&Tool{
+		Name:        "get_work_queue",
+		Description: "Get the next symbols for an agent to pick up: symbols with a status, highest priority first",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"status": map[string]interface{}{
+					"type":        "string",
+					"description": "Status to filter by (default: not_started)",
+					"enum":        []string{"not_started", "in_progress", "needs_review", "completed", "blocked"},
+				},
+				"limit": map[string]interface{}{
+					"type":        "integer",
+					"description": "Maximum number of symbols (default: 20)",
+				},
+			},
+		},
+		Handler: s.handleGetWorkQueue,
+	})
+
+	// Change tracking tools
+	s.registerTool(&Tool{
+		Name:        "simulate_change",
+		Description: "Simulate a code change and see its impact before applying it (shows affected files, broken references, auto-fix suggestions)",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"symbol_name": map[string]interface{}{
+					"type":        "string",
+					"description": "Name of the symbol to change",
+				},
+				"change_type": map[string]interface{}{
+					"type":        "string",
+					"description": "Type of change: add, modify, delete, rename, move",
+				},
+				"new_value": map[string]interface{}{
+					"type":        "string",
+					"description": "New value (e.g., new name for rename, new signature for modify)",
+				},
+			},
+			"required": []string{"symbol_name", "change_type"},
+		},
+		Handler: s.handleSimulateChange,
+	})
+
+	s.registerTool(&Tool{
+		Name:        "rename_symbol",
+		Description: "Plan renaming a symbol: lists the edits needed and flags files where the new name already exists. Only dry_run mode is supported; no files are changed",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"symbol_name": map[string]interface{}{
+					"type":        "string",
+					"description": "Name of the symbol to rename",
+				},
+				"new_name": map[string]interface{}{
+					"type":        "string",
+					"description": "New name for the symbol",
+				},
+				"dry_run": map[string]interface{}{
+					"type":        "boolean",
+					"description": "Preview the rename without applying it (default: true)",
+				},
+			},
+			"required": []string{"symbol_name", "new_name"},
+		},
+		Handler: s.handleRenameSymbol,
+	})
+
+	s.registerTool(&Tool{
+		Name:        "build_dependency_graph",
+		Description: "Build a dependency graph for a symbol showing what it depends on and what depends on it",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"symbol_name": map[string]interface{}{
+					"type":        "string",
+					"description": "Name of the symbol",
+				},
+				"max_depth": map[string]interface{}{
+					"type":        "number",
+					"description": "Maximum depth to traverse (default: 3)",
+				},
+			},
+			"required": []string{"symbol_name"},
+		},
+		ContextHandler: s.handleBuildDependencyGraph,
+	})
+
+	s.registerTool(&Tool{
+		Name:        "get_symbol_dependencies",
+		Description: "Get all symbols that a given symbol depends on",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"symbol_name": map[string]interface{}{
+					"type":        "string",
+					"description": "Name of the symbol",
+				},
+			},
+			"required": []string{"symbol_name"},
+		},
+		Handler: s.handleGetSymbolDependencies,
+	})
+
+	s.registerTool(&Tool{
+		Name:        "get_symbol_dependents",
+		Description: "Get all symbols that depend on a given symbol",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"symbol_name": map[string]interface{}{
+					"type":        "string",
+					"description": "Name of the symbol",
+				},
+			},
+			"required": []string{"symbol_name"},
+		},
+		Handler: s.handleGetSymbolDependents,
+	})
+
+	s.registerTool(&Tool{
+		Name:        "get_public_callers",
+		Description: "Get the exported symbols that depend on a given symbol, the blast radius of changing it across package boundaries",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"symbol_name": map[string]interface{}{
+					"type":        "string",
+					"description": "Name of the symbol",
+				},
+			},
+			"required": []string{"symbol_name"},
+		},
+		Handler: s.handleGetPublicCallers,
+	})
+
+	s.registerTool(&Tool{
+		Name:        "get_coupling_hotspots",
+		Description: "Find the most coupled symbols in the project, with afferent (Ca) and efferent (Ce) coupling and instability Ce/(Ca+Ce)",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"limit": map[string]interface{}{
+					"type":        "number",
+					"description": "Maximum number of results (default: 20)",
+				},
+			},
+		},
+		Handler: s.handleGetCouplingHotspots,
+	})
+
+	// Type validation tools
+	s.registerTool(&Tool{
+		Name:        "validate_file_types",
+		Description: "Validate all types in a file and find undefined usages, type mismatches, and missing methods",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"file_path": map[string]interface{}{
+					"type":        "string",
+					"description": "Path to the file to validate",
+				},
+			},
+			"required": []string{"file_path"},
+		},
+		Handler: s.handleValidateFileTypes,
+	})
+
+	s.registerTool(&Tool{
+		Name:        "find_undefined_usages",
+		Description: "Find all undefined symbol usages in a file (methods, functions, variables that don't exist)",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"file_path": map[string]interface{}{
+					"type":        "string",
+					"description": "Path to the file to check",
+				},
+			},
+			"required": []string{"file_path"},
+		},
+		Handler: s.handleFindUndefinedUsages,
+	})
+
+	s.registerTool(&Tool{
+		Name:        "check_method_exists",
+		Description: "Check if a specific method exists on a type/class",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"type_name": map[string]interface{}{
+					"type":        "string",
+					"description": "Name of the type/class",
+				},
+				"method_name": map[string]interface{}{
+					"type":        "string",
+					"description": "Name of the method to check",
+				},
+			},
+			"required": []string{"type_name", "method_name"},
+		},
+		Handler: s.handleCheckMethodExists,
+	})
+
+	s.registerTool(&Tool{
+		Name:        "check_conformance",
+		Description: "Check whether a type implements an interface, listing present, missing and mismatched methods",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"type_name": map[string]interface{}{
+					"type":        "string",
+					"description": "Name of the concrete type",
+				},
+				"interface_name": map[string]interface{}{
+					"type":        "string",
+					"description": "Name of the interface",
+				},
+			},
+			"required": []string{"type_name", "interface_name"},
+		},
+		Handler: s.handleCheckConformance,
+	})
+
+	s.registerTool(&Tool{
+		Name:        "calculate_type_safety_score",
+		Description: "Calculate type safety score for a file (0-100, with type coverage and error metrics)",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"file_path": map[string]interface{}{
+					"type":        "string",
+					"description": "Path to the file",
+				},
+			},
+			"required": []string{"file_path"},
+		},
+		Handler: s.handleCalculateTypeSafetyScore,
+	})
+}
+
+// registerTool registers a tool
+func (s *Server) registerTool(tool *Tool) {
+	s.tools[tool.Name] = tool
+}
+
+// Tool handlers
+
+func (s *Server) handleSearchSymbols(params json.RawMessage) (interface{}, error) {
+	var opts types.SearchOptions
+	if err := json.Unmarshal(params, &opts); err != nil {
+		return nil, err
+	}
+
+	symbols, err := s.indexer.SearchSymbols(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]interface{}{
+		"symbols": symbols,
+		"count":   len(symbols),
+	}, nil
+}
+
+func (s *Server) handleSearchInFile(params json.RawMessage) (interface{}, error) {
+	var req struct {
+		FilePath string `json:"file_path"`
+		Query    string `json:"query"`
+	}
+
+	if err := json.Unmarshal(params, &req); err != nil {
+		return nil, err
+	}
+
+	symbols, err := s.indexer.SearchSymbolsInFile(req.FilePath, req.Query)
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]interface{}{
+		"symbols": symbols,
+		"count":   len(symbols),
+	}, nil
+}
+
+func (s *Server) handleGetImportUsage(params json.RawMessage) (interface{}, error) {
+	var req struct {
+		FilePath string `json:"file_path"`
+	}
+
+	if err := json.Unmarshal(params, &req); err != nil {
+		return nil, err
+	}
+
+	usages, err := s.indexer.GetImportUsage(req.FilePath)
+	if err != nil {
+		return nil, err
+	}
+
+	unused := 0
+	for _, usage := range usages {
+		if usage.Unused {
+			unused++
+		}
+	}
+
+	return map[string]interface{}{
+		"file":    req.FilePath,
+		"imports": usages,
+		"unused":  unused,
+		"count":   len(usages),
+	}, nil
+}
+
+func (s *Server) handleQuerySymbols(params json.RawMessage) (interface{}, error) {
+	var filter types.SymbolFilter
+
+	if len(params) > 0 {
+		if err := json.Unmarshal(params, &filter); err != nil {
+			return nil, err
+		}
+	}
+
+	symbols, err := s.indexer.QuerySymbols(&filter)
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]interface{}{
+		"symbols": symbols,
+		"count":   len(symbols),
+	}, nil
+}
+
+func (s *Server) handleGetFileStructure(params json.RawMessage) (interface{}, error) {
+	var req struct {
+		FilePath string            `json:"file_path"`
+		Order    types.SymbolOrder `json:"order"`
+		Nested   bool              `json:"nested"`
+	}
+
+	if err := json.Unmarshal(params, &req); err != nil {
+		return nil, err
+	}
+
+	structure, err := s.indexer.GetFileStructureWithOptions(req.FilePath, types.FileStructureOptions{
+		Order:  req.Order,
+		Nested: req.Nested,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return structure, nil
+}
+
+func (s *Server) handleGetNamespaceTree(params json.RawMessage) (interface{}, error) {
+	return s.indexer.GetNamespaceTree()
+}
+
+func (s *Server) handleGetEntryPoints(params json.RawMessage) (interface{}, error) {
+	entryPoints, err := s.indexer.FindEntryPoints()
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]interface{}{
+		"entry_points": entryPoints,
+		"count":        len(entryPoints),
+	}, nil
+}
+
+func (s *Server) handleFindRecursive(params json.RawMessage) (interface{}, error) {
+	functions, err := s.indexer.FindRecursiveFunctions()
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]interface{}{
+		"functions": functions,
+		"count":     len(functions),
+	}, nil
+}
+
+func (s *Server) handleGetSymbolsInRange(params json.RawMessage) (interface{}, error) {
+	var req struct {
+		FilePath  string `json:"file_path"`
+		StartLine int    `json:"start_line"`
+		EndLine   int    `json:"end_line"`
+	}
+
+	if err := json.Unmarshal(params, &req); err != nil {
+		return nil, err
+	}
+
+	symbols, err := s.indexer.GetSymbolsInRange(req.FilePath, req.StartLine, req.EndLine)
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]interface{}{
+		"file":    req.FilePath,
+		"symbols": symbols,
+		"count":   len(symbols),
+	}, nil
+}
+
+func (s *Server) handleGetProjectOverview(params json.RawMessage) (interface{}, error) {
+	var opts types.ProjectOverviewOptions
+
+	if len(params) > 0 {
+		if err := json.Unmarshal(params, &opts); err != nil {
+			return nil, err
+		}
+	}
+
+	overview, err := s.indexer.GetProjectOverviewWithOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return overview, nil
+}
+
+func (s *Server) handleIndexProject(ctx context.Context, params json.RawMessage) (interface{}, error) {
+	if err := s.indexer.IndexAllContext(ctx); err != nil {
+		return nil, err
+	}
+
+	return map[string]interface{}{
+		"status":  "success",
+		"message": "Project indexed successfully",
+	}, nil
+}
+
+func (s *Server) handleGetSymbolDetails(params json.RawMessage) (interface{}, error) {
+	var req struct {
+		SymbolName string `json:"symbol_name"`
+	}
+
+	if err := json.Unmarshal(params, &req); err != nil {
+		return nil, err
+	}
+
+	details, err := s.indexer.GetSymbolDetails(req.SymbolName)
+	if err != nil {
+		return nil, err
+	}
+
+	return details, nil
+}
+
+func (s *Server) handleBulkGetSymbols(params json.RawMessage) (interface{}, error) {
+	var req struct {
+		SymbolNames []string `json:"symbol_names"`
+	}
+
+	if err := json.Unmarshal(params, &req); err != nil {
+		return nil, err
+	}
+
+	details, err := s.indexer.BulkGetSymbols(req.SymbolNames)
+	if err != nil {
+		return nil, err
+	}
+
+	notFound := []string{}
+	seen := make(map[string]bool)
+	for _, name := range req.SymbolNames {
+		if details[name] == nil && !seen[name] {
+			notFound = append(notFound, name)
+		}
+		seen[name] = true
+	}
+
+	return map[string]interface{}{
+		"symbols":   details,
+		"not_found": notFound,
+		"count":     len(details) - len(notFound),
+	}, nil
+}
+
+func (s *Server) handleResolveReference(params json.RawMessage) (interface{}, error) {
+	var req struct {
+		FilePath string `json:"file_path"`
+		Line     int    `json:"line"`
+		Column   int    `json:"column"`
+	}
+
+	if err := json.Unmarshal(params, &req); err != nil {
+		return nil, err
+	}
+
+	symbol, err := s.indexer.ResolveReference(req.FilePath, req.Line, req.Column)
+	if err != nil {
+		return nil, err
+	}
+
+	details, err := s.indexer.GetSymbolDetailsByID(symbol.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := map[string]interface{}{
+		"symbol":     symbol,
+		"start_line": symbol.StartLine,
+		"end_line":   symbol.EndLine,
+	}
+	if details.File != nil {
+		result["file_path"] = details.File.RelativePath
+	}
+	return result, nil
+}
+
+func (s *Server) handleGetSymbolByID(params json.RawMessage) (interface{}, error) {
+	var req struct {
+		ID float64 `json:"id"` // Some clients send integers as floats
+	}
+
+	if err := json.Unmarshal(params, &req); err != nil {
+		return nil, err
+	}
+
+	id := int64(req.ID)
+	if id <= 0 || float64(id) != req.ID {
+		return nil, fmt.Errorf("invalid symbol id: %v", req.ID)
+	}
+
+	return s.indexer.GetSymbolDetailsByID(id)
+}
+
+func (s *Server) handleGetSignature(params json.RawMessage) (interface{}, error) {
+	var req struct {
+		SymbolName string `json:"symbol_name"`
+	}
+
+	if err := json.Unmarshal(params, &req); err != nil {
+		return nil, err
+	}
+
+	signatures, err := s.indexer.GetSignatures(req.SymbolName)
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]interface{}{
+		"signatures": signatures,
+		"count":      len(signatures),
+	}, nil
+}
+
+func (s *Server) handleGetParameters(params json.RawMessage) (interface{}, error) {
+	var req struct {
+		SymbolName string `json:"symbol_name"`
+	}
+
+	if err := json.Unmarshal(params, &req); err != nil {
+		return nil, err
+	}
+
+	parameters, err := s.indexer.GetParameters(req.SymbolName)
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]interface{}{
+		"symbol_name": req.SymbolName,
+		"parameters":  parameters,
+		"count":       len(parameters),
+	}, nil
+}
+
+func (s *Server) handleFindSymbolsByAnnotation(params json.RawMessage) (interface{}, error) {
+	var req struct {
+		Annotation string `json:"annotation"`
+	}
+
+	if err := json.Unmarshal(params, &req); err != nil {
+		return nil, err
+	}
+
+	symbols, err := s.indexer.FindSymbolsByAnnotation(req.Annotation)
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]interface{}{
+		"annotation": req.Annotation,
+		"symbols":    symbols,
+		"count":      len(symbols),
+	}, nil
+}
+
+func (s *Server) handleFindDeprecated(params json.RawMessage) (interface{}, error) {
+	symbols, err := s.indexer.FindDeprecatedSymbols()
+	if err != nil {
+		return nil, err
+	}
+
+	deprecated := make([]map[string]interface{}, 0, len(symbols))
+	for _, symbol := range symbols {
+		usages, err := s.indexer.GetSymbolReferences(symbol.ID)
+		if err != nil {
+			return nil, err
+		}
+		deprecated = append(deprecated, map[string]interface{}{
+			"symbol":      symbol,
+			"usages":      usages,
+			"usage_count": len(usages),
+		})
+	}
+
+	return map[string]interface{}{
+		"symbols": deprecated,
+		"count":   len(deprecated),
+	}, nil
+}
+
+func (s *Server) handleGetSymbolSource(params json.RawMessage) (interface{}, error) {
+	var req struct {
+		SymbolName string `json:"symbol_name"`
+	}
+
+	if err := json.Unmarshal(params, &req); err != nil {
+		return nil, err
+	}
+
+	return s.indexer.GetSymbolSourceDetails(req.SymbolName)
+}
+
+func (s *Server) handleFindReferences(params json.RawMessage) (interface{}, error) {
+	var req struct {
+		SymbolName string `json:"symbol_name"`
+	}
+
+	if err := json.Unmarshal(params, &req); err != nil {
+		return nil, err
+	}
+
+	references, err := s.indexer.FindReferences(req.SymbolName)
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]interface{}{
+		"symbol":     req.SymbolName,
+		"references": references,
+		"count":      len(references),
+	}, nil
+}
+
+func (s *Server) handleFindReferencesGrouped(params json.RawMessage) (interface{}, error) {
+	var req struct {
+		SymbolName string `json:"symbol_name"`
+	}
+
+	if err := json.Unmarshal(params, &req); err != nil {
+		return nil, err
+	}
+
+	grouped, err := s.indexer.GetReferencesGrouped(req.SymbolName)
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[string]int, len(grouped))
+	total := 0
+	for path, refs := range grouped {
+		counts[path] = len(refs)
+		total += len(refs)
+	}
+
+	return map[string]interface{}{
+		"symbol":      req.SymbolName,
+		"files":       grouped,
+		"file_counts": counts,
+		"count":       total,
+	}, nil
+}
+
+func (s *Server) handleGetFilesTouchingSymbol(params json.RawMessage) (interface{}, error) {
+	var req struct {
+		SymbolName string `json:"symbol_name"`
+	}
+
+	if err := json.Unmarshal(params, &req); err != nil {
+		return nil, err
+	}
+
+	files, err := s.indexer.GetFilesTouchingSymbol(req.SymbolName)
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]interface{}{
+		"symbol": req.SymbolName,
+		"files":  files,
+		"count":  len(files),
+	}, nil
+}
+
+func (s *Server) handleGetFileReferences(params json.RawMessage) (interface{}, error) {
+	var req struct {
+		FilePath string `json:"file_path"`
+	}
+
+	if err := json.Unmarshal(params, &req); err != nil {
+		return nil, err
+	}
+
+	references, err := s.indexer.GetFileReferences(req.FilePath)
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]interface{}{
+		"file":       req.FilePath,
+		"references": references,
+		"count":      len(references),
+	}, nil
+}
+
+func (s *Server) handleGetTestsForSymbol(params json.RawMessage) (interface{}, error) {
+	var req struct {
+		SymbolName string `json:"symbol_name"`
+	}
+
+	if err := json.Unmarshal(params, &req); err != nil {
+		return nil, err
+	}
+
+	tests, err := s.indexer.GetTestsForSymbol(req.SymbolName)
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]interface{}{
+		"symbol": req.SymbolName,
+		"tests":  tests,
+		"count":  len(tests),
+	}, nil
+}
+
+func (s *Server) handleGetRelatedSymbols(params json.RawMessage) (interface{}, error) {
+	var req struct {
+		SymbolName string `json:"symbol_name"`
+		Limit      int    `json:"limit"`
+	}
+
+	if err := json.Unmarshal(params, &req); err != nil {
+		return nil, err
+	}
+
+	related, err := s.indexer.GetRelatedSymbols(req.SymbolName, req.Limit)
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]interface{}{
+		"symbol":  req.SymbolName,
+		"related": related,
+		"count":   len(related),
+	}, nil
+}
+
+func (s *Server) handleFindSimilarSignatures(params json.RawMessage) (interface{}, error) {
+	var req struct {
+		SymbolName string   `json:"symbol_name"`
+		Threshold  *float64 `json:"threshold"`
+	}
+
+	if err := json.Unmarshal(params, &req); err != nil {
+		return nil, err
+	}
+
+	threshold := core.DefaultSignatureSimilarity
+	if req.Threshold != nil {
+		threshold = *req.Threshold
+	}
+	similar, err := s.indexer.FindSimilarSignatures(req.SymbolName, threshold)
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]interface{}{
+		"symbol":  req.SymbolName,
+		"similar": similar,
+		"count":   len(similar),
+	}, nil
+}
+
+func (s *Server) handleGetPromptBundle(params json.RawMessage) (interface{}, error) {
+	var req struct {
+		SymbolName string `json:"symbol_name"`
+		FilePath   string `json:"file_path"`
+		MaxCallees int    `json:"max_callees"`
+	}
+
+	if err := json.Unmarshal(params, &req); err != nil {
+		return nil, err
+	}
+
+	return s.indexer.GetPromptBundle(req.SymbolName, req.FilePath, req.MaxCallees)
+}
+
+func (s *Server) handleCompareSymbols(params json.RawMessage) (interface{}, error) {
+	var req struct {
+		SymbolA string `json:"symbol_a"`
+		SymbolB string `json:"symbol_b"`
+	}
+
+	if err := json.Unmarshal(params, &req); err != nil {
+		return nil, err
+	}
+
+	return s.indexer.CompareSymbols(req.SymbolA, req.SymbolB)
+}
+
+func (s *Server) handleGetDependencies(params json.RawMessage) (interface{}, error) {
+	var req struct {
+		FilePath string `json:"file_path"`
+	}
+
+	if err := json.Unmarshal(params, &req); err != nil {
+		return nil, err
+	}
+
+	return s.indexer.GetFileDependencies(req.FilePath)
+}
+
+func (s *Server) handleGetSchema(params json.RawMessage) (interface{}, error) {
+	return s.indexer.GetSchema()
+}
+
+func (s *Server) handleGenerateJSONSchema(params json.RawMessage) (interface{}, error) {
+	var req struct {
+		TypeName string `json:"type_name"`
+	}
+
+	if err := json.Unmarshal(params, &req); err != nil {
+		return nil, err
+	}
+
+	return s.indexer.GenerateJSONSchema(req.TypeName)
+}
+
+func (s *Server) handleListFiles(params json.RawMessage) (interface{}, error) {
+	var req struct {
+		Language string `json:"language"`
+	}
+
+	if err := json.Unmarshal(params, &req); err != nil {
+		return nil, err
+	}
+
+	files, err := s.indexer.GetFileSummaries()
+	if err != nil {
+		return nil, err
+	}
+
+	// Filter by language if specified, accepting aliases such as LSP languageIds
+	if req.Language != "" {
+		language := parser.CanonicalLanguage(req.Language)
+		filtered := []*types.FileSummary{}
+		for _, file := range files {
+			if file.Language == language {
+				filtered = append(filtered, file)
+			}
+		}
+		files = filtered
+	}
+
+	return map[string]interface{}{
+		"files": files,
+		"count": len(files),
+	}, nil
+}
+
+func (s *Server) handleFindOrphanFiles(params json.RawMessage) (interface{}, error) {
+	files, err := s.indexer.FindOrphanFiles()
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]interface{}{
+		"files": files,
+		"count": len(files),
+	}, nil
+}
+
+func (s *Server) handleGetStaleFiles(params json.RawMessage) (interface{}, error) {
+	files, err := s.indexer.GetStaleFiles()
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]interface{}{
+		"files": files,
+		"count": len(files),
+	}, nil
+}
+
+func (s *Server) handleGetAPISurface(params json.RawMessage) (interface{}, error) {
+	var req struct {
+		Format string `json:"format"`
+	}
+
+	if err := json.Unmarshal(params, &req); err != nil {
+		return nil, err
+	}
+
+	surface, err := s.indexer.GetAPISurface()
+	if err != nil {
+		return nil, err
+	}
+
+	switch req.Format {
+	case "", "json":
+		return surface, nil
+	case "markdown":
+		return core.RenderAPISurfaceMarkdown(surface), nil
+	default:
+		return nil, fmt.Errorf("unknown format: %s", req.Format)
+	}
+}
+
+func (s *Server) handleGetParseErrors(params json.RawMessage) (interface{}, error) {
+	parseErrors, err := s.indexer.GetParseErrors()
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]interface{}{
+		"errors": parseErrors,
+		"count":  len(parseErrors),
+	}, nil
+}
+
+func (s *Server) handleGetCrossLanguageBindings(params json.RawMessage) (interface{}, error) {
+	bindings, err := s.indexer.GetCrossLanguageBindings()
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]interface{}{
+		"bindings": bindings,
+		"count":    len(bindings),
+	}, nil
+}
+
+func (s *Server) handleGetExternalDependencies(params json.RawMessage) (interface{}, error) {
+	deps, err := s.indexer.GetExternalDependencies()
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]interface{}{
+		"dependencies": deps,
+		"count":        len(deps),
+	}, nil
+}
+
+func (s *Server) handleRepairIndex(params json.RawMessage) (interface{}, error) {
+	return s.indexer.RepairIndex()
+}
+
+func (s *Server) handleScanSecrets(params json.RawMessage) (interface{}, error) {
+	findings, err := s.indexer.ScanSecrets()
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]interface{}{
+		"findings": findings,
+		"count":    len(findings),
+	}, nil
+}
+
+func (s *Server) handleDetectAntiPatterns(params json.RawMessage) (interface{}, error) {
+	findings, err := s.indexer.DetectAntiPatterns()
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]interface{}{
+		"findings": findings,
+		"count":    len(findings),
+	}, nil
+}
+
+func (s *Server) handleDiffSymbols(params json.RawMessage) (interface{}, error) {
+	var req struct {
+		SnapshotPath string `json:"snapshot_path"`
+	}
+
+	if err := json.Unmarshal(params, &req); err != nil {
+		return nil, err
+	}
+
+	if req.SnapshotPath == "" {
+		req.SnapshotPath = s.indexer.SnapshotPath()
+	}
+
+	snap, err := core.LoadSnapshot(req.SnapshotPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load snapshot: %w", err)
+	}
+
+	diff, err := s.indexer.DiffAgainstSnapshot(snap)
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]interface{}{
+		"snapshot_created_at": snap.CreatedAt,
+		"diff":                diff,
+		"count":               len(diff.Added) + len(diff.Removed) + len(diff.Modified),
+	}, nil
+}
+
+func (s *Server) handleGetCapabilities(params json.RawMessage) (interface{}, error) {
+	parsers := s.indexer.ListParsers()
+
+	return map[string]interface{}{
+		"languages": parsers,
+		"count":     len(parsers),
+	}, nil
+}
+
+// AI-powered tool handlers
+
+func (s *Server) handleGetCodeContext(params json.RawMessage) (interface{}, error) {
+	var req struct {
+		SymbolName string `json:"symbol_name"`
+		Depth      int    `json:"depth"`
+	}
+
+	if err := json.Unmarshal(params, &req); err != nil {
+		return nil, err
+	}
+
+	if req.Depth == 0 {
+		req.Depth = 5 // Default depth
+	}
+
+	context, err := s.indexer.GetCodeContext(req.SymbolName, req.Depth)
+	if err != nil {
+		return nil, err
+	}
+
+	return context, nil
+}
+
+func (s *Server) handleAnalyzeChangeImpact(params json.RawMessage) (interface{}, error) {
+	var req struct {
+		SymbolName string `json:"symbol_name"`
+	}
+
+	if err := json.Unmarshal(params, &req); err != nil {
+		return nil, err
+	}
+
+	impact, err := s.indexer.AnalyzeChangeImpact(req.SymbolName)
+	if err != nil {
+		return nil, err
+	}
+
+	return impact, nil
 }
 
-// registerTool registers a tool
-func (s *Server) registerTool(tool *Tool) {
-	s.tools[tool.Name] = tool
+func (s *Server) handleGetCodeMetrics(params json.RawMessage) (interface{}, error) {
+	var req struct {
+		SymbolName string `json:"symbol_name"`
+	}
+
+	if err := json.Unmarshal(params, &req); err != nil {
+		return nil, err
+	}
+
+	metrics, err := s.indexer.GetCodeMetrics(req.SymbolName)
+	if err != nil {
+		return nil, err
+	}
+
+	return metrics, nil
 }
 
-// Tool handlers
+func (s *Server) handleGetComplexityTrend(params json.RawMessage) (interface{}, error) {
+	var req struct {
+		SymbolName string `json:"symbol_name"`
+		FilePath   string `json:"file_path"`
+	}
 
-func (s *Server) handleSearchSymbols(params json.RawMessage) (interface{}, error) {
-	var opts types.SearchOptions
-	if err := json.Unmarshal(params, &opts); err != nil {
+	if err := json.Unmarshal(params, &req); err != nil {
 		return nil, err
 	}
 
-	symbols, err := s.indexer.SearchSymbols(opts)
+	trend, err := s.indexer.GetComplexityTrend(req.SymbolName, req.FilePath)
 	if err != nil {
 		return nil, err
 	}
 
 	return map[string]interface{}{
-		"symbols": symbols,
-		"count":   len(symbols),
+		"symbol":   req.SymbolName,
+		"versions": trend,
+		"count":    len(trend),
 	}, nil
 }
 
-func (s *Server) handleGetFileStructure(params json.RawMessage) (interface{}, error) {
+func (s *Server) handleGetChurn(params json.RawMessage) (interface{}, error) {
 	var req struct {
-		FilePath string `json:"file_path"`
+		Limit int `json:"limit"`
 	}
 
 	if err := json.Unmarshal(params, &req); err != nil {
 		return nil, err
 	}
 
-	structure, err := s.indexer.GetFileStructure(req.FilePath)
+	if req.Limit <= 0 {
+		req.Limit = 25
+	}
+
+	churn, err := s.indexer.GetFileChurn()
 	if err != nil {
 		return nil, err
 	}
 
-	return structure, nil
+	if len(churn) > req.Limit {
+		churn = churn[:req.Limit]
+	}
+
+	return map[string]interface{}{
+		"files": churn,
+		"count": len(churn),
+	}, nil
 }
 
-func (s *Server) handleGetProjectOverview(params json.RawMessage) (interface{}, error) {
-	overview, err := s.indexer.GetProjectOverview()
-	if err != nil {
+func (s *Server) handleGetOwnership(params json.RawMessage) (interface{}, error) {
+	var req struct {
+		SymbolName string `json:"symbol_name"`
+		FilePath   string `json:"file_path"`
+	}
+
+	if err := json.Unmarshal(params, &req); err != nil {
 		return nil, err
 	}
 
-	return overview, nil
+	return s.indexer.GetSymbolOwnership(req.SymbolName, req.FilePath)
 }
 
-func (s *Server) handleIndexProject(params json.RawMessage) (interface{}, error) {
-	if err := s.indexer.IndexAll(); err != nil {
+func (s *Server) handleRankComplexity(params json.RawMessage) (interface{}, error) {
+	var req struct {
+		Limit         int `json:"limit"`
+		MinComplexity int `json:"min_complexity"`
+	}
+
+	if err := json.Unmarshal(params, &req); err != nil {
+		return nil, err
+	}
+
+	if req.Limit <= 0 {
+		req.Limit = 25
+	}
+
+	ranked, err := s.indexer.RankByComplexity(req.Limit)
+	if err != nil {
 		return nil, err
 	}
 
+	// Results are sorted by descending complexity, so the threshold can be applied after the limit
+	functions := ranked[:0]
+	for _, m := range ranked {
+		if m.CyclomaticComplexity >= req.MinComplexity {
+			functions = append(functions, m)
+		}
+	}
+
 	return map[string]interface{}{
-		"status":  "success",
-		"message": "Project indexed successfully",
+		"functions": functions,
+		"count":     len(functions),
 	}, nil
 }
 
-func (s *Server) handleGetSymbolDetails(params json.RawMessage) (interface{}, error) {
+func (s *Server) handleGetComplexityDistribution(params json.RawMessage) (interface{}, error) {
+	return s.indexer.GetComplexityDistribution()
+}
+
+func (s *Server) handleExtractSmartSnippet(params json.RawMessage) (interface{}, error) {
 	var req struct {
 		SymbolName string `json:"symbol_name"`
 	}
@@ -667,15 +2586,15 @@ func (s *Server) handleGetSymbolDetails(params json.RawMessage) (interface{}, er
 		return nil, err
 	}
 
-	details, err := s.indexer.GetSymbolDetails(req.SymbolName)
+	snippet, err := s.indexer.ExtractSmartSnippet(req.SymbolName)
 	if err != nil {
 		return nil, err
 	}
 
-	return details, nil
+	return snippet, nil
 }
 
-func (s *Server) handleFindReferences(params json.RawMessage) (interface{}, error) {
+func (s *Server) handleGetUsageStatistics(params json.RawMessage) (interface{}, error) {
 	var req struct {
 		SymbolName string `json:"symbol_name"`
 	}
@@ -684,189 +2603,249 @@ func (s *Server) handleFindReferences(params json.RawMessage) (interface{}, erro
 		return nil, err
 	}
 
-	references, err := s.indexer.FindReferences(req.SymbolName)
+	stats, err := s.indexer.GetUsageStatistics(req.SymbolName)
 	if err != nil {
 		return nil, err
 	}
 
-	return map[string]interface{}{
-		"symbol":     req.SymbolName,
-		"references": references,
-		"count":      len(references),
-	}, nil
+	return stats, nil
 }
 
-func (s *Server) handleGetDependencies(params json.RawMessage) (interface{}, error) {
+func (s *Server) handleGetUsageExamples(params json.RawMessage) (interface{}, error) {
 	var req struct {
-		FilePath string `json:"file_path"`
+		SymbolName string `json:"symbol_name"`
+		Limit      int    `json:"limit"`
 	}
 
 	if err := json.Unmarshal(params, &req); err != nil {
 		return nil, err
 	}
 
-	deps, err := s.indexer.GetDependencies(req.FilePath)
+	examples, err := s.indexer.GetUsageExamples(req.SymbolName, req.Limit)
 	if err != nil {
 		return nil, err
 	}
 
-	return deps, nil
+	return map[string]interface{}{
+		"examples": examples,
+		"count":    len(examples),
+	}, nil
 }
 
-func (s *Server) handleListFiles(params json.RawMessage) (interface{}, error) {
+func (s *Server) handleSuggestRefactorings(params json.RawMessage) (interface{}, error) {
 	var req struct {
-		Language string `json:"language"`
+		SymbolName string `json:"symbol_name"`
 	}
 
 	if err := json.Unmarshal(params, &req); err != nil {
 		return nil, err
 	}
 
-	files, err := s.indexer.GetAllFiles()
+	suggestions, err := s.indexer.SuggestRefactorings(req.SymbolName)
 	if err != nil {
 		return nil, err
 	}
 
-	// Filter by language if specified
-	if req.Language != "" {
-		filtered := []*types.File{}
-		for _, file := range files {
-			if file.Language == req.Language {
-				filtered = append(filtered, file)
-			}
+	return map[string]interface{}{
+		"symbol":        req.SymbolName,
+		"opportunities": suggestions,
+		"count":         len(suggestions),
+	}, nil
+}
+
+func (s *Server) handleAnalyzeProject(params json.RawMessage) (interface{}, error) {
+	return s.indexer.AnalyzeProject()
+}
+
+func (s *Server) handleFindUnusedSymbols(ctx context.Context, params json.RawMessage) (interface{}, error) {
+	var req struct {
+		ExcludeTests bool `json:"exclude_tests"`
+	}
+
+	if len(params) > 0 {
+		if err := json.Unmarshal(params, &req); err != nil {
+			return nil, err
 		}
-		files = filtered
+	}
+
+	unused, err := s.indexer.FindUnusedSymbolsContext(ctx, req.ExcludeTests)
+	if err != nil {
+		return nil, err
 	}
 
 	return map[string]interface{}{
-		"files": files,
-		"count": len(files),
+		"unused_symbols": unused,
+		"count":          len(unused),
+		"suggestion":     "These symbols may be candidates for removal or refactoring",
 	}, nil
 }
 
-// AI-powered tool handlers
-
-func (s *Server) handleGetCodeContext(params json.RawMessage) (interface{}, error) {
+func (s *Server) handleFindGodObjects(params json.RawMessage) (interface{}, error) {
 	var req struct {
-		SymbolName string `json:"symbol_name"`
-		Depth      int    `json:"depth"`
+		MethodThreshold int `json:"method_threshold"`
+		FieldThreshold  int `json:"field_threshold"`
 	}
 
 	if err := json.Unmarshal(params, &req); err != nil {
 		return nil, err
 	}
 
-	if req.Depth == 0 {
-		req.Depth = 5 // Default depth
-	}
-
-	context, err := s.indexer.GetCodeContext(req.SymbolName, req.Depth)
+	godObjects, err := s.indexer.FindGodObjects(req.MethodThreshold, req.FieldThreshold)
 	if err != nil {
 		return nil, err
 	}
 
-	return context, nil
+	return map[string]interface{}{
+		"god_objects": godObjects,
+		"count":       len(godObjects),
+	}, nil
 }
 
-func (s *Server) handleAnalyzeChangeImpact(params json.RawMessage) (interface{}, error) {
+func (s *Server) handleFindLargeSymbols(params json.RawMessage) (interface{}, error) {
 	var req struct {
-		SymbolName string `json:"symbol_name"`
+		MinLines int `json:"min_lines"`
 	}
 
 	if err := json.Unmarshal(params, &req); err != nil {
 		return nil, err
 	}
 
-	impact, err := s.indexer.AnalyzeChangeImpact(req.SymbolName)
+	symbols, err := s.indexer.FindLargeSymbols(req.MinLines)
 	if err != nil {
 		return nil, err
 	}
 
-	return impact, nil
+	results := make([]map[string]interface{}, len(symbols))
+	for i, symbol := range symbols {
+		results[i] = map[string]interface{}{
+			"symbol":     symbol,
+			"line_count": symbol.EndLine - symbol.StartLine + 1,
+		}
+	}
+
+	return map[string]interface{}{
+		"symbols": results,
+		"count":   len(results),
+	}, nil
 }
 
-func (s *Server) handleGetCodeMetrics(params json.RawMessage) (interface{}, error) {
+func (s *Server) handleCheckNaming(params json.RawMessage) (interface{}, error) {
+	issues, err := s.indexer.CheckNamingConventions()
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]interface{}{
+		"issues": issues,
+		"count":  len(issues),
+	}, nil
+}
+
+// Development workflow tool handlers
+
+func (s *Server) handleSetSymbolStatus(params json.RawMessage) (interface{}, error) {
 	var req struct {
 		SymbolName string `json:"symbol_name"`
+		Status     string `json:"status"`
 	}
 
 	if err := json.Unmarshal(params, &req); err != nil {
 		return nil, err
 	}
 
-	metrics, err := s.indexer.GetCodeMetrics(req.SymbolName)
-	if err != nil {
+	if err := s.indexer.SetSymbolStatus(req.SymbolName, req.Status); err != nil {
 		return nil, err
 	}
 
-	return metrics, nil
+	return map[string]interface{}{
+		"symbol_name": req.SymbolName,
+		"status":      req.Status,
+	}, nil
 }
 
-func (s *Server) handleExtractSmartSnippet(params json.RawMessage) (interface{}, error) {
+func (s *Server) handleAssignSymbol(params json.RawMessage) (interface{}, error) {
 	var req struct {
 		SymbolName string `json:"symbol_name"`
+		Agent      string `json:"agent"`
 	}
 
 	if err := json.Unmarshal(params, &req); err != nil {
 		return nil, err
 	}
 
-	snippet, err := s.indexer.ExtractSmartSnippet(req.SymbolName)
-	if err != nil {
+	if err := s.indexer.AssignSymbol(req.SymbolName, req.Agent); err != nil {
 		return nil, err
 	}
 
-	return snippet, nil
+	return map[string]interface{}{
+		"symbol_name":    req.SymbolName,
+		"assigned_agent": req.Agent,
+	}, nil
 }
 
-func (s *Server) handleGetUsageStatistics(params json.RawMessage) (interface{}, error) {
+func (s *Server) handleGetSymbolsByStatus(params json.RawMessage) (interface{}, error) {
 	var req struct {
-		SymbolName string `json:"symbol_name"`
+		Status string `json:"status"`
 	}
 
 	if err := json.Unmarshal(params, &req); err != nil {
 		return nil, err
 	}
 
-	stats, err := s.indexer.GetUsageStatistics(req.SymbolName)
+	symbols, err := s.indexer.GetSymbolsByStatus(req.Status)
 	if err != nil {
 		return nil, err
 	}
 
-	return stats, nil
+	return map[string]interface{}{
+		"symbols": symbols,
+		"count":   len(symbols),
+	}, nil
 }
 
-func (s *Server) handleSuggestRefactorings(params json.RawMessage) (interface{}, error) {
+func (s *Server) handleSetSymbolPriority(params json.RawMessage) (interface{}, error) {
 	var req struct {
-		SymbolName string `json:"symbol_name"`
+		SymbolName string  `json:"symbol_name"`
+		Priority   float64 `json:"priority"` // Some clients send integers as floats
 	}
 
 	if err := json.Unmarshal(params, &req); err != nil {
 		return nil, err
 	}
 
-	suggestions, err := s.indexer.SuggestRefactorings(req.SymbolName)
-	if err != nil {
+	priority := int(req.Priority)
+	if float64(priority) != req.Priority {
+		return nil, fmt.Errorf("invalid priority: %v (expected an integer)", req.Priority)
+	}
+
+	if err := s.indexer.SetSymbolPriority(req.SymbolName, priority); err != nil {
 		return nil, err
 	}
 
 	return map[string]interface{}{
-		"symbol":        req.SymbolName,
-		"opportunities": suggestions,
-		"count":         len(suggestions),
+		"symbol_name": req.SymbolName,
+		"priority":    priority,
 	}, nil
 }
 
-func (s *Server) handleFindUnusedSymbols(params json.RawMessage) (interface{}, error) {
-	unused, err := s.indexer.FindUnusedSymbols()
+func (s *Server) handleGetWorkQueue(params json.RawMessage) (interface{}, error) {
+	var req struct {
+		Status string `json:"status"`
+		Limit  int    `json:"limit"`
+	}
+
+	if err := json.Unmarshal(params, &req); err != nil {
+		return nil, err
+	}
+
+	queue, err := s.indexer.GetWorkQueue(req.Status, req.Limit)
 	if err != nil {
 		return nil, err
 	}
 
 	return map[string]interface{}{
-		"unused_symbols": unused,
-		"count":          len(unused),
-		"suggestion":     "These symbols may be candidates for removal or refactoring",
+		"symbols": queue,
+		"count":   len(queue),
 	}, nil
 }
 
@@ -919,7 +2898,48 @@ func (s *Server) handleSimulateChange(params json.RawMessage) (interface{}, erro
 	}, nil
 }
 
-func (s *Server) handleBuildDependencyGraph(params json.RawMessage) (interface{}, error) {
+func (s *Server) handleRenameSymbol(params json.RawMessage) (interface{}, error) {
+	var req struct {
+		SymbolName string `json:"symbol_name"`
+		NewName    string `json:"new_name"`
+		DryRun     *bool  `json:"dry_run"`
+	}
+
+	if err := json.Unmarshal(params, &req); err != nil {
+		return nil, err
+	}
+
+	if req.NewName == "" {
+		return nil, fmt.Errorf("new_name is required")
+	}
+	if req.DryRun != nil && !*req.DryRun {
+		return nil, fmt.Errorf("applying renames is not supported yet; use dry_run")
+	}
+
+	impact, err := s.indexer.SimulateSymbolChange(req.SymbolName, types.ChangeTypeRename, req.NewName)
+	if err != nil {
+		return nil, err
+	}
+
+	collisions := []*types.ValidationError{}
+	for _, valError := range impact.ValidationErrors {
+		if valError.Code == "rename_collision" {
+			collisions = append(collisions, valError)
+		}
+	}
+
+	return map[string]interface{}{
+		"symbol":     req.SymbolName,
+		"new_name":   req.NewName,
+		"dry_run":    true,
+		"edits":      impact.RequiredUpdates,
+		"collisions": collisions,
+		"safe":       len(collisions) == 0,
+		"count":      len(impact.RequiredUpdates),
+	}, nil
+}
+
+func (s *Server) handleBuildDependencyGraph(ctx context.Context, params json.RawMessage) (interface{}, error) {
 	var req struct {
 		SymbolName string `json:"symbol_name"`
 		MaxDepth   int    `json:"max_depth"`
@@ -933,7 +2953,7 @@ func (s *Server) handleBuildDependencyGraph(params json.RawMessage) (interface{}
 		req.MaxDepth = 3 // Default depth
 	}
 
-	graph, err := s.indexer.BuildDependencyGraph(req.SymbolName, req.MaxDepth)
+	graph, err := s.indexer.BuildDependencyGraphContext(ctx, req.SymbolName, req.MaxDepth)
 	if err != nil {
 		return nil, err
 	}
@@ -991,6 +3011,51 @@ func (s *Server) handleGetSymbolDependents(params json.RawMessage) (interface{},
 	}, nil
 }
 
+func (s *Server) handleGetPublicCallers(params json.RawMessage) (interface{}, error) {
+	var req struct {
+		SymbolName string `json:"symbol_name"`
+	}
+
+	if err := json.Unmarshal(params, &req); err != nil {
+		return nil, err
+	}
+
+	callers, err := s.indexer.GetPublicCallers(req.SymbolName)
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]interface{}{
+		"symbol":  req.SymbolName,
+		"callers": callers,
+		"count":   len(callers),
+	}, nil
+}
+
+func (s *Server) handleGetCouplingHotspots(params json.RawMessage) (interface{}, error) {
+	var req struct {
+		Limit int `json:"limit"`
+	}
+
+	if err := json.Unmarshal(params, &req); err != nil {
+		return nil, err
+	}
+
+	if req.Limit <= 0 {
+		req.Limit = 20
+	}
+
+	hotspots, err := s.indexer.GetCouplingHotspots(req.Limit)
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]interface{}{
+		"hotspots": hotspots,
+		"count":    len(hotspots),
+	}, nil
+}
+
 // Type validation tool handlers
 
 func (s *Server) handleValidateFileTypes(params json.RawMessage) (interface{}, error) {
@@ -1077,6 +3142,24 @@ func (s *Server) handleCheckMethodExists(params json.RawMessage) (interface{}, e
 	}, nil
 }
 
+func (s *Server) handleCheckConformance(params json.RawMessage) (interface{}, error) {
+	var req struct {
+		TypeName      string `json:"type_name"`
+		InterfaceName string `json:"interface_name"`
+	}
+
+	if err := json.Unmarshal(params, &req); err != nil {
+		return nil, err
+	}
+
+	report, err := s.indexer.CheckConformance(req.TypeName, req.InterfaceName)
+	if err != nil {
+		return nil, err
+	}
+
+	return report, nil
+}
+
 func (s *Server) handleCalculateTypeSafetyScore(params json.RawMessage) (interface{}, error) {
 	var req struct {
 		FilePath string `json:"file_path"`
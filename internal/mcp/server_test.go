@@ -1,10 +1,17 @@
 package mcp
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
 	"os"
 	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/aaamil13/CodeIndexerMCP/internal/core"
 	"github.com/aaamil13/CodeIndexerMCP/pkg/types"
@@ -270,6 +277,98 @@ func Main() {
 	}
 }
 
+func TestMCPServer_HandleGetSignature(t *testing.T) {
+	server, indexer, projectPath := setupTestMCPServer(t)
+	defer indexer.Close()
+
+	tsFile := filepath.Join(projectPath, "calc.ts")
+	code := `export function add(a: number, b: number): number;
+export function add(a: string, b: string): string;
+export function add(a: any, b: any): any {
+  return a + b;
+}
+`
+	os.WriteFile(tsFile, []byte(code), 0644)
+	indexer.IndexAll()
+
+	paramsJSON, _ := json.Marshal(map[string]string{"symbol_name": "add"})
+	result, err := server.handleGetSignature(paramsJSON)
+	if err != nil {
+		t.Fatalf("handleGetSignature failed: %v", err)
+	}
+
+	payload := result.(map[string]interface{})
+	signatures := payload["signatures"].([]*types.SymbolSignature)
+	if len(signatures) != 3 {
+		t.Fatalf("Expected 2 overloads and the implementation, got %d", len(signatures))
+	}
+	if !strings.Contains(signatures[0].Signature, "a: number") || !strings.Contains(signatures[1].Signature, "a: string") {
+		t.Errorf("Expected both overload signatures, got %q and %q", signatures[0].Signature, signatures[1].Signature)
+	}
+
+	// The payload carries no bodies, references or relationships
+	encoded, _ := json.Marshal(payload)
+	for _, key := range []string{"references", "relationships", "children", "return a + b"} {
+		if strings.Contains(string(encoded), key) {
+			t.Errorf("Expected payload to omit %q, got %s", key, encoded)
+		}
+	}
+}
+
+func TestMCPServer_HandleGetSymbolByID(t *testing.T) {
+	server, indexer, projectPath := setupTestMCPServer(t)
+	defer indexer.Close()
+
+	code := `export function format(value: number): string;
+export function format(value: Date): string;
+`
+	os.WriteFile(filepath.Join(projectPath, "format.ts"), []byte(code), 0644)
+	indexer.IndexAll()
+
+	searchJSON, _ := json.Marshal(map[string]string{"query": "format"})
+	result, err := server.handleSearchSymbols(searchJSON)
+	if err != nil {
+		t.Fatalf("handleSearchSymbols failed: %v", err)
+	}
+
+	ids := map[int64]string{}
+	for _, sym := range result.(map[string]interface{})["symbols"].([]*types.Symbol) {
+		if sym.Name == "format" {
+			ids[sym.ID] = sym.Signature
+		}
+	}
+	if len(ids) != 2 {
+		t.Fatalf("Expected 2 distinct ids for the overloads, got %v", ids)
+	}
+
+	for id, signature := range ids {
+		paramsJSON, _ := json.Marshal(map[string]int64{"id": id})
+		result, err := server.handleGetSymbolByID(paramsJSON)
+		if err != nil {
+			t.Fatalf("handleGetSymbolByID(%d) failed: %v", id, err)
+		}
+
+		details := result.(*types.SymbolDetails)
+		if details.Symbol.ID != id || details.Symbol.Signature != signature {
+			t.Errorf("Expected symbol %d (%s), got %d (%s)", id, signature, details.Symbol.ID, details.Symbol.Signature)
+		}
+	}
+
+	if _, err := server.handleGetSymbolByID(json.RawMessage(`{"id": 1.5}`)); err == nil {
+		t.Error("Expected error for a non-integer id")
+	}
+
+	// Re-indexing the file retires its ids rather than handing them to other symbols
+	os.WriteFile(filepath.Join(projectPath, "format.ts"), []byte("export function parse(text: string): number;\n"+code), 0644)
+	indexer.IndexAll()
+	for id := range ids {
+		paramsJSON, _ := json.Marshal(map[string]int64{"id": id})
+		if _, err := server.handleGetSymbolByID(paramsJSON); err == nil {
+			t.Errorf("Expected id %d to be gone after re-indexing", id)
+		}
+	}
+}
+
 func TestMCPServer_HandleGetCodeContext(t *testing.T) {
 	server, indexer, projectPath := setupTestMCPServer(t)
 	defer indexer.Close()
@@ -476,7 +575,7 @@ func Main() {
 	}
 	paramsJSON, _ := json.Marshal(params)
 
-	result, err := server.handleBuildDependencyGraph(paramsJSON)
+	result, err := server.handleBuildDependencyGraph(context.Background(), paramsJSON)
 	if err != nil {
 		t.Fatalf("handleBuildDependencyGraph failed: %v", err)
 	}
@@ -533,6 +632,31 @@ func TestMCPServer_HandleListFiles(t *testing.T) {
 	}
 }
 
+func TestMCPServer_HandleListFilesLanguageAlias(t *testing.T) {
+	server, indexer, projectPath := setupTestMCPServer(t)
+	defer indexer.Close()
+
+	files := map[string]string{
+		"app.ts":  "export function start(): void {}",
+		"main.go": "package main",
+	}
+	for name, content := range files {
+		os.WriteFile(filepath.Join(projectPath, name), []byte(content), 0644)
+	}
+
+	indexer.IndexAll()
+
+	result, err := server.handleListFiles(json.RawMessage(`{"language": "ts"}`))
+	if err != nil {
+		t.Fatalf("handleListFiles failed: %v", err)
+	}
+
+	summaries := result.(map[string]interface{})["files"].([]*types.FileSummary)
+	if len(summaries) != 1 || summaries[0].Language != "typescript" {
+		t.Fatalf("Expected the typescript file for a filter of ts, got %+v", summaries)
+	}
+}
+
 func TestMCPServer_HandleInvalidToolCall(t *testing.T) {
 	server, indexer, _ := setupTestMCPServer(t)
 	defer indexer.Close()
@@ -540,7 +664,7 @@ func TestMCPServer_HandleInvalidToolCall(t *testing.T) {
 	// Call non-existent tool
 	params := json.RawMessage(`{"name": "non_existent_tool"}`)
 
-	result, err := server.handleToolCall(params)
+	result, err := server.handleToolCall(context.Background(), params)
 	if err == nil {
 		t.Error("Expected error for non-existent tool")
 	}
@@ -586,3 +710,262 @@ func TestMCPServer_RegisterTool(t *testing.T) {
 		}
 	}
 }
+
+func TestMCPServer_ToolCallTimeout(t *testing.T) {
+	server, indexer, _ := setupTestMCPServer(t)
+	defer indexer.Close()
+
+	// A traversal that only stops when its context is cancelled
+	var running atomic.Bool
+	server.registerTool(&Tool{
+		Name: "slow_traversal",
+		ContextHandler: func(ctx context.Context, params json.RawMessage) (interface{}, error) {
+			running.Store(true)
+			defer running.Store(false)
+			for {
+				if err := ctx.Err(); err != nil {
+					return nil, err
+				}
+				time.Sleep(time.Millisecond)
+			}
+		},
+	})
+	server.SetToolTimeout(20 * time.Millisecond)
+
+	req := &MCPRequest{
+		JSONRPC: "2.0",
+		ID:      1,
+		Method:  "tools/call",
+		Params:  json.RawMessage(`{"name": "slow_traversal", "arguments": {}}`),
+	}
+
+	start := time.Now()
+	resp := server.handleRequest(context.Background(), req)
+	if time.Since(start) > time.Second {
+		t.Errorf("Tool call was not cut off by the timeout")
+	}
+	if running.Load() {
+		t.Errorf("Tool handler still running after the call timed out")
+	}
+
+	if resp.Error == nil {
+		t.Fatal("Expected timeout error")
+	}
+	if resp.Error.Code != -32603 {
+		t.Errorf("Expected error code -32603, got %d", resp.Error.Code)
+	}
+	if !strings.Contains(resp.Error.Message, "timed out") {
+		t.Errorf("Expected timeout message, got %q", resp.Error.Message)
+	}
+}
+
+func TestMCPServer_HandleRankComplexity(t *testing.T) {
+	server, indexer, projectPath := setupTestMCPServer(t)
+	defer indexer.Close()
+
+	// Simple has complexity 1, Medium 5 and Heavy 10
+	goFile := filepath.Join(projectPath, "code.go")
+	code := `package main
+
+func Simple(x int) int {
+	return x
+}
+
+func Medium(x int) int {
+	if x > 1 {
+		x++
+	}
+	if x > 2 {
+		x++
+	}
+	if x > 3 {
+		x++
+	}
+	if x > 4 {
+		x++
+	}
+	return x
+}
+
+func Heavy(x int) int {
+	if x > 1 {
+		x++
+	}
+	if x > 2 {
+		x++
+	}
+	if x > 3 {
+		x++
+	}
+	if x > 4 {
+		x++
+	}
+	if x > 5 {
+		x++
+	}
+	if x > 6 {
+		x++
+	}
+	if x > 7 {
+		x++
+	}
+	if x > 8 {
+		x++
+	}
+	if x > 9 {
+		x++
+	}
+	return x
+}
+`
+	os.WriteFile(goFile, []byte(code), 0644)
+	indexer.IndexAll()
+
+	result, err := server.handleRankComplexity(json.RawMessage(`{}`))
+	if err != nil {
+		t.Fatalf("handleRankComplexity failed: %v", err)
+	}
+
+	functions := result.(map[string]interface{})["functions"].([]*types.CodeMetrics)
+	if len(functions) != 3 {
+		t.Fatalf("Expected 3 functions, got %d", len(functions))
+	}
+
+	expected := []struct {
+		name       string
+		complexity int
+	}{
+		{"Heavy", 10},
+		{"Medium", 5},
+		{"Simple", 1},
+	}
+	for i, want := range expected {
+		if functions[i].FunctionName != want.name || functions[i].CyclomaticComplexity != want.complexity {
+			t.Errorf("Expected #%d to be %s (%d), got %s (%d)", i, want.name, want.complexity,
+				functions[i].FunctionName, functions[i].CyclomaticComplexity)
+		}
+	}
+
+	// Threshold filtering
+	result, err = server.handleRankComplexity(json.RawMessage(`{"min_complexity": 5}`))
+	if err != nil {
+		t.Fatalf("handleRankComplexity failed: %v", err)
+	}
+
+	functions = result.(map[string]interface{})["functions"].([]*types.CodeMetrics)
+	if len(functions) != 2 {
+		t.Fatalf("Expected 2 functions with complexity >= 5, got %d", len(functions))
+	}
+	if functions[0].FunctionName != "Heavy" || functions[1].FunctionName != "Medium" {
+		t.Errorf("Expected Heavy then Medium, got %s then %s", functions[0].FunctionName, functions[1].FunctionName)
+	}
+}
+
+func TestMCPServer_BatchRequest(t *testing.T) {
+	server, indexer, projectPath := setupTestMCPServer(t)
+	defer indexer.Close()
+
+	goFile := filepath.Join(projectPath, "test.go")
+	os.WriteFile(goFile, []byte("package main\n\nfunc Hello() {}\n"), 0644)
+	indexer.IndexAll()
+
+	// Two requests plus a notification, which must not get a response
+	batch := `[
+		{"jsonrpc": "2.0", "id": 1, "method": "tools/list"},
+		{"jsonrpc": "2.0", "method": "notifications/initialized"},
+		{"jsonrpc": "2.0", "id": 2, "method": "tools/call", "params": {"name": "search_symbols", "arguments": {"query": "Hello"}}}
+	]`
+
+	var out bytes.Buffer
+	server.stdin = strings.NewReader(batch)
+	server.stdout = &out
+
+	if err := server.Start(context.Background()); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+
+	var responses []MCPResponse
+	if err := json.Unmarshal(out.Bytes(), &responses); err != nil {
+		t.Fatalf("Expected a JSON array of responses, got %q: %v", out.String(), err)
+	}
+
+	if len(responses) != 2 {
+		t.Fatalf("Expected 2 responses, got %d", len(responses))
+	}
+
+	for i, want := range []float64{1, 2} {
+		if responses[i].ID != want {
+			t.Errorf("Expected response %d to have id %v, got %v", i, want, responses[i].ID)
+		}
+		if responses[i].Error != nil {
+			t.Errorf("Unexpected error in response %d: %s", i, responses[i].Error.Message)
+		}
+	}
+
+	text, _ := json.Marshal(responses[1].Result)
+	if !strings.Contains(string(text), "Hello") {
+		t.Errorf("Expected search_symbols result to contain Hello, got %s", text)
+	}
+}
+
+// chunkedWriter splits every write into small pieces, yielding in between,
+// so unsynchronized writers would interleave their output
+type chunkedWriter struct {
+	buf bytes.Buffer
+}
+
+func (w *chunkedWriter) Write(p []byte) (int, error) {
+	for i := 0; i < len(p); i += 4 {
+		end := i + 4
+		if end > len(p) {
+			end = len(p)
+		}
+		w.buf.Write(p[i:end])
+		runtime.Gosched()
+	}
+	return len(p), nil
+}
+
+func TestMCPServer_ConcurrentWrites(t *testing.T) {
+	server, indexer, _ := setupTestMCPServer(t)
+	defer indexer.Close()
+
+	out := &chunkedWriter{}
+	server.stdout = out
+
+	const writers = 50
+	var wg sync.WaitGroup
+	for i := 0; i < writers; i++ {
+		wg.Add(1)
+		go func(id int) {
+			defer wg.Done()
+			response := &MCPResponse{
+				JSONRPC: "2.0",
+				ID:      id,
+				Result:  map[string]interface{}{"text": strings.Repeat("x", 200)},
+			}
+			if err := server.writeResponse(response); err != nil {
+				t.Errorf("writeResponse failed: %v", err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	decoder := json.NewDecoder(&out.buf)
+	seen := make(map[float64]bool)
+	for decoder.More() {
+		var response MCPResponse
+		if err := decoder.Decode(&response); err != nil {
+			t.Fatalf("Output is not a sequence of valid JSON objects: %v", err)
+		}
+		id, ok := response.ID.(float64)
+		if !ok {
+			t.Fatalf("Unexpected response id %v", response.ID)
+		}
+		seen[id] = true
+	}
+
+	if len(seen) != writers {
+		t.Errorf("Expected %d distinct responses, got %d", writers, len(seen))
+	}
+}
@@ -0,0 +1,66 @@
+package parser
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/aaamil13/CodeIndexerMCP/pkg/types"
+)
+
+// DeprecatedKey is the metadata flag set on symbols marked deprecated
+const DeprecatedKey = "deprecated"
+
+var (
+	// goDeprecated matches Go's "Deprecated: " paragraph convention
+	goDeprecated = regexp.MustCompile(`(?m)^\s*Deprecated: `)
+
+	// docDeprecated matches the @deprecated tag of JSDoc, Javadoc, KDoc and PHPDoc
+	docDeprecated = regexp.MustCompile(`@deprecated\b`)
+
+	// pyDeprecated matches a Python docstring line saying the symbol is
+	// deprecated, including Sphinx's ".. deprecated::" directive
+	pyDeprecated = regexp.MustCompile(`(?im)^\s*(?:\.\.\s+)?deprecated\b`)
+)
+
+// IsDeprecated reports whether a symbol's documentation or decorators mark
+// it deprecated by its language's convention: a "Deprecated:" paragraph in
+// Go, @deprecated in doc comments, @Deprecated and [Obsolete] annotations,
+// and Python's @deprecated decorator or a docstring line starting with
+// "deprecated"
+func IsDeprecated(language, documentation string, decorators []string) bool {
+	for _, decorator := range decorators {
+		name := strings.ToLower(strings.TrimPrefix(decorator, "@"))
+		if i := strings.IndexByte(name, '('); i >= 0 {
+			name = name[:i]
+		}
+		if i := strings.LastIndex(name, "."); i >= 0 {
+			name = name[i+1:] // typing_extensions.deprecated, warnings.deprecated
+		}
+		if name == "deprecated" || name == "obsolete" {
+			return true
+		}
+	}
+
+	switch language {
+	case "go":
+		return goDeprecated.MatchString(documentation)
+	case "python":
+		return pyDeprecated.MatchString(documentation)
+	}
+	return docDeprecated.MatchString(documentation)
+}
+
+// MarkDeprecated sets the DeprecatedKey metadata flag on the symbols that
+// IsDeprecated reports, reading decorators from their "decorators" metadata
+func MarkDeprecated(language string, symbols []*types.Symbol) {
+	for _, symbol := range symbols {
+		decorators, _ := symbol.Metadata["decorators"].([]string)
+		if !IsDeprecated(language, symbol.Documentation, decorators) {
+			continue
+		}
+		if symbol.Metadata == nil {
+			symbol.Metadata = make(map[string]interface{})
+		}
+		symbol.Metadata[DeprecatedKey] = true
+	}
+}
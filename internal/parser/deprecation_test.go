@@ -0,0 +1,60 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/aaamil13/CodeIndexerMCP/pkg/types"
+)
+
+func TestIsDeprecated(t *testing.T) {
+	tests := []struct {
+		name          string
+		language      string
+		documentation string
+		decorators    []string
+		want          bool
+	}{
+		{"go paragraph", "go", "Fetch loads a page.\n\nDeprecated: Use FetchContext instead.\n", nil, true},
+		{"go single line", "go", "Deprecated: Use NewClient.", nil, true},
+		{"go mid sentence", "go", "Fetch replaces the deprecated Get.", nil, false},
+		{"go lowercase", "go", "deprecated: use NewClient", nil, false},
+		{"jsdoc tag", "typescript", "Formats a date.\n@deprecated use formatDate", nil, true},
+		{"jsdoc bare tag", "javascript", "@deprecated", nil, true},
+		{"jsdoc other tag", "typescript", "@param value the value", nil, false},
+		{"javadoc tag", "java", "@deprecated since 2.0", nil, true},
+		{"java annotation", "java", "", []string{"@Deprecated"}, true},
+		{"java annotation with args", "java", "", []string{"@Deprecated(since = \"9\")"}, true},
+		{"csharp attribute", "csharp", "", []string{"Obsolete(\"Use Save\")"}, true},
+		{"python decorator", "python", "", []string{"@typing_extensions.deprecated(\"use run\")"}, true},
+		{"python docstring", "python", "Load the config.\n\nDeprecated: use load_settings.", nil, true},
+		{"sphinx directive", "python", ".. deprecated:: 1.2", nil, true},
+		{"other decorator", "python", "", []string{"@staticmethod"}, false},
+		{"undocumented", "go", "", nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsDeprecated(tt.language, tt.documentation, tt.decorators); got != tt.want {
+				t.Errorf("IsDeprecated(%q, %q, %v) = %v, want %v", tt.language, tt.documentation, tt.decorators, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMarkDeprecated(t *testing.T) {
+	old := &types.Symbol{Name: "formatDate", Documentation: "@deprecated use format"}
+	current := &types.Symbol{Name: "format", Documentation: "Formats a date"}
+	annotated := &types.Symbol{Name: "save", Metadata: map[string]interface{}{"decorators": []string{"@Deprecated"}}}
+
+	MarkDeprecated("typescript", []*types.Symbol{old, current, annotated})
+
+	if deprecated, _ := old.Metadata[DeprecatedKey].(bool); !deprecated {
+		t.Errorf("Expected %s to be marked deprecated", old.Name)
+	}
+	if _, ok := current.Metadata[DeprecatedKey]; ok {
+		t.Errorf("Expected %s not to be marked deprecated", current.Name)
+	}
+	if deprecated, _ := annotated.Metadata[DeprecatedKey].(bool); !deprecated {
+		t.Errorf("Expected %s to be marked deprecated", annotated.Name)
+	}
+}
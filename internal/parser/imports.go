@@ -0,0 +1,132 @@
+package parser
+
+import (
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/aaamil13/CodeIndexerMCP/pkg/types"
+)
+
+// goVersionSuffix matches the major-version element of a Go module path
+var goVersionSuffix = regexp.MustCompile(`^v[0-9]+$`)
+
+// CodeOutsideImports returns a file's content without its import lines, so
+// that names mentioned only by the imports themselves don't count as usage
+func CodeOutsideImports(content string, imports []*types.Import) string {
+	importLines := make(map[int]bool)
+	for _, imp := range imports {
+		importLines[imp.LineNumber] = true
+	}
+	lines := strings.Split(content, "\n")
+	body := make([]string, 0, len(lines))
+	for i, line := range lines {
+		if !importLines[i+1] {
+			body = append(body, line)
+		}
+	}
+	return strings.Join(body, "\n")
+}
+
+// ImportMembers returns the members of imp that code uses. Named imports
+// (from x import a, b / import { a, b }) are checked name by name; module
+// imports (Go packages, import os) by the members accessed through their
+// qualifier, e.g. fmt.Println. known is false when usage can't be
+// determined, e.g. for wildcard imports, Go dot imports and Go blank
+// imports, which are imported for their side effects.
+func ImportMembers(imp *types.Import, language, code string) (members []string, known bool) {
+	members = []string{}
+
+	names := imp.ImportedNames
+	if len(names) == 0 && imp.ImportedSymbol != "" {
+		names = []string{imp.ImportedSymbol}
+	}
+	if len(names) > 0 {
+		for _, name := range names {
+			member, local := SplitImportAlias(name)
+			if mentions(code, local) {
+				members = append(members, member)
+			}
+		}
+		return members, true
+	}
+
+	source, _ := SplitImportAlias(imp.Source)
+	switch language {
+	case "java", "kotlin", "scala":
+		// Imports name a class directly
+		if strings.HasSuffix(source, ".*") {
+			return members, false
+		}
+		class := source[strings.LastIndex(source, ".")+1:]
+		if mentions(code, class) {
+			members = append(members, class)
+		}
+		return members, true
+	case "csharp":
+		// using brings a whole namespace into scope
+		return members, false
+	}
+
+	qualifier := ImportQualifier(imp, language)
+	if qualifier == "" {
+		return members, false
+	}
+	seen := make(map[string]bool)
+	memberPattern := regexp.MustCompile(`\b` + regexp.QuoteMeta(qualifier) + `\.(\w+)`)
+	for _, match := range memberPattern.FindAllStringSubmatch(code, -1) {
+		if !seen[match[1]] {
+			seen[match[1]] = true
+			members = append(members, match[1])
+		}
+	}
+	return members, true
+}
+
+// ImportQualifier returns the name a module import's members are accessed
+// through: its alias, e.g. np for import numpy as np or f for Go's
+// import f "fmt", otherwise the module itself, with Go packages named after
+// their import path. It returns "" for Go blank and dot imports, whose
+// members aren't accessed through a name.
+func ImportQualifier(imp *types.Import, language string) string {
+	source, qualifier := SplitImportAlias(imp.Source)
+	if language != "go" {
+		return qualifier
+	}
+	switch imp.Alias {
+	case "_", ".":
+		return ""
+	case "":
+		return GoPackageName(source)
+	}
+	return imp.Alias
+}
+
+// SplitImportAlias splits "name as alias" into the imported name and the
+// name it is bound to locally
+func SplitImportAlias(name string) (string, string) {
+	name = strings.TrimSpace(name)
+	if original, alias, ok := strings.Cut(name, " as "); ok {
+		return strings.TrimSpace(original), strings.TrimSpace(alias)
+	}
+	return name, name
+}
+
+// GoPackageName guesses the package name of a Go import path from its last
+// element, skipping major-version elements and common prefixes and suffixes
+func GoPackageName(importPath string) string {
+	name := path.Base(importPath)
+	if goVersionSuffix.MatchString(name) {
+		name = path.Base(path.Dir(importPath))
+	}
+	if dot := strings.Index(name, ".v"); dot > 0 {
+		name = name[:dot] // gopkg.in/yaml.v3
+	}
+	name = strings.TrimPrefix(name, "go-")
+	return strings.TrimSuffix(name, "-go")
+}
+
+// mentions reports whether code mentions name as a whole word
+func mentions(code, name string) bool {
+	return regexp.MustCompile(`\b` + regexp.QuoteMeta(name) + `\b`).MatchString(code)
+}
@@ -0,0 +1,48 @@
+package parser
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/aaamil13/CodeIndexerMCP/pkg/types"
+)
+
+func TestImportMembers(t *testing.T) {
+	tests := []struct {
+		name      string
+		language  string
+		imp       *types.Import
+		code      string
+		want      []string
+		wantKnown bool
+	}{
+		{"go package", "go", &types.Import{Source: "fmt"}, `fmt.Println("hi")`, []string{"Println"}, true},
+		{"go unused", "go", &types.Import{Source: "strings"}, `fmt.Println("hi")`, []string{}, true},
+		{"go alias", "go", &types.Import{Source: "fmt", Alias: "f"}, `f.Println("hi")`, []string{"Println"}, true},
+		{"go alias hides package name", "go", &types.Import{Source: "fmt", Alias: "f"}, `fmt.Println("hi")`, []string{}, true},
+		{"go versioned path", "go", &types.Import{Source: "gopkg.in/yaml.v3"}, `yaml.Marshal(v)`, []string{"Marshal"}, true},
+		{"go blank import", "go", &types.Import{Source: "github.com/lib/pq", Alias: "_"}, `sql.Open("postgres", dsn)`, []string{}, false},
+		{"go dot import", "go", &types.Import{Source: "math", Alias: "."}, `Sqrt(2)`, []string{}, false},
+		{"python module alias", "python", &types.Import{Source: "numpy as np"}, `np.array([1])`, []string{"array"}, true},
+		{"python named imports", "python", &types.Import{Source: "os.path", ImportedNames: []string{"join", "exists"}}, `join(a, b)`, []string{"join"}, true},
+		{"java wildcard", "java", &types.Import{Source: "java.util.*"}, `new ArrayList<>()`, []string{}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, known := ImportMembers(tt.imp, tt.language, tt.code)
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") || known != tt.wantKnown {
+				t.Errorf("ImportMembers() = %v, %v; want %v, %v", got, known, tt.want, tt.wantKnown)
+			}
+		})
+	}
+}
+
+func TestCodeOutsideImports(t *testing.T) {
+	code := "import json\nimport os\n\nos.getcwd()\n"
+	imports := []*types.Import{{Source: "json", LineNumber: 1}, {Source: "os", LineNumber: 2}}
+
+	if got := CodeOutsideImports(code, imports); got != "\nos.getcwd()\n" {
+		t.Errorf("Unexpected code outside imports: %q", got)
+	}
+}
@@ -0,0 +1,62 @@
+package parser
+
+import "strings"
+
+// languageAliases maps other names for a language, such as LSP languageIds
+// and file extensions, to the name its parser stores files under
+var languageAliases = map[string]string{
+	"golang": "go",
+
+	"py":      "python",
+	"python3": "python",
+
+	// The TypeScript parser also indexes JavaScript
+	"ts":              "typescript",
+	"tsx":             "typescript",
+	"typescriptreact": "typescript",
+	"js":              "typescript",
+	"jsx":             "typescript",
+	"mjs":             "typescript",
+	"cjs":             "typescript",
+	"javascript":      "typescript",
+	"javascriptreact": "typescript",
+
+	"c#": "csharp",
+	"cs": "csharp",
+
+	"c++": "cpp",
+	"cc":  "cpp",
+	"cxx": "cpp",
+	"hpp": "cpp",
+
+	"kt":  "kotlin",
+	"kts": "kotlin",
+	"rs":  "rust",
+	"rb":  "ruby",
+
+	"sh":          "bash",
+	"shell":       "bash",
+	"shellscript": "bash",
+	"ps1":         "powershell",
+	"pwsh":        "powershell",
+
+	"htm":  "html",
+	"scss": "css",
+	"sass": "css",
+	"less": "css",
+	"yml":  "yaml",
+	"md":   "markdown",
+	"rest": "rst",
+}
+
+// CanonicalLanguage returns the language name files are stored under for a
+// language identifier, so "ts", "typescript" and "typescriptreact" all give
+// "typescript". Identifiers are case-insensitive and may carry a leading dot;
+// unknown ones are returned lowercased.
+func CanonicalLanguage(id string) string {
+	id = strings.TrimPrefix(strings.ToLower(strings.TrimSpace(id)), ".")
+	if canonical, ok := languageAliases[id]; ok {
+		return canonical
+	}
+	return id
+}
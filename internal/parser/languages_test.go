@@ -0,0 +1,30 @@
+package parser
+
+import "testing"
+
+func TestCanonicalLanguage(t *testing.T) {
+	tests := []struct {
+		id   string
+		want string
+	}{
+		{"typescript", "typescript"},
+		{"ts", "typescript"},
+		{"typescriptreact", "typescript"},
+		{"javascriptreact", "typescript"},
+		{".tsx", "typescript"},
+		{"TypeScript", "typescript"},
+		{" golang ", "go"},
+		{"py", "python"},
+		{"c#", "csharp"},
+		{"shellscript", "bash"},
+		{"yml", "yaml"},
+		{"elixir", "elixir"},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		if got := CanonicalLanguage(tt.id); got != tt.want {
+			t.Errorf("CanonicalLanguage(%q) = %q, want %q", tt.id, got, tt.want)
+		}
+	}
+}
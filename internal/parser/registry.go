@@ -3,12 +3,16 @@ package parser
 import (
 	"fmt"
 	"path/filepath"
+	"sort"
 	"strings"
 	"sync"
 
 	"github.com/aaamil13/CodeIndexerMCP/pkg/types"
 )
 
+// knownFrameworks lists the frameworks probed when describing parser capabilities
+var knownFrameworks = []string{"react", "vue", "angular", "express", "nest", "django", "flask"}
+
 // Registry manages language parsers
 type Registry struct {
 	parsers map[string]types.Parser // language -> parser
@@ -36,23 +40,49 @@ func (r *Registry) Register(parser types.Parser) error {
 
 	r.parsers[lang] = parser
 
-	// Map extensions to language
+	// Map extensions to language. When two parsers claim the same
+	// extension the higher priority one keeps it; ties go to the first.
 	for _, ext := range parser.Extensions() {
 		if !strings.HasPrefix(ext, ".") {
 			ext = "." + ext
 		}
+		ext = strings.ToLower(ext)
+		if owner, ok := r.extMap[ext]; ok && parserPriority(r.parsers[owner]) >= parserPriority(parser) {
+			continue
+		}
 		r.extMap[ext] = lang
 	}
 
 	return nil
 }
 
-// GetParser retrieves a parser for a language
+// parserPriority returns a parser's priority, or 0 for parsers without one
+func parserPriority(p types.Parser) int {
+	if pp, ok := p.(interface{ Priority() int }); ok {
+		return pp.Priority()
+	}
+	return 0
+}
+
+// Version returns a parser's version, or "" for parsers without one. A parser
+// whose output changes should change its version so cached results are dropped.
+func Version(p types.Parser) string {
+	if pv, ok := p.(interface{ Version() string }); ok {
+		return pv.Version()
+	}
+	return ""
+}
+
+// GetParser retrieves a parser for a language, accepting any of the
+// language's aliases known to CanonicalLanguage
 func (r *Registry) GetParser(language string) (types.Parser, error) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
 	parser, ok := r.parsers[language]
+	if !ok {
+		parser, ok = r.parsers[CanonicalLanguage(language)]
+	}
 	if !ok {
 		return nil, fmt.Errorf("no parser found for language: %s", language)
 	}
@@ -111,3 +141,35 @@ func (r *Registry) SupportedExtensions() []string {
 
 	return exts
 }
+
+// ListParsers returns information about all registered parsers, sorted by language
+func (r *Registry) ListParsers() []types.ParserInfo {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	infos := make([]types.ParserInfo, 0, len(r.parsers))
+	for lang, p := range r.parsers {
+		info := types.ParserInfo{
+			Language:   lang,
+			Extensions: append([]string(nil), p.Extensions()...),
+			Priority:   parserPriority(p),
+		}
+
+		// Framework support is only available on plugin-style parsers
+		if fp, ok := p.(interface{ SupportsFramework(string) bool }); ok {
+			for _, fw := range knownFrameworks {
+				if fp.SupportsFramework(fw) {
+					info.Frameworks = append(info.Frameworks, fw)
+				}
+			}
+		}
+
+		infos = append(infos, info)
+	}
+
+	sort.Slice(infos, func(i, j int) bool {
+		return infos[i].Language < infos[j].Language
+	})
+
+	return infos
+}
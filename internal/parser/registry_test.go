@@ -0,0 +1,55 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/aaamil13/CodeIndexerMCP/pkg/types"
+)
+
+// stubParser is a parser with a fixed language, extensions and priority
+type stubParser struct {
+	lang     string
+	exts     []string
+	priority int
+}
+
+func (p *stubParser) Language() string              { return p.lang }
+func (p *stubParser) Extensions() []string          { return p.exts }
+func (p *stubParser) CanParse(filePath string) bool { return true }
+func (p *stubParser) Priority() int                 { return p.priority }
+
+func (p *stubParser) Parse(content []byte, filePath string) (*types.ParseResult, error) {
+	return &types.ParseResult{}, nil
+}
+
+func TestRegistry_ExtensionCollision(t *testing.T) {
+	tests := []struct {
+		name     string
+		second   *stubParser
+		wantLang string
+	}{
+		{"higher priority wins", &stubParser{lang: "custom", exts: []string{".x"}, priority: 10}, "custom"},
+		{"equal priority keeps existing", &stubParser{lang: "custom", exts: []string{".x"}, priority: 5}, "builtin"},
+		{"lower priority keeps existing", &stubParser{lang: "custom", exts: []string{".X"}, priority: 1}, "builtin"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reg := NewRegistry()
+			if err := reg.Register(&stubParser{lang: "builtin", exts: []string{".x"}, priority: 5}); err != nil {
+				t.Fatalf("Register failed: %v", err)
+			}
+			if err := reg.Register(tt.second); err != nil {
+				t.Fatalf("Register failed: %v", err)
+			}
+
+			p, err := reg.GetParserForFile("file.x")
+			if err != nil {
+				t.Fatalf("GetParserForFile failed: %v", err)
+			}
+			if p.Language() != tt.wantLang {
+				t.Errorf("Expected %s to own .x, got %s", tt.wantLang, p.Language())
+			}
+		})
+	}
+}
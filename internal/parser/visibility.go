@@ -0,0 +1,89 @@
+package parser
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/aaamil13/CodeIndexerMCP/pkg/types"
+)
+
+// DetermineVisibility infers a symbol's visibility from the naming and
+// modifier conventions of its language. The signature may be the full
+// declaration or just its modifiers.
+func DetermineVisibility(language, name, signature string) types.Visibility {
+	switch language {
+	case "go":
+		r, _ := utf8.DecodeRuneInString(name)
+		if unicode.IsUpper(r) {
+			return types.VisibilityPublic
+		}
+		return types.VisibilityPrivate
+
+	case "python":
+		if strings.HasPrefix(name, "__") && strings.HasSuffix(name, "__") {
+			return types.VisibilityPublic // Dunder methods are part of the protocol
+		}
+		if strings.HasPrefix(name, "__") {
+			return types.VisibilityInternal // Name-mangled to the class
+		}
+		if strings.HasPrefix(name, "_") {
+			return types.VisibilityPrivate
+		}
+		return types.VisibilityPublic
+
+	case "java":
+		if vis, ok := modifierVisibility(signature); ok {
+			return vis
+		}
+		return types.VisibilityPackage // Java default is package-private
+
+	case "csharp":
+		if vis, ok := modifierVisibility(signature); ok {
+			return vis
+		}
+		return types.VisibilityPrivate // C# default is private
+
+	case "typescript", "javascript":
+		if strings.HasPrefix(name, "#") {
+			return types.VisibilityPrivate // ES private field
+		}
+		if vis, ok := modifierVisibility(signature); ok {
+			return vis
+		}
+		return types.VisibilityPublic
+	}
+
+	return types.VisibilityPublic
+}
+
+// modifierVisibility looks for access modifier keywords in a declaration
+func modifierVisibility(signature string) (types.Visibility, bool) {
+	// Modifiers precede the parameter list
+	if i := strings.IndexAny(signature, "(={"); i >= 0 {
+		signature = signature[:i]
+	}
+
+	words := strings.Fields(signature)
+	for i, word := range words {
+		switch word {
+		case "public":
+			return types.VisibilityPublic, true
+		case "private":
+			// C# "private protected" is assembly-scoped
+			if i+1 < len(words) && words[i+1] == "protected" {
+				return types.VisibilityInternal, true
+			}
+			return types.VisibilityPrivate, true
+		case "protected":
+			if i+1 < len(words) && words[i+1] == "internal" {
+				return types.VisibilityInternal, true
+			}
+			return types.VisibilityProtected, true
+		case "internal":
+			return types.VisibilityInternal, true
+		}
+	}
+
+	return "", false
+}
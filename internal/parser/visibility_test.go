@@ -0,0 +1,42 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/aaamil13/CodeIndexerMCP/pkg/types"
+)
+
+func TestDetermineVisibility(t *testing.T) {
+	tests := []struct {
+		language  string
+		name      string
+		signature string
+		want      types.Visibility
+	}{
+		{"go", "Handler", "func Handler()", types.VisibilityPublic},
+		{"go", "handler", "func handler()", types.VisibilityPrivate},
+		{"python", "run", "def run(self)", types.VisibilityPublic},
+		{"python", "_helper", "def _helper(self)", types.VisibilityPrivate},
+		{"python", "__secret", "def __secret(self)", types.VisibilityInternal},
+		{"python", "__init__", "def __init__(self)", types.VisibilityPublic},
+		{"java", "getName", "public String getName()", types.VisibilityPublic},
+		{"java", "reset", "private void reset()", types.VisibilityPrivate},
+		{"java", "load", "void load()", types.VisibilityPackage},
+		{"csharp", "Save", "public async Task Save(User user)", types.VisibilityPublic},
+		{"csharp", "Validate", "private bool Validate()", types.VisibilityPrivate},
+		{"csharp", "Build", "protected internal void Build()", types.VisibilityInternal},
+		{"csharp", "count", "int count", types.VisibilityPrivate},
+		{"typescript", "render", "public render(): void {", types.VisibilityPublic},
+		{"typescript", "cache", "private cache = new Map()", types.VisibilityPrivate},
+		{"typescript", "#count", "#count = 0", types.VisibilityPrivate},
+		{"typescript", "format", "export function format(value: string) {", types.VisibilityPublic},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.language+"/"+tt.name, func(t *testing.T) {
+			if got := DetermineVisibility(tt.language, tt.name, tt.signature); got != tt.want {
+				t.Errorf("DetermineVisibility(%q, %q, %q) = %q, want %q", tt.language, tt.name, tt.signature, got, tt.want)
+			}
+		})
+	}
+}
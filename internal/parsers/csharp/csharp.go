@@ -46,6 +46,8 @@ func (p *CSharpParser) Parse(content []byte, filePath string) (*types.ParseResul
 
 	result.Metadata["language"] = "csharp"
 
+	parser.MarkDeprecated(p.Language(), result.Symbols)
+
 	return result, nil
 }
 
@@ -183,6 +185,8 @@ func (p *CSharpParser) extractTypes(lines []string, content string, result *type
 			}
 		}
 
+		p.addAttributes(symbol, p.extractAttributes(lines, strings.Count(content[:match[8]], "\n")))
+
 		result.Symbols = append(result.Symbols, symbol)
 
 		// Add relationships for inheritance
@@ -255,6 +259,8 @@ func (p *CSharpParser) extractMembers(lines []string, content string, result *ty
 			}
 		}
 
+		p.addAttributes(symbol, p.extractAttributes(lines, strings.Count(content[:match[8]], "\n")))
+
 		result.Symbols = append(result.Symbols, symbol)
 	}
 
@@ -291,6 +297,8 @@ func (p *CSharpParser) extractMembers(lines []string, content string, result *ty
 			}
 		}
 
+		p.addAttributes(symbol, p.extractAttributes(lines, strings.Count(content[:match[8]], "\n")))
+
 		result.Symbols = append(result.Symbols, symbol)
 	}
 
@@ -332,23 +340,122 @@ func (p *CSharpParser) extractMembers(lines []string, content string, result *ty
 			}
 		}
 
+		p.addAttributes(symbol, p.extractAttributes(lines, strings.Count(content[:match[8]], "\n")))
+
 		result.Symbols = append(result.Symbols, symbol)
 	}
 }
 
-func (p *CSharpParser) parseVisibility(vis string) types.Visibility {
-	switch strings.ToLower(strings.TrimSpace(vis)) {
-	case "public":
-		return types.VisibilityPublic
-	case "private":
-		return types.VisibilityPrivate
-	case "protected":
-		return types.VisibilityProtected
-	case "internal", "protected internal", "private protected":
-		return types.VisibilityInternal
-	default:
-		return types.VisibilityPrivate // C# default is private
+// extractAttributes returns the attributes applied to the declaration on line
+// declLine (0-based): those on the lines directly above it and those in front
+// of it on the same line, in source order
+func (p *CSharpParser) extractAttributes(lines []string, declLine int) []string {
+	attributes, _ := leadingAttributes(lines[declLine])
+	for i := declLine - 1; i >= 0; i-- {
+		lineAttributes, rest := leadingAttributes(lines[i])
+		if len(lineAttributes) == 0 || rest != "" {
+			break
+		}
+		attributes = append(lineAttributes, attributes...)
 	}
+	return attributes
+}
+
+// leadingAttributes splits the attribute sections at the start of a line,
+// e.g. [HttpGet("{id}"), Authorize], from whatever follows them
+func leadingAttributes(line string) ([]string, string) {
+	var attributes []string
+	rest := strings.TrimSpace(line)
+	for strings.HasPrefix(rest, "[") {
+		end := attributeSectionEnd(rest)
+		if end < 0 {
+			break
+		}
+
+		section := rest[1:end]
+		// Drop an attribute target such as "return:" or "assembly:"
+		if colon := strings.Index(section, ":"); colon >= 0 && !strings.ContainsAny(section[:colon], "(\"") {
+			section = section[colon+1:]
+		}
+		for _, attribute := range splitTopLevel(section) {
+			if attribute = strings.TrimSpace(attribute); attribute != "" {
+				attributes = append(attributes, attribute)
+			}
+		}
+
+		rest = strings.TrimSpace(rest[end+1:])
+	}
+	return attributes, rest
+}
+
+// attributeSectionEnd returns the index of the ] closing the section opened
+// at text[0], or -1 if it isn't closed on this line
+func attributeSectionEnd(text string) int {
+	depth := 0
+	inString := false
+	for i := 0; i < len(text); i++ {
+		switch c := text[i]; {
+		case c == '"':
+			inString = !inString
+		case inString:
+		case c == '[' || c == '(':
+			depth++
+		case c == ']' || c == ')':
+			depth--
+			if depth == 0 && c == ']' {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// splitTopLevel splits on commas that aren't inside parentheses or strings
+func splitTopLevel(text string) []string {
+	var parts []string
+	depth, start := 0, 0
+	inString := false
+	for i := 0; i < len(text); i++ {
+		switch c := text[i]; {
+		case c == '"':
+			inString = !inString
+		case inString:
+		case c == '(':
+			depth++
+		case c == ')':
+			depth--
+		case c == ',' && depth == 0:
+			parts = append(parts, text[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, text[start:])
+}
+
+// addAttributes records attributes in the symbol's metadata, both verbatim
+// and as decorator names
+func (p *CSharpParser) addAttributes(symbol *types.Symbol, attributes []string) {
+	if len(attributes) == 0 {
+		return
+	}
+
+	decorators := make([]string, 0, len(attributes))
+	for _, attribute := range attributes {
+		if paren := strings.Index(attribute, "("); paren >= 0 {
+			attribute = attribute[:paren]
+		}
+		decorators = append(decorators, strings.TrimSpace(attribute))
+	}
+
+	if symbol.Metadata == nil {
+		symbol.Metadata = make(map[string]interface{})
+	}
+	symbol.Metadata["annotations"] = attributes
+	symbol.Metadata["decorators"] = decorators
+}
+
+func (p *CSharpParser) parseVisibility(vis string) types.Visibility {
+	return parser.DetermineVisibility("csharp", "", strings.ToLower(vis))
 }
 
 func (p *CSharpParser) findClosingBrace(lines []string, startLine int) int {
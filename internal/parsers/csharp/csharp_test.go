@@ -0,0 +1,70 @@
+package csharp
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/aaamil13/CodeIndexerMCP/pkg/types"
+)
+
+func TestParseAttributes(t *testing.T) {
+	code := `namespace Shop.Api
+{
+    [ApiController]
+    [Route("api/[controller]")]
+    public class OrdersController : ControllerBase
+    {
+        [HttpGet("{id}"), Authorize(Roles = "admin,ops")]
+        public IActionResult Get(int id)
+        {
+            return Ok(id);
+        }
+
+        [Obsolete] public void Legacy()
+        {
+        }
+
+        public void Helper()
+        {
+        }
+    }
+}
+`
+	parser := NewParser()
+	result, err := parser.Parse([]byte(code), "OrdersController.cs")
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	symbols := make(map[string]*types.Symbol)
+	for _, sym := range result.Symbols {
+		symbols[sym.Name] = sym
+	}
+
+	controller := symbols["OrdersController"]
+	if controller == nil {
+		t.Fatal("Expected OrdersController class")
+	}
+	if got := controller.Metadata["decorators"]; !reflect.DeepEqual(got, []string{"ApiController", "Route"}) {
+		t.Errorf("Expected class decorators [ApiController Route], got %v", got)
+	}
+
+	get := symbols["Get"]
+	if get == nil {
+		t.Fatal("Expected Get method")
+	}
+	if got := get.Metadata["annotations"]; !reflect.DeepEqual(got, []string{`HttpGet("{id}")`, `Authorize(Roles = "admin,ops")`}) {
+		t.Errorf("Expected method attributes [HttpGet(\"{id}\") Authorize(...)], got %v", got)
+	}
+	if got := get.Metadata["decorators"]; !reflect.DeepEqual(got, []string{"HttpGet", "Authorize"}) {
+		t.Errorf("Expected method decorators [HttpGet Authorize], got %v", got)
+	}
+
+	if legacy := symbols["Legacy"]; legacy == nil || !reflect.DeepEqual(legacy.Metadata["decorators"], []string{"Obsolete"}) {
+		t.Errorf("Expected same-line [Obsolete] attribute on Legacy, got %+v", legacy)
+	}
+
+	if helper := symbols["Helper"]; helper == nil || helper.Metadata["decorators"] != nil {
+		t.Errorf("Expected Helper without decorators, got %+v", helper)
+	}
+}
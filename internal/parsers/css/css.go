@@ -66,64 +66,118 @@ func (p *CSSParser) extractImports(content string, result *types.ParseResult) {
 		lineNum := strings.Count(content[:match[0]], "\n") + 1
 
 		imp := &types.Import{
-			Source: source,
-			Line:   lineNum,
+			Source:     source,
+			ImportType: importType(source),
+			LineNumber: lineNum,
 		}
 
 		result.Imports = append(result.Imports, imp)
 	}
 }
 
+// importType tells imported URLs apart from files of the project
+func importType(source string) types.ImportType {
+	if strings.Contains(source, "://") || strings.HasPrefix(source, "//") {
+		return types.ImportTypeExternal
+	}
+	return types.ImportTypeLocal
+}
+
+// extractSelectors records a symbol per selector of each rule, including
+// grouped (.a, .b) and nested (SCSS/LESS) rules. Keyframe steps are skipped.
 func (p *CSSParser) extractSelectors(content string, result *types.ParseResult) {
-	// CSS rule: selector { properties }
-	// Match class selectors, ID selectors, element selectors
-	selectorRe := regexp.MustCompile(`(?m)^\s*([.#]?[\w-]+(?:[.#:][\w-]+)*(?:\s*[>+~]\s*[\w.-]+)*)\s*{`)
+	code := stripComments(content)
 
+	type block struct {
+		symbols   []*types.Symbol // Opened by this block, ended by its closing brace
+		keyframes bool
+	}
+	var stack []block
 	seen := make(map[string]bool)
-
-	matches := selectorRe.FindAllStringSubmatchIndex(content, -1)
-	for _, match := range matches {
-		selector := strings.TrimSpace(content[match[2]:match[3]])
-
-		// Skip @-rules
-		if strings.HasPrefix(selector, "@") {
-			continue
-		}
-
-		// Skip if already seen
-		if seen[selector] {
-			continue
-		}
-		seen[selector] = true
-
-		lineNum := strings.Count(content[:match[0]], "\n") + 1
-
-		// Determine type based on selector
-		var symbolType types.SymbolType
-		if strings.HasPrefix(selector, ".") {
-			symbolType = types.SymbolTypeVariable // Class
-		} else if strings.HasPrefix(selector, "#") {
-			symbolType = types.SymbolTypeConstant // ID
-		} else {
-			symbolType = types.SymbolTypeVariable // Element or complex selector
+	start := 0
+
+	for i := 0; i < len(code); i++ {
+		switch code[i] {
+		case '{':
+			prelude := strings.TrimSpace(code[start:i])
+			offset := start + strings.Index(code[start:i+1], prelude)
+			start = i + 1
+
+			inKeyframes := len(stack) > 0 && stack[len(stack)-1].keyframes
+			current := block{keyframes: inKeyframes || keyframesRule.MatchString(prelude)}
+
+			// Skip at-rules, keyframe steps and #{...} interpolation
+			if prelude != "" && !strings.HasPrefix(prelude, "@") && !inKeyframes && !strings.HasSuffix(prelude, "#") {
+				lineNum := strings.Count(code[:offset], "\n") + 1
+				for _, selector := range strings.Split(prelude, ",") {
+					selector = strings.Join(strings.Fields(selector), " ")
+					if selector == "" || seen[selector] {
+						continue
+					}
+					seen[selector] = true
+
+					symbol := &types.Symbol{
+						Name:       selector,
+						Type:       selectorType(selector),
+						StartLine:  lineNum,
+						EndLine:    lineNum,
+						Visibility: types.VisibilityPublic,
+						Signature:  selector + " { }",
+						Metadata: map[string]interface{}{
+							"selector": true,
+						},
+					}
+					current.symbols = append(current.symbols, symbol)
+					result.Symbols = append(result.Symbols, symbol)
+				}
+			}
+			stack = append(stack, current)
+
+		case '}':
+			start = i + 1
+			if len(stack) == 0 {
+				continue
+			}
+			endLine := strings.Count(code[:i], "\n") + 1
+			for _, symbol := range stack[len(stack)-1].symbols {
+				symbol.EndLine = endLine
+			}
+			stack = stack[:len(stack)-1]
+
+		case ';':
+			start = i + 1
 		}
+	}
+}
 
-		symbol := &types.Symbol{
-			Name:       selector,
-			Type:       symbolType,
-			StartLine:  lineNum,
-			EndLine:    lineNum,
-			Visibility: types.VisibilityPublic,
-			Signature:  selector + " { }",
-			Metadata: map[string]interface{}{
-				"selector": true,
-			},
-		}
+// keyframesRule matches the prelude of an @keyframes block
+var keyframesRule = regexp.MustCompile(`^@(?:-webkit-|-moz-|-o-)?keyframes\b`)
 
-		result.Symbols = append(result.Symbols, symbol)
+// selectorType classifies a selector: ids are constants, classes, elements
+// and complex selectors variables
+func selectorType(selector string) types.SymbolType {
+	if strings.HasPrefix(selector, "#") {
+		return types.SymbolTypeConstant
 	}
+	return types.SymbolTypeVariable
 }
 
+// stripComments blanks out /* */ comments, keeping newlines so line numbers
+// stay the same
+func stripComments(content string) string {
+	return blockComment.ReplaceAllStringFunc(content, func(comment string) string {
+		return strings.Map(func(r rune) rune {
+			if r == '\n' {
+				return r
+			}
+			return ' '
+		}, comment)
+	})
+}
+
+// blockComment matches a CSS comment
+var blockComment = regexp.MustCompile(`(?s)/\*.*?\*/`)
+
 func (p *CSSParser) extractVariables(content string, result *types.ParseResult) {
 	// CSS custom properties: --variable-name
 	varRe := regexp.MustCompile(`(--[\w-]+)\s*:`)
@@ -1,12 +1,15 @@
 package golang
 
 import (
+	"bytes"
 	"go/ast"
 	"go/parser"
+	"go/printer"
 	"go/token"
 	"path/filepath"
 	"strings"
 
+	indexparser "github.com/aaamil13/CodeIndexerMCP/internal/parser"
 	"github.com/aaamil13/CodeIndexerMCP/pkg/types"
 )
 
@@ -66,8 +69,14 @@ func (p *Parser) Parse(content []byte, filePath string) (*types.ParseResult, err
 			importType = types.ImportTypeStdlib
 		}
 
-	result.Imports = append(result.Imports, &types.Import{
+		var alias string
+		if imp.Name != nil {
+			alias = imp.Name.Name
+		}
+
+		result.Imports = append(result.Imports, &types.Import{
 			Source:     importPath,
+			Alias:      alias,
 			ImportType: importType,
 			LineNumber: fset.Position(imp.Pos()).Line,
 		})
@@ -89,17 +98,22 @@ func (p *Parser) Parse(content []byte, filePath string) (*types.ParseResult, err
 		return true
 	})
 
+	indexparser.MarkDeprecated(p.Language(), result.Symbols)
+
 	return result, nil
 }
 
 // extractFunction extracts function/method information
 func (p *Parser) extractFunction(fn *ast.FuncDecl, fset *token.FileSet, file *ast.File) *types.Symbol {
 	symbol := &types.Symbol{
-		Name:      fn.Name.Name,
-		Type:      types.SymbolTypeFunction,
-		StartLine: fset.Position(fn.Pos()).Line,
-		EndLine:   fset.Position(fn.End()).Line,
-		Signature: p.buildFunctionSignature(fn),
+		Name:       fn.Name.Name,
+		Type:       types.SymbolTypeFunction,
+		StartLine:  fset.Position(fn.Pos()).Line,
+		EndLine:    fset.Position(fn.End()).Line,
+		Signature:  p.buildFunctionSignature(fn),
+		TypeParams: typeParams(fn.Type.TypeParams),
+		Parameters: parameters(fn.Type.Params),
+		ReturnType: formatResults(fn.Type.Results),
 	}
 
 	// Check if it's a method
@@ -117,9 +131,7 @@ func (p *Parser) extractFunction(fn *ast.FuncDecl, fset *token.FileSet, file *as
 	}
 
 	// Extract documentation
-	if fn.Doc != nil {
-		symbol.Documentation = fn.Doc.Text()
-	}
+	symbol.Documentation = extractDocumentation(fn.Doc)
 
 	return symbol
 }
@@ -159,9 +171,7 @@ func (p *Parser) extractGenDecl(decl *ast.GenDecl, fset *token.FileSet, file *as
 					symbol.IsExported = false
 				}
 
-				if decl.Doc != nil {
-					symbol.Documentation = decl.Doc.Text()
-				}
+				symbol.Documentation = extractDocumentation(s.Doc, decl.Doc)
 
 				symbols = append(symbols, symbol)
 			}
@@ -174,10 +184,11 @@ func (p *Parser) extractGenDecl(decl *ast.GenDecl, fset *token.FileSet, file *as
 // extractTypeSpec extracts type definitions (struct, interface, etc.)
 func (p *Parser) extractTypeSpec(spec *ast.TypeSpec, decl *ast.GenDecl, fset *token.FileSet) *types.Symbol {
 	symbol := &types.Symbol{
-		Name:      spec.Name.Name,
-		Type:      types.SymbolTypeType,
-		StartLine: fset.Position(spec.Pos()).Line,
-		EndLine:   fset.Position(spec.End()).Line,
+		Name:       spec.Name.Name,
+		Type:       types.SymbolTypeType,
+		StartLine:  fset.Position(spec.Pos()).Line,
+		EndLine:    fset.Position(spec.End()).Line,
+		TypeParams: typeParams(spec.TypeParams),
 	}
 
 	// Determine specific type
@@ -197,15 +208,28 @@ func (p *Parser) extractTypeSpec(spec *ast.TypeSpec, decl *ast.GenDecl, fset *to
 		symbol.IsExported = false
 	}
 
-	// Documentation
-	if decl.Doc != nil {
-		symbol.Documentation = decl.Doc.Text()
-	}
+	// Documentation (a spec inside a grouped declaration may carry its own)
+	symbol.Documentation = extractDocumentation(spec.Doc, decl.Doc)
 
 	return symbol
 }
 
-// buildFunctionSignature builds a function signature string
+// extractDocumentation returns the text of the first non-empty doc comment,
+// with comment markers stripped and the lines joined by newlines
+func extractDocumentation(groups ...*ast.CommentGroup) string {
+	for _, group := range groups {
+		if group == nil {
+			continue
+		}
+		if doc := strings.TrimSpace(group.Text()); doc != "" {
+			return doc
+		}
+	}
+	return ""
+}
+
+// buildFunctionSignature builds a function signature string, e.g.
+// func (c *Cache[K, V]) Get(key K) (V, bool)
 func (p *Parser) buildFunctionSignature(fn *ast.FuncDecl) string {
 	var sig strings.Builder
 
@@ -214,40 +238,112 @@ func (p *Parser) buildFunctionSignature(fn *ast.FuncDecl) string {
 	// Add receiver for methods
 	if fn.Recv != nil {
 		sig.WriteString("(")
-		// Simplified - would need proper formatting
-		sig.WriteString("receiver")
+		sig.WriteString(formatFieldList(fn.Recv))
 		sig.WriteString(") ")
 	}
 
 	sig.WriteString(fn.Name.Name)
+	if fn.Type.TypeParams != nil {
+		sig.WriteString("[")
+		sig.WriteString(formatFieldList(fn.Type.TypeParams))
+		sig.WriteString("]")
+	}
+
 	sig.WriteString("(")
+	sig.WriteString(formatFieldList(fn.Type.Params))
+	sig.WriteString(")")
 
-	// Parameters (simplified)
-	if fn.Type.Params != nil {
-		for i, field := range fn.Type.Params.List {
-			if i > 0 {
-				sig.WriteString(", ")
-			}
-			// Add parameter names/types (simplified)
-			if len(field.Names) > 0 {
-				sig.WriteString(field.Names[0].Name)
-			} else {
-				sig.WriteString("_")
-			}
+	if results := formatResults(fn.Type.Results); results != "" {
+		sig.WriteString(" ")
+		sig.WriteString(results)
+	}
+
+	return sig.String()
+}
+
+// formatResults formats a function's results as written, e.g. "error" or
+// "(n int, err error)"
+func formatResults(results *ast.FieldList) string {
+	if results == nil || len(results.List) == 0 {
+		return ""
+	}
+	if len(results.List) == 1 && len(results.List[0].Names) == 0 {
+		return formatExpr(results.List[0].Type)
+	}
+	return "(" + formatFieldList(results) + ")"
+}
+
+// parameters lists a function's parameters one per name, with the element
+// type of a variadic parameter
+func parameters(fields *ast.FieldList) []*types.Parameter {
+	if fields == nil {
+		return nil
+	}
+
+	var params []*types.Parameter
+	for _, field := range fields.List {
+		typ, variadic := field.Type, false
+		if ellipsis, ok := typ.(*ast.Ellipsis); ok {
+			typ, variadic = ellipsis.Elt, true
+		}
+
+		if len(field.Names) == 0 {
+			params = append(params, &types.Parameter{Type: formatExpr(typ), Variadic: variadic})
+			continue
+		}
+		for _, name := range field.Names {
+			params = append(params, &types.Parameter{Name: name.Name, Type: formatExpr(typ), Variadic: variadic})
 		}
 	}
+	return params
+}
 
-	sig.WriteString(")")
+// typeParams lists a generic declaration's type parameters one per name,
+// e.g. [K comparable, V any] -> ["K comparable", "V any"]
+func typeParams(fields *ast.FieldList) []string {
+	if fields == nil {
+		return nil
+	}
 
-	// Return type (simplified)
-	if fn.Type.Results != nil && len(fn.Type.Results.List) > 0 {
-		sig.WriteString(" ")
-		if len(fn.Type.Results.List) > 1 {
-			sig.WriteString("(...)")
-		} else {
-			sig.WriteString("...")
+	var params []string
+	for _, field := range fields.List {
+		constraint := formatExpr(field.Type)
+		for _, name := range field.Names {
+			params = append(params, name.Name+" "+constraint)
 		}
 	}
+	return params
+}
 
-	return sig.String()
+// formatFieldList formats parameters, results or type parameters as written,
+// e.g. "a, b int, opts ...Option"
+func formatFieldList(fields *ast.FieldList) string {
+	if fields == nil {
+		return ""
+	}
+
+	parts := make([]string, 0, len(fields.List))
+	for _, field := range fields.List {
+		typ := formatExpr(field.Type)
+		if len(field.Names) == 0 {
+			parts = append(parts, typ)
+			continue
+		}
+
+		names := make([]string, len(field.Names))
+		for i, name := range field.Names {
+			names[i] = name.Name
+		}
+		parts = append(parts, strings.Join(names, ", ")+" "+typ)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// formatExpr formats a type expression as Go source
+func formatExpr(expr ast.Expr) string {
+	var buf bytes.Buffer
+	if err := printer.Fprint(&buf, token.NewFileSet(), expr); err != nil {
+		return ""
+	}
+	return buf.String()
 }
@@ -296,3 +296,115 @@ func invalid syntax {
 		t.Error("Expected error for invalid syntax, got nil")
 	}
 }
+
+func TestParseBlockAndGroupedDocumentation(t *testing.T) {
+	code := `package main
+
+/*
+Config holds the server settings.
+*/
+type Config struct {
+	Port int
+}
+
+/* Start starts the server */
+func Start(cfg Config) error {
+	return nil
+}
+
+type (
+	// Handler handles a request
+	Handler func()
+
+	// Middleware wraps a handler
+	Middleware func(Handler) Handler
+)
+`
+	parser := NewParser()
+	result, err := parser.Parse([]byte(code), "test.go")
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	expected := map[string]string{
+		"Config":     "Config holds the server settings.",
+		"Start":      "Start starts the server",
+		"Handler":    "Handler handles a request",
+		"Middleware": "Middleware wraps a handler",
+	}
+
+	for _, sym := range result.Symbols {
+		want, ok := expected[sym.Name]
+		if !ok {
+			continue
+		}
+		if sym.Documentation != want {
+			t.Errorf("Expected documentation for %s to be '%s', got '%s'", sym.Name, want, sym.Documentation)
+		}
+		delete(expected, sym.Name)
+	}
+
+	for name := range expected {
+		t.Errorf("Expected symbol %s not found", name)
+	}
+}
+
+func TestParseGenerics(t *testing.T) {
+	code := `package main
+
+// Map applies f to every element of s
+func Map[T any, U any](s []T, f func(T) U) []U {
+	return nil
+}
+
+// Pair holds two values
+type Pair[K comparable, V any] struct {
+	Key   K
+	Value V
+}
+
+func (p *Pair[K, V]) Swap() (V, K) {
+	return p.Value, p.Key
+}
+`
+	parser := NewParser()
+	result, err := parser.Parse([]byte(code), "test.go")
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	symbols := make(map[string]*types.Symbol)
+	for _, sym := range result.Symbols {
+		symbols[sym.Name] = sym
+	}
+
+	fn := symbols["Map"]
+	if fn == nil {
+		t.Fatal("Expected symbol Map, names should not include type parameters")
+	}
+	if fn.Signature != "func Map[T any, U any](s []T, f func(T) U) []U" {
+		t.Errorf("Unexpected signature: %s", fn.Signature)
+	}
+	if len(fn.TypeParams) != 2 || fn.TypeParams[0] != "T any" || fn.TypeParams[1] != "U any" {
+		t.Errorf("Expected type params [T any U any], got %v", fn.TypeParams)
+	}
+
+	pair := symbols["Pair"]
+	if pair == nil {
+		t.Fatal("Expected symbol Pair, names should not include type parameters")
+	}
+	if pair.Type != types.SymbolTypeStruct {
+		t.Errorf("Expected type struct, got %s", pair.Type)
+	}
+	if len(pair.TypeParams) != 2 || pair.TypeParams[0] != "K comparable" || pair.TypeParams[1] != "V any" {
+		t.Errorf("Expected type params [K comparable V any], got %v", pair.TypeParams)
+	}
+
+	swap := symbols["Swap"]
+	if swap == nil {
+		t.Fatal("Expected symbol Swap")
+	}
+	if swap.Signature != "func (p *Pair[K, V]) Swap() (V, K)" {
+		t.Errorf("Unexpected signature: %s", swap.Signature)
+	}
+}
@@ -37,6 +37,9 @@ func (p *HTMLParser) Parse(content []byte, filePath string) (*types.ParseResult,
 	// Extract elements with classes
 	p.extractClasses(contentStr, result)
 
+	// Extract custom elements (web components)
+	p.extractCustomElements(contentStr, result)
+
 	// Extract script/link tags (imports)
 	p.extractImports(contentStr, result)
 
@@ -47,7 +50,7 @@ func (p *HTMLParser) Parse(content []byte, filePath string) (*types.ParseResult,
 
 func (p *HTMLParser) extractIDs(content string, result *types.ParseResult) {
 	// Match id="..." or id='...'
-	idRe := regexp.MustCompile(`<(\w+)[^>]*\sid=["']([^"']+)["']`)
+	idRe := regexp.MustCompile(`<([\w-]+)[^>]*\sid=["']([^"']+)["']`)
 
 	matches := idRe.FindAllStringSubmatchIndex(content, -1)
 	for _, match := range matches {
@@ -75,7 +78,7 @@ func (p *HTMLParser) extractIDs(content string, result *types.ParseResult) {
 
 func (p *HTMLParser) extractClasses(content string, result *types.ParseResult) {
 	// Match class="..." or class='...'
-	classRe := regexp.MustCompile(`<(\w+)[^>]*\sclass=["']([^"']+)["']`)
+	classRe := regexp.MustCompile(`<([\w-]+)[^>]*\sclass=["']([^"']+)["']`)
 
 	seen := make(map[string]bool)
 
@@ -117,6 +120,39 @@ func (p *HTMLParser) extractClasses(content string, result *types.ParseResult) {
 	}
 }
 
+func (p *HTMLParser) extractCustomElements(content string, result *types.ParseResult) {
+	// Custom element names must contain a hyphen: <user-card>, <app-root>
+	elementRe := regexp.MustCompile(`<([a-z][a-z0-9]*-[\w-]*)[\s/>]`)
+
+	seen := make(map[string]bool)
+
+	matches := elementRe.FindAllStringSubmatchIndex(content, -1)
+	for _, match := range matches {
+		tag := content[match[2]:match[3]]
+		if seen[tag] {
+			continue
+		}
+		seen[tag] = true
+
+		lineNum := strings.Count(content[:match[0]], "\n") + 1
+
+		symbol := &types.Symbol{
+			Name:       tag,
+			Type:       types.SymbolTypeClass,
+			StartLine:  lineNum,
+			EndLine:    lineNum,
+			Visibility: types.VisibilityPublic,
+			Signature:  "<" + tag + ">",
+			Metadata: map[string]interface{}{
+				"tag":            tag,
+				"custom_element": true,
+			},
+		}
+
+		result.Symbols = append(result.Symbols, symbol)
+	}
+}
+
 func (p *HTMLParser) extractImports(content string, result *types.ParseResult) {
 	// Script tags
 	scriptRe := regexp.MustCompile(`<script[^>]*\ssrc=["']([^"']+)["']`)
@@ -127,9 +163,9 @@ func (p *HTMLParser) extractImports(content string, result *types.ParseResult) {
 		lineNum := strings.Count(content[:match[0]], "\n") + 1
 
 		imp := &types.Import{
-			Source: src,
-			Line:   lineNum,
-			Alias:  "script",
+			Source:     src,
+			ImportType: importType(src),
+			LineNumber: lineNum,
 		}
 
 		result.Imports = append(result.Imports, imp)
@@ -146,12 +182,20 @@ func (p *HTMLParser) extractImports(content string, result *types.ParseResult) {
 		// Only add if it's a stylesheet
 		if strings.Contains(content[match[0]:match[1]], "stylesheet") {
 			imp := &types.Import{
-				Source: href,
-				Line:   lineNum,
-				Alias:  "stylesheet",
+				Source:     href,
+				ImportType: importType(href),
+				LineNumber: lineNum,
 			}
 
 			result.Imports = append(result.Imports, imp)
 		}
 	}
 }
+
+// importType tells URLs such as CDN scripts apart from files of the project
+func importType(source string) types.ImportType {
+	if strings.Contains(source, "://") || strings.HasPrefix(source, "//") {
+		return types.ImportTypeExternal
+	}
+	return types.ImportTypeLocal
+}
@@ -46,6 +46,8 @@ func (p *JavaParser) Parse(content []byte, filePath string) (*types.ParseResult,
 
 	result.Metadata["language"] = "java"
 
+	parser.MarkDeprecated(p.Language(), result.Symbols)
+
 	return result, nil
 }
 
@@ -163,6 +165,8 @@ func (p *JavaParser) extractTypes(lines []string, content string, result *types.
 			}
 		}
 
+		p.addAnnotations(symbol, p.extractAnnotations(lines, strings.Count(content[:match[8]], "\n")))
+
 		result.Symbols = append(result.Symbols, symbol)
 
 		// Add relationships for extends
@@ -256,6 +260,8 @@ func (p *JavaParser) extractMembers(lines []string, content string, result *type
 			}
 		}
 
+		p.addAnnotations(symbol, p.extractAnnotations(lines, strings.Count(content[:match[8]], "\n")))
+
 		result.Symbols = append(result.Symbols, symbol)
 	}
 
@@ -297,21 +303,66 @@ func (p *JavaParser) extractMembers(lines []string, content string, result *type
 			}
 		}
 
+		p.addAnnotations(symbol, p.extractAnnotations(lines, strings.Count(content[:match[8]], "\n")))
+
 		result.Symbols = append(result.Symbols, symbol)
 	}
 }
 
-func (p *JavaParser) parseVisibility(vis string) types.Visibility {
-	switch strings.ToLower(vis) {
-	case "public":
-		return types.VisibilityPublic
-	case "private":
-		return types.VisibilityPrivate
-	case "protected":
-		return types.VisibilityProtected
-	default:
-		return types.VisibilityPackage // Java default is package-private
+// annotationRe matches one annotation, e.g. @GetMapping("/users/{id}")
+var annotationRe = regexp.MustCompile(`^@([\w.]+)\s*(\([^)]*\))?`)
+
+// extractAnnotations returns the annotations on the lines directly above the
+// declaration on line declLine (0-based), in source order
+func (p *JavaParser) extractAnnotations(lines []string, declLine int) []string {
+	var annotations []string
+	for i := declLine - 1; i >= 0; i-- {
+		lineAnnotations, rest := leadingAnnotations(lines[i])
+		if len(lineAnnotations) == 0 || rest != "" {
+			break
+		}
+		annotations = append(lineAnnotations, annotations...)
 	}
+	return annotations
+}
+
+// leadingAnnotations splits the annotations at the start of a line from
+// whatever follows them
+func leadingAnnotations(line string) ([]string, string) {
+	var annotations []string
+	rest := strings.TrimSpace(line)
+	for !strings.HasPrefix(rest, "@interface") {
+		match := annotationRe.FindString(rest)
+		if match == "" {
+			break
+		}
+		annotations = append(annotations, match)
+		rest = strings.TrimSpace(rest[len(match):])
+	}
+	return annotations, rest
+}
+
+// addAnnotations records annotations in the symbol's metadata, both verbatim
+// and as decorator names
+func (p *JavaParser) addAnnotations(symbol *types.Symbol, annotations []string) {
+	if len(annotations) == 0 {
+		return
+	}
+
+	decorators := make([]string, 0, len(annotations))
+	for _, annotation := range annotations {
+		decorators = append(decorators, "@"+annotationRe.FindStringSubmatch(annotation)[1])
+	}
+
+	if symbol.Metadata == nil {
+		symbol.Metadata = make(map[string]interface{})
+	}
+	symbol.Metadata["annotations"] = annotations
+	symbol.Metadata["decorators"] = decorators
+}
+
+func (p *JavaParser) parseVisibility(vis string) types.Visibility {
+	return parser.DetermineVisibility("java", "", strings.ToLower(vis))
 }
 
 func (p *JavaParser) findClosingBrace(lines []string, startLine int) int {
@@ -0,0 +1,63 @@
+package java
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/aaamil13/CodeIndexerMCP/pkg/types"
+)
+
+func TestParseAnnotations(t *testing.T) {
+	code := `package com.example.web;
+
+@RestController
+@RequestMapping("/users")
+public class UserController {
+
+    /**
+     * Looks up a single user
+     */
+    @GetMapping("/{id}")
+    @ResponseBody
+    public User getUser(long id) {
+        return service.find(id);
+    }
+
+    public void helper() {
+    }
+}
+`
+	parser := NewParser()
+	result, err := parser.Parse([]byte(code), "UserController.java")
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	symbols := make(map[string]*types.Symbol)
+	for _, sym := range result.Symbols {
+		symbols[sym.Name] = sym
+	}
+
+	controller := symbols["UserController"]
+	if controller == nil {
+		t.Fatal("Expected UserController class")
+	}
+	if got := controller.Metadata["decorators"]; !reflect.DeepEqual(got, []string{"@RestController", "@RequestMapping"}) {
+		t.Errorf("Expected class decorators [@RestController @RequestMapping], got %v", got)
+	}
+
+	getUser := symbols["getUser"]
+	if getUser == nil {
+		t.Fatal("Expected getUser method")
+	}
+	if got := getUser.Metadata["annotations"]; !reflect.DeepEqual(got, []string{`@GetMapping("/{id}")`, "@ResponseBody"}) {
+		t.Errorf("Expected method annotations [@GetMapping(\"/{id}\") @ResponseBody], got %v", got)
+	}
+	if got := getUser.Metadata["decorators"]; !reflect.DeepEqual(got, []string{"@GetMapping", "@ResponseBody"}) {
+		t.Errorf("Expected method decorators [@GetMapping @ResponseBody], got %v", got)
+	}
+
+	if helper := symbols["helper"]; helper == nil || helper.Metadata["decorators"] != nil {
+		t.Errorf("Expected helper without decorators, got %+v", helper)
+	}
+}
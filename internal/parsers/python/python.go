@@ -6,6 +6,7 @@ import (
 	"regexp"
 	"strings"
 
+	"github.com/aaamil13/CodeIndexerMCP/internal/parser"
 	"github.com/aaamil13/CodeIndexerMCP/pkg/types"
 )
 
@@ -50,6 +51,9 @@ func (p *Parser) Parse(content []byte, filePath string) (*types.ParseResult, err
 	var docstringLines []string
 	inDocstring := false
 	docstringMarker := ""
+	docstringStart := 0
+	deprecatedComment := false     // A "# deprecated" comment awaits the next definition
+	var undocumented *types.Symbol // The definition on the line above, whose docstring may follow
 
 	// Regex patterns
 	classRegex := regexp.MustCompile(`^class\s+(\w+)(\(.*?\))?:`)
@@ -57,6 +61,7 @@ func (p *Parser) Parse(content []byte, filePath string) (*types.ParseResult, err
 	importRegex := regexp.MustCompile(`^import\s+(.+)`)
 	fromImportRegex := regexp.MustCompile(`^from\s+(.+?)\s+import\s+(.+)`)
 	decoratorRegex := regexp.MustCompile(`^@(\w+)`)
+	deprecatedRegex := regexp.MustCompile(`(?i)^#\s*deprecated\b`)
 	varRegex := regexp.MustCompile(`^(\w+)\s*[:=]`)
 
 	for scanner.Scan() {
@@ -68,6 +73,7 @@ func (p *Parser) Parse(content []byte, filePath string) (*types.ParseResult, err
 		if strings.HasPrefix(trimmed, `"""`) || strings.HasPrefix(trimmed, "'''") {
 			if !inDocstring {
 				inDocstring = true
+				docstringStart = lineNumber
 				docstringMarker = trimmed[:3]
 				docstringLines = []string{strings.TrimPrefix(trimmed, docstringMarker)}
 				if strings.HasSuffix(trimmed, docstringMarker) && len(trimmed) > 6 {
@@ -79,6 +85,12 @@ func (p *Parser) Parse(content []byte, filePath string) (*types.ParseResult, err
 				inDocstring = false
 				docstringLines = append(docstringLines, strings.TrimSuffix(trimmed, docstringMarker))
 			}
+
+			// A docstring documents the definition it directly follows
+			if !inDocstring && undocumented != nil {
+				undocumented.Documentation = strings.Join(docstringLines, "\n")
+				undocumented = nil
+			}
 			continue
 		}
 
@@ -89,11 +101,15 @@ func (p *Parser) Parse(content []byte, filePath string) (*types.ParseResult, err
 
 		// Skip empty lines and comments
 		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			if deprecatedRegex.MatchString(trimmed) {
+				deprecatedComment = true
+			}
 			continue
 		}
 
 		// Get indentation level
 		indent := len(line) - len(trimmed)
+		undocumented = nil
 
 		// Reset class context if we're back at top level
 		if indent == 0 && currentClass != "" {
@@ -110,12 +126,11 @@ func (p *Parser) Parse(content []byte, filePath string) (*types.ParseResult, err
 			}
 
 			symbol := &types.Symbol{
-				Name:          className,
-				Type:          types.SymbolTypeClass,
-				StartLine:     lineNumber,
-				Visibility:    p.getVisibility(className),
-				IsExported:    p.isExported(className),
-				Documentation: strings.Join(docstringLines, "\n"),
+				Name:       className,
+				Type:       types.SymbolTypeClass,
+				StartLine:  lineNumber,
+				Visibility: p.getVisibility(className),
+				IsExported: p.isExported(className),
 			}
 
 			if parentClasses != "" {
@@ -123,11 +138,15 @@ func (p *Parser) Parse(content []byte, filePath string) (*types.ParseResult, err
 					"parent_classes": parentClasses,
 				}
 			}
+			if deprecatedComment {
+				markDeprecated(symbol)
+				deprecatedComment = false
+			}
 
 			result.Symbols = append(result.Symbols, symbol)
 			currentClass = className
 			currentClassSymbol = symbol
-			docstringLines = []string{}
+			undocumented = symbol
 			continue
 		}
 
@@ -138,7 +157,7 @@ func (p *Parser) Parse(content []byte, filePath string) (*types.ParseResult, err
 			params := match[3]
 			returnType := ""
 			if len(match) > 4 {
-				returnType = strings.TrimSpace(strings.TrimPrefix(match[4], "->"))
+				returnType = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(match[4]), "->"))
 			}
 
 			symbolType := types.SymbolTypeFunction
@@ -155,15 +174,16 @@ func (p *Parser) Parse(content []byte, filePath string) (*types.ParseResult, err
 			signature := p.buildSignature(funcName, params, returnType, isAsync)
 
 			symbol := &types.Symbol{
-				Name:          funcName,
-				Type:          symbolType,
-				Signature:     signature,
-				ParentID:      parentID,
-				StartLine:     lineNumber,
-				Visibility:    p.getVisibility(funcName),
-				IsExported:    p.isExported(funcName),
-				IsAsync:       isAsync,
-				Documentation: strings.Join(docstringLines, "\n"),
+				Name:       funcName,
+				Type:       symbolType,
+				Signature:  signature,
+				ParentID:   parentID,
+				StartLine:  lineNumber,
+				Visibility: p.getVisibility(funcName),
+				IsExported: p.isExported(funcName),
+				IsAsync:    isAsync,
+				Parameters: parseParameters(params),
+				ReturnType: returnType,
 			}
 
 			// Check for decorators
@@ -175,9 +195,24 @@ func (p *Parser) Parse(content []byte, filePath string) (*types.ParseResult, err
 					}
 				}
 			}
+			if deprecatedComment {
+				markDeprecated(symbol)
+				deprecatedComment = false
+			}
 
 			result.Symbols = append(result.Symbols, symbol)
-			docstringLines = []string{}
+			undocumented = symbol
+			continue
+		}
+
+		// A complete-looking definition that didn't match is malformed; unbalanced
+		// parentheses mean the signature continues on the next line
+		if isDefinition(trimmed) && strings.Count(trimmed, "(") == strings.Count(trimmed, ")") {
+			result.Errors = append(result.Errors, types.ParseError{
+				Line:    lineNumber,
+				Column:  indent + 1,
+				Message: "malformed definition: " + trimmed,
+			})
 			continue
 		}
 
@@ -230,6 +265,9 @@ func (p *Parser) Parse(content []byte, filePath string) (*types.ParseResult, err
 			continue
 		}
 
+		// Any other statement ends what a "# deprecated" comment applied to
+		deprecatedComment = false
+
 		// Check for variables (simple detection)
 		if indent == 0 && !strings.HasPrefix(trimmed, "def") && !strings.HasPrefix(trimmed, "class") {
 			if match := varRegex.FindStringSubmatch(trimmed); match != nil {
@@ -253,9 +291,88 @@ func (p *Parser) Parse(content []byte, filePath string) (*types.ParseResult, err
 		}
 	}
 
+	if inDocstring {
+		result.Errors = append(result.Errors, types.ParseError{
+			Line:    docstringStart,
+			Column:  1,
+			Message: "unterminated docstring",
+		})
+	}
+
+	parser.MarkDeprecated(p.Language(), result.Symbols)
+
 	return result, scanner.Err()
 }
 
+// markDeprecated flags a symbol preceded by a "# deprecated" comment
+func markDeprecated(symbol *types.Symbol) {
+	if symbol.Metadata == nil {
+		symbol.Metadata = make(map[string]interface{})
+	}
+	symbol.Metadata[parser.DeprecatedKey] = true
+}
+
+// isDefinition reports whether a line starts a function or class definition
+func isDefinition(trimmed string) bool {
+	return strings.HasPrefix(trimmed, "def ") ||
+		strings.HasPrefix(trimmed, "async def ") ||
+		strings.HasPrefix(trimmed, "class ")
+}
+
+// parseParameters parses a parameter list such as
+// "self, name: str, retries: int = 3, *args, **kwargs", skipping the bare
+// "*" and "/" markers
+func parseParameters(params string) []*types.Parameter {
+	var parsed []*types.Parameter
+	for _, param := range splitTopLevel(params, ',') {
+		param = strings.TrimSpace(param)
+		if param == "" || param == "*" || param == "/" {
+			continue
+		}
+
+		parameter := &types.Parameter{}
+		if parts := splitTopLevel(param, '='); len(parts) > 1 {
+			param = parts[0]
+			parameter.Default = strings.TrimSpace(strings.Join(parts[1:], "="))
+		}
+		if i := strings.Index(param, ":"); i >= 0 {
+			parameter.Type = strings.TrimSpace(param[i+1:])
+			param = param[:i]
+		}
+
+		name := strings.TrimSpace(param)
+		parameter.Variadic = strings.HasPrefix(name, "*")
+		parameter.Name = strings.TrimLeft(name, "*")
+		parsed = append(parsed, parameter)
+	}
+	return parsed
+}
+
+// splitTopLevel splits s at sep where it isn't nested in brackets or quotes
+func splitTopLevel(s string, sep rune) []string {
+	var parts []string
+	depth, start := 0, 0
+	var quote rune
+	for i, r := range s {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case r == '\'' || r == '"':
+			quote = r
+		case r == '(' || r == '[' || r == '{':
+			depth++
+		case r == ')' || r == ']' || r == '}':
+			depth--
+		case r == sep && depth == 0:
+			parts = append(parts, s[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, s[start:])
+}
+
 // buildSignature builds a function signature string
 func (p *Parser) buildSignature(name, params, returnType string, isAsync bool) string {
 	sig := ""
@@ -271,13 +388,7 @@ func (p *Parser) buildSignature(name, params, returnType string, isAsync bool) s
 
 // getVisibility determines visibility based on naming convention
 func (p *Parser) getVisibility(name string) types.Visibility {
-	if strings.HasPrefix(name, "__") && !strings.HasSuffix(name, "__") {
-		return types.VisibilityPrivate
-	}
-	if strings.HasPrefix(name, "_") {
-		return types.VisibilityProtected
-	}
-	return types.VisibilityPublic
+	return parser.DetermineVisibility("python", name, "")
 }
 
 // isExported checks if a symbol is exported (public)
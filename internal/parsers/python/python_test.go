@@ -3,6 +3,7 @@ package python
 import (
 	"testing"
 
+	indexparser "github.com/aaamil13/CodeIndexerMCP/internal/parser"
 	"github.com/aaamil13/CodeIndexerMCP/pkg/types"
 )
 
@@ -369,3 +370,82 @@ func TestParseEmptyFile(t *testing.T) {
 		t.Errorf("Expected 0 symbols for empty file, got %d", len(result.Symbols))
 	}
 }
+
+func TestParseMalformedDefinitions(t *testing.T) {
+	code := `def ok():
+    pass
+
+def missing_colon(x)
+    pass
+
+def multiline(a,
+              b):
+    pass
+`
+	parser := NewParser()
+	result, err := parser.Parse([]byte(code), "test.py")
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	if len(result.Errors) != 1 {
+		t.Fatalf("Expected 1 parse error, got %d: %+v", len(result.Errors), result.Errors)
+	}
+	if result.Errors[0].Line != 4 {
+		t.Errorf("Expected error on line 4, got line %d", result.Errors[0].Line)
+	}
+
+	found := false
+	for _, sym := range result.Symbols {
+		if sym.Name == "ok" {
+			found = true
+		}
+	}
+	if !found {
+		t.Error("Expected well-formed function ok to still be parsed")
+	}
+}
+
+func TestParseDocstringBelongsToItsDefinition(t *testing.T) {
+	code := `
+def first():
+    """First function."""
+    return 1
+
+def second():
+    return 2
+
+class Widget:
+    """Deprecated: use Gadget."""
+
+    def render(self):
+        return None
+`
+	parser := NewParser()
+	result, err := parser.Parse([]byte(code), "test.py")
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	symbols := make(map[string]*types.Symbol)
+	for _, sym := range result.Symbols {
+		symbols[sym.Name] = sym
+	}
+
+	if doc := symbols["first"].Documentation; doc != "First function." {
+		t.Errorf("Expected first's own docstring, got %q", doc)
+	}
+	if doc := symbols["second"].Documentation; doc != "" {
+		t.Errorf("Expected second to have no documentation, got %q", doc)
+	}
+	if doc := symbols["render"].Documentation; doc != "" {
+		t.Errorf("Expected render not to inherit Widget's docstring, got %q", doc)
+	}
+
+	if symbols["Widget"].Metadata[indexparser.DeprecatedKey] != true {
+		t.Error("Expected Widget to be deprecated by its docstring")
+	}
+	if symbols["render"].Metadata[indexparser.DeprecatedKey] == true {
+		t.Error("Expected render not to be deprecated")
+	}
+}
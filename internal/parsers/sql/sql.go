@@ -3,6 +3,7 @@ package sql
 import (
 	"regexp"
 	"strings"
+	"unicode"
 
 	"github.com/aaamil13/CodeIndexerMCP/internal/parser"
 	"github.com/aaamil13/CodeIndexerMCP/pkg/types"
@@ -36,6 +37,10 @@ func (p *SQLParser) Parse(content []byte, filePath string) (*types.ParseResult,
 
 	// Extract tables
 	p.extractTables(contentStr, result)
+	p.extractAlteredForeignKeys(contentStr, result)
+
+	// Extract indexes
+	p.extractIndexes(contentStr, result)
 
 	// Extract views
 	p.extractViews(contentStr, result)
@@ -59,36 +64,339 @@ func (p *SQLParser) removeComments(content string) string {
 	singleLineRe := regexp.MustCompile(`--[^\n]*`)
 	content = singleLineRe.ReplaceAllString(content, "")
 
-	// Remove multi-line comments
+	// Remove multi-line comments, keeping their line breaks so line numbers
+	// still match the file
 	multiLineRe := regexp.MustCompile(`/\*[\s\S]*?\*/`)
-	content = multiLineRe.ReplaceAllString(content, "")
+	content = multiLineRe.ReplaceAllStringFunc(content, func(comment string) string {
+		return strings.Repeat("\n", strings.Count(comment, "\n"))
+	})
 
 	return content
 }
 
+// Metadata keys set on schema symbols
+const (
+	TableKey      = "table"     // true on tables; the table's name on columns
+	ColumnKey     = "column"    // true on columns
+	DataTypeKey   = "data_type" // A column's type as written, e.g. "VARCHAR(255)"
+	PrimaryKeyKey = "primary_key"
+	NotNullKey    = "not_null"
+	ReferencesKey = "references" // The "table.column" a foreign key column points at
+	IndexKey      = "index"      // true on indexes
+	ColumnsKey    = "columns"    // An index's columns
+	UniqueKey     = "unique"
+	ViewKey       = "view" // true on views
+)
+
+// columnConstraint matches the first constraint keyword after a column's type
+var columnConstraint = regexp.MustCompile(`(?i)\b(?:CONSTRAINT|PRIMARY|NOT|NULL|DEFAULT|REFERENCES|UNIQUE|CHECK|AUTO_INCREMENT|AUTOINCREMENT|GENERATED|COLLATE|IDENTITY)\b`)
+
+// referencesClause matches a foreign key target: REFERENCES table (columns)
+var referencesClause = regexp.MustCompile(`(?i)\bREFERENCES\s+(?:[\w."` + "`" + `\[\]]+\.)?["` + "`" + `\[]?(\w+)["` + "`" + `\]]?\s*(?:\(([^)]*)\))?`)
+
+// tableConstraint matches table-level constraints that name their columns
+var tableConstraint = regexp.MustCompile(`(?i)^(?:CONSTRAINT\s+\S+\s+)?(PRIMARY\s+KEY|FOREIGN\s+KEY|UNIQUE|CHECK|INDEX|KEY|EXCLUDE)\b\s*(?:\w+\s*)?(?:\(([^)]*)\))?`)
+
+// indexConstraint matches MySQL's inline KEY [name] [USING type] (columns)
+var indexConstraint = regexp.MustCompile(`(?i)^(?:INDEX|KEY)\s*(?:["` + "`" + `]?\w+["` + "`" + `]?\s+)?(?:USING\s+\w+\s*)?\(([^)]*)\)`)
+
+// alterTable matches the start of an ALTER TABLE statement, capturing the table
+var alterTable = regexp.MustCompile(`(?i)ALTER\s+TABLE\s+(?:ONLY\s+)?(?:IF\s+EXISTS\s+)?(?:ONLY\s+)?(?:[\w."` + "`" + `\[\]]+\.)?["` + "`" + `\[]?(\w+)["` + "`" + `\]]?`)
+
+// foreignKeyClause matches FOREIGN KEY (columns), capturing the columns
+var foreignKeyClause = regexp.MustCompile(`(?i)\bFOREIGN\s+KEY\s*\(([^)]*)\)`)
+
+// isTableConstraint reports whether a table body entry is a constraint rather
+// than a column. INDEX and KEY only start a constraint when a column list
+// follows, so columns named key or index, e.g. "key VARCHAR(20)", stay columns.
+func isTableConstraint(definition string) bool {
+	m := tableConstraint.FindStringSubmatch(definition)
+	if m == nil {
+		return false
+	}
+	switch strings.ToUpper(m[1]) {
+	case "INDEX", "KEY":
+		index := indexConstraint.FindStringSubmatch(definition)
+		return index != nil && strings.ContainsFunc(index[1], unicode.IsLetter)
+	}
+	return true
+}
+
 func (p *SQLParser) extractTables(content string, result *types.ParseResult) {
 	// CREATE TABLE
-	tableRe := regexp.MustCompile(`(?i)CREATE\s+TABLE\s+(?:IF\s+NOT\s+EXISTS\s+)?(?:[\w.]+\.)?(\w+)\s*\(`)
+	tableRe := regexp.MustCompile(`(?i)CREATE\s+(?:(?:GLOBAL\s+|LOCAL\s+)?(?:TEMP|TEMPORARY)\s+)?TABLE\s+(?:IF\s+NOT\s+EXISTS\s+)?(?:[\w."` + "`" + `\[\]]+\.)?["` + "`" + `\[]?(\w+)["` + "`" + `\]]?\s*\(`)
 
 	matches := tableRe.FindAllStringSubmatchIndex(content, -1)
 	for _, match := range matches {
 		name := content[match[2]:match[3]]
 		lineNum := strings.Count(content[:match[0]], "\n") + 1
 
+		body, bodyEnd := enclosed(content, match[1])
 		symbol := &types.Symbol{
 			Name:       name,
-			Type:       types.SymbolTypeClass, // Use class for tables
+			Type:       types.SymbolTypeStruct, // Tables are records of columns
 			StartLine:  lineNum,
-			EndLine:    lineNum,
+			EndLine:    strings.Count(content[:bodyEnd], "\n") + 1,
 			Visibility: types.VisibilityPublic,
 			Signature:  "CREATE TABLE " + name,
 			Metadata: map[string]interface{}{
-				"table": true,
+				TableKey: true,
 			},
 		}
+		result.Symbols = append(result.Symbols, symbol)
+
+		p.extractColumns(content, match[1], body, name, result)
+	}
+}
 
+// extractColumns adds the columns defined in a table's body, which starts
+// at offset in content, as fields. Table-level PRIMARY KEY and FOREIGN KEY
+// constraints are recorded on the columns they name.
+func (p *SQLParser) extractColumns(content string, offset int, body, table string, result *types.ParseResult) {
+	columns := make(map[string]*types.Symbol)
+	var constraints []string
+
+	for _, part := range splitTopLevel(body) {
+		definition := strings.Join(strings.Fields(body[part[0]:part[1]]), " ")
+		if definition == "" {
+			continue
+		}
+		if isTableConstraint(definition) {
+			constraints = append(constraints, definition)
+			continue
+		}
+
+		name, rest := columnName(definition)
+		if name == "" {
+			continue
+		}
+		dataType := rest
+		if loc := columnConstraint.FindStringIndex(rest); loc != nil {
+			dataType = strings.TrimSpace(rest[:loc[0]])
+		}
+		upper := strings.ToUpper(rest)
+
+		lead := len(body[part[0]:part[1]]) - len(strings.TrimLeft(body[part[0]:part[1]], " \t\r\n"))
+		lineNum := strings.Count(content[:offset+part[0]+lead], "\n") + 1
+		symbol := &types.Symbol{
+			Name:       name,
+			Type:       types.SymbolTypeField,
+			Signature:  definition,
+			StartLine:  lineNum,
+			EndLine:    lineNum,
+			Visibility: types.VisibilityPublic,
+			Metadata: map[string]interface{}{
+				TableKey:    table,
+				ColumnKey:   true,
+				DataTypeKey: dataType,
+			},
+		}
+		if strings.Contains(upper, "PRIMARY KEY") {
+			symbol.Metadata[PrimaryKeyKey] = true
+		}
+		if strings.Contains(upper, "NOT NULL") || symbol.Metadata[PrimaryKeyKey] == true {
+			symbol.Metadata[NotNullKey] = true
+		}
+		if m := referencesClause.FindStringSubmatch(rest); m != nil {
+			symbol.Metadata[ReferencesKey] = reference(m[1], m[2], 0)
+		}
+
+		columns[strings.ToLower(name)] = symbol
 		result.Symbols = append(result.Symbols, symbol)
 	}
+
+	for _, constraint := range constraints {
+		m := tableConstraint.FindStringSubmatch(constraint)
+		kind := strings.ToUpper(strings.Join(strings.Fields(m[1]), " "))
+		names := splitNames(m[2])
+
+		switch kind {
+		case "PRIMARY KEY":
+			for _, name := range names {
+				if column, ok := columns[strings.ToLower(name)]; ok {
+					column.Metadata[PrimaryKeyKey] = true
+					column.Metadata[NotNullKey] = true
+				}
+			}
+		case "FOREIGN KEY":
+			ref := referencesClause.FindStringSubmatch(constraint)
+			if ref == nil {
+				continue
+			}
+			for i, name := range names {
+				if column, ok := columns[strings.ToLower(name)]; ok {
+					column.Metadata[ReferencesKey] = reference(ref[1], ref[2], i)
+				}
+			}
+		}
+	}
+}
+
+// extractAlteredForeignKeys records the foreign keys that ALTER TABLE ... ADD
+// [CONSTRAINT name] FOREIGN KEY (columns) REFERENCES ... adds on the columns
+// they name. Only tables created in the same file are updated, since the
+// parser doesn't see other files' columns.
+func (p *SQLParser) extractAlteredForeignKeys(content string, result *types.ParseResult) {
+	for _, match := range alterTable.FindAllStringSubmatchIndex(content, -1) {
+		table := content[match[2]:match[3]]
+		statement := content[match[1]:]
+		if end := strings.IndexByte(statement, ';'); end >= 0 {
+			statement = statement[:end]
+		}
+
+		for _, fk := range foreignKeyClause.FindAllStringSubmatchIndex(statement, -1) {
+			ref := referencesClause.FindStringSubmatch(statement[fk[1]:])
+			if ref == nil {
+				continue
+			}
+			for i, name := range splitNames(statement[fk[2]:fk[3]]) {
+				if column := findColumn(result.Symbols, table, name); column != nil {
+					column.Metadata[ReferencesKey] = reference(ref[1], ref[2], i)
+				}
+			}
+		}
+	}
+}
+
+// findColumn returns the column of a table among symbols, matching names
+// case-insensitively as SQL does
+func findColumn(symbols []*types.Symbol, table, name string) *types.Symbol {
+	for _, symbol := range symbols {
+		if symbol.Metadata[ColumnKey] != true || !strings.EqualFold(symbol.Name, name) {
+			continue
+		}
+		if owner, _ := symbol.Metadata[TableKey].(string); strings.EqualFold(owner, table) {
+			return symbol
+		}
+	}
+	return nil
+}
+
+func (p *SQLParser) extractIndexes(content string, result *types.ParseResult) {
+	// CREATE INDEX
+	indexRe := regexp.MustCompile(`(?i)CREATE\s+(UNIQUE\s+)?(?:CLUSTERED\s+|NONCLUSTERED\s+)?INDEX\s+(?:CONCURRENTLY\s+)?(?:IF\s+NOT\s+EXISTS\s+)?(?:[\w."` + "`" + `\[\]]+\.)?["` + "`" + `\[]?(\w+)["` + "`" + `\]]?\s+ON\s+(?:ONLY\s+)?(?:[\w."` + "`" + `\[\]]+\.)?["` + "`" + `\[]?(\w+)["` + "`" + `\]]?\s*(?:USING\s+\w+\s*)?\(([^)]*)\)`)
+
+	matches := indexRe.FindAllStringSubmatchIndex(content, -1)
+	for _, match := range matches {
+		unique := match[2] != -1
+		name := content[match[4]:match[5]]
+		table := content[match[6]:match[7]]
+		columns := splitNames(content[match[8]:match[9]])
+		lineNum := strings.Count(content[:match[0]], "\n") + 1
+
+		sig := "CREATE INDEX "
+		if unique {
+			sig = "CREATE UNIQUE INDEX "
+		}
+		sig += name + " ON " + table + " (" + strings.Join(columns, ", ") + ")"
+
+		symbol := &types.Symbol{
+			Name:       name,
+			Type:       types.SymbolTypeVariable,
+			StartLine:  lineNum,
+			EndLine:    strings.Count(content[:match[1]], "\n") + 1,
+			Visibility: types.VisibilityPublic,
+			Signature:  sig,
+			Metadata: map[string]interface{}{
+				IndexKey:   true,
+				TableKey:   table,
+				ColumnsKey: columns,
+				UniqueKey:  unique,
+			},
+		}
+
+		result.Symbols = append(result.Symbols, symbol)
+	}
+}
+
+// enclosed returns the text from start up to the parenthesis closing the one
+// just before it, and the offset of that parenthesis
+func enclosed(content string, start int) (string, int) {
+	depth := 1
+	var quote byte
+	for i := start; i < len(content); i++ {
+		c := content[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"' || c == '`':
+			quote = c
+		case c == '(':
+			depth++
+		case c == ')':
+			if depth--; depth == 0 {
+				return content[start:i], i
+			}
+		}
+	}
+	return content[start:], len(content)
+}
+
+// splitTopLevel returns the start and end offsets of the parts of a list
+// separated by commas that aren't nested in parentheses or quotes
+func splitTopLevel(list string) [][2]int {
+	var parts [][2]int
+	depth, start := 0, 0
+	var quote byte
+	for i := 0; i < len(list); i++ {
+		c := list[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"' || c == '`':
+			quote = c
+		case c == '(':
+			depth++
+		case c == ')':
+			depth--
+		case c == ',' && depth == 0:
+			parts = append(parts, [2]int{start, i})
+			start = i + 1
+		}
+	}
+	return append(parts, [2]int{start, len(list)})
+}
+
+// columnName splits a column definition into its unquoted name and the rest
+func columnName(definition string) (string, string) {
+	if definition == "" {
+		return "", ""
+	}
+	if close := map[byte]byte{'"': '"', '`': '`', '[': ']'}[definition[0]]; close != 0 {
+		if end := strings.IndexByte(definition[1:], close); end >= 0 {
+			return definition[1 : end+1], strings.TrimSpace(definition[end+2:])
+		}
+	}
+	name, rest, _ := strings.Cut(definition, " ")
+	return name, strings.TrimSpace(rest)
+}
+
+// splitNames splits a comma-separated list of column names, unquoting them
+// and dropping sort orders such as DESC
+func splitNames(list string) []string {
+	names := []string{}
+	for _, name := range strings.Split(list, ",") {
+		fields := strings.Fields(name)
+		if len(fields) == 0 {
+			continue
+		}
+		names = append(names, strings.Trim(fields[0], "\"`[]"))
+	}
+	return names
+}
+
+// reference formats the i-th column of a foreign key target as
+// "table.column", or just the table when no columns are given
+func reference(table, columns string, i int) string {
+	names := splitNames(columns)
+	if i < len(names) {
+		return table + "." + names[i]
+	}
+	return table
 }
 
 func (p *SQLParser) extractViews(content string, result *types.ParseResult) {
@@ -108,7 +416,7 @@ func (p *SQLParser) extractViews(content string, result *types.ParseResult) {
 			Visibility: types.VisibilityPublic,
 			Signature:  "CREATE VIEW " + name,
 			Metadata: map[string]interface{}{
-				"view": true,
+				ViewKey: true,
 			},
 		}
 
@@ -114,6 +114,42 @@ func (p *TypeScriptParser) simpleExtraction(content []byte, result *types.ParseR
 			// Handle export statements
 		}
 	}
+
+	for _, symbol := range result.Symbols {
+		if symbol.Documentation == "" {
+			symbol.Documentation = jsDocAbove(lines, symbol.StartLine)
+		}
+	}
+	parser.MarkDeprecated(p.Language(), result.Symbols)
+}
+
+// jsDocAbove returns the text of the /** ... */ comment ending on the line
+// before a declaration, without its comment markers
+func jsDocAbove(lines []string, lineNum int) string {
+	end := lineNum - 2
+	if end < 0 || end >= len(lines) || !strings.HasSuffix(strings.TrimSpace(lines[end]), "*/") {
+		return ""
+	}
+
+	start := end
+	for start >= 0 && !strings.HasPrefix(strings.TrimSpace(lines[start]), "/*") {
+		start--
+	}
+	if start < 0 || !strings.HasPrefix(strings.TrimSpace(lines[start]), "/**") {
+		return "" // A plain block comment
+	}
+
+	var doc []string
+	for _, line := range lines[start : end+1] {
+		line = strings.TrimSpace(line)
+		line = strings.TrimPrefix(line, "/**")
+		line = strings.TrimSuffix(line, "*/")
+		line = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(line), "*"))
+		if line != "" {
+			doc = append(doc, line)
+		}
+	}
+	return strings.Join(doc, "\n")
 }
 
 func (p *TypeScriptParser) extractFunction(line string, lineNum int, result *types.ParseResult) {
@@ -129,7 +165,7 @@ func (p *TypeScriptParser) extractFunction(line string, lineNum int, result *typ
 				Type:       types.SymbolTypeFunction,
 				StartLine:  lineNum,
 				EndLine:    lineNum,
-				Visibility: types.VisibilityPublic,
+				Visibility: parser.DetermineVisibility(p.Language(), name, line),
 				Signature:  line,
 				IsExported: strings.HasPrefix(line, "export"),
 			}
@@ -153,7 +189,7 @@ func (p *TypeScriptParser) extractArrowFunction(line string, lineNum int, result
 				Type:       types.SymbolTypeFunction,
 				StartLine:  lineNum,
 				EndLine:    lineNum,
-				Visibility: types.VisibilityPublic,
+				Visibility: parser.DetermineVisibility(p.Language(), name, line),
 				Signature:  line,
 				IsExported: strings.HasPrefix(line, "export"),
 			}
@@ -175,7 +211,7 @@ func (p *TypeScriptParser) extractClass(line string, lineNum int, result *types.
 				Type:       types.SymbolTypeClass,
 				StartLine:  lineNum,
 				EndLine:    lineNum,
-				Visibility: types.VisibilityPublic,
+				Visibility: parser.DetermineVisibility(p.Language(), name, line),
 				Signature:  line,
 				IsExported: strings.HasPrefix(line, "export"),
 			}
@@ -198,7 +234,7 @@ func (p *TypeScriptParser) extractInterface(line string, lineNum int, result *ty
 				Type:       types.SymbolTypeInterface,
 				StartLine:  lineNum,
 				EndLine:    lineNum,
-				Visibility: types.VisibilityPublic,
+				Visibility: parser.DetermineVisibility(p.Language(), name, line),
 				Signature:  line,
 				IsExported: true, // Interfaces are always exported in TS
 			}
@@ -221,7 +257,7 @@ func (p *TypeScriptParser) extractType(line string, lineNum int, result *types.P
 				Type:       types.SymbolTypeClass, // Use class for type aliases
 				StartLine:  lineNum,
 				EndLine:    lineNum,
-				Visibility: types.VisibilityPublic,
+				Visibility: parser.DetermineVisibility(p.Language(), name, line),
 				Signature:  line,
 				IsExported: strings.HasPrefix(line, "export"),
 			}
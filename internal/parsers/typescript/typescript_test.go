@@ -0,0 +1,63 @@
+package typescript
+
+import (
+	"testing"
+
+	"github.com/aaamil13/CodeIndexerMCP/internal/parser"
+	"github.com/aaamil13/CodeIndexerMCP/pkg/types"
+)
+
+func TestParseJSDocDeprecation(t *testing.T) {
+	code := `/**
+ * Formats a name.
+ * @deprecated Use formatName instead.
+ */
+export function format(name: string): string {
+  return name;
+}
+
+/* @deprecated in a plain block comment */
+function plain(): void {}
+
+/** @deprecated but separated by a blank line */
+
+function detached(): void {}
+
+/**
+ * Formats a full name.
+ */
+export function formatName(first: string, last: string): string {
+  return first + " " + last;
+}
+`
+	result, err := NewTypeScriptParser().Parse([]byte(code), "format.ts")
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	symbols := make(map[string]*types.Symbol)
+	for _, sym := range result.Symbols {
+		symbols[sym.Name] = sym
+	}
+
+	expected := map[string]bool{
+		"format":     true,
+		"plain":      false,
+		"detached":   false,
+		"formatName": false,
+	}
+	for name, deprecated := range expected {
+		sym, ok := symbols[name]
+		if !ok {
+			t.Errorf("Expected symbol %s", name)
+			continue
+		}
+		if got := sym.Metadata[parser.DeprecatedKey] == true; got != deprecated {
+			t.Errorf("Expected %s deprecated=%v, got %v", name, deprecated, got)
+		}
+	}
+
+	if doc := symbols["formatName"].Documentation; doc != "Formats a full name." {
+		t.Errorf("Expected formatName's JSDoc as documentation, got %q", doc)
+	}
+}
@@ -0,0 +1,105 @@
+package utils
+
+import "strings"
+
+// hashCommentLanguages start line comments with # instead of //
+var hashCommentLanguages = map[string]bool{
+	"python":     true,
+	"ruby":       true,
+	"bash":       true,
+	"powershell": true,
+}
+
+// StripCommentsAndStrings blanks out the comments and the contents of the
+// string literals in source code, so that searching it for names, calls or
+// braces only finds code. Blanked characters become spaces and newlines are
+// kept, so offsets and line numbers are unchanged. language picks the
+// comment syntax: # for Python, Ruby and shell, // and /* */ otherwise.
+func StripCommentsAndStrings(code, language string) string {
+	hashComments := hashCommentLanguages[language]
+	src := []byte(code)
+	out := []byte(code)
+
+	blank := func(from, to int) {
+		for i := from; i < to && i < len(out); i++ {
+			if out[i] != '\n' {
+				out[i] = ' '
+			}
+		}
+	}
+
+	for i := 0; i < len(src); {
+		rest := code[i:]
+		switch {
+		case hashComments && src[i] == '#',
+			!hashComments && strings.HasPrefix(rest, "//"):
+			end := strings.IndexByte(rest, '\n')
+			if end < 0 {
+				end = len(rest)
+			}
+			blank(i, i+end)
+			i += end
+
+		case !hashComments && strings.HasPrefix(rest, "/*"):
+			end := strings.Index(rest[2:], "*/")
+			if end < 0 {
+				end = len(rest)
+			} else {
+				end += 4
+			}
+			blank(i, i+end)
+			i += end
+
+		case language == "python" && (strings.HasPrefix(rest, `"""`) || strings.HasPrefix(rest, "'''")):
+			end := strings.Index(rest[3:], rest[:3])
+			if end < 0 {
+				blank(i+3, len(src))
+				return string(out)
+			}
+			blank(i+3, i+3+end)
+			i += end + 6
+
+		case src[i] == '"' || src[i] == '`' || (src[i] == '\'' && !isLifetime(language, rest)):
+			end, closed := stringEnd(rest, src[i] == '`')
+			if closed {
+				blank(i+1, i+end-1)
+			} else {
+				blank(i+1, i+end)
+			}
+			i += end
+
+		default:
+			i++
+		}
+	}
+
+	return string(out)
+}
+
+// stringEnd returns the length of the string literal s starts with, and
+// whether it ends with a closing quote. A backslash escapes the next
+// character unless raw; only raw strings (Go raw strings, template literals)
+// span lines.
+func stringEnd(s string, raw bool) (int, bool) {
+	quote := s[0]
+	for i := 1; i < len(s); i++ {
+		switch {
+		case !raw && s[i] == '\\':
+			i++
+		case s[i] == quote:
+			return i + 1, true
+		case !raw && s[i] == '\n':
+			return i, false
+		}
+	}
+	return len(s), false
+}
+
+// isLifetime reports whether a quote in Rust starts a lifetime or loop
+// label such as 'a rather than a character literal such as 'a'
+func isLifetime(language, s string) bool {
+	if language != "rust" || len(s) < 3 || s[1] == '\\' {
+		return false
+	}
+	return s[2] != '\''
+}
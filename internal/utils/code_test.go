@@ -0,0 +1,57 @@
+package utils
+
+import "testing"
+
+func TestStripCommentsAndStrings(t *testing.T) {
+	tests := []struct {
+		name     string
+		language string
+		code     string
+		want     string
+	}{
+		{
+			name:     "line and block comments",
+			language: "go",
+			code:     "a { // b }\n/* c {\n} */ d",
+			want:     "a {       \n      \n     d",
+		},
+		{
+			name:     "strings and escapes",
+			language: "go",
+			code:     `f("x{\"}", '}') + g`,
+			want:     `f("     ", ' ') + g`,
+		},
+		{
+			name:     "raw string spanning lines",
+			language: "go",
+			code:     "s := `a {\nb` + c",
+			want:     "s := `   \n ` + c",
+		},
+		{
+			name:     "unterminated string stops at the line end",
+			language: "javascript",
+			code:     "x = \"abc\ny()",
+			want:     "x = \"   \ny()",
+		},
+		{
+			name:     "hash comments and docstrings",
+			language: "python",
+			code:     "def f():  # g()\n    \"\"\"h()\n    \"\"\"\n    return k()",
+			want:     "def f():       \n    \"\"\"   \n    \"\"\"\n    return k()",
+		},
+		{
+			name:     "rust lifetimes aren't strings",
+			language: "rust",
+			code:     "fn f<'a>(x: &'a str) -> char { 'b' }",
+			want:     "fn f<'a>(x: &'a str) -> char { ' ' }",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := StripCommentsAndStrings(tt.code, tt.language); got != tt.want {
+				t.Errorf("StripCommentsAndStrings(%q) = %q, want %q", tt.code, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,140 @@
+package utils
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// Encodings reported by DetectEncoding
+const (
+	EncodingUTF8    = "utf-8"
+	EncodingUTF16LE = "utf-16le"
+	EncodingUTF16BE = "utf-16be"
+	EncodingLatin1  = "iso-8859-1"
+	EncodingBinary  = "binary"
+)
+
+// encodingSampleSize is how much of a file the heuristics look at
+const encodingSampleSize = 8192
+
+var (
+	bomUTF8    = []byte{0xEF, 0xBB, 0xBF}
+	bomUTF16LE = []byte{0xFF, 0xFE}
+	bomUTF16BE = []byte{0xFE, 0xFF}
+)
+
+// DetectEncoding guesses the encoding of file content from its byte order
+// mark, or failing that from its bytes: valid UTF-8 is UTF-8, NULs in every
+// other byte are BOM-less UTF-16, other NULs and control bytes mean binary
+// and anything else is taken as Latin-1
+func DetectEncoding(content []byte) string {
+	switch {
+	case bytes.HasPrefix(content, bomUTF8):
+		return EncodingUTF8
+	case bytes.HasPrefix(content, bomUTF16LE):
+		return EncodingUTF16LE
+	case bytes.HasPrefix(content, bomUTF16BE):
+		return EncodingUTF16BE
+	}
+
+	sample := content
+	if len(sample) > encodingSampleSize {
+		sample = sample[:encodingSampleSize]
+	}
+
+	// ASCII text in UTF-16 has a NUL as the high byte of every character
+	var evenNULs, oddNULs, control int
+	for i, b := range sample {
+		switch {
+		case b == 0 && i%2 == 0:
+			evenNULs++
+		case b == 0:
+			oddNULs++
+		case b < 0x20 && b != '\t' && b != '\n' && b != '\r' && b != '\f' && b != 0x1B:
+			control++
+		}
+	}
+	pairs := len(sample) / 2
+	switch {
+	case pairs > 0 && oddNULs > pairs*2/5 && evenNULs < pairs/20:
+		return EncodingUTF16LE
+	case pairs > 0 && evenNULs > pairs*2/5 && oddNULs < pairs/20:
+		return EncodingUTF16BE
+	case evenNULs+oddNULs > 0 || control > len(sample)/10:
+		return EncodingBinary
+	}
+
+	if utf8.Valid(trimIncompleteRune(sample, len(content))) {
+		return EncodingUTF8
+	}
+	return EncodingLatin1
+}
+
+// trimIncompleteRune drops a multi-byte character cut off at the end of a
+// sample taken from a longer file
+func trimIncompleteRune(sample []byte, total int) []byte {
+	if len(sample) == total {
+		return sample
+	}
+	for i := 1; i < utf8.UTFMax && i <= len(sample); i++ {
+		if utf8.RuneStart(sample[len(sample)-i]) {
+			if !utf8.FullRune(sample[len(sample)-i:]) {
+				return sample[:len(sample)-i]
+			}
+			break
+		}
+	}
+	return sample
+}
+
+// ToUTF8 converts content in the given encoding to UTF-8, dropping any byte
+// order mark. An empty encoding, as recorded for files indexed before
+// encodings were detected, is taken as UTF-8.
+func ToUTF8(content []byte, encoding string) ([]byte, error) {
+	switch encoding {
+	case EncodingUTF8, "":
+		return bytes.TrimPrefix(content, bomUTF8), nil
+
+	case EncodingUTF16LE, EncodingUTF16BE:
+		if encoding == EncodingUTF16LE {
+			content = bytes.TrimPrefix(content, bomUTF16LE)
+		} else {
+			content = bytes.TrimPrefix(content, bomUTF16BE)
+		}
+
+		units := make([]uint16, len(content)/2)
+		for i := range units {
+			lo, hi := content[2*i], content[2*i+1]
+			if encoding == EncodingUTF16BE {
+				lo, hi = hi, lo
+			}
+			units[i] = uint16(lo) | uint16(hi)<<8
+		}
+		return []byte(string(utf16.Decode(units))), nil
+
+	case EncodingLatin1:
+		// Latin-1 bytes are the first 256 code points
+		var buf bytes.Buffer
+		buf.Grow(len(content) * 2)
+		for _, b := range content {
+			buf.WriteRune(rune(b))
+		}
+		return buf.Bytes(), nil
+	}
+
+	return nil, fmt.Errorf("unsupported encoding: %s", encoding)
+}
+
+// ReadFileUTF8 reads a file as UTF-8, transcoding it from encoding, such as
+// the encoding its file record was indexed with, so line numbers match the
+// parsed symbols
+func ReadFileUTF8(path, encoding string) ([]byte, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ToUTF8(content, encoding)
+}
@@ -0,0 +1,43 @@
+package utils
+
+import (
+	"strings"
+	"unicode"
+)
+
+// SplitIdentifier splits a camelCase, PascalCase, snake_case or kebab-case
+// identifier into lowercase words (e.g. "handleHTTPRequest" -> handle, http, request)
+func SplitIdentifier(name string) []string {
+	var words []string
+	var current []rune
+
+	flush := func() {
+		if len(current) > 0 {
+			words = append(words, strings.ToLower(string(current)))
+			current = current[:0]
+		}
+	}
+
+	runes := []rune(name)
+	for i, r := range runes {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			flush()
+			continue
+		}
+
+		if unicode.IsUpper(r) && len(current) > 0 {
+			prev := runes[i-1]
+			nextIsLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+
+			// "handleHTTP" splits before H; "HTTPRequest" splits before R
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextIsLower) {
+				flush()
+			}
+		}
+
+		current = append(current, r)
+	}
+	flush()
+
+	return words
+}
@@ -62,6 +62,11 @@ func NewIgnoreMatcher(projectPath string) (*IgnoreMatcher, error) {
 	}, nil
 }
 
+// AddPatterns adds gitignore-style patterns to ignore
+func (im *IgnoreMatcher) AddPatterns(patterns ...string) {
+	im.patterns = append(im.patterns, patterns...)
+}
+
 // ShouldIgnore checks if a path should be ignored
 func (im *IgnoreMatcher) ShouldIgnore(path string) bool {
 	base := filepath.Base(path)
@@ -1,8 +1,11 @@
 package utils
 
 import (
+	"fmt"
+	"io"
 	"log"
 	"os"
+	"sync"
 )
 
 // Logger levels
@@ -17,22 +20,54 @@ const (
 
 var currentLevel = INFO
 
-// Logger is a simple logger
+// syncWriter serializes writes so lines from concurrent loggers don't interleave
+type syncWriter struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func (s *syncWriter) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.w.Write(p)
+}
+
+// stdout is shared by every logger writing to standard output
+var stdout = &syncWriter{w: os.Stdout}
+
+// Logger is a simple logger, safe for concurrent use
 type Logger struct {
 	prefix string
 	level  LogLevel
+	out    *syncWriter
 	logger *log.Logger
 }
 
 // NewLogger creates a new logger
 func NewLogger(prefix string) *Logger {
+	return newLogger(prefix, currentLevel, stdout)
+}
+
+// NewLoggerWithOutput creates a new logger writing to w
+func NewLoggerWithOutput(prefix string, w io.Writer) *Logger {
+	return newLogger(prefix, currentLevel, &syncWriter{w: w})
+}
+
+func newLogger(prefix string, level LogLevel, out *syncWriter) *Logger {
 	return &Logger{
 		prefix: prefix,
-		level:  currentLevel,
-		logger: log.New(os.Stdout, prefix+" ", log.LstdFlags),
+		level:  level,
+		out:    out,
+		logger: log.New(out, prefix+" ", log.LstdFlags),
 	}
 }
 
+// WithWorker returns a logger for one of several concurrent workers. It
+// shares this logger's output and tags each line with the worker's number.
+func (l *Logger) WithWorker(id int) *Logger {
+	return newLogger(fmt.Sprintf("%s [worker %d]", l.prefix, id), l.level, l.out)
+}
+
 // SetLevel sets the log level
 func SetLevel(level LogLevel) {
 	currentLevel = level
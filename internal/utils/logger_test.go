@@ -0,0 +1,53 @@
+package utils
+
+import (
+	"bytes"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestLoggerConcurrentLines(t *testing.T) {
+	var buf bytes.Buffer
+	logger := NewLoggerWithOutput("[Test]", &buf)
+
+	const workers, lines = 8, 200
+	payload := strings.Repeat("x", 512) // Long enough for unguarded writes to interleave
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(worker *Logger, id int) {
+			defer wg.Done()
+			for i := 0; i < lines; i++ {
+				worker.Warnf("line %d-%d %s end", id, i, payload)
+			}
+		}(logger.WithWorker(w), w)
+	}
+	wg.Wait()
+
+	pattern := regexp.MustCompile(`^\[Test\] \[worker (\d+)\] \S+ \S+ \[WARN\] line (\d+)-\d+ x{512} end$`)
+	output := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(output) != workers*lines {
+		t.Fatalf("Expected %d lines, got %d", workers*lines, len(output))
+	}
+	for _, line := range output {
+		match := pattern.FindStringSubmatch(line)
+		if match == nil {
+			t.Fatalf("Garbled log line: %q", line)
+		}
+		if match[1] != match[2] {
+			t.Errorf("Line from worker %s tagged as worker %s", match[2], match[1])
+		}
+	}
+}
+
+func TestLoggerWithWorkerPrefix(t *testing.T) {
+	var buf bytes.Buffer
+	NewLoggerWithOutput("[Indexer]", &buf).WithWorker(3).Warnf("failed to parse %s", "a.go")
+
+	if want := "[Indexer] [worker 3] "; !strings.HasPrefix(buf.String(), want) {
+		t.Errorf("Expected line to start with %q, got %q", want, buf.String())
+	}
+}
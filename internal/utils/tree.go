@@ -0,0 +1,38 @@
+package utils
+
+import (
+	"fmt"
+	"io"
+)
+
+// TreeNode is a labeled node printed by WriteTree
+type TreeNode struct {
+	Label    string
+	Children []*TreeNode
+}
+
+// WriteTree prints each root on its own line with its descendants indented
+// beneath it using box-drawing characters:
+//
+//	main.go
+//	├── Server (class)
+//	│   └── Start (method)
+//	└── main (function)
+func WriteTree(w io.Writer, roots []*TreeNode) {
+	for _, root := range roots {
+		fmt.Fprintln(w, root.Label)
+		writeTreeChildren(w, root.Children, "")
+	}
+}
+
+// writeTreeChildren prints nodes below a parent whose lines start with prefix
+func writeTreeChildren(w io.Writer, nodes []*TreeNode, prefix string) {
+	for i, node := range nodes {
+		connector, indent := "├── ", "│   "
+		if i == len(nodes)-1 {
+			connector, indent = "└── ", "    "
+		}
+		fmt.Fprintln(w, prefix+connector+node.Label)
+		writeTreeChildren(w, node.Children, prefix+indent)
+	}
+}
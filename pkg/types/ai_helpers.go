@@ -1,5 +1,7 @@
 package types
 
+import "time"
+
 // CodeContext represents extracted code context for AI analysis
 type CodeContext struct {
 	Symbol            *Symbol            `json:"symbol"`
@@ -34,6 +36,8 @@ type ChangeImpact struct {
 	RiskLevel           string    `json:"risk_level"`            // low, medium, high
 	Suggestions         []string  `json:"suggestions"`           // Refactoring suggestions
 	BreakingChanges     bool      `json:"breaking_changes"`      // Would this break the API?
+	Summary             string    `json:"summary"`               // Why the risk level is what it is
+	RiskFactors         []string  `json:"risk_factors"`          // Contributing factors, most significant first
 }
 
 // CodeMetrics represents various code quality metrics
@@ -52,6 +56,67 @@ type CodeMetrics struct {
 	Quality               string  `json:"quality"` // excellent, good, fair, poor
 }
 
+// ComplexityHistogram counts a project's functions by cyclomatic complexity
+type ComplexityHistogram struct {
+	Buckets        []*ComplexityBucket `json:"buckets"`
+	TotalFunctions int                 `json:"total_functions"`
+}
+
+// ComplexityBucket is one complexity range of a ComplexityHistogram
+type ComplexityBucket struct {
+	Label    string         `json:"label"` // e.g. "6-10"
+	Min      int            `json:"min"`
+	Max      int            `json:"max,omitempty"` // 0 for the open-ended last bucket
+	Count    int            `json:"count"`
+	Examples []*CodeMetrics `json:"examples"` // The bucket's most complex functions
+}
+
+// ComplexityPoint is a symbol's complexity at one recorded version
+type ComplexityPoint struct {
+	Version              int       `json:"version"` // 1 for the first version recorded
+	FilePath             string    `json:"file_path"`
+	Hash                 string    `json:"hash"` // Hash of the symbol's source
+	LinesOfCode          int       `json:"lines_of_code"`
+	CyclomaticComplexity int       `json:"cyclomatic_complexity"`
+	RecordedAt           time.Time `json:"recorded_at"`
+}
+
+// SymbolVersionKey identifies the symbol a run of recorded versions belongs to
+type SymbolVersionKey struct {
+	FilePath string     `json:"file_path"`
+	Type     SymbolType `json:"type"`
+}
+
+// FileChurn combines how often a file changes with how complex it is
+type FileChurn struct {
+	FilePath        string  `json:"file_path"`
+	Commits         int     `json:"commits"`          // Commits touching the file
+	SymbolCount     int     `json:"symbol_count"`     // Indexed symbols in the file
+	TotalComplexity int     `json:"total_complexity"` // Sum of function cyclomatic complexity
+	MaxComplexity   int     `json:"max_complexity"`   // Most complex function
+	Score           float64 `json:"score"`            // Commits x complexity; higher means refactor first
+}
+
+// Ownership is the git authorship of a symbol's lines
+type Ownership struct {
+	Symbol         string         `json:"symbol"`
+	FilePath       string         `json:"file_path"`
+	StartLine      int            `json:"start_line"`
+	EndLine        int            `json:"end_line"`
+	Contributors   []*Contributor `json:"contributors"` // Most lines first
+	LastModifiedBy string         `json:"last_modified_by,omitempty"`
+	LastModified   time.Time      `json:"last_modified"`
+	Warning        string         `json:"warning,omitempty"` // Why authorship is unavailable
+}
+
+// Contributor is an author of some of a symbol's committed lines
+type Contributor struct {
+	Name  string  `json:"name"`
+	Email string  `json:"email"`
+	Lines int     `json:"lines"`
+	Share float64 `json:"share"` // Fraction of the symbol's committed lines
+}
+
 // SmartSnippet represents a code snippet with all its dependencies
 type SmartSnippet struct {
 	Symbol        *Symbol  `json:"symbol"`
@@ -97,6 +162,8 @@ type RefactoringOpportunity struct {
 	Effort      string   `json:"effort"`       // Effort level (low, medium, high)
 	Benefits    []string `json:"benefits"`     // Benefits of refactoring
 	Risks       []string `json:"risks"`        // Potential risks
+	StartLine   int      `json:"start_line,omitempty"` // Suggested range, for extract_method
+	EndLine     int      `json:"end_line,omitempty"`
 }
 
 // NavigationHint represents a quick navigation suggestion for AI
@@ -117,3 +184,13 @@ type CodeSimilarity struct {
 	Differences     []string `json:"differences"`
 	SuggestRefactor bool     `json:"suggest_refactor"`
 }
+
+// AntiPatternFinding is a function flagged by an anti-pattern rule
+type AntiPatternFinding struct {
+	Rule       string `json:"rule"`
+	FilePath   string `json:"file_path"`
+	Line       int    `json:"line"`
+	Symbol     string `json:"symbol"`
+	Message    string `json:"message"`
+	Suggestion string `json:"suggestion"`
+}
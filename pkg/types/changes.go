@@ -1,5 +1,7 @@
 package types
 
+import "time"
+
 // ChangeType represents the type of change
 type ChangeType string
 
@@ -104,6 +106,15 @@ type DependencyEdge struct {
 	Weight       int    `json:"weight"`       // Usage count
 }
 
+// CouplingHotspot is a symbol with its coupling to the rest of the project
+type CouplingHotspot struct {
+	Symbol      *Symbol `json:"symbol"`
+	FilePath    string  `json:"file_path"`
+	Afferent    int     `json:"afferent"`    // Ca: symbols that depend on this one
+	Efferent    int     `json:"efferent"`    // Ce: symbols this one depends on
+	Instability float64 `json:"instability"` // Ce / (Ca + Ce); 0 is maximally stable
+}
+
 // ChangeSet represents a set of related changes
 type ChangeSet struct {
 	ID          string    `json:"id"`
@@ -124,3 +135,56 @@ type ValidationResult struct {
 	CanProceed      bool                `json:"can_proceed"`
 	Recommendations []string            `json:"recommendations"`
 }
+
+// SymbolSnapshot is the recorded state of a single symbol in an index snapshot
+type SymbolSnapshot struct {
+	Key       string     `json:"key"` // Stable identity across re-indexing: file, type and qualified name
+	ID        int64      `json:"id"`
+	Name      string     `json:"name"`
+	Type      SymbolType `json:"type"`
+	FilePath  string     `json:"file_path"`
+	StartLine int        `json:"start_line"`
+	Hash      string     `json:"hash"` // Hash of the signature, documentation and source
+}
+
+// IndexSnapshot is a point-in-time record of all indexed symbols
+type IndexSnapshot struct {
+	ProjectPath string                     `json:"project_path"`
+	CreatedAt   time.Time                  `json:"created_at"`
+	Symbols     map[string]*SymbolSnapshot `json:"symbols"` // Keyed by SymbolSnapshot.Key
+}
+
+// SymbolDiff lists symbol changes between a snapshot and the current index
+type SymbolDiff struct {
+	Added    []*SymbolSnapshot `json:"added"`
+	Removed  []*SymbolSnapshot `json:"removed"`
+	Modified []*SymbolSnapshot `json:"modified"` // Current state of symbols whose hash changed
+}
+
+// Operations of a DiffLine
+const (
+	DiffEqual   = "equal"
+	DiffAdded   = "added"
+	DiffRemoved = "removed"
+)
+
+// DiffLine is one line of a line-level diff
+type DiffLine struct {
+	Op    string `json:"op"`
+	Text  string `json:"text"`
+	LineA int    `json:"line_a,omitempty"` // Line in the first symbol's file; 0 if added
+	LineB int    `json:"line_b,omitempty"` // Line in the second symbol's file; 0 if removed
+}
+
+// SymbolComparison compares the signatures and bodies of two symbols
+type SymbolComparison struct {
+	SymbolA          *Symbol     `json:"symbol_a"`
+	SymbolB          *Symbol     `json:"symbol_b"`
+	FileA            string      `json:"file_a"`
+	FileB            string      `json:"file_b"`
+	SignatureChanged bool        `json:"signature_changed"` // Signatures differ other than by name
+	Diff             []*DiffLine `json:"diff"`
+	LinesAdded       int         `json:"lines_added"`
+	LinesRemoved     int         `json:"lines_removed"`
+	Similarity       float64     `json:"similarity"` // Share of lines in common, 0..1
+}
@@ -14,6 +14,13 @@ type File struct {
 	Hash         string    `json:"hash"`
 	LastModified time.Time `json:"last_modified"`
 	LastIndexed  time.Time `json:"last_indexed"`
+	Encoding     string    `json:"encoding,omitempty"` // Encoding detected on disk, e.g. utf-8 or utf-16le
+}
+
+// FileSummary is an indexed file with the number of symbols found in it
+type FileSummary struct {
+	*File
+	SymbolCount int `json:"symbol_count"`
 }
 
 // ImportType represents the type of import
@@ -30,8 +37,32 @@ type Import struct {
 	ID             int64      `json:"id"`
 	FileID         int64      `json:"file_id"`
 	Source         string     `json:"source"`
+	Alias          string     `json:"alias,omitempty"` // Go's local package name, e.g. f for import f "fmt", or "_" or "."
 	ImportedNames  []string   `json:"imported_names,omitempty"`
 	ImportType     ImportType `json:"import_type"`
 	LineNumber     int        `json:"line_number"`
 	ImportedSymbol string     `json:"imported_symbol,omitempty"` // For specific symbol imports
 }
+
+// ImportUsage lists which members of an import a file actually uses
+type ImportUsage struct {
+	Import  *Import  `json:"import"`
+	Members []string `json:"members"` // Used names, e.g. "Println" for fmt or "join" for from os.path import join
+	Unused  bool     `json:"unused"`  // None of the import's members are referenced
+}
+
+// FileDependencies are the project files a file imports and is imported by
+type FileDependencies struct {
+	FilePath   string   `json:"file_path"`
+	Imports    []string `json:"imports"`     // Project files this file imports
+	ImportedBy []string `json:"imported_by"` // Project files importing this file
+	External   []string `json:"external"`    // Imports outside the project, such as stdlib and third-party packages
+}
+
+// ExternalDep is a third-party package imported by the project
+type ExternalDep struct {
+	Name       string   `json:"name"`
+	Language   string   `json:"language"`
+	UsageCount int      `json:"usage_count"` // Number of import statements
+	Files      []string `json:"files"`       // Files importing the package
+}
@@ -9,19 +9,116 @@ type MCPTool struct {
 
 // SearchOptions contains options for symbol search
 type SearchOptions struct {
-	Query       string       `json:"query"`
-	Type        *SymbolType  `json:"type,omitempty"`
-	Language    string       `json:"language,omitempty"`
-	FilePattern string       `json:"file_pattern,omitempty"`
+	Query       string        `json:"query"`
+	Type        *SymbolType   `json:"type,omitempty"`
+	Language    string        `json:"language,omitempty"`
+	FilePattern string        `json:"file_pattern,omitempty"`
+	Limit       int           `json:"limit,omitempty"`
+	Ranking     SearchRanking `json:"ranking,omitempty"`
+	FileID      int64         `json:"-"` // Restrict to one file when non-zero
+	ProjectID   int64         `json:"-"` // Restrict to one project when non-zero
+}
+
+// SearchRanking selects how search results are ordered
+type SearchRanking string
+
+const (
+	SearchRankingRelevance SearchRanking = "relevance" // Exact, then prefix, then best full-text matches (default)
+	SearchRankingName      SearchRanking = "name"      // Alphabetical by name
+)
+
+// SymbolFilter selects symbols by structural predicates, all of which must
+// hold; zero-valued fields are ignored
+type SymbolFilter struct {
+	Types       []SymbolType `json:"types,omitempty"`        // Any of these kinds
+	Visibility  []Visibility `json:"visibility,omitempty"`   // Any of these visibilities
+	MinLines    int          `json:"min_lines,omitempty"`    // Inclusive
+	MaxLines    int          `json:"max_lines,omitempty"`    // Inclusive
+	HasDocs     *bool        `json:"has_docs,omitempty"`     // Documented (true) or undocumented (false)
+	FilePattern string       `json:"file_pattern,omitempty"` // Glob on the relative path; * also matches /
 	Limit       int          `json:"limit,omitempty"`
 }
 
 // FileStructure represents the structure of a file
 type FileStructure struct {
+	FilePath string        `json:"file_path"`
+	Language string        `json:"language"`
+	Symbols  []*Symbol     `json:"symbols"`
+	Imports  []*Import     `json:"imports"`
+	Tree     []*SymbolNode `json:"tree,omitempty"` // Only set in nested mode
+}
+
+// APISurface is the exported API of a project, for documentation generation
+type APISurface struct {
+	Files       []*APIFile `json:"files"`
+	SymbolCount int        `json:"symbol_count"`
+}
+
+// APIFile holds the exported symbols of one file
+type APIFile struct {
 	FilePath string    `json:"file_path"`
 	Language string    `json:"language"`
+	Package  string    `json:"package,omitempty"`
+	Symbols  []*Symbol `json:"symbols"`
+}
+
+// SymbolOrder controls how symbols in a file structure are sorted
+type SymbolOrder string
+
+const (
+	SymbolOrderLine SymbolOrder = "line" // Source order (default)
+	SymbolOrderName SymbolOrder = "name" // Alphabetical by name
+	SymbolOrderKind SymbolOrder = "kind" // Grouped by symbol type, then source order
+)
+
+// FileStructureOptions contains options for building a file structure
+type FileStructureOptions struct {
+	Order  SymbolOrder `json:"order,omitempty"`
+	Nested bool        `json:"nested,omitempty"` // Group members under their parent class or type
+}
+
+// SymbolNode is a symbol with its nested members
+type SymbolNode struct {
+	Symbol   *Symbol       `json:"symbol"`
+	Children []*SymbolNode `json:"children,omitempty"`
+}
+
+// NamespaceTree is a project's files and top-level symbols grouped by
+// package, module or namespace
+type NamespaceTree struct {
+	Roots      []*NamespaceNode `json:"roots"`
+	Namespaces int              `json:"namespaces"`
+	Files      int              `json:"files"`
+}
+
+// NamespaceNode is one package, module or namespace; its Path is the full
+// name, e.g. com.example.app or internal/core
+type NamespaceNode struct {
+	Name     string           `json:"name"`
+	Path     string           `json:"path"`
+	Files    []*NamespaceFile `json:"files,omitempty"`
+	Children []*NamespaceNode `json:"children,omitempty"`
+}
+
+// NamespaceFile is a file within a namespace and its top-level symbols
+type NamespaceFile struct {
+	Path     string    `json:"path"`
+	Language string    `json:"language"`
 	Symbols  []*Symbol `json:"symbols"`
-	Imports  []*Import `json:"imports"`
+}
+
+// RangeOverlap describes how a symbol's lines relate to a selected line range
+type RangeOverlap string
+
+const (
+	RangeOverlapContained   RangeOverlap = "contained"   // Symbol lies entirely within the range
+	RangeOverlapOverlapping RangeOverlap = "overlapping" // Symbol only partially overlaps the range
+)
+
+// SymbolInRange is a symbol found within a line range of a file
+type SymbolInRange struct {
+	Symbol  *Symbol      `json:"symbol"`
+	Overlap RangeOverlap `json:"overlap"`
 }
 
 // SymbolDetails contains detailed information about a symbol
@@ -52,3 +149,8 @@ type ProjectOverview struct {
 	TopLevelSymbols  []*Symbol          `json:"top_level_symbols"`
 	RecentlyModified []*File            `json:"recently_modified"`
 }
+
+// ProjectOverviewOptions controls how much work GetProjectOverview does
+type ProjectOverviewOptions struct {
+	Detailed bool `json:"detailed,omitempty"` // Load every file for per-language stats, top-level symbols and recent changes
+}
@@ -31,3 +31,22 @@ type ParseError struct {
 	Column  int    `json:"column"`
 	Message string `json:"message"`
 }
+
+// FileParseError is a recorded parse failure or diagnostic for an indexed file
+type FileParseError struct {
+	ID               int64  `json:"id"`
+	FilePath         string `json:"file_path"`
+	Line             int    `json:"line,omitempty"`
+	Column           int    `json:"column,omitempty"`
+	Message          string `json:"message"`
+	SymbolsRecovered int    `json:"symbols_recovered"` // Symbols still extracted from the file
+	Recovered        bool   `json:"recovered"`         // False when the parser failed outright
+}
+
+// ParserInfo describes a registered language parser
+type ParserInfo struct {
+	Language   string   `json:"language"`
+	Extensions []string `json:"extensions"`
+	Priority   int      `json:"priority"`
+	Frameworks []string `json:"frameworks,omitempty"` // Frameworks with dedicated analysis support
+}
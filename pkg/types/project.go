@@ -21,3 +21,30 @@ type ProjectConfig struct {
 	Plugins      []string          `json:"plugins"`
 	CustomConfig map[string]string `json:"custom_config,omitempty"`
 }
+
+// IndexStats is the time a full index spent in each phase. Files are indexed
+// concurrently, so the per-file phases add up the time of every worker and
+// may exceed Total.
+type IndexStats struct {
+	Files           int                      `json:"files"`
+	Total           time.Duration            `json:"total"`             // Wall-clock time of the whole index
+	Scan            time.Duration            `json:"scan"`              // Walking the project for files
+	Hash            time.Duration            `json:"hash"`              // Reading, hashing and checking files for changes
+	Parse           time.Duration            `json:"parse"`             // Decoding and parsing files
+	ParseByLanguage map[string]time.Duration `json:"parse_by_language"` // Parse split by parser language
+	Write           time.Duration            `json:"write"`             // Saving results to the database
+	Link            time.Duration            `json:"link"`              // Linking symbols across files once all are indexed
+}
+
+// DryRunReport is what indexing a project would do, found without writing
+// to the index
+type DryRunReport struct {
+	Files      int            `json:"files"`       // Files that would be in the index
+	New        []string       `json:"new"`         // Files not indexed yet
+	Changed    []string       `json:"changed"`     // Files whose content changed since they were indexed
+	Unchanged  int            `json:"unchanged"`   // Files that would be skipped
+	Missing    []string       `json:"missing"`     // Indexed files no longer found in the project
+	Failed     []string       `json:"failed"`      // New or changed files that fail to parse
+	Symbols    int            `json:"symbols"`     // Symbols parsed from new and changed files
+	ByLanguage map[string]int `json:"by_language"` // Files per language
+}
@@ -0,0 +1,20 @@
+package types
+
+// SecretKind identifies the detector that flagged a possible secret
+type SecretKind string
+
+const (
+	SecretKindAWSAccessKey SecretKind = "aws_access_key"
+	SecretKindPrivateKey   SecretKind = "private_key"
+	SecretKindGitHubToken  SecretKind = "github_token"
+	SecretKindHighEntropy  SecretKind = "high_entropy_token"
+)
+
+// SecretFinding represents a possible hardcoded credential in a string literal
+type SecretFinding struct {
+	FilePath string     `json:"file_path"`
+	Line     int        `json:"line"`
+	Kind     SecretKind `json:"kind"`
+	Match    string     `json:"match"`             // Redacted match
+	Entropy  float64    `json:"entropy,omitempty"` // Shannon entropy (bits per character) for generic tokens
+}
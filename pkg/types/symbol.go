@@ -4,17 +4,21 @@ package types
 type SymbolType string
 
 const (
-	SymbolTypeFunction  SymbolType = "function"
-	SymbolTypeClass     SymbolType = "class"
-	SymbolTypeMethod    SymbolType = "method"
-	SymbolTypeVariable  SymbolType = "variable"
-	SymbolTypeInterface SymbolType = "interface"
-	SymbolTypeType      SymbolType = "type"
-	SymbolTypeEnum      SymbolType = "enum"
-	SymbolTypeStruct    SymbolType = "struct"
-	SymbolTypeConstant  SymbolType = "constant"
-	SymbolTypePackage   SymbolType = "package"
-	SymbolTypeModule    SymbolType = "module"
+	SymbolTypeFunction    SymbolType = "function"
+	SymbolTypeClass       SymbolType = "class"
+	SymbolTypeMethod      SymbolType = "method"
+	SymbolTypeVariable    SymbolType = "variable"
+	SymbolTypeInterface   SymbolType = "interface"
+	SymbolTypeType        SymbolType = "type"
+	SymbolTypeEnum        SymbolType = "enum"
+	SymbolTypeStruct      SymbolType = "struct"
+	SymbolTypeConstant    SymbolType = "constant"
+	SymbolTypePackage     SymbolType = "package"
+	SymbolTypeModule      SymbolType = "module"
+	SymbolTypeNamespace   SymbolType = "namespace"
+	SymbolTypeField       SymbolType = "field"
+	SymbolTypeProperty    SymbolType = "property"
+	SymbolTypeConstructor SymbolType = "constructor"
 )
 
 // Visibility represents symbol visibility
@@ -25,6 +29,7 @@ const (
 	VisibilityPrivate   Visibility = "private"
 	VisibilityProtected Visibility = "protected"
 	VisibilityInternal  Visibility = "internal"
+	VisibilityPackage   Visibility = "package" // Java package-private
 )
 
 // Symbol represents a code symbol (function, class, variable, etc.)
@@ -34,6 +39,9 @@ type Symbol struct {
 	Name          string                 `json:"name"`
 	Type          SymbolType             `json:"type"`
 	Signature     string                 `json:"signature"`
+	TypeParams    []string               `json:"type_params,omitempty"` // Generic type parameters, e.g. "T any"
+	Parameters    []*Parameter           `json:"parameters,omitempty"`  // Function parameters, if the parser extracts them
+	ReturnType    string                 `json:"return_type,omitempty"` // Function return type as written
 	ParentID      *int64                 `json:"parent_id,omitempty"`
 	StartLine     int                    `json:"start_line"`
 	EndLine       int                    `json:"end_line"`
@@ -45,9 +53,25 @@ type Symbol struct {
 	IsStatic      bool                   `json:"is_static"`
 	IsAbstract    bool                   `json:"is_abstract"`
 	Documentation string                 `json:"documentation,omitempty"`
+	Dependencies  []string               `json:"dependencies,omitempty"` // Names of the symbols it calls or uses, filled in after indexing
 	Metadata      map[string]interface{} `json:"metadata,omitempty"`
 }
 
+// Parameter is a function parameter as parsed from its declaration
+type Parameter struct {
+	Name     string `json:"name,omitempty"` // Empty for unnamed parameters
+	Type     string `json:"type,omitempty"` // Empty if not annotated; excludes the variadic marker
+	Default  string `json:"default,omitempty"`
+	Variadic bool   `json:"variadic,omitempty"` // ...T, *args or **kwargs
+}
+
+// FunctionDetails is the parsed parameter list and return type of a function
+type FunctionDetails struct {
+	SymbolID   int64        `json:"symbol_id"`
+	Parameters []*Parameter `json:"parameters"`
+	ReturnType string       `json:"return_type,omitempty"`
+}
+
 // RelationshipType represents the type of relationship between symbols
 type RelationshipType string
 
@@ -58,14 +82,30 @@ const (
 	RelationshipUses       RelationshipType = "uses"
 	RelationshipImports    RelationshipType = "imports"
 	RelationshipContains   RelationshipType = "contains"
+	RelationshipTests      RelationshipType = "tests"      // From a test function to the symbol it exercises
+	RelationshipReferences RelationshipType = "references" // From a table to a table its foreign keys point at
 )
 
 // Relationship represents a relationship between two symbols
 type Relationship struct {
-	ID           int64            `json:"id"`
-	FromSymbolID int64            `json:"from_symbol_id"`
-	ToSymbolID   int64            `json:"to_symbol_id"`
-	Type         RelationshipType `json:"type"`
+	ID           int64                  `json:"id"`
+	FromSymbolID int64                  `json:"from_symbol_id"`
+	ToSymbolID   int64                  `json:"to_symbol_id"`
+	Type         RelationshipType       `json:"type"`
+	Metadata     map[string]interface{} `json:"metadata,omitempty"`
+}
+
+// CrossLanguageBinding is a call from a symbol in one language to a symbol
+// in another through a foreign function interface, such as Go calling C
+// through cgo
+type CrossLanguageBinding struct {
+	Binding      string  `json:"binding"` // The interface used, e.g. "cgo" or "ctypes"
+	From         *Symbol `json:"from"`
+	FromFile     string  `json:"from_file"`
+	FromLanguage string  `json:"from_language"`
+	To           *Symbol `json:"to"`
+	ToFile       string  `json:"to_file"`
+	ToLanguage   string  `json:"to_language"`
 }
 
 // Reference represents a reference to a symbol
@@ -77,3 +117,159 @@ type Reference struct {
 	ColumnNumber  int    `json:"column_number"`
 	ReferenceType string `json:"reference_type"` // 'call', 'assignment', 'type_reference'
 }
+
+// ScoredSymbol is a symbol with a relatedness score and what contributed to it
+type ScoredSymbol struct {
+	Symbol   *Symbol  `json:"symbol"`
+	FilePath string   `json:"file_path"`
+	Score    float64  `json:"score"`
+	Reasons  []string `json:"reasons"` // e.g. "relationship", "calls", "same_file", "similar_name"
+}
+
+// GodObject is a class or struct with an excessive number of members
+type GodObject struct {
+	Symbol      *Symbol `json:"symbol"`
+	FilePath    string  `json:"file_path"`
+	MethodCount int     `json:"method_count"`
+	FieldCount  int     `json:"field_count"`
+}
+
+// Schema is the database schema declared by a project's SQL files
+type Schema struct {
+	Tables  []*SchemaTable `json:"tables"`
+	Views   []*SchemaView  `json:"views"`
+	Indexes []*SchemaIndex `json:"indexes"`
+}
+
+// SchemaTable is a table from a CREATE TABLE statement
+type SchemaTable struct {
+	Name         string          `json:"name"`
+	FilePath     string          `json:"file_path"`
+	StartLine    int             `json:"start_line"`
+	Columns      []*SchemaColumn `json:"columns"`
+	ReferencedBy []string        `json:"referenced_by"` // Tables with foreign keys to this one
+}
+
+// SchemaColumn is a column of a SchemaTable
+type SchemaColumn struct {
+	Name       string `json:"name"`
+	Type       string `json:"type"`
+	PrimaryKey bool   `json:"primary_key,omitempty"`
+	NotNull    bool   `json:"not_null,omitempty"`
+	References string `json:"references,omitempty"` // "table.column" its foreign key points at
+}
+
+// SchemaView is a view from a CREATE VIEW statement
+type SchemaView struct {
+	Name      string `json:"name"`
+	FilePath  string `json:"file_path"`
+	StartLine int    `json:"start_line"`
+}
+
+// SchemaIndex is an index from a CREATE INDEX statement
+type SchemaIndex struct {
+	Name     string   `json:"name"`
+	Table    string   `json:"table"`
+	FilePath string   `json:"file_path"`
+	Columns  []string `json:"columns"`
+	Unique   bool     `json:"unique,omitempty"`
+}
+
+// SymbolSignature is the lightweight view of a symbol used for tooltips
+type SymbolSignature struct {
+	Name      string     `json:"name"`
+	Type      SymbolType `json:"type"`
+	Signature string     `json:"signature"`
+	Summary   string     `json:"summary,omitempty"` // First line of the documentation
+	FilePath  string     `json:"file_path"`
+	StartLine int        `json:"start_line"`
+}
+
+// SymbolSource is the raw source text of a symbol as it is on disk
+type SymbolSource struct {
+	Name      string `json:"name"`
+	FilePath  string `json:"file_path"`
+	StartLine int    `json:"start_line"`
+	EndLine   int    `json:"end_line"`
+	Source    string `json:"source"`
+	Stale     bool   `json:"stale"`             // File changed since it was indexed
+	Warning   string `json:"warning,omitempty"` // Set when the line range may be out of date
+}
+
+// PromptBundle is a symbol's source with the context an LLM needs to work
+// on it: the source of the functions it calls and the signatures of its callers
+type PromptBundle struct {
+	Symbol     *BundledSymbol   `json:"symbol"`
+	Callees    []*BundledSymbol `json:"callees"`
+	Callers    []*BundledSymbol `json:"callers"`
+	TotalChars int              `json:"total_chars"` // Characters of source and signatures included
+	MaxChars   int              `json:"max_chars"`
+	Omitted    int              `json:"omitted"` // Callees and callers left out to stay within the limits
+}
+
+// BundledSymbol is one entry of a PromptBundle, carrying either its full
+// source or just its signature
+type BundledSymbol struct {
+	Name      string     `json:"name"`
+	Type      SymbolType `json:"type"`
+	FilePath  string     `json:"file_path"`
+	StartLine int        `json:"start_line"`
+	EndLine   int        `json:"end_line"`
+	Signature string     `json:"signature,omitempty"`
+	Source    string     `json:"source,omitempty"`
+}
+
+// NamingIssue is a symbol whose name breaks its language's naming convention
+type NamingIssue struct {
+	Symbol     *Symbol `json:"symbol"`
+	FilePath   string  `json:"file_path"`
+	Convention string  `json:"convention"` // e.g. "snake_case", "PascalCase"
+	Expected   string  `json:"expected"`   // The name rewritten to follow the convention
+}
+
+// DevelopmentStatus tracks where a symbol is in an AI-driven development workflow
+type DevelopmentStatus string
+
+const (
+	StatusNotStarted  DevelopmentStatus = "not_started"
+	StatusInProgress  DevelopmentStatus = "in_progress"
+	StatusNeedsReview DevelopmentStatus = "needs_review"
+	StatusCompleted   DevelopmentStatus = "completed"
+	StatusBlocked     DevelopmentStatus = "blocked"
+)
+
+// DevelopmentStatuses lists every valid DevelopmentStatus
+var DevelopmentStatuses = []DevelopmentStatus{
+	StatusNotStarted, StatusInProgress, StatusNeedsReview, StatusCompleted, StatusBlocked,
+}
+
+// IsValid reports whether s is one of the known statuses
+func (s DevelopmentStatus) IsValid() bool {
+	for _, status := range DevelopmentStatuses {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
+
+// SymbolTracking is the development-workflow state stored with a symbol
+type SymbolTracking struct {
+	Status        DevelopmentStatus `json:"status"`
+	Priority      int               `json:"priority"`
+	AssignedAgent string            `json:"assigned_agent,omitempty"`
+}
+
+// TrackedSymbol is a symbol with its workflow state
+type TrackedSymbol struct {
+	Symbol   *Symbol `json:"symbol"`
+	FilePath string  `json:"file_path"`
+	SymbolTracking
+}
+
+// AnnotatedSymbol is a symbol carrying an annotation, attribute or decorator
+type AnnotatedSymbol struct {
+	Symbol      *Symbol  `json:"symbol"`
+	FilePath    string   `json:"file_path"`
+	Annotations []string `json:"annotations"` // As written, e.g. @GetMapping("/{id}") or HttpGet("{id}")
+}
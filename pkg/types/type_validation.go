@@ -96,6 +96,24 @@ type InvalidCall struct {
 	Suggestion     string    `json:"suggestion,omitempty"`
 }
 
+// ConformanceReport describes how a concrete type matches an interface's method set
+type ConformanceReport struct {
+	TypeName          string            `json:"type_name"`
+	InterfaceName     string            `json:"interface_name"`
+	Conforms          bool              `json:"conforms"` // All methods present with matching signatures
+	PresentMethods    []string          `json:"present_methods"`
+	MissingMethods    []string          `json:"missing_methods"`
+	MismatchedMethods []*MethodMismatch `json:"mismatched_methods"`
+	Coverage          float64           `json:"coverage"` // Fraction of interface methods matched (0.0 - 1.0)
+}
+
+// MethodMismatch represents a method present on a type with a different signature than required
+type MethodMismatch struct {
+	MethodName        string `json:"method_name"`
+	ExpectedSignature string `json:"expected_signature"`
+	ActualSignature   string `json:"actual_signature"`
+}
+
 // TypeCheckResult represents comprehensive type checking results for a file or project
 type TypeCheckResult struct {
 	Files             []*File            `json:"files"`